@@ -3,50 +3,408 @@
 // 本服務提供帳戶建立、存提款、轉帳等 RESTful API。
 // 此檔案負責初始化模組（bank, server, storage），
 // 並啟動 HTTP 伺服器；同時支援啟動時載入與結束時保存 JSON 快照。
+// 監聽位址與快照路徑可由旗標（-addr、-data）或環境變數（BANK_ADDR、BANK_DATA_FILE）指定，
+// 其餘功能開關皆透過環境變數設定。
 
 package main
 
 import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	"banking/internal/bank"
 	"banking/internal/server"
 	"banking/internal/storage"
 )
 
+// 監聽位址與快照檔的預設值。
+const (
+	defaultAddr     = ":8080"
+	defaultDataFile = "data.json"
+)
+
+// config 為啟動設定；優先順序為命令列旗標 > 環境變數 > 預設值（見 loadConfig）。
+type config struct {
+	addr     string // 監聽位址（-addr / BANK_ADDR）
+	dataFile string // JSON 快照路徑（-data / BANK_DATA_FILE）
+
+	onListen func(net.Addr) // 開始監聽後呼叫（測試用，可為 nil）
+}
+
 func main() {
-	const dataFile = "data.json"
+	cfg, err := loadConfig(os.Args[1:], os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// 收到 SIGINT/SIGTERM 時取消 ctx，由 run 優雅關閉並保存
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := run(ctx, cfg); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// loadConfig 解析命令列旗標，未指定的項目依序改用環境變數（getenv）與預設值。
+// 空白或無效的值（例如不是 host:port 的位址）一律視為未設定。
+func loadConfig(args []string, getenv func(string) string) (config, error) {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	addr := flags.String("addr", "", "listen address, e.g. :8080 (env BANK_ADDR)")
+	dataFile := flags.String("data", "", "JSON snapshot path (env BANK_DATA_FILE)")
+	if err := flags.Parse(args); err != nil {
+		return config{}, err
+	}
+	cfg := config{addr: defaultAddr, dataFile: defaultDataFile}
+	for _, v := range []string{*addr, getenv("BANK_ADDR")} {
+		if validAddr(v) {
+			cfg.addr = strings.TrimSpace(v)
+			break
+		}
+	}
+	for _, v := range []string{*dataFile, getenv("BANK_DATA_FILE")} {
+		if v = strings.TrimSpace(v); v != "" {
+			cfg.dataFile = v
+			break
+		}
+	}
+	return cfg, nil
+}
+
+// validAddr 判斷 v 是否為可監聽的 host:port（port 可為 0，host 可省略）。
+func validAddr(v string) bool {
+	_, port, err := net.SplitHostPort(strings.TrimSpace(v))
+	if err != nil {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 0 && n <= 65535
+}
+
+// run 依 cfg 與環境變數組裝 bank、storage 與 server，服務請求直到 ctx 取消，
+// 之後優雅關閉並寫入最後一份快照。
+func run(ctx context.Context, cfg config) error {
+	// 初始化銀行核心模組；BANK_TRANSFER_FEE 為每筆轉帳的固定手續費（預設 0，不收取）
+	b := bank.NewBankWithFee(envInt("BANK_TRANSFER_FEE", 0))
+
+	// 帳戶 ID 策略（BANK_ID_GEN=uuid 改用隨機 UUID，預設為遞增序號 "1"、"2"…）
+	if os.Getenv("BANK_ID_GEN") == "uuid" {
+		b.SetIDGen(bank.UUIDGen{})
+	}
 
-	// 初始化銀行核心模組
-	b := bank.NewBank()
+	// 每帳戶備註位元組上限（BANK_NOTE_BUDGET，0 為不限制）與超限策略（BANK_NOTE_POLICY=truncate|reject）
+	if n := envInt("BANK_NOTE_BUDGET", 0); n > 0 {
+		policy := bank.NoteTruncate
+		if os.Getenv("BANK_NOTE_POLICY") == "reject" {
+			policy = bank.NoteReject
+		}
+		b.SetNoteBudget(int(n), policy)
+	}
+
+	// 啟動時即暫停交易的幣別（BANK_DISABLED_CURRENCIES，逗號分隔；執行期間可經 /admin/currencies 切換）
+	if v := os.Getenv("BANK_DISABLED_CURRENCIES"); v != "" {
+		b.DisableCurrencies(strings.Split(v, ",")...)
+	}
+
+	// 最低轉帳金額（BANK_MIN_TRANSFER）與最低存提款金額（BANK_MIN_DEPOSIT_WITHDRAW），預設 1
+	b.SetMinTransfer(envInt("BANK_MIN_TRANSFER", 1))
+	b.SetMinDepositWithdraw(envInt("BANK_MIN_DEPOSIT_WITHDRAW", 1))
+
+	// 大額轉帳審核：超過 BANK_TRANSFER_HOLD_OVER 的轉帳需管理者核准，
+	// 逾 BANK_TRANSFER_HOLD_TTL_MIN 分鐘（預設 1440）未處理則自動釋放
+	if over := envInt("BANK_TRANSFER_HOLD_OVER", 0); over > 0 {
+		b.SetTransferHold(over, time.Duration(envInt("BANK_TRANSFER_HOLD_TTL_MIN", 1440))*time.Minute)
+	}
+
+	// 授權保留的有效期限（BANK_AUTH_HOLD_TTL，例如 168h；預設永不到期）：逾期未請款的保留自動釋放
+	b.SetAuthHoldTTL(envDuration("BANK_AUTH_HOLD_TTL", 0))
+
+	// 開戶審核（BANK_REQUIRE_ACCOUNT_APPROVAL=1）：新帳戶須經 /admin/accounts/{id}/approve 核准
+	b.SetOpeningApproval(os.Getenv("BANK_REQUIRE_ACCOUNT_APPROVAL") == "1")
+
+	// 每帳戶日誌保留上限（BANK_MAX_LOGS，預設 0 不限制）：超過時丟棄最舊的日誌
+	b.SetMaxLogs(int(envInt("BANK_MAX_LOGS", 0)))
+
+	// 讀多寫少時可啟用 copy-on-write 唯讀檢視（BANK_READ_SNAPSHOT=1），查詢不取鎖
+	b.SetReadSnapshot(os.Getenv("BANK_READ_SNAPSHOT") == "1")
 
-	// 嘗試從上次的 JSON 快照載入資料，若不存在則以空銀行啟動
-	if snap, err := storage.LoadSnapshot(dataFile); err == nil {
+	// 帳戶名稱禁用清單（BANK_NAME_DENYLIST_FILE，每行一條；/regex/ 形式為正規表示式）
+	if path := os.Getenv("BANK_NAME_DENYLIST_FILE"); path != "" {
+		entries, err := readLines(path)
+		if err != nil {
+			return fmt.Errorf("load name denylist: %w", err)
+		}
+		if err := b.SetNameDenylist(entries); err != nil {
+			return fmt.Errorf("load name denylist: %w", err)
+		}
+	}
+
+	// 快照加密金鑰（SNAPSHOT_KEY）；未設定時維持明文 JSON
+	var storeOpts []storage.Option
+	if key := os.Getenv("SNAPSHOT_KEY"); key != "" {
+		storeOpts = append(storeOpts, storage.WithKey([]byte(key)))
+	}
+	// 保留較新版本快照中不認得的欄位（SNAPSHOT_PRESERVE_UNKNOWN=1），避免舊版程式覆寫時遺失資料
+	if os.Getenv("SNAPSHOT_PRESERVE_UNKNOWN") == "1" {
+		storeOpts = append(storeOpts, storage.WithPreserveUnknown())
+	}
+	// 輪替備份（SNAPSHOT_BACKUPS=<n>）：每次保存另寫 data-<timestamp>.json，只保留最新 n 份（預設 0，不備份）
+	if n := envInt("SNAPSHOT_BACKUPS", 0); n > 0 {
+		storeOpts = append(storeOpts, storage.WithBackups(int(n)))
+	}
+
+	// 儲存後端：預設為 JSON 快照（cfg.dataFile）；BANK_STORE=sqlite 改用 SQLite（SQLITE_PATH，預設 data.db）。
+	// bank 與 server 只依賴 storage.Store 介面
+	var store storage.Store = storage.NewJSONStore(cfg.dataFile, storeOpts...)
+	if os.Getenv("BANK_STORE") == "sqlite" {
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "data.db"
+		}
+		db, err := storage.OpenSQLite(path)
+		if err != nil {
+			return fmt.Errorf("open sqlite store: %w", err)
+		}
+		defer db.Close()
+		store = db
+	}
+
+	// 嘗試從上次的快照載入資料，若不存在則以空銀行啟動；
+	// 其他錯誤（例如 SNAPSHOT_KEY 錯誤）直接結束，避免以空銀行覆寫既有快照
+	snap, err := store.Load()
+	switch {
+	case err == nil:
 		b.Restore(snap)
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("load snapshot: %w", err)
 	}
 
-	// persist 函式：將當前銀行狀態快照存入 data.json
+	// 確保系統帳戶（ID "0"，利息與手續費的對手帳戶）存在
+	b.EnsureSystemAccount()
+
+	// 交易 journal（BANK_JOURNAL=<path>）：先重播上次快照之後的操作（崩潰復原），
+	// 之後每筆成功的存提款 / 轉帳都追加一行；正常結束時寫入快照並清空
+	var journal *storage.Journal
+	if path := os.Getenv("BANK_JOURNAL"); path != "" {
+		entries, err := storage.ReadJournal(path)
+		if err != nil {
+			return fmt.Errorf("read journal: %w", err)
+		}
+		n, err := b.ReplayJournal(entries)
+		if err != nil {
+			log.Printf("replay journal: %v", err)
+		}
+		if n > 0 {
+			log.Printf("replayed %d journal entries", n)
+		}
+		if journal, err = storage.OpenJournal(path); err != nil {
+			return fmt.Errorf("open journal: %w", err)
+		}
+		defer journal.Close()
+		b.SetJournal(func(e storage.JournalEntry) {
+			if err := journal.Append(e); err != nil {
+				log.Printf("append journal: %v", err)
+			}
+		})
+	}
+
+	// persist 函式：將當前銀行狀態快照交給 store 保存
+	save := storage.SaveFunc(store, b.Snapshot)
 	persist := func() error {
-		return storage.SaveSnapshot(dataFile, b.Snapshot())
+		err := save()
+		if err != nil {
+			log.Printf("save snapshot: %v", err)
+		}
+		return err
+	}
+
+	// 合併寫入：異動只標記 dirty，每 PERSIST_DEBOUNCE_MS 毫秒（預設 200）最多寫入一次；
+	// 設為 0 則恢復每次異動同步寫入
+	persister := storage.NewPersister(persist, time.Duration(envInt("PERSIST_DEBOUNCE_MS", 200))*time.Millisecond)
+
+	// 定期保存（AUTOSAVE_INTERVAL，例如 "30s"、"5m"；預設 30s，0 停用），降低 kill -9 時遺失的變更；
+	// 與合併寫入、結束前保存共用 persister 的鎖，不會同時寫入
+	persister.StartAutoSave(envDuration("AUTOSAVE_INTERVAL", 30*time.Second))
+
+	// 定期計息（BANK_INTEREST_INTERVAL，例如 "24h"；預設 0 不計息）：每個週期對設定利率的帳戶入帳一期利息，
+	// 入帳後交由 persister 保存；關機時先等待排程結束，最後一份快照才會涵蓋最後一期利息
+	interestDone := startInterest(ctx, b, envDuration("BANK_INTEREST_INTERVAL", 0), persister.MarkDirty)
+
+	// 伺服器可選設定：BANK_RECEIPT_KEY 啟用交易收據簽章；
+	// BANK_GZIP_MIN_SIZE 為回應壓縮門檻（bytes，預設 1024，負值停用）；
+	// BANK_MAX_BODY_BYTES 為請求本體上限（bytes，預設 1 MiB）；
+	// BANK_REQUEST_TIMEOUT 為每個請求的時限（Go duration，預設 30s，0 不限制）；
+	// BANK_AMOUNT_DECIMALS > 0 時回應金額改為該位數的小數字串（例如 2 → "12.00"）
+	var opts []server.Option
+	if key := os.Getenv("BANK_RECEIPT_KEY"); key != "" {
+		opts = append(opts, server.WithReceiptKey([]byte(key)))
 	}
+	opts = append(opts, server.WithGzipMinSize(int(envInt("BANK_GZIP_MIN_SIZE", 1024))))
+	opts = append(opts, server.WithMaxBodyBytes(envInt("BANK_MAX_BODY_BYTES", 1<<20)))
+	opts = append(opts, server.WithRequestTimeout(envDuration("BANK_REQUEST_TIMEOUT", 30*time.Second)))
+	opts = append(opts, server.WithDecimalAmounts(int(envInt("BANK_AMOUNT_DECIMALS", 0))))
 
-	// 初始化伺服器並注入 persist 回呼，以便在每次成功變更後自動儲存
-	s := server.NewServer(b, persist)
+	// API Key 與權限範圍（BANK_API_KEYS="k1:read,k2:read+write,k3:admin"）；未設定時不驗證
+	if spec := os.Getenv("BANK_API_KEYS"); spec != "" {
+		keys, err := server.ParseAPIKeys(spec)
+		if err != nil {
+			return fmt.Errorf("load api keys: %w", err)
+		}
+		opts = append(opts, server.WithAPIKeys(keys))
+	}
+	// Bearer token（BANK_AUTH_TOKENS="t1,t2"）：Authorization: Bearer <token>，有效 token 擁有完整權限
+	if spec := os.Getenv("BANK_AUTH_TOKENS"); spec != "" {
+		opts = append(opts, server.WithBearerTokens(server.ParseBearerTokens(spec)))
+	}
 
-	// 啟動背景 goroutine 監聽 SIGINT/SIGTERM 訊號，安全結束前保存狀態
+	// CORS 允許的來源（BANK_CORS_ORIGINS="https://a.example,https://b.example"）；未設定時允許任何來源（*）
+	if spec := os.Getenv("BANK_CORS_ORIGINS"); spec != "" {
+		opts = append(opts, server.WithCORSOrigins(server.ParseCORSOrigins(spec)))
+	}
+
+	// 速率限制：BANK_RATE_LIMIT_RPS 為每個用戶端 IP 每秒平均請求數（0 為不限制），
+	// BANK_RATE_LIMIT_BURST 為可連續發送的上限（預設 20）；BANK_TRUST_PROXY=1 時以 X-Forwarded-For 識別用戶端
+	if rps := envInt("BANK_RATE_LIMIT_RPS", 0); rps > 0 {
+		opts = append(opts, server.WithRateLimit(float64(rps), int(envInt("BANK_RATE_LIMIT_BURST", 20)), os.Getenv("BANK_TRUST_PROXY") == "1"))
+	}
+
+	// 請求日誌：BANK_LOG_SAMPLE 為成功請求取樣比例（每 N 筆記錄 1 筆，預設全記錄），
+	// BANK_LOG_SLOW_MS 為慢請求門檻（毫秒，0 為不啟用）；錯誤回應一律記錄
+	opts = append(opts,
+		server.WithLogger(log.Default()),
+		server.WithLogSampling(int(envInt("BANK_LOG_SAMPLE", 1)), time.Duration(envInt("BANK_LOG_SLOW_MS", 0))*time.Millisecond),
+	)
+
+	// 交易 webhook（BANK_WEBHOOK_URL）：存提款與轉帳成功後非同步 POST 事件，失敗時退避重試
+	if url := os.Getenv("BANK_WEBHOOK_URL"); url != "" {
+		opts = append(opts, server.WithWebhook(url, nil))
+	}
+
+	// 初始化伺服器並注入 persist 回呼：每次成功變更後標記 dirty，由 persister 合併寫入
+	s := server.NewServer(b, persister.MarkDirty, opts...)
+	// 背景保存（合併寫入、定期保存）的結果同樣反映在 /health/ready
+	persister.OnSave(s.RecordPersist)
+
+	ln, err := net.Listen("tcp", cfg.addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	if cfg.onListen != nil {
+		cfg.onListen(ln.Addr())
+	}
+	srv := &http.Server{Handler: s.Router()}
+	srv.RegisterOnShutdown(s.CloseStreams) // Shutdown 不會中斷 SSE 長連線，須主動結束
+
+	// ctx 取消（SIGINT/SIGTERM）時優雅關閉：先停止接受新連線並等待進行中的請求完成
+	// （最多 SHUTDOWN_TIMEOUT，預設 10s），之後才寫入最後一份快照，確保快照涵蓋所有已回應的請求
+	shutdownErr := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), envDuration("SHUTDOWN_TIMEOUT", 10*time.Second))
+		defer cancel()
+		shutdownErr <- server.Shutdown(sctx, srv, func() error {
+			// ctx 已取消，排程不會再開始新的一期；等待進行中的計息完成
+			<-interestDone
+			if err := s.FlushWebhooks(sctx); err != nil { // 送出關機前已提交交易的事件
+				log.Print(err)
+			}
+			_ = persister.MarkDirty() // 結束前一律保存一次（與過去行為一致）
+			err := persister.Close()  // 停止背景寫入並立即補寫
+			if journal != nil && err == nil {
+				_ = journal.Truncate() // 快照已涵蓋所有操作
+			}
+			return err
+		})
+	}()
+
+	log.Printf("Bank server running at %s", ln.Addr())
+	// 服務請求；使用自定義 router 提供所有 API。
+	// Shutdown 後 Serve 立即回傳 ErrServerClosed，須等待關機流程（含保存）完成再返回
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	if err := <-shutdownErr; err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	log.Println("Bank server stopped")
+	return nil
+}
+
+// startInterest 每隔 every 呼叫一次 b.AccrueInterest，有利息入帳時呼叫 onAccrued；every <= 0 時不啟動。
+// 回傳的 channel 於 ctx 取消、排程結束後關閉（未啟動時立即關閉）。
+func startInterest(ctx context.Context, b *bank.Bank, every time.Duration, onAccrued func() error) <-chan struct{} {
+	done := make(chan struct{})
+	if every <= 0 {
+		close(done)
+		return done
+	}
 	go func() {
-		ch := make(chan os.Signal, 1)
-		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
-		<-ch
-		_ = persist()
-		os.Exit(0)
+		defer close(done)
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			txs, err := b.AccrueInterest()
+			if err != nil {
+				log.Printf("accrue interest: %v", err)
+			}
+			if len(txs) > 0 {
+				log.Printf("accrued interest for %d accounts", len(txs))
+				_ = onAccrued()
+			}
+		}
 	}()
+	return done
+}
 
-	log.Println("Bank server running at :8080")
-	// 啟動 HTTP 伺服器；使用自定義 router 提供所有 API
-	log.Fatal(http.ListenAndServe(":8080", s.Router()))
+// envInt 讀取整數型環境變數；未設定或格式錯誤時回傳預設值。
+func envInt(name string, def int64) int64 {
+	v, err := strconv.ParseInt(os.Getenv(name), 10, 64)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envDuration 讀取 time.ParseDuration 格式的環境變數；未設定或格式錯誤時回傳預設值。
+func envDuration(name string, def time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// readLines 讀取文字檔的每一行，略過空行與以 # 開頭的註解行。
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return out, sc.Err()
 }
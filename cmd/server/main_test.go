@@ -0,0 +1,141 @@
+// cmd/server/main_test.go
+//
+// 測試啟動設定：
+//  1. loadConfig 的優先順序為旗標 > 環境變數 > 預設值，空白或無效的值改用下一順位。
+//  2. run 綁定到指定的位址並提供服務，ctx 取消後優雅關閉並寫入快照到指定路徑。
+//  3. startInterest 依週期入帳利息並通知保存，ctx 取消後停止；週期為 0 時不啟動。
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	env := func(m map[string]string) func(string) string {
+		return func(k string) string { return m[k] }
+	}
+	cases := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		addr     string
+		dataFile string
+	}{
+		{"defaults", nil, nil, defaultAddr, defaultDataFile},
+		{"env", nil, map[string]string{"BANK_ADDR": ":9090", "BANK_DATA_FILE": "/tmp/bank.json"}, ":9090", "/tmp/bank.json"},
+		{"flag over env", []string{"-addr", "127.0.0.1:7000", "-data", "flag.json"},
+			map[string]string{"BANK_ADDR": ":9090", "BANK_DATA_FILE": "env.json"}, "127.0.0.1:7000", "flag.json"},
+		{"invalid flag falls back to env", []string{"-addr", "nonsense"}, map[string]string{"BANK_ADDR": ":9090"}, ":9090", defaultDataFile},
+		{"invalid or empty env falls back to default", nil, map[string]string{"BANK_ADDR": ":99999", "BANK_DATA_FILE": "  "}, defaultAddr, defaultDataFile},
+	}
+	for _, tc := range cases {
+		cfg, err := loadConfig(tc.args, env(tc.env))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if cfg.addr != tc.addr || cfg.dataFile != tc.dataFile {
+			t.Fatalf("%s: got addr=%q data=%q, want %q %q", tc.name, cfg.addr, cfg.dataFile, tc.addr, tc.dataFile)
+		}
+	}
+
+	// ❌ 未知旗標 → 錯誤
+	if _, err := loadConfig([]string{"-nope"}, env(nil)); err == nil {
+		t.Fatal("unknown flag: want error")
+	}
+}
+
+func TestRunBindsAndSavesOnShutdown(t *testing.T) {
+	dataFile := filepath.Join(t.TempDir(), "snap.json")
+	addrCh := make(chan net.Addr, 1)
+	cfg := config{addr: "127.0.0.1:0", dataFile: dataFile, onListen: func(a net.Addr) { addrCh <- a }}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- run(ctx, cfg) }()
+
+	var addr net.Addr
+	select {
+	case addr = <-addrCh:
+	case err := <-done:
+		t.Fatalf("run exited early: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not start listening")
+	}
+	if host, _, _ := net.SplitHostPort(addr.String()); host != "127.0.0.1" {
+		t.Fatalf("bound to %s, want 127.0.0.1", addr)
+	}
+
+	// 1️⃣ 綁定的位址可提供服務
+	resp, err := http.Get("http://" + addr.String() + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("health code=%d", resp.StatusCode)
+	}
+
+	// 2️⃣ 取消 ctx：run 正常返回，且快照寫入指定的路徑
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run did not return after cancel")
+	}
+	if _, err := os.Stat(dataFile); err != nil {
+		t.Fatalf("snapshot not written to %s: %v", dataFile, err)
+	}
+}
+
+func TestStartInterest(t *testing.T) {
+	b := bank.NewBank()
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 10_000)
+	if err := b.SetInterestRate(a.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 週期為 0：不啟動，channel 立即關閉
+	select {
+	case <-startInterest(context.Background(), b, 0, nil):
+	case <-time.After(time.Second):
+		t.Fatal("disabled scheduler: channel not closed")
+	}
+
+	// 2️⃣ 每個週期入帳利息並呼叫 onAccrued；取消 ctx 後排程結束
+	var saved atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := startInterest(ctx, b, 5*time.Millisecond, func() error { saved.Add(1); return nil })
+	deadline := time.Now().Add(5 * time.Second)
+	for saved.Load() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("interest not accrued")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler did not stop after cancel")
+	}
+	acc, _ := b.Get(a.ID)
+	if acc.Balance <= 10_100 {
+		t.Fatalf("balance=%d want > 10100 after two periods", acc.Balance)
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
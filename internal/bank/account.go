@@ -3,21 +3,74 @@
 
 package bank
 
-import "time"
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+)
+
+// 帳戶狀態。已關閉的帳戶保留於系統中（可查詢），但拒絕任何資金異動。
+const (
+	StatusActive = "active"
+	StatusClosed = "closed"
+
+	// StatusPendingApproval：啟用開戶審核時，新帳戶須經管理者核准才可異動（見 approval.go）。
+	StatusPendingApproval = "pending_approval"
+)
 
 // Account represents a bank account.
 type Account struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Balance int64  `json:"balance"`
-	Logs    []Log  `json:"-"`
+	ID       string `json:"id"` // 不透明字串；目前由遞增整數產生，但呼叫端不得假設其格式
+	Name     string `json:"name"`
+	Balance  int64  `json:"balance"`
+	Currency string `json:"currency"`       // ISO-4217 幣別代碼，例如 "USD"
+	Status   string `json:"status"`         // 帳戶狀態：StatusActive / StatusClosed / StatusPendingApproval
+	Held     int64  `json:"held,omitempty"` // 待審核轉帳與授權保留的金額（見 hold.go、authhold.go）；可用餘額 = Balance - Held
+
+	ExternalRef string `json:"external_ref,omitempty"` // 整合方自訂的外部參照（如其使用者 ID），全行唯一（見 externalref.go）
+
+	OverdraftLimit  int64 `json:"overdraft_limit,omitempty"`   // 透支額度：餘額最低可至 -OverdraftLimit（見 overdraft.go）
+	MinBalance      int64 `json:"min_balance,omitempty"`       // 最低餘額：扣款後餘額不得低於此值（見 minbalance.go）
+	DailyLimit      int64 `json:"daily_limit,omitempty"`       // 每日提款限額：當日提款與轉出累計不得超過此值（見 dailylimit.go）
+	InterestRateBps int   `json:"interest_rate_bps,omitempty"` // 每個計息週期的利率（基點，1 bp = 0.01%）；0 為不計息（見 interest.go）
+	Frozen          bool  `json:"frozen,omitempty"`            // 帳戶凍結：可查詢，但拒絕一切資金異動（見 accountfreeze.go）
+	Version         int64 `json:"version"`                     // 每次異動遞增的版本號，供 If-Match 條件式異動使用（見 version.go）
+	Logs            []Log `json:"-"`
+
+	mu        *sync.RWMutex              // 帳戶鎖（見 locks.go）；以指標保存，值拷貝時不複製鎖本身
+	opening   int64                      // 期初餘額：開戶餘額加上已丟棄日誌的淨額，供一致性檢查重算餘額（見 verify.go、retention.go）
+	noteBytes int                        // 所有日誌 Note 的累計位元組數（見 notes.go）
+	withdraws []withdrawRecord           // 近期成功提款的 request_id 紀錄（見 dedup.go）
+	dailyUsed int64                      // dailyDay 當日的累計提款與轉出（見 dailylimit.go）
+	dailyDay  string                     // dailyUsed 所屬的 UTC 日期（YYYY-MM-DD）
+	extra     map[string]json.RawMessage // 快照中本版不認得的欄位，原樣寫回（唯讀，可於副本間共用）
 }
 
 // Log represents a transaction record.
+// TxID 在同一筆交易的所有日誌間共用（轉帳的雙邊日誌 TxID 相同）；
+// Type 為交易類型（deposit / withdraw / transfer），與可自由填寫的 Note 分離。
 type Log struct {
+	Seq       int64     `json:"seq"` // 全行遞增的日誌序號，不受時鐘解析度影響的全序（見 seq.go）
 	Time      time.Time `json:"time"`
-	Amount    int64     `json:"amount"`
+	TxID      string    `json:"tx_id,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Amount    int64     `json:"amount"` // 不加 omitempty：金額為 0 的日誌仍輸出 "amount":0
 	Direction string    `json:"direction"`
-	CounterID string    `json:"counter_account"`
-	Note      string    `json:"note"`
+	CounterID string    `json:"counter_account,omitempty"` // 存款、提款等沒有對手帳戶的日誌省略
+	Note      string    `json:"note,omitempty"`
+	Ref       string    `json:"ref_tx_id,omitempty"` // 沖正日誌（Type 為 reversal）所沖正的原 TxID
+}
+
+// available 回傳可動用餘額（扣除審核中與授權保留的金額）。
+func (a *Account) available() int64 {
+	return a.Balance - a.Held
+}
+
+// checkCredit 確認入帳 amt（> 0）後餘額不會超過 math.MaxInt64 而溢位；否則回傳 ErrOverflow。
+func (a *Account) checkCredit(amt int64) error {
+	if a.Balance > math.MaxInt64-amt {
+		return ErrOverflow
+	}
+	return nil
 }
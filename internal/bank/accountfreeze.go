@@ -0,0 +1,28 @@
+// internal/bank/accountfreeze.go
+//
+// 本檔實作單一帳戶的凍結（例如疑似詐欺時的緊急處置）。
+// 凍結的帳戶仍可查詢（Get、日誌、匯出），但存款、提款、轉帳（不論轉出或轉入）、
+// 利息與手續費一律回傳 ErrAccountFrozen，直到解除凍結。
+// 與全行凍結（見 freeze.go）不同，帳戶凍結屬於帳戶資料，會寫入快照，重啟後仍然有效。
+
+package bank
+
+// SetFrozen 凍結（frozen 為 true）或解除凍結帳戶；重複設定相同狀態視為成功（冪等）。
+// 帳戶不存在回傳 ErrNotFound，系統帳戶回傳 ErrSystemAccount，已關閉回傳 ErrClosed。
+func (b *Bank) SetFrozen(id string, frozen bool) error {
+	b.mu.Lock()
+	defer b.unlock()
+	if id == SystemAccountID {
+		return ErrSystemAccount
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if a.Status == StatusClosed {
+		return ErrClosed
+	}
+	a.Frozen = frozen
+	a.bump()
+	return nil
+}
@@ -0,0 +1,93 @@
+// internal/bank/accountfreeze_test.go
+//
+// 測試單一帳戶凍結：凍結帳戶的存款、提款、轉出與轉入皆被拒且不改變狀態，
+// 仍可查詢；其他帳戶不受影響；凍結狀態寫入快照，解除後恢復。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAccountFreeze(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 100)
+	a3, _ := b.Create("C", 100)
+
+	// 1️⃣ 凍結 A：所有資金異動回傳 ErrAccountFrozen
+	if err := b.SetFrozen(a1.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Deposit(a1.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("deposit: want ErrAccountFrozen, got %v", err)
+	}
+	if _, err := b.Withdraw(a1.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("withdraw: want ErrAccountFrozen, got %v", err)
+	}
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(1, "USD")); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("transfer out: want ErrAccountFrozen, got %v", err)
+	}
+	if err := b.Transfer(a2.ID, a1.ID, NewMoney(1, "USD")); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("transfer in: want ErrAccountFrozen, got %v", err)
+	}
+
+	// ✅ 仍可查詢，餘額與日誌未改變；其他帳戶照常
+	got := get(t, b, a1.ID)
+	if !got.Frozen || got.Balance != 100 {
+		t.Fatalf("frozen account=%+v", got)
+	}
+	if logs, _ := b.Logs(a1.ID); len(logs) != 0 {
+		t.Fatalf("logs=%d want 0", len(logs))
+	}
+	if err := b.Transfer(a2.ID, a3.ID, NewMoney(10, "USD")); err != nil {
+		t.Fatalf("unrelated transfer: %v", err)
+	}
+
+	// 2️⃣ 凍結狀態寫入快照
+	restored := NewBank()
+	restored.Restore(b.Snapshot())
+	if _, err := restored.Withdraw(a1.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("restored withdraw: want ErrAccountFrozen, got %v", err)
+	}
+
+	// 3️⃣ 解除凍結後恢復
+	if err := b.SetFrozen(a1.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(a1.ID, NewMoney(30, "USD"), ""); err != nil {
+		t.Fatalf("withdraw after unfreeze: %v", err)
+	}
+	if got := get(t, b, a1.ID); got.Frozen || got.Balance != 70 {
+		t.Fatalf("after unfreeze=%+v", got)
+	}
+
+	// ❌ 不存在的帳戶、系統帳戶
+	if err := b.SetFrozen("999", true); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing: want ErrNotFound, got %v", err)
+	}
+	b.EnsureSystemAccount()
+	if err := b.SetFrozen(SystemAccountID, true); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("system: want ErrSystemAccount, got %v", err)
+	}
+}
+
+// TestFrozenBlocksHeldTransferApproval 保留期間來源帳戶遭凍結時，核准不得過帳，保留維持不變。
+func TestFrozenBlocksHeldTransferApproval(t *testing.T) {
+	b := NewBank()
+	b.SetTransferHold(50, 0)
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	tx, err := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 80})
+	if err != nil || tx.Status != TxPendingReview {
+		t.Fatalf("hold tx=%+v err=%v", tx, err)
+	}
+	_ = b.SetFrozen(a1.ID, true)
+	if _, err := b.ApproveTransfer(tx.ID); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("approve: want ErrAccountFrozen, got %v", err)
+	}
+	if got := get(t, b, a2.ID); got.Balance != 0 {
+		t.Fatalf("destination credited: %d", got.Balance)
+	}
+}
@@ -0,0 +1,48 @@
+// internal/bank/approval.go
+//
+// 本檔實作選用的開戶審核流程（受監理的部署情境）。
+// 啟用後，新開立的帳戶狀態為 StatusPendingApproval：可查詢、可關閉（等同撤件），
+// 但存款、提款、轉帳、利息與手續費一律回傳 ErrPendingApproval，
+// 直到管理者呼叫 ApproveAccount 將其轉為 StatusActive。預設停用，行為與原本相同。
+
+package bank
+
+// SetOpeningApproval 啟用或停用開戶審核；只影響之後開立的帳戶。
+func (b *Bank) SetOpeningApproval(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.approval = enabled
+}
+
+// ApproveAccount 核准待審核帳戶並回傳其快照；已為 active 時視為成功（冪等）。
+// 帳戶不存在回傳 ErrNotFound，已關閉回傳 ErrClosed。
+func (b *Bank) ApproveAccount(id string) (*Account, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if a.Status == StatusClosed {
+		return nil, ErrClosed
+	}
+	a.Status = StatusActive
+	a.bump()
+	cp := *a
+	return &cp, nil
+}
+
+// checkActive 確認帳戶可進行資金異動：已關閉回傳 ErrClosed、待審核回傳 ErrPendingApproval、
+// 已凍結回傳 ErrAccountFrozen（見 accountfreeze.go）。
+func (a *Account) checkActive() error {
+	switch a.Status {
+	case StatusClosed:
+		return ErrClosed
+	case StatusPendingApproval:
+		return ErrPendingApproval
+	}
+	if a.Frozen {
+		return ErrAccountFrozen
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// internal/bank/approval_test.go
+//
+// 測試開戶審核：待審核帳戶拒絕異動、核准後恢復；停用時新帳戶直接為 active。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOpeningApproval(t *testing.T) {
+	b := NewBank()
+	b.SetOpeningApproval(true)
+	a, _ := b.Create("A", 100)
+	other, _ := b.Create("B", 0)
+	if a.Status != StatusPendingApproval {
+		t.Fatalf("status=%q want pending_approval", a.Status)
+	}
+
+	// 1️⃣ 待審核：存款、轉入 / 轉出皆被拒
+	if _, err := b.Deposit(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrPendingApproval) {
+		t.Fatalf("deposit: want ErrPendingApproval, got %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(1, "USD")); !errors.Is(err, ErrPendingApproval) {
+		t.Fatalf("transfer: want ErrPendingApproval, got %v", err)
+	}
+
+	// 2️⃣ 核准後恢復正常
+	if got, err := b.ApproveAccount(a.ID); err != nil || got.Status != StatusActive {
+		t.Fatalf("approve: %+v %v", got, err)
+	}
+	if _, err := b.Deposit(a.ID, NewMoney(1, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ApproveAccount("999"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
+
+func TestOpeningApprovalDisabled(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 0)
+	if a.Status != StatusActive {
+		t.Fatalf("status=%q want active when workflow is off", a.Status)
+	}
+	if _, err := b.Deposit(a.ID, NewMoney(1, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,258 @@
+// internal/bank/authhold.go
+//
+// 本檔實作兩階段的授權保留（預先授權，例如刷卡）：
+//   1. Hold 保留帳戶的資金：可動用餘額（Balance - Held）立即減少，帳面餘額與日誌不變；
+//   2. Capture 請款：實際扣款並寫入一筆 capture 日誌，保留隨之解除；
+//      Release 取消：只解除保留，不產生任何日誌。
+//
+// 與大額轉帳的審核保留（見 hold.go）共用 Account.Held，因此提款、轉帳的餘額檢查自動扣除授權保留。
+// 授權保留寫入快照（重啟後仍然有效）；設定期限後，逾期者於下一次相關操作時自動釋放（惰性到期）。
+// 保留、請款與釋放都寫入 journal（見 journal.go），崩潰重啟後可由快照加 journal 重建；
+// 請款與提款相同受幣別暫停與每日提款限額約束，無法以保留加請款繞過限額。
+
+package bank
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking/internal/storage"
+)
+
+// AuthHold 為一筆授權保留（唯讀檢視，由 GetHold 回傳）。
+type AuthHold struct {
+	ID      string    `json:"hold_id"`
+	Account string    `json:"account"`
+	Amount  int64     `json:"amount"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires,omitzero"` // 零值代表永不到期
+}
+
+// SetAuthHoldTTL 設定之後建立的授權保留的有效期限；ttl <= 0 代表永不到期（預設）。
+func (b *Bank) SetAuthHoldTTL(ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.authTTL = ttl
+}
+
+// journal 中授權保留的項目類型：保留與釋放不是交易（不配發 TxID），重播時以保留 ID 判斷是否已包含在快照中；
+// 請款則是一般的 TxCapture 交易，經由 applyLocked 寫入 journal。
+const (
+	journalHold    = "hold"
+	journalRelease = "release"
+)
+
+// Hold 保留帳戶 id 的 amount 資金並回傳保留 ID（例如 "hold-1"）。
+// 保留期間可動用餘額減少 amount，帳面餘額不變；可動用額度不足回傳 ErrInsufficient，
+// 其餘檢查（全行凍結、帳戶狀態、暫停幣別、每日提款限額）與提款相同。
+// 保留本身不計入當日累計提款，請款時才計入（見 Capture）。
+func (b *Bank) Hold(id string, amount int64) (string, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	h, err := b.placeHold(id, amount)
+	if err != nil {
+		return "", err
+	}
+	b.appendJournal(storage.JournalEntry{Time: h.Created, Type: journalHold, Account: id, Amount: amount, Ref: h.ID})
+	return h.ID, nil
+}
+
+// placeHold 為 Hold 的本體；須持有 mu 寫鎖。
+func (b *Bank) placeHold(id string, amount int64) (*AuthHold, error) {
+	if b.frozen {
+		return nil, ErrFrozen
+	}
+	if amount <= 0 {
+		return nil, ErrBadAmount
+	}
+	if id == SystemAccountID {
+		return nil, ErrSystemAccount
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := a.checkActive(); err != nil {
+		return nil, err
+	}
+	if b.disabledCcy[a.Currency] {
+		return nil, ErrCurrencyDisabled
+	}
+	b.expireHoldsFor(a)
+	if !a.canDebit(amount) {
+		return nil, ErrInsufficient
+	}
+	if err := a.checkDaily(amount, b.now()); err != nil {
+		return nil, err
+	}
+	b.nextHold++
+	h := &AuthHold{ID: fmt.Sprintf("hold-%d", b.nextHold), Account: id, Amount: amount, Created: b.now()}
+	if b.authTTL > 0 {
+		h.Expires = h.Created.Add(b.authTTL)
+	}
+	b.auths[h.ID] = h
+	a.Held += amount
+	a.bump()
+	return h, nil
+}
+
+// GetHold 回傳授權保留的快照；不存在、已請款、已釋放或已逾期回傳 ErrAuthHoldNotFound。
+func (b *Bank) GetHold(holdID string) (AuthHold, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	h, ok := b.auths[holdID]
+	if !ok || h.expired(b.now()) {
+		return AuthHold{}, ErrAuthHoldNotFound
+	}
+	return *h, nil
+}
+
+// Capture 請款：自帳戶扣除保留的金額並寫入 capture 日誌，回傳已提交的交易（同 Apply 的 TxCapture 操作）。
+// 保留不存在、已請款、已釋放或已逾期回傳 ErrAuthHoldNotFound；
+// 帳戶凍結或關閉、幣別暫停、超過每日提款限額時回傳對應錯誤，保留維持不變。
+func (b *Bank) Capture(holdID string) (Tx, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	b.expireHolds()
+	return b.applyLocked(Op{Type: TxCapture, Ref: holdID})
+}
+
+// capture 為請款的本體；須在 mu 保護下呼叫（持有寫鎖，或讀鎖加上保留所屬帳戶的帳戶鎖，見 opAccounts）。
+// 請款金額與提款相同計入當日累計（見 dailylimit.go）。
+func (b *Bank) capture(holdID string) (Tx, error) {
+	h := b.authHold(holdID)
+	if h == nil {
+		return Tx{}, ErrAuthHoldNotFound
+	}
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
+	a, ok := b.accts[h.Account]
+	if !ok {
+		return Tx{}, ErrNotFound
+	}
+	if b.expireHoldsFor(a); b.authHold(holdID) == nil {
+		return Tx{}, ErrAuthHoldNotFound
+	}
+	if err := a.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if b.disabledCcy[a.Currency] {
+		return Tx{}, ErrCurrencyDisabled
+	}
+	if err := a.checkDaily(h.Amount, b.now()); err != nil {
+		return Tx{}, err
+	}
+	note, err := b.fitNote(a, TxCapture)
+	if err != nil {
+		return Tx{}, err
+	}
+	b.txMu.Lock()
+	delete(b.auths, holdID)
+	b.txMu.Unlock()
+	tx := b.newTx(TxCapture)
+	tx.Account, tx.Amount = a.ID, h.Amount
+	a.Held -= h.Amount
+	a.Balance -= h.Amount
+	a.addDaily(h.Amount, tx.Time)
+	b.addLog(a, Log{Time: tx.Time, TxID: tx.ID, Type: TxCapture, Amount: h.Amount, Direction: "out", Note: note})
+	b.indexTx(tx.ID, a.ID)
+	return tx, nil
+}
+
+// authHold 回傳保留 ID 對應的授權保留；不存在時回傳 nil。須在 mu 保護下呼叫。
+func (b *Bank) authHold(holdID string) *AuthHold {
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	return b.auths[holdID]
+}
+
+// Release 取消授權保留，恢復可動用餘額；保留不存在、已請款、已釋放或已逾期回傳 ErrAuthHoldNotFound。
+func (b *Bank) Release(holdID string) error {
+	b.mu.Lock()
+	defer b.unlock()
+	b.expireHolds()
+	h, ok := b.auths[holdID]
+	if !ok {
+		return ErrAuthHoldNotFound
+	}
+	b.releaseAuth(h)
+	b.appendJournal(storage.JournalEntry{Time: b.now(), Type: journalRelease, Ref: holdID})
+	return nil
+}
+
+// replayAuth 重播 journal 中的保留或釋放項目，回報是否實際套用；須持有 mu 寫鎖。
+// 保留 ID 序號不大於目前 nextHold 的保留已包含在快照中；要釋放的保留不存在時代表快照中已釋放或已請款。
+func (b *Bank) replayAuth(e storage.JournalEntry) (bool, error) {
+	switch e.Type {
+	case journalHold:
+		seq := holdSeq(e.Ref)
+		if seq <= b.nextHold {
+			return false, nil
+		}
+		b.nextHold = seq - 1 // 沿用原本的保留 ID
+		_, err := b.placeHold(e.Account, e.Amount)
+		return err == nil, err
+	default: // journalRelease
+		h, ok := b.auths[e.Ref]
+		if !ok {
+			return false, nil
+		}
+		b.releaseAuth(h)
+		return true, nil
+	}
+}
+
+// releaseAuth 移除授權保留並恢復帳戶的可動用餘額；須在 mu 保護下呼叫（持有寫鎖或已鎖定該帳戶並持有 txMu）。
+func (b *Bank) releaseAuth(h *AuthHold) {
+	delete(b.auths, h.ID)
+	if a, ok := b.accts[h.Account]; ok {
+		a.Held -= h.Amount
+		a.bump()
+	}
+}
+
+// expired 回報授權保留在 now 時是否已逾期。
+func (h *AuthHold) expired(now time.Time) bool {
+	return !h.Expires.IsZero() && !now.Before(h.Expires)
+}
+
+// persistHolds 將授權保留轉為快照格式（依 ID 序號排序）；須持有 mu。
+func (b *Bank) persistHolds() []storage.PersistHold {
+	out := make([]storage.PersistHold, 0, len(b.auths))
+	for _, h := range b.auths {
+		out = append(out, storage.PersistHold{ID: h.ID, Account: h.Account, Amount: h.Amount, Created: h.Created, Expires: h.Expires})
+	}
+	slices.SortFunc(out, func(x, y storage.PersistHold) int { return cmp.Compare(holdSeq(x.ID), holdSeq(y.ID)) })
+	return out
+}
+
+// restoreHolds 由快照還原授權保留並計入帳戶的 Held；帳戶不存在的保留略過。須持有 mu 寫鎖。
+func (b *Bank) restoreHolds(s storage.Snapshot) {
+	b.nextHold = s.NextHoldID
+	b.auths = make(map[string]*AuthHold, len(s.Holds))
+	for _, ph := range s.Holds {
+		a, ok := b.accts[ph.Account]
+		if !ok {
+			continue
+		}
+		b.auths[ph.ID] = &AuthHold{ID: ph.ID, Account: ph.Account, Amount: ph.Amount, Created: ph.Created, Expires: ph.Expires}
+		a.Held += ph.Amount
+		b.nextHold = max(b.nextHold, holdSeq(ph.ID))
+	}
+}
+
+// holdSeq 解析 "hold-<n>" 形式的序號；格式不符時回傳 0。
+func holdSeq(id string) int64 {
+	n, err := strconv.ParseInt(strings.TrimPrefix(id, "hold-"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,257 @@
+// internal/bank/authhold_test.go
+//
+// 測試授權保留：保留只減少可動用餘額，請款才改變帳面餘額並寫入日誌；釋放與逾期恢復可動用餘額；
+// 保留隨快照保存與還原。
+
+package bank
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"banking/internal/storage"
+)
+
+// TestAuthHoldCapture 驗證 hold → capture：
+// 1️⃣ 保留後帳面餘額不變、可動用餘額減少、無日誌，超出可動用餘額的提款被拒；
+// 2️⃣ 請款後帳面餘額減少、保留解除，寫入一筆 capture 日誌且 Verify 一致；
+// 3️⃣ 同一保留不可重複請款或釋放。
+func TestAuthHoldCapture(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1000)
+
+	holdID, err := b.Hold(a.ID, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc := get(t, b, a.ID)
+	if acc.Balance != 1000 || acc.Held != 300 || acc.available() != 700 {
+		t.Fatalf("after hold balance=%d held=%d", acc.Balance, acc.Held)
+	}
+	if logs, _ := b.Logs(a.ID); len(logs) != 0 {
+		t.Fatalf("hold must not write logs, got %+v", logs)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(701, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("withdraw over available err=%v want ErrInsufficient", err)
+	}
+	if h, err := b.GetHold(holdID); err != nil || h.Account != a.ID || h.Amount != 300 {
+		t.Fatalf("GetHold=%+v,%v", h, err)
+	}
+
+	tx, err := b.Capture(holdID)
+	if err != nil || tx.Type != TxCapture || tx.Account != a.ID || tx.Amount != 300 {
+		t.Fatalf("capture tx=%+v err=%v", tx, err)
+	}
+	acc = get(t, b, a.ID)
+	if acc.Balance != 700 || acc.Held != 0 {
+		t.Fatalf("after capture balance=%d held=%d want 700/0", acc.Balance, acc.Held)
+	}
+	logs, _ := b.Logs(a.ID)
+	if len(logs) != 1 || logs[0].Type != TxCapture || logs[0].Direction != "out" || logs[0].TxID != tx.ID {
+		t.Fatalf("logs=%+v want one capture", logs)
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	if _, err := b.Capture(holdID); !errors.Is(err, ErrAuthHoldNotFound) {
+		t.Fatalf("second capture err=%v want ErrAuthHoldNotFound", err)
+	}
+	if err := b.Release(holdID); !errors.Is(err, ErrAuthHoldNotFound) {
+		t.Fatalf("release after capture err=%v want ErrAuthHoldNotFound", err)
+	}
+}
+
+// TestAuthHoldRelease 驗證 hold → release：可動用餘額恢復、帳面餘額與日誌皆不變，之後不可請款。
+func TestAuthHoldRelease(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 500)
+
+	holdID, err := b.Hold(a.ID, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Hold(a.ID, 1); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("hold over available err=%v want ErrInsufficient", err)
+	}
+	if err := b.Release(holdID); err != nil {
+		t.Fatal(err)
+	}
+	acc := get(t, b, a.ID)
+	if acc.Balance != 500 || acc.Held != 0 {
+		t.Fatalf("after release balance=%d held=%d want 500/0", acc.Balance, acc.Held)
+	}
+	if logs, _ := b.Logs(a.ID); len(logs) != 0 {
+		t.Fatalf("release must not write logs, got %+v", logs)
+	}
+	if _, err := b.Capture(holdID); !errors.Is(err, ErrAuthHoldNotFound) {
+		t.Fatalf("capture after release err=%v want ErrAuthHoldNotFound", err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(500, "USD"), ""); err != nil {
+		t.Fatalf("withdraw after release: %v", err)
+	}
+}
+
+// TestAuthHoldValidation 驗證保留的參數與帳戶檢查。
+func TestAuthHoldValidation(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 100)
+	cases := []struct {
+		id     string
+		amount int64
+		want   error
+	}{
+		{a.ID, 0, ErrBadAmount},
+		{a.ID, -5, ErrBadAmount},
+		{"999", 10, ErrNotFound},
+		{SystemAccountID, 10, ErrSystemAccount},
+	}
+	for _, c := range cases {
+		if _, err := b.Hold(c.id, c.amount); !errors.Is(err, c.want) {
+			t.Errorf("Hold(%q, %d) err=%v want %v", c.id, c.amount, err, c.want)
+		}
+	}
+	if err := b.SetFrozen(a.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Hold(a.ID, 10); !errors.Is(err, ErrAccountFrozen) {
+		t.Fatalf("hold on frozen account err=%v want ErrAccountFrozen", err)
+	}
+}
+
+// TestAuthHoldExpiry 驗證設定期限後，逾期的保留自動釋放：可動用餘額恢復，且不可再請款。
+func TestAuthHoldExpiry(t *testing.T) {
+	b := NewBank()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b.SetClock(func() time.Time { return now })
+	b.SetAuthHoldTTL(time.Hour)
+	a, _ := b.Create("A", 100)
+
+	holdID, err := b.Hold(a.ID, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h, _ := b.GetHold(holdID); !h.Expires.Equal(now.Add(time.Hour)) {
+		t.Fatalf("expires=%v want %v", h.Expires, now.Add(time.Hour))
+	}
+	now = now.Add(time.Hour)
+	if _, err := b.GetHold(holdID); !errors.Is(err, ErrAuthHoldNotFound) {
+		t.Fatalf("GetHold after expiry err=%v want ErrAuthHoldNotFound", err)
+	}
+	// 提款時惰性釋放逾期保留
+	if _, err := b.Withdraw(a.ID, NewMoney(100, "USD"), ""); err != nil {
+		t.Fatalf("withdraw after expiry: %v", err)
+	}
+	if _, err := b.Capture(holdID); !errors.Is(err, ErrAuthHoldNotFound) {
+		t.Fatalf("capture after expiry err=%v want ErrAuthHoldNotFound", err)
+	}
+}
+
+// TestAuthHoldSnapshot 驗證保留隨快照保存：還原後仍減少可動用餘額、可請款，新保留 ID 不重複。
+func TestAuthHoldSnapshot(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1000)
+	h1, _ := b.Hold(a.ID, 200)
+	h2, _ := b.Hold(a.ID, 300)
+	if err := b.Release(h2); err != nil {
+		t.Fatal(err)
+	}
+
+	snap := b.Snapshot()
+	if len(snap.Holds) != 1 || snap.Holds[0].ID != h1 || snap.NextHoldID != 2 {
+		t.Fatalf("snapshot holds=%+v next=%d", snap.Holds, snap.NextHoldID)
+	}
+	r := NewBank()
+	r.Restore(snap)
+	if acc := get(t, r, a.ID); acc.Held != 200 {
+		t.Fatalf("restored held=%d want 200", acc.Held)
+	}
+	if h3, _ := r.Hold(a.ID, 10); h3 == h1 || h3 == h2 {
+		t.Fatalf("restored hold ID %s reused", h3)
+	}
+	if _, err := r.Capture(h1); err != nil {
+		t.Fatalf("capture restored hold: %v", err)
+	}
+	if acc := get(t, r, a.ID); acc.Balance != 800 || acc.Held != 10 {
+		t.Fatalf("after capture balance=%d held=%d want 800/10", acc.Balance, acc.Held)
+	}
+}
+
+// TestAuthHoldLimits 驗證保留加請款無法繞過提款的限制：
+// 1️⃣ 保留金額超過每日限額即被拒；請款計入當日累計，超過限額的請款被拒且保留維持不變；
+// 2️⃣ 幣別暫停時請款被拒，恢復後可請款。
+func TestAuthHoldLimits(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1000)
+	if err := b.SetDailyLimit(a.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Hold(a.ID, 101); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("hold over daily limit err=%v want ErrDailyLimitExceeded", err)
+	}
+	h1, _ := b.Hold(a.ID, 80)
+	h2, _ := b.Hold(a.ID, 80)
+	if _, err := b.Capture(h1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Capture(h2); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("capture over daily limit err=%v want ErrDailyLimitExceeded", err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(21, "USD"), ""); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("capture must count toward the daily limit, withdraw err=%v", err)
+	}
+	if acc := get(t, b, a.ID); acc.Balance != 920 || acc.Held != 80 {
+		t.Fatalf("balance=%d held=%d want 920/80", acc.Balance, acc.Held)
+	}
+
+	_ = b.SetDailyLimit(a.ID, 0)
+	b.DisableCurrencies(DefaultCurrency)
+	if _, err := b.Capture(h2); !errors.Is(err, ErrCurrencyDisabled) {
+		t.Fatalf("capture in disabled currency err=%v want ErrCurrencyDisabled", err)
+	}
+	b.EnableCurrencies(DefaultCurrency)
+	if _, err := b.Capture(h2); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestAuthHoldJournalReplay 驗證保留、請款與釋放都寫入 journal：由快照還原後重播，狀態與直接執行相同，
+// 且重複重播不會再次套用。
+func TestAuthHoldJournalReplay(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1000)
+	h0, _ := b.Hold(a.ID, 50) // 快照中已存在、之後才請款的保留
+	snap := b.Snapshot()
+	var entries []storage.JournalEntry
+	b.SetJournal(func(e storage.JournalEntry) { entries = append(entries, e) })
+
+	h1, _ := b.Hold(a.ID, 200)
+	h2, _ := b.Hold(a.ID, 300)
+	if _, err := b.Capture(h1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Capture(h0); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Release(h2); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = b.Hold(a.ID, 10)
+	if len(entries) != 6 {
+		t.Fatalf("journal entries=%d want 6: %+v", len(entries), entries)
+	}
+
+	fresh := NewBank()
+	fresh.Restore(snap)
+	if n, err := fresh.ReplayJournal(entries); n != 6 || err != nil {
+		t.Fatalf("replayed=%d err=%v want 6", n, err)
+	}
+	if want, got := b.Snapshot(), fresh.Snapshot(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("replayed state differs:\nwant %+v\ngot  %+v", want, got)
+	}
+	if n, err := fresh.ReplayJournal(entries); n != 0 || err != nil {
+		t.Fatalf("second replay applied=%d err=%v, want 0", n, err)
+	}
+}
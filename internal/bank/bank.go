@@ -1,202 +1,785 @@
 // internal/bank/bank.go
 
 // Package bank 定義核心商業邏輯：帳戶建立、存款、提款、轉帳、查詢與交易日誌。
-// 採用單一互斥鎖 (sync.Mutex) 保障所有狀態變更「原子且序列化」，避免競爭條件。
+// 以帳戶層級的鎖保障每筆異動「原子且序列化」，不同帳戶上的操作可平行執行（見 locks.go）。
 // 金額以 int64 的最小貨幣單位（如分）儲存，避免浮點誤差。
 package bank
 
 import (
 	"banking/internal/storage"
+	"cmp"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
 // Bank 為聚合根 (Aggregate Root)：管理全系統帳戶。
-// - mu：序列化所有讀寫，確保跨帳戶操作（轉帳）原子完成。
-// - nextID：以原子遞增產生帳戶 ID，避免並發碰撞。
+// - mu：帳戶表與營運設定的讀寫鎖；快速路徑持讀鎖再鎖定參與的帳戶，結構性操作持寫鎖（見 locks.go）。
+// - txMu：保護 nextTx、txIndex、pending 與 auths（快速路徑下多個帳戶同時配發交易）。
+// - ids：帳戶 ID 產生器，預設為原子遞增的計數器（見 idgen.go）。
 // - accts：帳戶索引表（ID → *Account），內部所有指標只在臨界區內修改。
+// - noteCap / notePolicy：每帳戶備註總位元組上限與超限策略（見 notes.go）。
+// - denylist：帳戶名稱禁用規則（見 names.go）。
+// - nextTx / txIndex：交易 ID 序號與 TxID → 帳戶 ID 索引（見 tx.go）。
+// - logSeq：日誌序號（見 seq.go）。
+// - disabledCcy：暫停交易的幣別集合（見 currency.go）。
+// - minTransfer / minCash：金額下限設定（見 limits.go）。
+// - frozen：全行凍結旗標（見 freeze.go）。
+// - cow / view / viewMu：copy-on-write 唯讀檢視（見 readview.go）。
+// - holdOver / holdTTL / pending：大額轉帳審核保留（見 hold.go）。
+// - authTTL / nextHold / auths：授權保留（見 authhold.go）。
+// - approval：是否啟用開戶審核（見 approval.go）。
+// - transferFee：每筆轉帳的固定手續費（見 fee.go）。
+// - maxLogs：每帳戶的日誌保留上限（見 retention.go）。
+// - journal：交易提交後的 journal 回呼（見 journal.go）。
+// - subMu / subs / deferLogs：帳戶日誌的訂閱者與 atomic 批次中暫存的通知（見 subscribe.go）。
+// - snapExtra / Account.extra：還原快照時保留的未知欄位（見 storage.WithPreserveUnknown）。
 type Bank struct {
-	mu     sync.Mutex
-	nextID int64
-	accts  map[string]*Account
+	mu      sync.RWMutex
+	ids     IDGen
+	accts   map[string]*Account
+	txMu    sync.Mutex
+	nextTx  int64
+	txIndex map[string][]string
+	logSeq  int64 // 最近一次配發的日誌序號（原子遞增，見 seq.go）
+
+	noteCap    int
+	notePolicy NotePolicy
+	denylist   []nameRule
+
+	disabledCcy map[string]bool
+
+	minTransfer int64 // 最低轉帳金額（見 limits.go）
+	minCash     int64 // 最低存款 / 提款金額
+
+	frozen bool // 全行凍結；為 true 時所有異動回傳 ErrFrozen
+
+	cow    bool                     // 是否啟用唯讀檢視
+	view   atomic.Pointer[readView] // 目前發布的唯讀檢視；未啟用時為 nil
+	viewMu sync.Mutex               // 序列化快速路徑的檢視發布
+
+	holdOver int64                   // 超過此金額的轉帳進入審核；<= 0 為停用
+	holdTTL  time.Duration           // 審核保留的有效期限
+	pending  map[string]*pendingHold // TxID → 待審核轉帳
+
+	authTTL  time.Duration        // 授權保留的有效期限；<= 0 為永不到期（見 authhold.go）
+	nextHold int64                // 最近一次配發的授權保留序號
+	auths    map[string]*AuthHold // 保留 ID → 授權保留
+
+	approval bool // 新帳戶是否須經核准（StatusPendingApproval）才可異動
+
+	transferFee int64 // 每筆轉帳的固定手續費（見 fee.go）；0 為不收取
+
+	maxLogs int // 每帳戶最多保留的日誌筆數（見 retention.go）；0 為不限制
+
+	now func() time.Time // 時鐘；預設 time.Now，測試可透過 SetClock 注入
+
+	journal func(storage.JournalEntry) // 成功提交的操作寫入 journal；nil 為停用
+	epoch   int64                      // 帳本世代：Reset 時遞增，寫入快照與 journal，重播只套用同一世代的項目（見 journal.go）
+
+	subMu     sync.Mutex                       // 保護 subs
+	subs      map[string]map[chan Log]struct{} // 帳戶 ID → 訂閱者
+	deferLogs *[]accountLog                    // atomic 批次進行中時暫存的通知；nil 代表立即送出
+
+	snapExtra map[string]json.RawMessage // 快照頂層中本版不認得的欄位，於 Snapshot 時原樣寫回
 }
 
 // NewBank 建立空白銀行實例（僅就緒的 in-memory 狀態，無外部依賴）。
 func NewBank() *Bank {
-	return &Bank{accts: make(map[string]*Account)}
+	return &Bank{
+		accts:       make(map[string]*Account),
+		txIndex:     make(map[string][]string),
+		disabledCcy: make(map[string]bool),
+		pending:     make(map[string]*pendingHold),
+		auths:       make(map[string]*AuthHold),
+		ids:         new(CounterIDGen),
+		now:         time.Now,
+	}
+}
+
+// SetClock 替換銀行使用的時鐘（交易時間戳、時間窗查詢皆以此為準）。
+// 主要供測試注入固定或可控的時間；傳入 nil 則恢復 time.Now。
+func (b *Bank) SetClock(now func() time.Time) {
+	if now == nil {
+		now = time.Now
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.now = now
 }
 
-// newID 回傳唯一遞增字串 ID。
-// 使用 atomic 避免在高併發下 ID 碰撞；真正寫入 map 仍在 mu 保護下。
-func (b *Bank) newID() string {
-	id := atomic.AddInt64(&b.nextID, 1)
-	return fmt.Sprintf("%d", id)
+// AccountSpec 描述開戶所需的參數；未填欄位採預設值（例如 Currency 預設 DefaultCurrency）。
+type AccountSpec struct {
+	Name        string
+	Balance     int64
+	Currency    string
+	ExternalRef string // 選填；有填寫時須全行唯一，否則回傳 ErrDuplicateRef（見 externalref.go）
 }
 
-// Create 以名稱與初始餘額建立帳戶；初始餘額不得為負。
-// 回傳淺拷貝（非內部指標）避免呼叫端越權修改內部狀態。
+// Create 以名稱與初始餘額建立帳戶（幣別為 DefaultCurrency）；詳見 Open。
 func (b *Bank) Create(name string, balance int64) (*Account, error) {
-	if balance < 0 {
-		return nil, ErrBadAmount
+	return b.Open(AccountSpec{Name: name, Balance: balance})
+}
+
+// Open 依 AccountSpec 建立帳戶；初始餘額不得為負（ErrNegativeBalance），名稱不得命中禁用清單，
+// 幣別須為三個英文字母的 ISO-4217 代碼（空值視為 DefaultCurrency），外部參照不得與既有帳戶重複。
+// 回傳淺拷貝（非內部指標）避免呼叫端越權修改內部狀態。
+func (b *Bank) Open(spec AccountSpec) (*Account, error) {
+	if spec.Balance < 0 {
+		return nil, ErrNegativeBalance
 	}
 	b.mu.Lock()
-	defer b.mu.Unlock()
-	id := b.newID()
-	a := &Account{ID: id, Name: name, Balance: balance}
+	defer b.unlock()
+	if b.frozen {
+		return nil, ErrFrozen
+	}
+	if err := b.checkName(spec.Name); err != nil {
+		return nil, err
+	}
+	if !validCurrency(spec.Currency) {
+		return nil, ErrBadCurrency
+	}
+	if err := b.checkRef(spec.ExternalRef); err != nil {
+		return nil, err
+	}
+	id, err := b.newID()
+	if err != nil {
+		return nil, err
+	}
+	a := &Account{ID: id, Name: spec.Name, Balance: spec.Balance, Currency: normalizeCurrency(spec.Currency), Status: StatusActive,
+		ExternalRef: spec.ExternalRef, opening: spec.Balance, mu: new(sync.RWMutex)}
+	if b.approval {
+		a.Status = StatusPendingApproval
+	}
 	b.accts[id] = a
-	return a, nil
+	cp := *a
+	return &cp, nil
 }
 
 // Get 依 ID 取得帳戶的目前快照；若不存在回傳 ErrNotFound。
 // 回傳的是值拷貝，避免外部直接改寫內部指標。
+// 啟用唯讀檢視（見 readview.go）時不取鎖，直接讀取最近發布的檢視。
 func (b *Bank) Get(id string) (*Account, error) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	if v := b.view.Load(); v != nil {
+		a, ok := v.byID[id]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		cp := *a
+		return &cp, nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	a, ok := b.accts[id]
 	if !ok {
 		return nil, ErrNotFound
 	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	cp := *a
 	return &cp, nil
 }
 
+// GetMany 於單一臨界區內（以讀鎖鎖定所有相關帳戶）批次取得多個帳戶的快照（ID → 帳戶值拷貝）。
+// strict 為 true 時，任一 ID 不存在即回傳 ErrNotFound（錯誤訊息附帶該 ID）；
+// 否則略過不存在的 ID，只回傳找到的帳戶。
+func (b *Bank) GetMany(ids []string, strict bool) (map[string]*Account, error) {
+	var accts map[string]*Account
+	if v := b.view.Load(); v != nil {
+		accts = v.byID
+	} else {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		locked := b.accountsByID(ids)
+		rlockAccounts(locked)
+		defer runlockAccounts(locked)
+		accts = b.accts
+	}
+	out := make(map[string]*Account, len(ids))
+	for _, id := range ids {
+		a, ok := accts[id]
+		if !ok {
+			if strict {
+				return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+			}
+			continue
+		}
+		cp := *a
+		out[id] = &cp
+	}
+	return out, nil
+}
+
 // List 回傳所有帳戶的淺拷貝快照；不暴露內部指標，維持封裝。
+// 結果依 ID 排序（見 lessID），確保每次呼叫順序一致。
+// 期間以讀鎖鎖定所有帳戶，因此結果是一致的時間點（不會看到只扣款未入帳的轉帳），且不阻塞其他查詢。
 func (b *Bank) List() []*Account {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	out := make([]*Account, 0, len(b.accts))
-	for _, a := range b.accts {
+	var sorted []*Account
+	if v := b.view.Load(); v != nil {
+		sorted = v.sorted
+	} else {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		sorted = b.sortedAccounts()
+		rlockAccounts(sorted)
+		defer runlockAccounts(sorted)
+	}
+	out := make([]*Account, 0, len(sorted))
+	for _, a := range sorted {
 		cp := *a
 		out = append(out, &cp)
 	}
 	return out
 }
 
-// Deposit 存款：金額需 > 0；若帳戶不存在回傳 ErrNotFound。
+// ListPage 的排序鍵。
+const (
+	SortByID      = "id"      // 依 ID（見 lessID）
+	SortByName    = "name"    // 依名稱；同名再依 ID
+	SortByBalance = "balance" // 依餘額由小到大；同額再依 ID
+)
+
+// ListPage 依 sortBy（空值視為 SortByID）排序後，回傳自 offset 起最多 limit 筆帳戶（淺拷貝）與帳戶總數。
+// limit <= 0 代表不限筆數；offset 超出範圍時回傳空切片（非 nil）；未知的排序鍵回傳 ErrBadSort。
+// 排序鍵相同時一律再依 ID 排序，因此同一狀態下每次呼叫的分頁結果都相同。
+func (b *Bank) ListPage(limit, offset int, sortBy string) ([]*Account, int, error) {
+	var less func(x, y *Account) bool
+	switch sortBy {
+	case "", SortByID:
+		less = func(x, y *Account) bool { return lessID(x.ID, y.ID) }
+	case SortByName:
+		less = func(x, y *Account) bool {
+			if x.Name != y.Name {
+				return x.Name < y.Name
+			}
+			return lessID(x.ID, y.ID)
+		}
+	case SortByBalance:
+		less = func(x, y *Account) bool {
+			if x.Balance != y.Balance {
+				return x.Balance < y.Balance
+			}
+			return lessID(x.ID, y.ID)
+		}
+	default:
+		return nil, 0, fmt.Errorf("%w: %q", ErrBadSort, sortBy)
+	}
+	all := b.List() // 已依 ID 排序的一致時間點快照
+	sort.SliceStable(all, func(i, j int) bool { return less(all[i], all[j]) })
+	total := len(all)
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	return all[start:end], total, nil
+}
+
+// TotalBalance 回傳全行帳戶（含系統帳戶）的餘額總和，供監控與對帳檢查。
+// 不做匯率換算：不同幣別的餘額直接相加。
+func (b *Bank) TotalBalance() int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	accts := b.sortedAccounts()
+	rlockAccounts(accts)
+	defer runlockAccounts(accts)
+	var total int64
+	for _, a := range accts {
+		total += a.Balance
+	}
+	return total
+}
+
+// AccountCount 回傳帳戶數（含系統帳戶與已關閉帳戶）。
+func (b *Bank) AccountCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.accts)
+}
+
+// sortedAccounts 回傳依 ID 排序的內部帳戶指標；須持有 mu（讀鎖即可，只讀取帳戶表）。
+// map 迭代順序是隨機的，凡是對外輸出（List、Snapshot）都應經過此函式。
+func (b *Bank) sortedAccounts() []*Account {
+	out := make([]*Account, 0, len(b.accts))
+	for _, a := range b.accts {
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool { return lessID(out[i].ID, out[j].ID) })
+	return out
+}
+
+// lessID 定義帳戶 ID 的排序：兩者皆為數字時依數值大小（"2" < "10"），
+// 否則依字典序；數字 ID 一律排在非數字 ID 之前。
+func lessID(a, b string) bool {
+	na, errA := strconv.ParseInt(a, 10, 64)
+	nb, errB := strconv.ParseInt(b, 10, 64)
+	switch {
+	case errA == nil && errB == nil:
+		return na < nb
+	case errA == nil:
+		return true
+	case errB == nil:
+		return false
+	default:
+		return a < b
+	}
+}
+
+// Deposit 存款：金額需 > 0，幣別須與帳戶相同（否則 ErrCurrencyMismatch）；若帳戶不存在回傳 ErrNotFound。
+// note 寫入日誌的 Note（例如 "cash deposit branch 12"），空值沿用預設的 "deposit"。
 // 於臨界區內同時更新餘額與追加日誌，確保兩者一致性。
-func (b *Bank) Deposit(id string, amt int64) (*Account, error) {
+func (b *Bank) Deposit(id string, m Money, note string) (*Account, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.lockAccounts(id)
+	defer b.unlockAccounts(locked)
+	if err := b.checkCurrency(m, id); err != nil {
+		return nil, err
+	}
+	if _, err := b.applyLocked(Op{Type: TxDeposit, Account: id, Amount: m.Amount, Note: note}); err != nil {
+		return nil, err
+	}
+	cp := *b.accts[id]
+	return &cp, nil
+}
+
+// deposit 為存款核心邏輯，回傳已提交的交易；note 為空時採預設備註。須在 mu 保護下呼叫。
+func (b *Bank) deposit(id string, amt int64, note string) (Tx, error) {
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
 	if amt <= 0 {
-		return nil, ErrBadAmount
+		return Tx{}, ErrBadAmount
+	}
+	if err := checkMin(amt, b.minCash); err != nil {
+		return Tx{}, err
 	}
-	b.mu.Lock()
-	defer b.mu.Unlock()
 	a, ok := b.accts[id]
 	if !ok {
-		return nil, ErrNotFound
+		return Tx{}, ErrNotFound
+	}
+	if err := a.checkActive(); err != nil {
+		return Tx{}, err
 	}
+	if b.disabledCcy[a.Currency] {
+		return Tx{}, ErrCurrencyDisabled
+	}
+	if err := a.checkCredit(amt); err != nil {
+		return Tx{}, err
+	}
+	note, err := b.fitNote(a, cmp.Or(note, TxDeposit))
+	if err != nil {
+		return Tx{}, err
+	}
+	tx := b.newTx(TxDeposit)
+	tx.Account, tx.Amount = id, amt
 	a.Balance += amt
-	a.Logs = append(a.Logs, Log{Time: time.Now(), Amount: amt, Direction: "in", Note: "deposit"})
-	cp := *a
-	return &cp, nil
+	b.addLog(a, Log{Time: tx.Time, TxID: tx.ID, Type: TxDeposit, Amount: amt, Direction: "in", Note: note})
+	b.indexTx(tx.ID, id)
+	return tx, nil
 }
 
-// Withdraw 提款：金額需 > 0 且不得超過餘額（維持非負）；不存在則 ErrNotFound。
+// Withdraw 提款：金額需 > 0 且不得超過可用餘額（含透支額度，見 overdraft.go），幣別須與帳戶相同；不存在則 ErrNotFound。
+// note 寫入日誌的 Note（例如 "chargeback"），空值沿用預設的 "withdraw"。
 // 同樣於臨界區內一併更新餘額與日誌，避免部分成功。
-func (b *Bank) Withdraw(id string, amt int64) (*Account, error) {
+func (b *Bank) Withdraw(id string, m Money, note string) (*Account, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.lockAccounts(id)
+	defer b.unlockAccounts(locked)
+	if err := b.checkCurrency(m, id); err != nil {
+		return nil, err
+	}
+	if _, err := b.applyLocked(Op{Type: TxWithdraw, Account: id, Amount: m.Amount, Note: note}); err != nil {
+		return nil, err
+	}
+	cp := *b.accts[id]
+	return &cp, nil
+}
+
+// withdraw 為提款核心邏輯，回傳已提交的交易；note 為空時採預設備註。須在 mu 保護下呼叫。
+func (b *Bank) withdraw(id string, amt int64, note string) (Tx, error) {
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
 	if amt <= 0 {
-		return nil, ErrBadAmount
+		return Tx{}, ErrBadAmount
+	}
+	if err := checkMin(amt, b.minCash); err != nil {
+		return Tx{}, err
+	}
+	if id == SystemAccountID {
+		return Tx{}, ErrSystemAccount
 	}
-	b.mu.Lock()
-	defer b.mu.Unlock()
 	a, ok := b.accts[id]
 	if !ok {
-		return nil, ErrNotFound
+		return Tx{}, ErrNotFound
+	}
+	if err := a.checkActive(); err != nil {
+		return Tx{}, err
 	}
-	if a.Balance < amt {
-		return nil, ErrInsufficient
+	if b.disabledCcy[a.Currency] {
+		return Tx{}, ErrCurrencyDisabled
 	}
+	b.expireHoldsFor(a)
+	if !a.canDebit(amt) {
+		return Tx{}, ErrInsufficient
+	}
+	if err := a.checkDaily(amt, b.now()); err != nil {
+		return Tx{}, err
+	}
+	note, err := b.fitNote(a, cmp.Or(note, TxWithdraw))
+	if err != nil {
+		return Tx{}, err
+	}
+	tx := b.newTx(TxWithdraw)
+	tx.Account, tx.Amount = id, amt
 	a.Balance -= amt
-	a.Logs = append(a.Logs, Log{Time: time.Now(), Amount: amt, Direction: "out", Note: "withdraw"})
-	cp := *a
-	return &cp, nil
+	a.addDaily(amt, tx.Time)
+	b.addLog(a, Log{Time: tx.Time, TxID: tx.ID, Type: TxWithdraw, Amount: amt, Direction: "out", Note: note})
+	b.indexTx(tx.ID, id)
+	return tx, nil
+}
+
+// Transfer 轉帳為「單一臨界區內」的原子操作（依 ID 順序鎖定雙方帳戶，見 locks.go）：
+// 1) 檢核參數與帳戶存在性 → 2) 檢查餘額與備註額度 → 3) 同步扣款與入帳 → 4) 同步雙邊日誌。
+// 任一步驟失敗皆不會改變任何帳戶狀態。m 的幣別須與雙方帳戶相同，否則回傳 ErrCurrencyMismatch。
+func (b *Bank) Transfer(fromID, toID string, m Money) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.lockAccounts(b.opAccounts(Op{Type: TxTransfer, From: fromID, To: toID})...)
+	defer b.unlockAccounts(locked)
+	if err := b.checkCurrency(m, fromID, toID); err != nil {
+		return err
+	}
+	_, err := b.applyLocked(Op{Type: TxTransfer, From: fromID, To: toID, Amount: m.Amount})
+	return err
 }
 
-// Transfer 轉帳為「單一臨界區內」的原子操作：
-// 1) 檢核參數與帳戶存在性 → 2) 檢查餘額 → 3) 同步扣款與入帳 → 4) 同步雙邊日誌。
-// 任一步驟失敗皆不會改變任何帳戶狀態。
-func (b *Bank) Transfer(fromID, toID string, amt int64) error {
+// transfer 為轉帳核心邏輯，回傳已提交的交易；須在 mu 保護下呼叫。
+// 雙邊日誌共用同一個 TxID 與時間戳。
+func (b *Bank) transfer(fromID, toID string, amt int64) (Tx, error) {
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
 	if amt <= 0 {
-		return ErrBadAmount
+		return Tx{}, ErrBadAmount
+	}
+	if err := checkMin(amt, b.minTransfer); err != nil {
+		return Tx{}, err
 	}
 	if fromID == toID {
-		return ErrSameAccount
+		return Tx{}, ErrSameAccount
+	}
+	if fromID == SystemAccountID {
+		return Tx{}, ErrSystemAccount
 	}
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
 	from, ok1 := b.accts[fromID]
 	to, ok2 := b.accts[toID]
 	if !ok1 || !ok2 {
-		return ErrNotFound
+		return Tx{}, ErrNotFound
+	}
+	if err := from.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if err := to.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if from.Currency != to.Currency {
+		return Tx{}, fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, from.Currency, to.Currency)
 	}
-	if from.Balance < amt {
-		return ErrInsufficient
+	if b.disabledCcy[from.Currency] || b.disabledCcy[to.Currency] {
+		return Tx{}, ErrCurrencyDisabled
 	}
+	b.expireHoldsFor(from)
+	total := amt + b.transferFee // 本金 + 手續費
+	if total < amt {
+		return Tx{}, ErrOverflow
+	}
+	if !from.canDebit(total) {
+		return Tx{}, ErrInsufficient
+	}
+	if err := from.checkDaily(amt, b.now()); err != nil {
+		return Tx{}, err
+	}
+	if err := to.checkCredit(amt); err != nil {
+		return Tx{}, err
+	}
+	fromNote, err := b.fitNote(from, "transfer")
+	if err != nil {
+		return Tx{}, err
+	}
+	toNote, err := b.fitNote(to, "transfer")
+	if err != nil {
+		return Tx{}, err
+	}
+	fee, err := b.prepareFee(from)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	tx := b.newTx(TxTransfer)
+	tx.From, tx.To, tx.Amount = fromID, toID, amt
+	from.addDaily(amt, tx.Time)
+	if b.holdOver > 0 && amt > b.holdOver {
+		b.hold(tx, from)
+		tx.Status = TxPendingReview
+		return tx, nil
+	}
+	tx.Fee = fee.amount
+	b.postTransfer(tx, from, to, fromNote, toNote, fee)
+	return tx, nil
+}
 
-	from.Balance -= amt
-	to.Balance += amt
+// postTransfer 實際過帳：扣款、入帳、寫入雙邊日誌並建立索引，最後收取手續費（見 fee.go）；
+// 須在 mu 保護下呼叫。
+func (b *Bank) postTransfer(tx Tx, from, to *Account, fromNote, toNote string, fee feeLeg) {
+	from.Balance -= tx.Amount
+	to.Balance += tx.Amount
+	b.addLog(from, Log{Time: tx.Time, TxID: tx.ID, Type: TxTransfer, Amount: tx.Amount, Direction: "out", CounterID: to.ID, Note: fromNote})
+	b.addLog(to, Log{Time: tx.Time, TxID: tx.ID, Type: TxTransfer, Amount: tx.Amount, Direction: "in", CounterID: from.ID, Note: toNote})
+	b.indexTx(tx.ID, from.ID, to.ID)
+	b.postFee(from, fee, tx.Time)
+}
 
-	now := time.Now()
-	from.Logs = append(from.Logs, Log{Time: now, Amount: amt, Direction: "out", CounterID: toID, Note: "transfer"})
-	to.Logs = append(to.Logs, Log{Time: now, Amount: amt, Direction: "in", CounterID: fromID, Note: "transfer"})
+// Close 關閉帳戶：僅允許餘額為 0 的帳戶關閉（否則 ErrNonZeroBalance），
+// 已關閉則回傳 ErrClosed。關閉與所有資金異動共用 mu，因此兩者必定序列化：
+// 關閉之後到達的存款 / 轉帳一律回傳 ErrClosed，不會部分套用。
+func (b *Bank) Close(id string) (*Account, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return nil, ErrFrozen
+	}
+	if id == SystemAccountID {
+		return nil, ErrSystemAccount
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if a.Status == StatusClosed {
+		return nil, ErrClosed
+	}
+	if a.Balance != 0 {
+		return nil, ErrNonZeroBalance
+	}
+	a.Status = StatusClosed
+	a.bump()
+	cp := *a
+	return &cp, nil
+}
+
+// Delete 將帳戶自系統中移除（含其日誌）。
+// 餘額不為零、或仍有待審核轉帳將轉入此帳戶時回傳 ErrNonZeroBalance；系統帳戶不可刪除。
+// 對手帳戶的日誌保留原樣（counter_account 仍指向已刪除的 ID）。
+func (b *Bank) Delete(id string) error {
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return ErrFrozen
+	}
+	if id == SystemAccountID {
+		return ErrSystemAccount
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	if a.Balance != 0 {
+		return ErrNonZeroBalance
+	}
+	b.expireHolds()
+	for _, p := range b.pending {
+		if p.tx.To == id {
+			return ErrNonZeroBalance
+		}
+	}
+	for _, l := range a.Logs {
+		b.unindexTx(l.TxID, id)
+	}
+	for hid, h := range b.auths { // 帳戶已不存在，其授權保留一併作廢
+		if h.Account == id {
+			delete(b.auths, hid)
+		}
+	}
+	delete(b.accts, id)
 	return nil
 }
 
 // Logs 回傳指定帳戶的交易日誌（值拷貝），避免外部修改內部切片。
 func (b *Bank) Logs(id string) ([]Log, error) {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	b.mu.RLock()
+	defer b.mu.RUnlock()
 	a, ok := b.accts[id]
 	if !ok {
 		return nil, ErrNotFound
 	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	out := make([]Log, len(a.Logs))
 	copy(out, a.Logs)
+	sortBySeq(out)
+	return out, nil
+}
+
+// LogsPage 回傳指定帳戶自 offset 起最多 limit 筆日誌（值拷貝）與日誌總筆數。
+// limit <= 0 代表不限筆數；offset 超出範圍時回傳空切片（非 nil）。
+// 只複製所需區段，避免大量日誌的帳戶每次查詢都拷貝整份日誌。
+func (b *Bank) LogsPage(id string, limit, offset int) ([]Log, int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	total := len(a.Logs)
+	start := min(max(offset, 0), total)
+	end := total
+	if limit > 0 {
+		end = min(start+limit, total)
+	}
+	out := make([]Log, end-start)
+	copy(out, a.Logs[start:end])
+	return out, total, nil
+}
+
+// LogsMulti 於單一臨界區內（以讀鎖鎖定所有相關帳戶）批次取得多個帳戶的日誌（ID → 值拷貝，依 Seq 排序），
+// 各帳戶的日誌屬於同一時間點（不會只看到轉帳的一側）。不存在的 ID 不列入結果，由呼叫端自行判定；
+// 目前不會回傳錯誤，保留 error 供日後限制批次大小等檢查使用。
+func (b *Bank) LogsMulti(ids []string) (map[string][]Log, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.accountsByID(ids)
+	rlockAccounts(locked)
+	defer runlockAccounts(locked)
+	out := make(map[string][]Log, len(locked))
+	for _, a := range locked {
+		logs := slices.Clone(a.Logs)
+		sortBySeq(logs)
+		out[a.ID] = logs
+	}
 	return out, nil
 }
 
 // Snapshot 匯出銀行狀態到可持久化的 storage.Snapshot：
-// - 包含 nextID 與所有帳戶（含日誌）
+// - 包含帳戶 ID 序號與所有帳戶（含日誌），帳戶依 ID 排序，便於比對備份差異
 // - _meta.section 內寫入 storage 類型與版本，便於未來 schema 遷移/換後端存儲。
+//
+// 持鎖期間只做值拷貝（帳戶欄位與日誌切片，見 snapshotCopy）；轉換為儲存格式在解鎖後進行，
+// 大型帳本保存時不會長時間阻擋異動。拷貝與帳本不共用任何可變狀態，之後的異動不影響已取得的快照。
 func (b *Bank) Snapshot() storage.Snapshot {
-	b.mu.Lock()
-	defer b.mu.Unlock()
+	s, accts := b.snapshotCopy()
+	if snapshotCopied != nil {
+		snapshotCopied()
+	}
+	s.Accounts = make([]storage.PersistAccount, 0, len(accts))
+	for i := range accts {
+		a := &accts[i]
+		s.Accounts = append(s.Accounts, storage.PersistAccount{
+			ID: a.ID, Name: a.Name, Balance: a.Balance, Currency: a.Currency, Status: a.Status, ExternalRef: a.ExternalRef,
+			OverdraftLimit: a.OverdraftLimit, MinBalance: a.MinBalance, Frozen: a.Frozen, Version: a.Version,
+			DailyLimit: a.DailyLimit, DailyWithdrawn: a.dailyUsed, DailyWithdrawnOn: a.dailyDay, InterestRateBps: a.InterestRateBps, Logs: toAnySlice(a.Logs), Extra: a.extra,
+			OpeningBalance: &a.opening,
+		})
+	}
+	return s
+}
+
+// snapshotCopied 於 Snapshot 完成持鎖拷貝、釋放鎖之後呼叫；僅供測試觀察保存期間的並行行為。
+var snapshotCopied func()
+
+// snapshotCopy 於持鎖期間取得快照所需的最少資料：快照層級欄位，以及依 ID 排序的帳戶值拷貝
+// （日誌切片另行複製，不與帳本共用底層陣列；extra 為唯讀，可共用）。
+func (b *Bank) snapshotCopy() (storage.Snapshot, []Account) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	accts := b.sortedAccounts()
+	rlockAccounts(accts)
+	defer runlockAccounts(accts)
+	b.txMu.Lock()
+	nextTx := b.nextTx
+	b.txMu.Unlock()
 	s := storage.Snapshot{
 		Meta: storage.Meta{
 			Storage: "json_snapshot",
-			Version: 1,
+			Version: storage.SnapshotVersion,
 			Note:    "Can be replaced by database backend in the future.",
 		},
-		NextID: b.nextID,
+		NextID:   b.idSeq(),
+		NextTxID: nextTx,
+		NextSeq:  atomic.LoadInt64(&b.logSeq),
+		Epoch:    b.epoch,
+		Extra:    b.snapExtra,
+
+		NextHoldID: b.nextHold,
+		Holds:      b.persistHolds(),
 	}
-	for _, a := range b.accts {
-		s.Accounts = append(s.Accounts, storage.PersistAccount{
-			ID: a.ID, Name: a.Name, Balance: a.Balance, Logs: toAnySlice(a.Logs),
-		})
+	out := make([]Account, len(accts))
+	for i, a := range accts {
+		out[i] = *a
+		out[i].Logs = slices.Clone(a.Logs)
+		out[i].mu = nil
 	}
-	return s
+	return s, out
 }
 
-// Restore 由 storage.Snapshot 還原銀行狀態：重建 nextID 與帳戶 map。
-// 為確保未來向後相容，對未知欄位採用 JSON 中介轉換（logs）。
+// Restore 由 storage.Snapshot 還原銀行狀態：重建帳戶 ID 序號（僅計數器策略）、帳戶 map 與交易索引。
+// 為確保未來向後相容，對未知欄位採用 JSON 中介轉換（logs）；
+// 舊版快照的日誌沒有 Type，依當時固定的 Note 推回交易類型。
 func (b *Bank) Restore(s storage.Snapshot) {
 	b.mu.Lock()
-	defer b.mu.Unlock()
-	b.nextID = s.NextID
+	defer b.unlock()
+	b.restoreLocked(s)
+}
+
+// restoreLocked 為 Restore 的本體；須持有 mu 寫鎖。
+func (b *Bank) restoreLocked(s storage.Snapshot) {
+	b.setIDSeq(s.NextID)
+	b.nextTx = s.NextTxID
+	b.logSeq = s.NextSeq
+	b.epoch = s.Epoch
+	b.snapExtra = s.Extra
 	b.accts = make(map[string]*Account)
+	b.txIndex = make(map[string][]string)
+	b.pending = make(map[string]*pendingHold) // 待審核轉帳不寫入快照，還原後視同釋放
 	for _, pa := range s.Accounts {
-		a := &Account{ID: pa.ID, Name: pa.Name, Balance: pa.Balance}
+		a := &Account{ID: pa.ID, Name: pa.Name, Balance: pa.Balance, Currency: normalizeCurrency(pa.Currency), Status: pa.Status, ExternalRef: pa.ExternalRef,
+			OverdraftLimit: pa.OverdraftLimit, MinBalance: pa.MinBalance, Frozen: pa.Frozen, Version: pa.Version,
+			DailyLimit: pa.DailyLimit, dailyUsed: pa.DailyWithdrawn, dailyDay: pa.DailyWithdrawnOn, InterestRateBps: pa.InterestRateBps, extra: pa.Extra, mu: new(sync.RWMutex)}
+		if a.Status == "" {
+			a.Status = StatusActive
+		}
 		for _, l := range pa.Logs {
 			var log Log
 			j, _ := json.Marshal(l)
 			_ = json.Unmarshal(j, &log)
-			a.Logs = append(a.Logs, log)
+			if log.Type == "" {
+				log.Type = log.Note
+			}
+			appendLog(a, log)
+			if log.TxID != "" {
+				b.indexTx(log.TxID, a.ID)
+				b.nextTx = max(b.nextTx, txSeq(log.TxID))
+			}
+		}
+		if pa.OpeningBalance != nil {
+			a.opening = *pa.OpeningBalance
+		} else { // 舊版快照：以目前餘額反推（見 verify.go）
+			a.opening = a.Balance - logsNet(a.Logs)
 		}
+		trimLogs(a, b.maxLogs)
 		b.accts[a.ID] = a
 	}
+	b.restoreHolds(s)
+	b.restoreSeqs()
 }
 
 // toAnySlice 將型別化切片轉為 []any，供快照序列化使用。
@@ -7,9 +7,15 @@
 package bank
 
 import (
+	"encoding/json"
 	"errors"
+	"math"
+	"strings"
 	"sync"
 	"testing"
+	"time"
+
+	"banking/internal/storage"
 )
 
 // get 為小工具：安全取出帳戶狀態。
@@ -51,12 +57,20 @@ func TestCreateAndListGet(t *testing.T) {
 	}
 }
 
-// TestCreateNegativeBalance 驗證建立帳戶時不得為負餘額。
+// TestCreateNegativeBalance 驗證建立帳戶時不得為負餘額（零可以）。
 // 對應題目：「Account balance cannot be negative」
+// 開戶的負餘額（ErrNegativeBalance）與異動的非法金額（ErrBadAmount）是不同的錯誤。
 func TestCreateNegativeBalance(t *testing.T) {
 	b := NewBank()
-	if _, err := b.Create("A", -1); !errors.Is(err, ErrBadAmount) {
-		t.Fatalf("want ErrBadAmount, got %v", err)
+	if _, err := b.Create("A", -1); !errors.Is(err, ErrNegativeBalance) || errors.Is(err, ErrBadAmount) {
+		t.Fatalf("want ErrNegativeBalance, got %v", err)
+	}
+	a, err := b.Create("B", 0)
+	if err != nil {
+		t.Fatalf("zero initial balance: %v", err)
+	}
+	if _, err := b.Deposit(a.ID, NewMoney(0, "USD"), ""); !errors.Is(err, ErrBadAmount) || errors.Is(err, ErrNegativeBalance) {
+		t.Fatalf("zero deposit want ErrBadAmount, got %v", err)
 	}
 }
 
@@ -67,10 +81,10 @@ func TestDepositWithdraw(t *testing.T) {
 	a, _ := b.Create("A", 100)
 
 	// ✅ 正常存提款
-	if _, err := b.Deposit(a.ID, 50); err != nil {
+	if _, err := b.Deposit(a.ID, NewMoney(50, "USD"), ""); err != nil {
 		t.Fatal(err)
 	}
-	if _, err := b.Withdraw(a.ID, 30); err != nil {
+	if _, err := b.Withdraw(a.ID, NewMoney(30, "USD"), ""); err != nil {
 		t.Fatal(err)
 	}
 	if bal := get(t, b, a.ID).Balance; bal != 120 {
@@ -78,15 +92,15 @@ func TestDepositWithdraw(t *testing.T) {
 	}
 
 	// ❌ 錯誤金額：0 或負數
-	if _, err := b.Deposit(a.ID, 0); !errors.Is(err, ErrBadAmount) {
+	if _, err := b.Deposit(a.ID, NewMoney(0, "USD"), ""); !errors.Is(err, ErrBadAmount) {
 		t.Fatalf("expect ErrBadAmount, got %v", err)
 	}
-	if _, err := b.Withdraw(a.ID, -1); !errors.Is(err, ErrBadAmount) {
+	if _, err := b.Withdraw(a.ID, NewMoney(-1, "USD"), ""); !errors.Is(err, ErrBadAmount) {
 		t.Fatalf("expect ErrBadAmount, got %v", err)
 	}
 
 	// ❌ 餘額不足
-	if _, err := b.Withdraw(a.ID, 9999); !errors.Is(err, ErrInsufficient) {
+	if _, err := b.Withdraw(a.ID, NewMoney(9999, "USD"), ""); !errors.Is(err, ErrInsufficient) {
 		t.Fatalf("expect ErrInsufficient, got %v", err)
 	}
 }
@@ -99,7 +113,7 @@ func TestTransfer(t *testing.T) {
 	a2, _ := b.Create("B", 500)
 
 	// ✅ 正常轉帳
-	if err := b.Transfer(a1.ID, a2.ID, 300); err != nil {
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(300, "USD")); err != nil {
 		t.Fatal(err)
 	}
 	if got := get(t, b, a1.ID).Balance; got != 700 {
@@ -110,12 +124,12 @@ func TestTransfer(t *testing.T) {
 	}
 
 	// ❌ 相同帳戶不得轉帳
-	if err := b.Transfer(a1.ID, a1.ID, 1); !errors.Is(err, ErrSameAccount) {
+	if err := b.Transfer(a1.ID, a1.ID, NewMoney(1, "USD")); !errors.Is(err, ErrSameAccount) {
 		t.Fatalf("expect ErrSameAccount, got %v", err)
 	}
 
 	// ❌ 餘額不足
-	if err := b.Transfer(a1.ID, a2.ID, 99999); !errors.Is(err, ErrInsufficient) {
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(99999, "USD")); !errors.Is(err, ErrInsufficient) {
 		t.Fatalf("expect ErrInsufficient, got %v", err)
 	}
 }
@@ -127,7 +141,7 @@ func TestTransferBadAmount(t *testing.T) {
 	a2, _ := b.Create("B", 100)
 
 	for _, amt := range []int64{0, -5} {
-		if err := b.Transfer(a1.ID, a2.ID, amt); !errors.Is(err, ErrBadAmount) {
+		if err := b.Transfer(a1.ID, a2.ID, NewMoney(amt, "USD")); !errors.Is(err, ErrBadAmount) {
 			t.Fatalf("amt=%d want ErrBadAmount, got %v", amt, err)
 		}
 	}
@@ -149,7 +163,7 @@ func TestConcurrentTransfersAtomicity(t *testing.T) {
 	for i := 0; i < n; i++ {
 		go func() {
 			defer wg.Done()
-			if err := b.Transfer(a1.ID, a2.ID, 1); err != nil {
+			if err := b.Transfer(a1.ID, a2.ID, NewMoney(1, "USD")); err != nil {
 				t.Errorf("A->B: %v", err)
 			}
 		}()
@@ -158,7 +172,7 @@ func TestConcurrentTransfersAtomicity(t *testing.T) {
 	for i := 0; i < n; i++ {
 		go func() {
 			defer wg.Done()
-			if err := b.Transfer(a2.ID, a1.ID, 1); err != nil {
+			if err := b.Transfer(a2.ID, a1.ID, NewMoney(1, "USD")); err != nil {
 				t.Errorf("B->A: %v", err)
 			}
 		}()
@@ -178,6 +192,105 @@ func TestConcurrentTransfersAtomicity(t *testing.T) {
 	}
 }
 
+// TestHighContentionTransfers 以大量 goroutine 在少數帳戶間交叉轉帳（含反向），
+// 驗證高度競爭下所有轉帳最終都能完成、無死結且資金守恆。
+// 轉帳在 mu 讀鎖下依帳戶 ID 排序取得雙方的帳戶鎖（見 locks.go），反向轉帳也以相同順序上鎖，
+// 因此不會死結，也不需要重試或 ErrBusy；不相交的轉帳可並行執行。
+func TestHighContentionTransfers(t *testing.T) {
+	b := NewBank()
+	const accounts, workers, rounds = 6, 64, 300
+	ids := make([]string, accounts)
+	for i := range ids {
+		a, _ := b.Create("acct", 1_000_000)
+		ids[i] = a.ID
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < rounds; i++ {
+					from, to := ids[(w+i)%accounts], ids[(w+2*i+1)%accounts]
+					if from == to {
+						continue
+					}
+					if err := b.Transfer(from, to, NewMoney(1, "USD")); err != nil {
+						t.Errorf("transfer %s->%s: %v", from, to, err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatal("transfers did not finish: possible deadlock")
+	}
+
+	var total int64
+	for _, a := range b.List() {
+		if a.Balance < 0 {
+			t.Fatalf("negative balance: %+v", a)
+		}
+		total += a.Balance
+	}
+	if total != accounts*1_000_000 {
+		t.Fatalf("total=%d want %d", total, accounts*1_000_000)
+	}
+}
+
+// TestTotals 驗證 TotalBalance 與 AccountCount 反映所有帳戶，且轉帳不改變總額。
+func TestTotals(t *testing.T) {
+	b := NewBank()
+	if b.TotalBalance() != 0 || b.AccountCount() != 0 {
+		t.Fatal("empty bank should have zero totals")
+	}
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 250)
+	_, _ = b.Create("C", 0)
+	_ = b.Transfer(a2.ID, a1.ID, NewMoney(50, "USD"))
+	_, _ = b.Deposit(a1.ID, NewMoney(25, "USD"), "")
+
+	if got := b.TotalBalance(); got != 375 {
+		t.Fatalf("total=%d want 375", got)
+	}
+	if got := b.AccountCount(); got != 3 {
+		t.Fatalf("count=%d want 3", got)
+	}
+}
+
+// TestDepositTransferOverflow 驗證入帳後超出 int64 範圍的存款與轉帳被拒（ErrOverflow），
+// 且雙方餘額與日誌皆不變。
+func TestDepositTransferOverflow(t *testing.T) {
+	b := NewBank()
+	rich, _ := b.Create("Rich", math.MaxInt64-10)
+	other, _ := b.Create("Other", 100)
+
+	if _, err := b.Deposit(rich.ID, NewMoney(11, "USD"), ""); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("deposit want ErrOverflow, got %v", err)
+	}
+	if err := b.Transfer(other.ID, rich.ID, NewMoney(11, "USD")); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("transfer want ErrOverflow, got %v", err)
+	}
+	if got := get(t, b, rich.ID); got.Balance != math.MaxInt64-10 || len(got.Logs) != 0 {
+		t.Fatalf("rich changed: balance=%d logs=%d", got.Balance, len(got.Logs))
+	}
+	if got := get(t, b, other.ID).Balance; got != 100 {
+		t.Fatalf("sender balance=%d want 100", got)
+	}
+
+	// 恰好到達上限仍允許
+	if _, err := b.Deposit(rich.ID, NewMoney(10, "USD"), ""); err != nil {
+		t.Fatalf("deposit up to MaxInt64: %v", err)
+	}
+}
+
 // TestLogs 驗證每筆操作都會生成正確的交易日誌。
 // 對應題目：「Generate transaction logs for each account transfer」
 func TestLogs(t *testing.T) {
@@ -186,9 +299,9 @@ func TestLogs(t *testing.T) {
 	a2, _ := b.Create("B", 0)
 
 	// 模擬存、提、轉帳
-	_, _ = b.Deposit(a2.ID, 200)
-	_, _ = b.Withdraw(a2.ID, 50)
-	_ = b.Transfer(a1.ID, a2.ID, 300)
+	_, _ = b.Deposit(a2.ID, NewMoney(200, "USD"), "")
+	_, _ = b.Withdraw(a2.ID, NewMoney(50, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(300, "USD"))
 
 	logs1, err := b.Logs(a1.ID)
 	if err != nil {
@@ -239,7 +352,7 @@ func TestConcurrentDepositsRaceSafety(t *testing.T) {
 	for i := 0; i < workers; i++ {
 		go func() {
 			defer wg.Done()
-			if _, err := b.Deposit(a.ID, amt); err != nil {
+			if _, err := b.Deposit(a.ID, NewMoney(amt, "USD"), ""); err != nil {
 				t.Errorf("deposit err: %v", err)
 			}
 		}()
@@ -258,9 +371,9 @@ func TestSnapshotRestore(t *testing.T) {
 	b := NewBank()
 	a1, _ := b.Create("A", 1000)
 	a2, _ := b.Create("B", 500)
-	_, _ = b.Deposit(a1.ID, 200)
-	_, _ = b.Withdraw(a2.ID, 100)
-	_ = b.Transfer(a1.ID, a2.ID, 800)
+	_, _ = b.Deposit(a1.ID, NewMoney(200, "USD"), "")
+	_, _ = b.Withdraw(a2.ID, NewMoney(100, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(800, "USD"))
 
 	snap := b.Snapshot()
 
@@ -288,3 +401,231 @@ func TestSnapshotRestore(t *testing.T) {
 		t.Fatalf("logs count mismatch a2: %d vs %d", len(l2), len(l2r))
 	}
 }
+
+// TestSnapshotKeepsUnknownFields 驗證快照中的未知欄位經 Restore → 異動 → Snapshot 後仍被帶出。
+func TestSnapshotKeepsUnknownFields(t *testing.T) {
+	snap := storage.Snapshot{
+		NextID:   2,
+		Accounts: []storage.PersistAccount{{ID: "1", Name: "A", Balance: 100, Extra: map[string]json.RawMessage{"kyc_level": json.RawMessage("3")}}},
+		Extra:    map[string]json.RawMessage{"retention_policy": json.RawMessage(`{"days":90}`)},
+	}
+	b := NewBank()
+	b.Restore(snap)
+	if _, err := b.Deposit("1", NewMoney(5, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	out := b.Snapshot()
+	if string(out.Extra["retention_policy"]) != `{"days":90}` {
+		t.Fatalf("top-level extra lost: %v", out.Extra)
+	}
+	if len(out.Accounts) != 1 || out.Accounts[0].Balance != 105 || string(out.Accounts[0].Extra["kyc_level"]) != "3" {
+		t.Fatalf("account extra lost: %+v", out.Accounts)
+	}
+}
+
+// TestDeterministicOrdering 驗證 List 與 Snapshot 的帳戶順序固定且依 ID 排序。
+// 建立超過 10 個帳戶，確保數字 ID 依數值而非字典序排列（"2" < "10"）。
+func TestDeterministicOrdering(t *testing.T) {
+	b := NewBank()
+	for i := 0; i < 12; i++ {
+		_, _ = b.Create("A", int64(i))
+	}
+
+	first, second := b.List(), b.List()
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Fatalf("List order differs at %d: %s vs %s", i, first[i].ID, second[i].ID)
+		}
+		if i > 0 && !lessID(first[i-1].ID, first[i].ID) {
+			t.Fatalf("List not sorted: %s before %s", first[i-1].ID, first[i].ID)
+		}
+	}
+	if first[1].ID != "2" || first[9].ID != "10" {
+		t.Fatalf("numeric ordering broken: [1]=%s [9]=%s", first[1].ID, first[9].ID)
+	}
+
+	snap := b.Snapshot()
+	for i := 1; i < len(snap.Accounts); i++ {
+		if !lessID(snap.Accounts[i-1].ID, snap.Accounts[i].ID) {
+			t.Fatalf("Snapshot not sorted: %s before %s", snap.Accounts[i-1].ID, snap.Accounts[i].ID)
+		}
+	}
+}
+
+// TestTxIDAndFindTx 驗證每筆交易取得唯一 TxID，轉帳雙邊日誌共用同一 TxID，
+// 且 FindTx 在快照還原後仍能查回交易。
+func TestTxIDAndFindTx(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+
+	dep, err := b.Apply(Op{Type: TxDeposit, Account: a1.ID, Amount: 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr, err := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dep.ID == "" || dep.ID == tr.ID {
+		t.Fatalf("tx ids should be unique: %q %q", dep.ID, tr.ID)
+	}
+	l1, _ := b.Logs(a1.ID)
+	l2, _ := b.Logs(a2.ID)
+	if l1[1].TxID != tr.ID || l2[0].TxID != tr.ID || l1[1].Type != TxTransfer {
+		t.Fatalf("transfer legs should share tx id: %+v %+v", l1[1], l2[0])
+	}
+	if _, err := b.Apply(Op{Type: "bogus"}); !errors.Is(err, ErrBadOp) {
+		t.Fatalf("want ErrBadOp, got %v", err)
+	}
+
+	b2 := NewBank()
+	b2.Restore(b.Snapshot())
+	got, err := b2.FindTx(tr.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.From != a1.ID || got.To != a2.ID || got.Amount != 30 || got.Type != TxTransfer {
+		t.Fatalf("FindTx=%+v", got)
+	}
+	// 還原後的新交易不得與既有 TxID 碰撞
+	next, _ := b2.Apply(Op{Type: TxDeposit, Account: a2.ID, Amount: 1})
+	if next.ID == tr.ID || next.ID == dep.ID {
+		t.Fatalf("tx id reused after restore: %s", next.ID)
+	}
+	if _, err := b2.FindTx("tx-999"); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("want ErrTxNotFound, got %v", err)
+	}
+}
+
+// TestGetMany 驗證批次查詢：寬鬆模式略過不存在的 ID，嚴格模式回傳 ErrNotFound。
+func TestGetMany(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 200)
+
+	got, err := b.GetMany([]string{a1.ID, a2.ID, "999"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[a1.ID].Balance != 100 || got[a2.ID].Balance != 200 {
+		t.Fatalf("lenient result=%v", got)
+	}
+	if _, err := b.GetMany([]string{a1.ID, "999"}, true); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("strict want ErrNotFound, got %v", err)
+	}
+}
+
+// TestLogsPage 驗證日誌分頁：第一頁、中間頁、超出範圍與不限筆數。
+func TestLogsPage(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 0)
+	for i := 1; i <= 5; i++ {
+		_, _ = b.Deposit(a.ID, NewMoney(int64(i), "USD"), "")
+	}
+
+	cases := []struct {
+		limit, offset int
+		want          []int64
+	}{
+		{2, 0, []int64{1, 2}},       // 第一頁
+		{2, 2, []int64{3, 4}},       // 中間頁
+		{2, 4, []int64{5}},          // 最後一頁不足 limit
+		{2, 9, []int64{}},           // 超出範圍
+		{0, 1, []int64{2, 3, 4, 5}}, // 不限筆數
+	}
+	for _, c := range cases {
+		logs, total, err := b.LogsPage(a.ID, c.limit, c.offset)
+		if err != nil || total != 5 || logs == nil || len(logs) != len(c.want) {
+			t.Fatalf("LogsPage(%d,%d)=%v total=%d err=%v", c.limit, c.offset, logs, total, err)
+		}
+		for i, l := range logs {
+			if l.Amount != c.want[i] {
+				t.Fatalf("LogsPage(%d,%d)[%d]=%d want %d", c.limit, c.offset, i, l.Amount, c.want[i])
+			}
+		}
+	}
+	if _, _, err := b.LogsPage("999", 1, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
+
+// TestListPage 驗證帳戶列表分頁：依 id / name / balance 排序（同值再依 ID）、分頁切片與不合法排序鍵。
+func TestListPage(t *testing.T) {
+	b := NewBank()
+	for _, s := range []struct {
+		name    string
+		balance int64
+	}{{"carol", 300}, {"alice", 100}, {"bob", 300}, {"alice", 50}, {"dave", 0}} {
+		_, _ = b.Create(s.name, s.balance)
+	}
+
+	cases := []struct {
+		sortBy        string
+		limit, offset int
+		want          []string // 帳戶 ID
+	}{
+		{"", 2, 0, []string{"1", "2"}},  // 預設依 ID
+		{SortByID, 2, 4, []string{"5"}}, // 最後一頁不足 limit
+		{SortByName, 0, 0, []string{"2", "4", "3", "1", "5"}},
+		{SortByName, 2, 1, []string{"4", "3"}}, // 中間頁
+		{SortByBalance, 0, 0, []string{"5", "4", "2", "1", "3"}},
+		{SortByBalance, 2, 9, []string{}}, // 超出範圍
+	}
+	for _, c := range cases {
+		// 重複呼叫結果須一致（與 map 迭代順序無關）
+		for range 3 {
+			page, total, err := b.ListPage(c.limit, c.offset, c.sortBy)
+			if err != nil || total != 5 || page == nil || len(page) != len(c.want) {
+				t.Fatalf("ListPage(%d,%d,%q)=%v total=%d err=%v", c.limit, c.offset, c.sortBy, page, total, err)
+			}
+			for i, a := range page {
+				if a.ID != c.want[i] {
+					t.Fatalf("ListPage(%d,%d,%q)[%d]=%s want %s", c.limit, c.offset, c.sortBy, i, a.ID, c.want[i])
+				}
+			}
+		}
+	}
+	if _, _, err := b.ListPage(10, 0, "created"); !errors.Is(err, ErrBadSort) {
+		t.Fatalf("want ErrBadSort, got %v", err)
+	}
+}
+
+// TestLogsMulti 驗證批次取得日誌：回傳各帳戶依 Seq 排序的值拷貝，不存在的 ID 不列入結果。
+func TestLogsMulti(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(30, "USD"))
+	got, err := b.LogsMulti([]string{a1.ID, a2.ID, "999", a1.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got[a1.ID]) != 1 || len(got[a2.ID]) != 1 {
+		t.Fatalf("logs=%+v", got)
+	}
+	if _, ok := got["999"]; ok {
+		t.Fatal("missing id should not appear in the result")
+	}
+	got[a1.ID][0].Amount = 999
+	if l, _ := b.Logs(a1.ID); l[0].Amount != 30 {
+		t.Fatalf("LogsMulti returned a shared slice: amount=%d", l[0].Amount)
+	}
+}
+
+// TestLogJSON 驗證 Log 的 JSON：空的 counter_account 與 note 省略，金額為 0 時仍輸出 amount。
+func TestLogJSON(t *testing.T) {
+	out, _ := json.Marshal(Log{Seq: 1, Type: TxDeposit, Direction: "in"})
+	s := string(out)
+	if strings.Contains(s, "counter_account") || strings.Contains(s, `"note"`) {
+		t.Fatalf("empty fields not omitted: %s", s)
+	}
+	if !strings.Contains(s, `"amount":0`) {
+		t.Fatalf("zero amount omitted: %s", s)
+	}
+	out, _ = json.Marshal(Log{Seq: 2, Type: TxTransfer, Amount: 5, Direction: "out", CounterID: "2", Note: "rent"})
+	if s := string(out); !strings.Contains(s, `"counter_account":"2"`) || !strings.Contains(s, `"note":"rent"`) {
+		t.Fatalf("transfer log=%s", s)
+	}
+}
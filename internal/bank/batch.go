@@ -0,0 +1,84 @@
+// internal/bank/batch.go
+//
+// 本檔實作批次操作（ApplyBatch），整批於單一臨界區內執行，支援兩種模式：
+//   - atomic（全有或全無）：任一筆失敗即回滾整批，帳戶狀態與交易序號皆恢復原狀。
+//   - partial（部分成功）：每筆獨立套用，失敗者略過並回報錯誤，成功者照常提交。
+//
+// 不論哪種模式，每一筆本身都是原子的（例如轉帳的雙邊永遠同時成立或同時不成立）。
+
+package bank
+
+import (
+	"fmt"
+	"maps"
+)
+
+// BatchResult 為批次中單筆操作的結果；Err 為 nil 代表成功並已提交。
+type BatchResult struct {
+	Tx  Tx
+	Err error
+}
+
+// ApplyBatch 依序套用 ops。
+// atomic 為 true 時任一筆失敗即回滾整批，回傳 nil 與標示失敗序號的錯誤（可以 errors.Is 判斷原因）；
+// 否則逐筆套用，回傳與 ops 等長的結果，error 恆為 nil。
+func (b *Bank) ApplyBatch(ops []Op, atomic bool) ([]BatchResult, error) {
+	b.mu.Lock()
+	defer b.unlock()
+
+	results := make([]BatchResult, len(ops))
+	if !atomic {
+		for i, op := range ops {
+			results[i].Tx, results[i].Err = b.applyLocked(op)
+		}
+		return results, nil
+	}
+
+	// atomic：先保存所有相關帳戶、交易序號、日誌序號與授權保留，失敗時還原
+	saved := make(map[string]Account)
+	for _, op := range ops {
+		if op.Type == TxCapture {
+			if h := b.auths[op.Ref]; h != nil {
+				op.Account = h.Account // 請款異動保留所屬的帳戶
+			}
+		}
+		for _, id := range []string{op.Account, op.From, op.To, SystemAccountID} {
+			if a, ok := b.accts[id]; ok {
+				if _, done := saved[id]; !done {
+					saved[id] = *a
+				}
+			}
+		}
+	}
+	nextTx, logSeq, auths := b.nextTx, b.logSeq, maps.Clone(b.auths)
+	var deferred []accountLog // 日誌通知待整批提交後才送出（見 subscribe.go）
+	b.deferLogs = &deferred
+	defer func() { b.deferLogs = nil }()
+	fresh := make([]bool, len(ops))
+	for i, op := range ops {
+		tx, ok, err := b.dispatch(op) // journal 待整批提交後才寫入
+		if err != nil {
+			for id, a := range saved {
+				*b.accts[id] = a
+			}
+			for _, r := range results[:i] {
+				delete(b.pending, r.Tx.ID)
+			}
+			for seq := nextTx + 1; seq <= b.nextTx; seq++ { // 含手續費等附帶交易
+				delete(b.txIndex, fmt.Sprintf("tx-%d", seq))
+			}
+			b.nextTx, b.logSeq, b.auths = nextTx, logSeq, auths
+			return nil, fmt.Errorf("op %d: %w", i, err)
+		}
+		results[i].Tx, fresh[i] = tx, ok
+	}
+	for i, op := range ops {
+		if fresh[i] {
+			b.record(op, results[i].Tx)
+		}
+	}
+	for _, l := range deferred {
+		b.notify(l.id, l.log)
+	}
+	return results, nil
+}
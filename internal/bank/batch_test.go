@@ -0,0 +1,68 @@
+// internal/bank/batch_test.go
+//
+// 測試批次操作：atomic 模式失敗時整批回滾；partial 模式逐筆回報且成功者已提交。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyBatchAtomicRollback(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+
+	ops := []Op{
+		{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 60},
+		{Type: TxDeposit, Account: a2.ID, Amount: 5},
+		{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 60}, // 餘額不足 → 整批回滾
+	}
+	res, err := b.ApplyBatch(ops, true)
+	if !errors.Is(err, ErrInsufficient) || res != nil {
+		t.Fatalf("want ErrInsufficient, got res=%v err=%v", res, err)
+	}
+	if get(t, b, a1.ID).Balance != 100 || get(t, b, a2.ID).Balance != 0 {
+		t.Fatal("balances changed after rollback")
+	}
+	if logs, _ := b.Logs(a2.ID); len(logs) != 0 {
+		t.Fatalf("logs=%d want 0 after rollback", len(logs))
+	}
+	// 交易序號亦回滾：下一筆交易沿用原本的序號
+	tx, _ := b.Apply(Op{Type: TxDeposit, Account: a1.ID, Amount: 1})
+	if tx.ID != "tx-1" {
+		t.Fatalf("tx id=%q want tx-1", tx.ID)
+	}
+
+	// ✅ 全部成功時整批提交
+	res, err = b.ApplyBatch(ops[:2], true)
+	if err != nil || len(res) != 2 || res[1].Tx.ID != "tx-3" {
+		t.Fatalf("res=%+v err=%v", res, err)
+	}
+	if get(t, b, a2.ID).Balance != 65 {
+		t.Fatalf("balance=%d want 65", get(t, b, a2.ID).Balance)
+	}
+}
+
+func TestApplyBatchPartial(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+
+	res, err := b.ApplyBatch([]Op{
+		{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 30},
+		{Type: TxTransfer, From: a1.ID, To: "999", Amount: 10}, // 帳戶不存在
+		{Type: TxWithdraw, Account: a2.ID, Amount: 1000},       // 餘額不足
+		{Type: TxDeposit, Account: a1.ID, Amount: 5},
+	}, false)
+	if err != nil || len(res) != 4 {
+		t.Fatalf("res=%v err=%v", res, err)
+	}
+	if res[0].Err != nil || !errors.Is(res[1].Err, ErrNotFound) || !errors.Is(res[2].Err, ErrInsufficient) || res[3].Err != nil {
+		t.Fatalf("results=%+v", res)
+	}
+	if get(t, b, a1.ID).Balance != 75 || get(t, b, a2.ID).Balance != 30 {
+		t.Fatalf("balances: a1=%d a2=%d", get(t, b, a1.ID).Balance, get(t, b, a2.ID).Balance)
+	}
+}
@@ -0,0 +1,152 @@
+// internal/bank/close_test.go
+//
+// 測試帳戶關閉：基本規則，以及與並行存款 / 轉帳的一致性（請搭配 -race 執行）。
+
+package bank
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// TestClose 驗證關閉規則：有餘額不得關閉、關閉後拒絕異動但仍可查詢。
+func TestClose(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 10)
+	other, _ := b.Create("B", 10)
+
+	if _, err := b.Close(a.ID); !errors.Is(err, ErrNonZeroBalance) {
+		t.Fatalf("want ErrNonZeroBalance, got %v", err)
+	}
+	_, _ = b.Withdraw(a.ID, NewMoney(10, "USD"), "")
+	if _, err := b.Close(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Close(a.ID); !errors.Is(err, ErrClosed) {
+		t.Fatalf("want ErrClosed, got %v", err)
+	}
+	if _, err := b.Deposit(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrClosed) {
+		t.Fatalf("deposit want ErrClosed, got %v", err)
+	}
+	if err := b.Transfer(other.ID, a.ID, NewMoney(1, "USD")); !errors.Is(err, ErrClosed) {
+		t.Fatalf("transfer want ErrClosed, got %v", err)
+	}
+	if got := get(t, b, a.ID); got.Status != StatusClosed || got.Balance != 0 {
+		t.Fatalf("closed account=%+v", got)
+	}
+	if _, err := b.Close("999"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
+
+// TestDelete 驗證刪除規則：有餘額不得刪除，歸零後可刪除，之後查詢回傳 ErrNotFound。
+func TestDelete(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 10)
+	other, _ := b.Create("B", 0)
+	if err := b.Transfer(a.ID, other.ID, NewMoney(4, "USD")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 有餘額 → ErrNonZeroBalance，帳戶仍在
+	if err := b.Delete(a.ID); !errors.Is(err, ErrNonZeroBalance) {
+		t.Fatalf("want ErrNonZeroBalance, got %v", err)
+	}
+	get(t, b, a.ID)
+
+	// 2️⃣ 提領歸零後刪除成功
+	_, _ = b.Withdraw(a.ID, NewMoney(6, "USD"), "")
+	if err := b.Delete(a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Get(a.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted account Get want ErrNotFound, got %v", err)
+	}
+	if err := b.Delete(a.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("second delete want ErrNotFound, got %v", err)
+	}
+
+	// 3️⃣ 索引同步移除：對手帳戶的交易仍可查，重建索引無異常
+	if _, err := b.FindTx("tx-1"); err != nil {
+		t.Fatalf("counterparty tx lost: %v", err)
+	}
+	if got := b.Reindex(); len(got) != 0 {
+		t.Fatalf("anomalies after delete: %v", got)
+	}
+}
+
+// TestConcurrentCloseAndOperations 於並行下同時關閉帳戶、存款與雙向轉帳，驗證：
+//   - 無 panic、無負餘額；
+//   - 已關閉的帳戶餘額必為 0；
+//   - 全行總額 = 初始總額 + 成功存款總額（資金守恆，不會有部分套用）。
+func TestConcurrentCloseAndOperations(t *testing.T) {
+	b := NewBank()
+	src, _ := b.Create("source", 10000)
+	const targets = 20
+	ids := make([]string, targets)
+	for i := range ids {
+		a, _ := b.Create("t", 0)
+		ids[i] = a.ID
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		deposited int64
+	)
+	okOrExpected := func(err error) {
+		if err != nil && !errors.Is(err, ErrClosed) && !errors.Is(err, ErrInsufficient) && !errors.Is(err, ErrNonZeroBalance) {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	for _, id := range ids {
+		wg.Add(4)
+		go func() { // 存款
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, err := b.Deposit(id, NewMoney(1, "USD"), "")
+				okOrExpected(err)
+				if err == nil {
+					mu.Lock()
+					deposited++
+					mu.Unlock()
+				}
+			}
+		}()
+		go func() { // 轉入
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				okOrExpected(b.Transfer(src.ID, id, NewMoney(2, "USD")))
+			}
+		}()
+		go func() { // 轉出
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				okOrExpected(b.Transfer(id, src.ID, NewMoney(3, "USD")))
+			}
+		}()
+		go func() { // 反覆嘗試關閉
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				_, err := b.Close(id)
+				okOrExpected(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var total int64
+	for _, a := range b.List() {
+		if a.Balance < 0 {
+			t.Fatalf("negative balance: %+v", a)
+		}
+		if a.Status == StatusClosed && a.Balance != 0 {
+			t.Fatalf("closed account holds money: %+v", a)
+		}
+		total += a.Balance
+	}
+	if want := 10000 + deposited; total != want {
+		t.Fatalf("total=%d want %d", total, want)
+	}
+}
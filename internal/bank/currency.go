@@ -0,0 +1,71 @@
+// internal/bank/currency.go
+//
+// 本檔管理幣別相關設定。
+// 營運方可在特定情境（例如某幣別遭凍結）暫停該幣別的所有存款、提款與轉帳，
+// 被暫停的操作回傳 ErrCurrencyDisabled；設定可於執行期間隨時切換。
+// 帳戶各有單一幣別，不同幣別帳戶之間的轉帳一律回傳 ErrCurrencyMismatch（不做匯率換算）。
+
+package bank
+
+import (
+	"sort"
+	"strings"
+)
+
+// DefaultCurrency 為未指定幣別時的預設值。
+const DefaultCurrency = "USD"
+
+// normalizeCurrency 將幣別代碼正規化為大寫；空值回傳 DefaultCurrency。
+func normalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
+
+// validCurrency 回報 code 是否為空值（採預設）或三個英文字母（不分大小寫）。
+func validCurrency(code string) bool {
+	code = normalizeCurrency(code)
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// DisableCurrencies 暫停指定幣別的交易；空白項目會被略過。
+func (b *Bank) DisableCurrencies(codes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range codes {
+		if strings.TrimSpace(c) != "" {
+			b.disabledCcy[normalizeCurrency(c)] = true
+		}
+	}
+}
+
+// EnableCurrencies 恢復指定幣別的交易。
+func (b *Bank) EnableCurrencies(codes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, c := range codes {
+		delete(b.disabledCcy, normalizeCurrency(c))
+	}
+}
+
+// DisabledCurrencies 回傳目前被暫停的幣別（排序後）。
+func (b *Bank) DisabledCurrencies() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := make([]string, 0, len(b.disabledCcy))
+	for c := range b.disabledCcy {
+		out = append(out, c)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,73 @@
+// internal/bank/currency_test.go
+//
+// 測試幣別設定：預設幣別、暫停幣別阻擋交易、恢復後交易正常。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDisabledCurrencyBlocksOperations 驗證暫停幣別時存提款與轉帳皆被拒，恢復後正常。
+func TestDisabledCurrencyBlocksOperations(t *testing.T) {
+	b := NewBank()
+	usd, _ := b.Create("A", 100)
+	rub1, _ := b.Open(AccountSpec{Name: "B", Balance: 100, Currency: "rub"})
+	rub2, _ := b.Open(AccountSpec{Name: "C", Balance: 100, Currency: "RUB"})
+	if usd.Currency != "USD" || rub1.Currency != "RUB" {
+		t.Fatalf("currencies=%s %s", usd.Currency, rub1.Currency)
+	}
+
+	b.DisableCurrencies("rub")
+	if got := b.DisabledCurrencies(); len(got) != 1 || got[0] != "RUB" {
+		t.Fatalf("disabled=%v", got)
+	}
+	if _, err := b.Deposit(rub1.ID, NewMoney(1, "RUB"), ""); !errors.Is(err, ErrCurrencyDisabled) {
+		t.Fatalf("deposit want ErrCurrencyDisabled, got %v", err)
+	}
+	if _, err := b.Withdraw(rub1.ID, NewMoney(1, "RUB"), ""); !errors.Is(err, ErrCurrencyDisabled) {
+		t.Fatalf("withdraw want ErrCurrencyDisabled, got %v", err)
+	}
+	if err := b.Transfer(rub1.ID, rub2.ID, NewMoney(1, "RUB")); !errors.Is(err, ErrCurrencyDisabled) {
+		t.Fatalf("transfer want ErrCurrencyDisabled, got %v", err)
+	}
+	// 其他幣別不受影響
+	if _, err := b.Deposit(usd.ID, NewMoney(1, "USD"), ""); err != nil {
+		t.Fatalf("USD deposit: %v", err)
+	}
+
+	b.EnableCurrencies("RUB")
+	if err := b.Transfer(rub1.ID, rub2.ID, NewMoney(10, "RUB")); err != nil {
+		t.Fatalf("transfer after enable: %v", err)
+	}
+	if get(t, b, rub2.ID).Balance != 110 {
+		t.Fatalf("rub2 balance=%d want 110", get(t, b, rub2.ID).Balance)
+	}
+}
+
+// TestCrossCurrencyTransfer 驗證同幣別轉帳成功、USD→EUR 轉帳被拒且雙方餘額不變，以及無效幣別代碼被拒。
+func TestCrossCurrencyTransfer(t *testing.T) {
+	b := NewBank()
+	usd1, _ := b.Create("A", 100)
+	usd2, _ := b.Open(AccountSpec{Name: "B", Balance: 0, Currency: "usd"})
+	eur, _ := b.Open(AccountSpec{Name: "C", Balance: 0, Currency: "EUR"})
+
+	// ✅ 同幣別
+	if err := b.Transfer(usd1.ID, usd2.ID, NewMoney(10, "USD")); err != nil {
+		t.Fatalf("USD->USD: %v", err)
+	}
+	// ❌ 跨幣別
+	if err := b.Transfer(usd1.ID, eur.ID, NewMoney(10, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("USD->EUR want ErrCurrencyMismatch, got %v", err)
+	}
+	if get(t, b, usd1.ID).Balance != 90 || get(t, b, eur.ID).Balance != 0 {
+		t.Fatal("rejected transfer changed balances")
+	}
+	// ❌ 無效代碼
+	for _, code := range []string{"dollars", "U$D", "12"} {
+		if _, err := b.Open(AccountSpec{Name: "D", Currency: code}); !errors.Is(err, ErrBadCurrency) {
+			t.Fatalf("Open(currency=%q) want ErrBadCurrency, got %v", code, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// internal/bank/dailylimit.go
+//
+// 本檔實作每帳戶的每日提款限額，用以控制盜用等情況下的單日損失。
+//   - 提款與轉出（不含手續費）計入當日累計；設定 DailyLimit = L（> 0）後，累計超過 L 的操作回傳 ErrDailyLimitExceeded。
+//   - 「一天」以 UTC 日期劃分：累計記錄所屬日期，跨日後第一次查詢即歸零，無需排程重設。
+//   - 未設定限額時仍照常累計，因此當天稍晚才設定的限額也會算入先前的提款。
+//   - 待審核轉帳（見 hold.go）於送出時即計入；事後駁回或逾期釋放不退回額度。
+//
+// 當日累計隨快照保存，重新啟動後不會重置額度。
+
+package bank
+
+import "time"
+
+// dayFormat 為當日累計所屬日期的格式（UTC）。
+const dayFormat = "2006-01-02"
+
+// SetDailyLimit 設定帳戶的每日提款限額（>= 0，0 為不限制）；負值回傳 ErrBadAmount，帳戶不存在回傳 ErrNotFound。
+// 調降限額不影響今日已完成的提款，只會讓後續扣款更早被拒。
+func (b *Bank) SetDailyLimit(id string, limit int64) error {
+	if limit < 0 {
+		return ErrBadAmount
+	}
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return ErrFrozen
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.DailyLimit = limit
+	a.bump()
+	return nil
+}
+
+// withdrawnOn 回傳帳戶於 now 所屬 UTC 日期的累計提款；累計屬於更早的日期時視為 0。
+func (a *Account) withdrawnOn(now time.Time) int64 {
+	if a.dailyDay != now.UTC().Format(dayFormat) {
+		return 0
+	}
+	return a.dailyUsed
+}
+
+// checkDaily 檢查再提款 amt 是否超過每日限額；未設定限額時一律通過。
+func (a *Account) checkDaily(amt int64, now time.Time) error {
+	if a.DailyLimit <= 0 {
+		return nil
+	}
+	if amt > a.DailyLimit-a.withdrawnOn(now) {
+		return ErrDailyLimitExceeded
+	}
+	return nil
+}
+
+// addDaily 將 amt 計入 now 所屬日期的累計；跨日時先歸零。
+func (a *Account) addDaily(amt int64, now time.Time) {
+	a.dailyUsed = a.withdrawnOn(now) + amt
+	a.dailyDay = now.UTC().Format(dayFormat)
+}
@@ -0,0 +1,99 @@
+// internal/bank/dailylimit_test.go
+//
+// 測試每日提款限額：提款與轉出共用當日額度，用盡後被拒，跨過 UTC 午夜後恢復；累計經快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestDailyLimit 驗證：
+// 1️⃣ 提款與轉出累計至限額皆成功；
+// 2️⃣ 再提款或轉出即回傳 ErrDailyLimitExceeded 且餘額不變，存款不受影響；
+// 3️⃣ 快照還原後累計仍在，同日仍被拒；
+// 4️⃣ 跨過 UTC 午夜後額度歸零，可再次提款。
+func TestDailyLimit(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 22, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a, _ := b.Create("A", 10_000)
+	other, _ := b.Create("B", 0)
+	if err := b.SetDailyLimit(a.ID, 1_000); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetDailyLimit(a.ID, -1); !errors.Is(err, ErrBadAmount) {
+		t.Fatalf("negative limit want ErrBadAmount, got %v", err)
+	}
+	if err := b.SetDailyLimit("999", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	// 1️⃣ 提款 600 + 轉出 400 = 1000，剛好用盡
+	if _, err := b.Withdraw(a.ID, NewMoney(600, "USD"), ""); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(400, "USD")); err != nil {
+		t.Fatalf("transfer: %v", err)
+	}
+
+	// 2️⃣ 額度用盡
+	if _, err := b.Withdraw(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("withdraw over limit want ErrDailyLimitExceeded, got %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(1, "USD")); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("transfer over limit want ErrDailyLimitExceeded, got %v", err)
+	}
+	if _, err := b.Deposit(a.ID, NewMoney(50, "USD"), ""); err != nil {
+		t.Fatalf("deposit: %v", err)
+	}
+	if got := get(t, b, a.ID).Balance; got != 9_050 {
+		t.Fatalf("balance=%d want 9050", got)
+	}
+
+	// 3️⃣ 快照還原後同日仍被拒
+	restored := NewBank()
+	restored.SetClock(clk.Now)
+	restored.Restore(b.Snapshot())
+	if _, err := restored.Withdraw(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("restored withdraw want ErrDailyLimitExceeded, got %v", err)
+	}
+
+	// 4️⃣ 跨過 UTC 午夜：額度歸零（還原的銀行亦同）
+	clk.Advance(2 * time.Hour)
+	if _, err := b.Withdraw(a.ID, NewMoney(1_000, "USD"), ""); err != nil {
+		t.Fatalf("withdraw on new day: %v", err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("second day over limit want ErrDailyLimitExceeded, got %v", err)
+	}
+	if _, err := restored.Withdraw(a.ID, NewMoney(1_000, "USD"), ""); err != nil {
+		t.Fatalf("restored withdraw on new day: %v", err)
+	}
+}
+
+// TestDailyLimitCountsEarlierWithdrawals 驗證當天稍晚才設定的限額會計入先前的提款；0 代表不限制。
+func TestDailyLimitCountsEarlierWithdrawals(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1_000)
+	if _, err := b.Withdraw(a.ID, NewMoney(300, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetDailyLimit(a.ID, 400); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(200, "USD"), ""); !errors.Is(err, ErrDailyLimitExceeded) {
+		t.Fatalf("want ErrDailyLimitExceeded, got %v", err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(100, "USD"), ""); err != nil {
+		t.Fatalf("withdraw within remaining limit: %v", err)
+	}
+	if err := b.SetDailyLimit(a.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(600, "USD"), ""); err != nil {
+		t.Fatalf("withdraw without limit: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+// internal/bank/dedup.go
+//
+// 本檔實作提款的請求去重，避免網路重試造成重複扣款。
+// 客戶端可於提款時附上 request_id；同一帳戶在有效期限內重送相同 request_id 時，
+// 不會再次扣款，而是直接回傳第一次提交的交易。
+//   - 只記錄成功的提款：失敗的請求沒有扣款，重試本來就安全。
+//   - 每個帳戶最多保留 withdrawDedupMax 筆、保留 withdrawDedupTTL，超過即淘汰最舊者。
+//   - 相同 request_id 但金額不同視為用戶端錯誤，回傳 ErrDuplicateRequest。
+//
+// 去重紀錄屬於執行期狀態，不寫入快照。
+
+package bank
+
+import "time"
+
+// 提款去重的保留上限與有效期限。
+const (
+	withdrawDedupMax = 128
+	withdrawDedupTTL = 10 * time.Minute
+)
+
+// withdrawRecord 為一筆已成功提款的去重紀錄。
+type withdrawRecord struct {
+	requestID string
+	tx        Tx
+}
+
+// withdrawOnce 以 requestID 去重的提款；requestID 為空時等同 withdraw。須在 mu 保護下呼叫。
+// 去重只比對金額：重送時 note 不同仍視為同一筆提款。
+// fresh 為 false 代表去重命中，回傳的是第一次提交的交易（未再扣款）。
+func (b *Bank) withdrawOnce(id string, amt int64, requestID, note string) (tx Tx, fresh bool, err error) {
+	if requestID == "" {
+		tx, err = b.withdraw(id, amt, note)
+		return tx, err == nil, err
+	}
+	if a, ok := b.accts[id]; ok {
+		a.pruneWithdraws(b.now())
+		for _, r := range a.withdraws {
+			if r.requestID != requestID {
+				continue
+			}
+			if r.tx.Amount != amt {
+				return Tx{}, false, ErrDuplicateRequest
+			}
+			return r.tx, false, nil
+		}
+	}
+	tx, err = b.withdraw(id, amt, note)
+	if err != nil {
+		return Tx{}, false, err
+	}
+	a := b.accts[id]
+	a.withdraws = append(a.withdraws, withdrawRecord{requestID: requestID, tx: tx})
+	if n := len(a.withdraws); n > withdrawDedupMax {
+		a.withdraws = append([]withdrawRecord(nil), a.withdraws[n-withdrawDedupMax:]...)
+	}
+	return tx, true, nil
+}
+
+// pruneWithdraws 移除超過有效期限的去重紀錄（紀錄依時間先後排列）。
+func (a *Account) pruneWithdraws(now time.Time) {
+	i := 0
+	for i < len(a.withdraws) && now.Sub(a.withdraws[i].tx.Time) >= withdrawDedupTTL {
+		i++
+	}
+	if i > 0 {
+		a.withdraws = append([]withdrawRecord(nil), a.withdraws[i:]...)
+	}
+}
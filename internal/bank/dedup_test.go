@@ -0,0 +1,76 @@
+// internal/bank/dedup_test.go
+//
+// 測試提款去重：同一 request_id 並行重送只扣款一次，並回傳相同的交易。
+
+package bank
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithdrawDedupConcurrent(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 100)
+
+	const n = 10
+	txs := make([]Tx, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			txs[i], errs[i] = b.Apply(Op{Type: TxWithdraw, Account: a.ID, Amount: 30, RequestID: "req-1"})
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < n; i++ {
+		if errs[i] != nil {
+			t.Fatalf("attempt %d: %v", i, errs[i])
+		}
+		if txs[i].ID != txs[0].ID {
+			t.Fatalf("attempt %d got tx %q, want replay of %q", i, txs[i].ID, txs[0].ID)
+		}
+	}
+	if got := get(t, b, a.ID).Balance; got != 70 {
+		t.Fatalf("balance=%d want 70 (debited once)", got)
+	}
+	if logs, _ := b.Logs(a.ID); len(logs) != 1 {
+		t.Fatalf("logs=%d want 1", len(logs))
+	}
+}
+
+func TestWithdrawDedupRules(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a, _ := b.Create("A", 100)
+	withdraw := func(amt int64, reqID string) error {
+		_, err := b.Apply(Op{Type: TxWithdraw, Account: a.ID, Amount: amt, RequestID: reqID})
+		return err
+	}
+
+	// ❌ 同 request_id 不同金額
+	_ = withdraw(10, "r1")
+	if err := withdraw(20, "r1"); !errors.Is(err, ErrDuplicateRequest) {
+		t.Fatalf("want ErrDuplicateRequest, got %v", err)
+	}
+	// ✅ 失敗的提款不記錄，補足餘額後可用同一 request_id 重試
+	if err := withdraw(500, "r2"); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+	_, _ = b.Deposit(a.ID, NewMoney(500, "USD"), "")
+	if err := withdraw(500, "r2"); err != nil {
+		t.Fatal(err)
+	}
+	// ✅ 逾期後同一 request_id 視為新請求
+	clk.Advance(withdrawDedupTTL)
+	_ = withdraw(10, "r1")
+	if got := get(t, b, a.ID).Balance; got != 80 {
+		t.Fatalf("balance=%d want 80", got)
+	}
+}
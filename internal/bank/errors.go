@@ -13,10 +13,14 @@ var (
 	// 對應 HTTP 狀態碼 404 Not Found。
 	ErrNotFound = errors.New("account not found")
 
-	// ErrBadAmount 代表金額非法（<=0 或初始餘額為負）。
+	// ErrBadAmount 代表存款、提款、轉帳等異動的金額非法（<= 0）。
 	// 對應 HTTP 狀態碼 400 Bad Request。
 	ErrBadAmount = errors.New("amount must be > 0")
 
+	// ErrNegativeBalance 代表開戶的初始餘額為負（零是允許的）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrNegativeBalance = errors.New("initial balance must be >= 0")
+
 	// ErrInsufficient 代表餘額不足，導致提款或轉帳失敗。
 	// 對應 HTTP 狀態碼 409 Conflict。
 	ErrInsufficient = errors.New("insufficient balance")
@@ -24,4 +28,120 @@ var (
 	// ErrSameAccount 代表轉帳來源與目標帳戶相同。
 	// 對應 HTTP 狀態碼 400 Bad Request。
 	ErrSameAccount = errors.New("from and to are same")
+
+	// ErrNoteBudget 代表帳戶備註累計位元組數已達上限（NoteReject 策略）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrNoteBudget = errors.New("note budget exceeded")
+
+	// ErrNameNotAllowed 代表帳戶名稱命中禁用清單。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrNameNotAllowed = errors.New("account name not allowed")
+
+	// ErrBadName 代表帳戶名稱為空（或僅含空白）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBadName = errors.New("account name must not be empty")
+
+	// ErrTxNotFound 代表交易 ID 不存在。
+	// 對應 HTTP 狀態碼 404 Not Found。
+	ErrTxNotFound = errors.New("transaction not found")
+
+	// ErrBadOp 代表不支援的操作類型（Apply 的 Op.Type 無法辨識）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBadOp = errors.New("unsupported operation")
+
+	// ErrCurrencyDisabled 代表帳戶幣別目前被暫停交易。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrCurrencyDisabled = errors.New("currency is disabled")
+
+	// ErrBelowMinimum 代表金額低於設定的最低轉帳 / 存提款金額。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBelowMinimum = errors.New("amount below minimum")
+
+	// ErrClosed 代表帳戶已關閉，不得再有資金異動。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrClosed = errors.New("account is closed")
+
+	// ErrNonZeroBalance 代表帳戶仍有餘額，須先轉出或提領才能關閉 / 刪除。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrNonZeroBalance = errors.New("account balance is not zero")
+
+	// ErrFrozen 代表全行處於凍結（緊急停止）狀態，暫不接受任何異動。
+	// 對應 HTTP 狀態碼 503 Service Unavailable。
+	ErrFrozen = errors.New("bank is frozen")
+
+	// ErrSystemAccount 代表操作不允許作用於系統帳戶（關閉、提款、一般轉出）。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrSystemAccount = errors.New("operation not allowed on system account")
+
+	// ErrCurrencyMismatch 代表金額與帳戶、兩筆金額之間或轉帳雙方帳戶的幣別不一致。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+
+	// ErrBadCurrency 代表幣別代碼不是三個英文字母的 ISO-4217 代碼。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBadCurrency = errors.New("currency must be a 3-letter ISO-4217 code")
+
+	// ErrBadFilter 代表日誌篩選條件不合法（例如未知的方向）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBadFilter = errors.New("invalid log filter")
+
+	// ErrNotReversible 代表交易不是可沖正的轉帳（例如存款、提款、手續費或沖正交易本身）。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrNotReversible = errors.New("transaction is not a reversible transfer")
+
+	// ErrAlreadyReversed 代表轉帳已經沖正過，不得重複沖正。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrAlreadyReversed = errors.New("transaction already reversed")
+
+	// ErrBadSort 代表列表的排序鍵不支援（見 Bank.ListPage）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrBadSort = errors.New("invalid sort key")
+
+	// ErrHoldNotFound 代表指定的待審核轉帳不存在（可能已核准、駁回或逾期釋放）。
+	// 對應 HTTP 狀態碼 404 Not Found。
+	ErrHoldNotFound = errors.New("pending transfer not found")
+
+	// ErrAuthHoldNotFound 代表指定的授權保留不存在（可能已請款、已釋放或已逾期，見 authhold.go）。
+	// 對應 HTTP 狀態碼 404 Not Found。
+	ErrAuthHoldNotFound = errors.New("authorization hold not found")
+
+	// ErrDuplicateRequest 代表 request_id 已被另一筆內容不同的請求使用。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrDuplicateRequest = errors.New("request_id reused with different parameters")
+
+	// ErrDuplicateRef 代表開戶指定的外部參照（ExternalRef）已被其他帳戶使用（見 externalref.go）。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrDuplicateRef = errors.New("external reference already in use")
+
+	// ErrPendingApproval 代表帳戶尚待管理者核准開戶，暫不接受任何資金異動。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrPendingApproval = errors.New("account is pending approval")
+
+	// ErrOverflow 代表入帳後餘額將超出 int64 範圍；操作被拒絕且不改變任何狀態。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrOverflow = errors.New("balance would overflow")
+
+	// ErrAccountFrozen 代表帳戶已被凍結（例如疑似詐欺），暫不接受任何資金異動。
+	// 與全行凍結 ErrFrozen 不同，只影響單一帳戶；對應 HTTP 狀態碼 423 Locked。
+	ErrAccountFrozen = errors.New("account is frozen")
+
+	// ErrDailyLimitExceeded 代表提款或轉出將使當日累計超過帳戶的每日提款限額。
+	// 對應 HTTP 狀態碼 429 Too Many Requests。
+	ErrDailyLimitExceeded = errors.New("daily withdrawal limit exceeded")
+
+	// ErrVersionMismatch 代表條件式異動指定的版本與帳戶目前版本不符（見 version.go）；操作未執行。
+	// 對應 HTTP 狀態碼 412 Precondition Failed。
+	ErrVersionMismatch = errors.New("account version mismatch")
+
+	// ErrInconsistent 代表帳戶餘額與其日誌不符（見 verify.go 的 VerifyError）。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrInconsistent = errors.New("ledger inconsistent")
+
+	// ErrSnapshotVersion 代表匯入的快照結構版本與目前版本（storage.SnapshotVersion）不符（見 import.go）。
+	// 對應 HTTP 狀態碼 400 Bad Request。
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+
+	// ErrNotEmpty 代表帳本已有帳戶，未指定強制覆蓋時拒絕匯入快照（見 import.go）。
+	// 對應 HTTP 狀態碼 409 Conflict。
+	ErrNotEmpty = errors.New("bank already has accounts")
 )
@@ -0,0 +1,51 @@
+// internal/bank/externalref.go
+//
+// 本檔實作外部參照（ExternalRef）：整合方開戶時可附上自己系統的使用者 ID，之後以該值反查帳戶。
+// 外部參照為選填；有填寫時須全行唯一（含已關閉的帳戶），重複時開戶回傳 ErrDuplicateRef。
+// 參照不另建索引，查詢時線性掃描帳戶表；帳戶數量大時可再改為維護 ref → ID 的索引。
+
+package bank
+
+// GetByExternalRef 回傳外部參照為 ref 的帳戶快照；ref 為空或查無帳戶時回傳 ErrNotFound。
+func (b *Bank) GetByExternalRef(ref string) (*Account, error) {
+	if ref == "" {
+		return nil, ErrNotFound
+	}
+	if v := b.view.Load(); v != nil {
+		for _, a := range v.sorted {
+			if a.ExternalRef == ref {
+				cp := *a
+				return &cp, nil
+			}
+		}
+		return nil, ErrNotFound
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	a := b.findRef(ref)
+	if a == nil {
+		return nil, ErrNotFound
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	cp := *a
+	return &cp, nil
+}
+
+// findRef 回傳外部參照為 ref 的帳戶；呼叫端須持有 b.mu。ExternalRef 開戶後不再變動，讀取不需帳戶鎖。
+func (b *Bank) findRef(ref string) *Account {
+	for _, a := range b.accts {
+		if a.ExternalRef == ref {
+			return a
+		}
+	}
+	return nil
+}
+
+// checkRef 確認開戶時指定的外部參照尚未被使用；空值不檢查。呼叫端須持有 b.mu 寫鎖。
+func (b *Bank) checkRef(ref string) error {
+	if ref != "" && b.findRef(ref) != nil {
+		return ErrDuplicateRef
+	}
+	return nil
+}
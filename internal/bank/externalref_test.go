@@ -0,0 +1,64 @@
+// internal/bank/externalref_test.go
+//
+// 測試外部參照：開戶時指定、重複時拒絕、可反查帳戶並隨快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestExternalRef 驗證：
+// 1️⃣ 開戶時指定的 ExternalRef 出現在回傳的帳戶上，且可用 GetByExternalRef 查回；
+// 2️⃣ 重複的 ExternalRef 回傳 ErrDuplicateRef，且不建立帳戶（已關閉的帳戶仍佔用參照）；
+// 3️⃣ 未指定 ExternalRef 的帳戶可任意建立多個，空值與不存在的參照查詢回傳 ErrNotFound；
+// 4️⃣ 快照還原後參照仍可查詢、仍須唯一。
+func TestExternalRef(t *testing.T) {
+	b := NewBank()
+	a, err := b.Open(AccountSpec{Name: "A", Balance: 100, ExternalRef: "user-42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ExternalRef != "user-42" {
+		t.Fatalf("ExternalRef=%q want user-42", a.ExternalRef)
+	}
+	got, err := b.GetByExternalRef("user-42")
+	if err != nil || got.ID != a.ID {
+		t.Fatalf("GetByExternalRef=%v,%v want account %s", got, err, a.ID)
+	}
+
+	if _, err := b.Open(AccountSpec{Name: "B", ExternalRef: "user-42"}); !errors.Is(err, ErrDuplicateRef) {
+		t.Fatalf("duplicate ref err=%v want ErrDuplicateRef", err)
+	}
+	if n := b.AccountCount(); n != 1 {
+		t.Fatalf("AccountCount=%d want 1 after rejected open", n)
+	}
+	c, _ := b.Open(AccountSpec{Name: "C", ExternalRef: "user-7"})
+	if _, err := b.Close(c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Open(AccountSpec{Name: "C2", ExternalRef: "user-7"}); !errors.Is(err, ErrDuplicateRef) {
+		t.Fatalf("ref of closed account err=%v want ErrDuplicateRef", err)
+	}
+
+	for range 2 {
+		if _, err := b.Open(AccountSpec{Name: "X"}); err != nil {
+			t.Fatalf("open without ref: %v", err)
+		}
+	}
+	for _, ref := range []string{"", "nobody"} {
+		if _, err := b.GetByExternalRef(ref); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetByExternalRef(%q) err=%v want ErrNotFound", ref, err)
+		}
+	}
+
+	r := NewBank()
+	r.Restore(b.Snapshot())
+	if got, err := r.GetByExternalRef("user-42"); err != nil || got.ID != a.ID {
+		t.Fatalf("restored GetByExternalRef=%v,%v want account %s", got, err, a.ID)
+	}
+	if _, err := r.Open(AccountSpec{Name: "D", ExternalRef: "user-42"}); !errors.Is(err, ErrDuplicateRef) {
+		t.Fatalf("restored duplicate ref err=%v want ErrDuplicateRef", err)
+	}
+}
@@ -0,0 +1,75 @@
+// internal/bank/fee.go
+//
+// 本檔實作轉帳的固定手續費。
+// 以 NewBankWithFee(fee) 建立的銀行，每筆轉出除了轉帳金額外，另向轉出方收取 fee：
+//   - 轉出方須有 amt + fee 的可動用額度，否則 ErrInsufficient；
+//   - 收款方恰好收到 amt；
+//   - 手續費為獨立交易（Type "fee"、Note "fee"、Direction "out"），有自己的 TxID；
+//     系統帳戶存在時由其收取（雙邊日誌），否則僅記錄於轉出方。
+//
+// 待審核的轉帳（見 hold.go）於核准過帳時才收取手續費。
+
+package bank
+
+import "time"
+
+// NewBankWithFee 建立每筆轉帳收取固定手續費 fee 的銀行；fee <= 0 等同 NewBank。
+func NewBankWithFee(fee int64) *Bank {
+	b := NewBank()
+	b.transferFee = max(fee, 0)
+	return b
+}
+
+// feeLeg 為一筆待過帳的手續費：於檢核階段備妥，過帳時不會再失敗。
+type feeLeg struct {
+	amount  int64
+	note    string
+	sys     *Account // 收取手續費的系統帳戶；nil 代表不存在
+	sysNote string
+}
+
+// TransferFee 回傳每筆轉帳的固定手續費（0 代表不收取）。
+func (b *Bank) TransferFee() int64 {
+	return b.transferFee
+}
+
+// prepareFee 檢核並備妥 from 的轉帳手續費（不含額度檢查，由呼叫端連同本金一起檢查）；
+// 未設定手續費時回傳零值。須在 mu 保護下呼叫。
+func (b *Bank) prepareFee(from *Account) (feeLeg, error) {
+	if b.transferFee <= 0 {
+		return feeLeg{}, nil
+	}
+	leg := feeLeg{amount: b.transferFee}
+	var err error
+	if leg.note, err = b.fitNote(from, TxFee); err != nil {
+		return feeLeg{}, err
+	}
+	if sys, ok := b.accts[SystemAccountID]; ok && sys != from {
+		if err := sys.checkCredit(leg.amount); err != nil {
+			return feeLeg{}, err
+		}
+		if leg.sysNote, err = b.fitNote(sys, TxFee); err != nil {
+			return feeLeg{}, err
+		}
+		leg.sys = sys
+	}
+	return leg, nil
+}
+
+// postFee 過帳手續費；leg 為零值時不做任何事。須在 mu 保護下呼叫。
+func (b *Bank) postFee(from *Account, leg feeLeg, at time.Time) {
+	if leg.amount <= 0 {
+		return
+	}
+	tx := b.newTx(TxFee)
+	from.Balance -= leg.amount
+	if leg.sys == nil {
+		b.addLog(from, Log{Time: at, TxID: tx.ID, Type: TxFee, Amount: leg.amount, Direction: "out", Note: leg.note})
+		b.indexTx(tx.ID, from.ID)
+		return
+	}
+	leg.sys.Balance += leg.amount
+	b.addLog(from, Log{Time: at, TxID: tx.ID, Type: TxFee, Amount: leg.amount, Direction: "out", CounterID: leg.sys.ID, Note: leg.note})
+	b.addLog(leg.sys, Log{Time: at, TxID: tx.ID, Type: TxFee, Amount: leg.amount, Direction: "in", CounterID: from.ID, Note: leg.sysNote})
+	b.indexTx(tx.ID, from.ID, leg.sys.ID)
+}
@@ -0,0 +1,82 @@
+// internal/bank/fee_test.go
+//
+// 測試轉帳手續費：轉出方多扣 fee 並留下獨立的手續費日誌，收款方恰好收到 amt。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestTransferFee 驗證：
+// 1️⃣ 轉帳後轉出方扣 amt + fee、收款方收到 amt，轉出方有 Note "fee" 的獨立日誌；
+// 2️⃣ 額度不足以支付 amt + fee 時回傳 ErrInsufficient 且不變更任何狀態；
+// 3️⃣ 系統帳戶存在時由其收取手續費，全行總額守恆、索引無異常。
+func TestTransferFee(t *testing.T) {
+	b := NewBankWithFee(2)
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("C", 0)
+
+	// 1️⃣
+	if err := b.Transfer(a.ID, c.ID, NewMoney(10, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	ga, gc := get(t, b, a.ID), get(t, b, c.ID)
+	if ga.Balance != 88 || gc.Balance != 10 {
+		t.Fatalf("balances a=%d c=%d want 88/10", ga.Balance, gc.Balance)
+	}
+	if len(ga.Logs) != 2 || len(gc.Logs) != 1 {
+		t.Fatalf("logs a=%d c=%d want 2/1", len(ga.Logs), len(gc.Logs))
+	}
+	fee := ga.Logs[1]
+	if fee.Note != "fee" || fee.Direction != "out" || fee.Amount != 2 || fee.Type != TxFee || fee.TxID == ga.Logs[0].TxID {
+		t.Fatalf("fee log=%+v", fee)
+	}
+
+	// 2️⃣ 餘額 88：轉 87 需 89 → 不足；轉 86 恰好歸零
+	if err := b.Transfer(a.ID, c.ID, NewMoney(87, "USD")); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+	if got := get(t, b, a.ID); got.Balance != 88 || len(got.Logs) != 2 {
+		t.Fatalf("failed transfer changed sender: %+v", got)
+	}
+	if err := b.Transfer(a.ID, c.ID, NewMoney(86, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, a.ID).Balance; got != 0 {
+		t.Fatalf("balance=%d want 0", got)
+	}
+
+	// 3️⃣ 系統帳戶收取手續費
+	sys := b.EnsureSystemAccount()
+	if err := b.Transfer(c.ID, a.ID, NewMoney(50, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, sys.ID).Balance; got != 2 {
+		t.Fatalf("system balance=%d want 2", got)
+	}
+	var total int64
+	for _, acct := range b.List() {
+		total += acct.Balance
+	}
+	if total != 100-4 { // 前兩筆手續費在系統帳戶建立前收取，已離開帳本
+		t.Fatalf("total=%d want 96", total)
+	}
+	if got := b.Reindex(); len(got) != 0 {
+		t.Fatalf("anomalies: %v", got)
+	}
+}
+
+// TestNoFeeByDefault 驗證 NewBank 不收取手續費。
+func TestNoFeeByDefault(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 10)
+	c, _ := b.Create("C", 0)
+	if err := b.Transfer(a.ID, c.ID, NewMoney(10, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, a.ID); got.Balance != 0 || len(got.Logs) != 1 {
+		t.Fatalf("sender=%+v", got)
+	}
+}
@@ -0,0 +1,84 @@
+// internal/bank/feed.go
+//
+// 本檔提供「全行交易流（feed）」：跨帳戶合併所有日誌並依序號（時間先後）排序。
+// 日誌本身只存在各帳戶內，feed 在臨界區內彙整並回傳值拷貝，供後台監控使用。
+
+package bank
+
+import (
+	"sort"
+	"time"
+)
+
+// FeedEntry 為交易流中的一筆紀錄：日誌內容加上所屬帳戶資訊。
+type FeedEntry struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	Log
+}
+
+// Activity 回傳最近 window 時間內（依銀行時鐘）提交的所有交易日誌，依時間先後排序。
+// limit > 0 時只保留最新的 limit 筆，避免回應過大。
+func (b *Bank) Activity(window time.Duration, limit int) []FeedEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	out := b.feed(b.now().Add(-window))
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out
+}
+
+// FeedOptions 為 AllLogs 的篩選條件；零值欄位代表不設限。
+type FeedOptions struct {
+	Account string    // 只列出此帳戶的日誌
+	Since   time.Time // 只列出時間不早於 Since 的日誌
+	Limit   int       // > 0 時只保留最新的 Limit 筆
+}
+
+// AllLogs 回傳全行（或 opts.Account 單一帳戶）符合 opts 的日誌拷貝，依時間先後排序並附帳戶資訊；
+// 無符合時為空切片。opts.Account 指定的帳戶不存在時回傳 ErrNotFound。
+func (b *Bank) AllLogs(opts FeedOptions) ([]FeedEntry, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	accts := b.sortedAccounts()
+	if opts.Account != "" {
+		a, ok := b.accts[opts.Account]
+		if !ok {
+			return nil, ErrNotFound
+		}
+		accts = []*Account{a}
+	}
+	out := feedOf(accts, opts.Since)
+	if opts.Limit > 0 && len(out) > opts.Limit {
+		out = out[len(out)-opts.Limit:]
+	}
+	if out == nil {
+		out = []FeedEntry{}
+	}
+	return out, nil
+}
+
+// feed 彙整所有帳戶中時間不早於 since 的日誌並依序號排序；須持有 mu（讀鎖即可）。
+func (b *Bank) feed(since time.Time) []FeedEntry {
+	return feedOf(b.sortedAccounts(), since)
+}
+
+// feedOf 彙整 accts 中時間不早於 since 的日誌並依序號（Log.Seq）排序；
+// 須持有 mu（讀鎖即可），期間以讀鎖鎖定 accts。
+// 序號為全行唯一的全序，時間相同（或時鐘倒退）的日誌順序仍可重現（見 seq.go）。
+func feedOf(accts []*Account, since time.Time) []FeedEntry {
+	var out []FeedEntry
+	rlockAccounts(accts)
+	defer runlockAccounts(accts)
+	for _, a := range accts {
+		for _, l := range a.Logs {
+			if l.Time.Before(since) {
+				continue
+			}
+			out = append(out, FeedEntry{AccountID: a.ID, AccountName: a.Name, Log: l})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Seq < out[j].Seq })
+	return out
+}
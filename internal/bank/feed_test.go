@@ -0,0 +1,101 @@
+// internal/bank/feed_test.go
+//
+// 測試全行交易流：以注入的時鐘控制交易時間，驗證時間窗篩選、排序、帳戶篩選與筆數上限。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock 為可手動推進的測試時鐘。
+type fakeClock struct{ t time.Time }
+
+func (c *fakeClock) Now() time.Time          { return c.t }
+func (c *fakeClock) Advance(d time.Duration) { c.t = c.t.Add(d) }
+
+// TestActivityWindow 驗證 Activity 只回傳時間窗內的交易，且依時間排序並附帳戶資訊。
+func TestActivityWindow(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	_, _ = b.Deposit(a1.ID, NewMoney(1, "USD"), "") // 12:00 → 窗外
+	clk.Advance(10 * time.Minute)
+	_, _ = b.Deposit(a2.ID, NewMoney(2, "USD"), "") // 12:10
+	clk.Advance(2 * time.Minute)
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(3, "USD")) // 12:12（雙邊兩筆）
+	clk.Advance(1 * time.Minute)                     // 現在 12:13，5 分鐘窗 = 12:08 起
+
+	got := b.Activity(5*time.Minute, 0)
+	if len(got) != 3 {
+		t.Fatalf("entries=%d want 3: %+v", len(got), got)
+	}
+	if got[0].AccountID != a2.ID || got[0].Amount != 2 || got[0].AccountName != "B" {
+		t.Fatalf("first entry=%+v", got[0])
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Time.Before(got[i-1].Time) {
+			t.Fatalf("not time-ordered at %d", i)
+		}
+	}
+
+	// 上限只保留最新的筆數
+	capped := b.Activity(5*time.Minute, 2)
+	if len(capped) != 2 || capped[0].Type != TxTransfer {
+		t.Fatalf("capped=%+v", capped)
+	}
+}
+
+// TestAllLogs 驗證 AllLogs 跨帳戶合併日誌並依時間排序，且 Account / Since / Limit 篩選正確。
+func TestAllLogs(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	_, _ = b.Deposit(a2.ID, NewMoney(5, "USD"), "") // 12:00
+	clk.Advance(time.Minute)
+	_, _ = b.Withdraw(a1.ID, NewMoney(10, "USD"), "") // 12:01
+	clk.Advance(time.Minute)
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(20, "USD")) // 12:02（雙邊兩筆）
+	clk.Advance(time.Minute)
+	_, _ = b.Deposit(a1.ID, NewMoney(30, "USD"), "") // 12:03
+
+	all, err := b.AllLogs(FeedOptions{})
+	if err != nil || len(all) != 5 {
+		t.Fatalf("all=%d err=%v want 5", len(all), err)
+	}
+	want := []struct {
+		id  string
+		amt int64
+	}{{a2.ID, 5}, {a1.ID, 10}, {a1.ID, 20}, {a2.ID, 20}, {a1.ID, 30}}
+	for i, w := range want {
+		if all[i].AccountID != w.id || all[i].Amount != w.amt {
+			t.Fatalf("entry %d=%+v want account %s amount %d", i, all[i], w.id, w.amt)
+		}
+	}
+
+	// 單一帳戶 + since
+	got, _ := b.AllLogs(FeedOptions{Account: a1.ID, Since: clk.t.Add(-time.Minute)})
+	if len(got) != 2 || got[0].Amount != 20 || got[1].Amount != 30 {
+		t.Fatalf("account+since=%+v", got)
+	}
+	// limit 保留最新
+	got, _ = b.AllLogs(FeedOptions{Limit: 2})
+	if len(got) != 2 || got[0].AccountID != a2.ID || got[1].Amount != 30 {
+		t.Fatalf("limit=%+v", got)
+	}
+	// 無符合時為空切片；帳戶不存在 → ErrNotFound
+	if got, _ = b.AllLogs(FeedOptions{Since: clk.t.Add(time.Hour)}); got == nil || len(got) != 0 {
+		t.Fatalf("empty feed=%v want []", got)
+	}
+	if _, err := b.AllLogs(FeedOptions{Account: "999"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing account err=%v want ErrNotFound", err)
+	}
+}
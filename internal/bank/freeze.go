@@ -0,0 +1,28 @@
+// internal/bank/freeze.go
+//
+// 本檔實作全行凍結（緊急停止）。
+// 事故發生時，營運方可一次凍結所有資金異動（開戶、存款、提款、轉帳、關戶），
+// 查詢類操作不受影響。凍結狀態屬於營運設定，不寫入快照，重啟後恢復為未凍結。
+
+package bank
+
+// FreezeAll 凍結全行：之後的所有異動皆回傳 ErrFrozen，直到 UnfreezeAll。
+func (b *Bank) FreezeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frozen = true
+}
+
+// UnfreezeAll 解除全行凍結。
+func (b *Bank) UnfreezeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.frozen = false
+}
+
+// Frozen 回報目前是否處於全行凍結狀態。
+func (b *Bank) Frozen() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.frozen
+}
@@ -0,0 +1,48 @@
+// internal/bank/freeze_test.go
+//
+// 測試全行凍結：凍結期間所有異動被拒且不改變狀態，查詢照常；解除後恢復。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFreezeAll(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+
+	// 1️⃣ 凍結後所有異動回傳 ErrFrozen
+	b.FreezeAll()
+	if _, err := b.Create("C", 0); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("create: want ErrFrozen, got %v", err)
+	}
+	if _, err := b.Deposit(a1.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("deposit: want ErrFrozen, got %v", err)
+	}
+	if _, err := b.Withdraw(a1.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("withdraw: want ErrFrozen, got %v", err)
+	}
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(1, "USD")); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("transfer: want ErrFrozen, got %v", err)
+	}
+	if _, err := b.Close(a2.ID); !errors.Is(err, ErrFrozen) {
+		t.Fatalf("close: want ErrFrozen, got %v", err)
+	}
+
+	// ✅ 查詢不受影響，且狀態未改變
+	if got := get(t, b, a1.ID); got.Balance != 100 {
+		t.Fatalf("balance=%d want 100", got.Balance)
+	}
+	if n := len(b.List()); n != 2 {
+		t.Fatalf("accounts=%d want 2", n)
+	}
+
+	// 2️⃣ 解除後恢復
+	b.UnfreezeAll()
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(1, "USD")); err != nil {
+		t.Fatalf("transfer after unfreeze: %v", err)
+	}
+}
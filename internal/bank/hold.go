@@ -0,0 +1,158 @@
+// internal/bank/hold.go
+//
+// 本檔實作大額轉帳的審核保留（AML 類控管）。
+// 啟用後，金額超過門檻的轉帳不會立即完成，而是：
+//   1. 配發 TxID、保留來源帳戶的資金（Account.Held），目的帳戶尚未入帳；
+//   2. 等待管理者核准（ApproveTransfer → 實際過帳）或駁回（RejectTransfer → 釋放保留）；
+//   3. 逾期未處理者於下一次相關操作時自動釋放（惰性到期）。
+//
+// 保留中的資金不可再被提款或轉出（餘額檢查以 Balance - Held 為準）。
+// 待審核清單屬於執行期狀態，不寫入快照；重啟後視同全部駁回。
+
+package bank
+
+import "time"
+
+// pendingHold 為一筆待審核轉帳。
+type pendingHold struct {
+	tx      Tx
+	expires time.Time
+}
+
+// SetTransferHold 設定審核門檻與保留期限：金額大於 threshold 的轉帳進入審核。
+// threshold <= 0 代表停用（預設）；ttl <= 0 代表永不逾期。
+func (b *Bank) SetTransferHold(threshold int64, ttl time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.holdOver = threshold
+	b.holdTTL = ttl
+}
+
+// ApproveTransfer 核准待審核轉帳並實際過帳，回傳已提交的交易（Time 為核准時間）。
+// 不存在或已逾期回傳 ErrHoldNotFound；目的帳戶已關閉等錯誤會保留該筆待審核狀態。
+func (b *Bank) ApproveTransfer(txID string) (Tx, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	b.expireHolds()
+	h, ok := b.pending[txID]
+	if !ok {
+		return Tx{}, ErrHoldNotFound
+	}
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
+	from, ok1 := b.accts[h.tx.From]
+	to, ok2 := b.accts[h.tx.To]
+	if !ok1 || !ok2 {
+		return Tx{}, ErrNotFound
+	}
+	// 保留期間任一方遭凍結（見 accountfreeze.go）都不得過帳
+	if err := from.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if err := to.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if err := to.checkCredit(h.tx.Amount); err != nil {
+		return Tx{}, err
+	}
+	fromNote, err := b.fitNote(from, "transfer")
+	if err != nil {
+		return Tx{}, err
+	}
+	toNote, err := b.fitNote(to, "transfer")
+	if err != nil {
+		return Tx{}, err
+	}
+	// 手續費於過帳時收取：本金已保留，只需再確認可動用額度足以支付手續費
+	fee, err := b.prepareFee(from)
+	if err != nil {
+		return Tx{}, err
+	}
+	if !from.canDebit(fee.amount) {
+		return Tx{}, ErrInsufficient
+	}
+
+	b.release(txID)
+	tx := h.tx
+	tx.Time = b.now()
+	tx.Fee = fee.amount
+	b.postTransfer(tx, from, to, fromNote, toNote, fee)
+	return tx, nil
+}
+
+// RejectTransfer 駁回待審核轉帳並釋放保留的資金；不存在或已逾期回傳 ErrHoldNotFound。
+func (b *Bank) RejectTransfer(txID string) error {
+	b.mu.Lock()
+	defer b.unlock()
+	b.expireHolds()
+	if _, ok := b.pending[txID]; !ok {
+		return ErrHoldNotFound
+	}
+	b.release(txID)
+	return nil
+}
+
+// hold 保留來源資金並登記待審核轉帳；須在 mu 保護下呼叫。
+func (b *Bank) hold(tx Tx, from *Account) {
+	from.Held += tx.Amount
+	from.bump()
+	h := &pendingHold{tx: tx}
+	if b.holdTTL > 0 {
+		h.expires = tx.Time.Add(b.holdTTL)
+	}
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	b.pending[tx.ID] = h
+}
+
+// release 移除待審核轉帳並釋放保留的資金；須在 mu 保護下呼叫。
+func (b *Bank) release(txID string) {
+	h := b.pending[txID]
+	delete(b.pending, txID)
+	if from, ok := b.accts[h.tx.From]; ok {
+		from.Held -= h.tx.Amount
+		from.bump()
+	}
+}
+
+// expireHolds 釋放所有已逾期的待審核轉帳與授權保留（見 authhold.go）；須持有 mu 寫鎖。
+func (b *Bank) expireHolds() {
+	if len(b.pending) == 0 && len(b.auths) == 0 {
+		return
+	}
+	now := b.now()
+	for id, h := range b.pending {
+		if !h.expires.IsZero() && !now.Before(h.expires) {
+			b.release(id)
+		}
+	}
+	for _, h := range b.auths {
+		if h.expired(now) {
+			b.releaseAuth(h)
+		}
+	}
+}
+
+// expireHoldsFor 只釋放以 a 為來源、已逾期的待審核轉帳與 a 的授權保留；須在 mu 保護下呼叫（a 已鎖定）。
+// 快速路徑只鎖定參與的帳戶，不能像 expireHolds 一樣動到其他帳戶的保留金額。
+func (b *Bank) expireHoldsFor(a *Account) {
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	if len(b.pending) == 0 && len(b.auths) == 0 {
+		return
+	}
+	now := b.now()
+	for id, h := range b.pending {
+		if h.tx.From == a.ID && !h.expires.IsZero() && !now.Before(h.expires) {
+			delete(b.pending, id)
+			a.Held -= h.tx.Amount
+			a.bump()
+		}
+	}
+	for _, h := range b.auths {
+		if h.Account == a.ID && h.expired(now) {
+			b.releaseAuth(h)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// internal/bank/hold_test.go
+//
+// 測試大額轉帳審核：門檻以下立即完成；門檻以上保留資金、核准後才入帳；駁回與逾期釋放。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTransferHoldBelowThreshold(t *testing.T) {
+	b := NewBank()
+	b.SetTransferHold(100, time.Hour)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	tx, err := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 100})
+	if err != nil || tx.Status != "" {
+		t.Fatalf("tx=%+v err=%v", tx, err)
+	}
+	if get(t, b, a2.ID).Balance != 100 {
+		t.Fatal("below-threshold transfer should complete immediately")
+	}
+}
+
+func TestTransferHoldApprove(t *testing.T) {
+	b := NewBank()
+	b.SetTransferHold(100, time.Hour)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	// 1️⃣ 超過門檻 → 審核中：來源保留、目的未入帳、無日誌
+	tx, err := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 600})
+	if err != nil || tx.Status != TxPendingReview {
+		t.Fatalf("tx=%+v err=%v", tx, err)
+	}
+	src := get(t, b, a1.ID)
+	if src.Balance != 1000 || src.Held != 600 || get(t, b, a2.ID).Balance != 0 {
+		t.Fatalf("src=%+v", src)
+	}
+	if logs, _ := b.Logs(a2.ID); len(logs) != 0 {
+		t.Fatal("held transfer must not write logs")
+	}
+
+	// ❌ 保留中的資金不可再動用
+	if _, err := b.Withdraw(a1.ID, NewMoney(500, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+
+	// 2️⃣ 核准 → 實際過帳，沿用同一個 TxID
+	done, err := b.ApproveTransfer(tx.ID)
+	if err != nil || done.ID != tx.ID || done.Status != "" {
+		t.Fatalf("approve: tx=%+v err=%v", done, err)
+	}
+	src = get(t, b, a1.ID)
+	if src.Balance != 400 || src.Held != 0 || get(t, b, a2.ID).Balance != 600 {
+		t.Fatalf("after approve src=%+v dst=%d", src, get(t, b, a2.ID).Balance)
+	}
+	if found, err := b.FindTx(tx.ID); err != nil || found.Amount != 600 {
+		t.Fatalf("FindTx=%+v err=%v", found, err)
+	}
+	if _, err := b.ApproveTransfer(tx.ID); !errors.Is(err, ErrHoldNotFound) {
+		t.Fatalf("second approve: want ErrHoldNotFound, got %v", err)
+	}
+}
+
+func TestTransferHoldRejectAndExpire(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	b.SetTransferHold(100, time.Hour)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	// 駁回 → 釋放保留
+	tx, _ := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 500})
+	if err := b.RejectTransfer(tx.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, a1.ID); got.Held != 0 || got.Balance != 1000 {
+		t.Fatalf("after reject=%+v", got)
+	}
+
+	// 逾期 → 自動釋放，核准回傳 ErrHoldNotFound
+	tx, _ = b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 500})
+	clk.Advance(2 * time.Hour)
+	if _, err := b.ApproveTransfer(tx.ID); !errors.Is(err, ErrHoldNotFound) {
+		t.Fatalf("want ErrHoldNotFound after expiry, got %v", err)
+	}
+	if got := get(t, b, a1.ID); got.Held != 0 || got.Balance != 1000 || get(t, b, a2.ID).Balance != 0 {
+		t.Fatalf("after expiry=%+v", got)
+	}
+}
@@ -0,0 +1,93 @@
+// internal/bank/idgen.go
+//
+// 本檔定義帳戶 ID 的產生策略（IDGen）：
+//   - CounterIDGen（預設）：原子遞增的十進位 ID "1"、"2"…；目前序號寫入快照的 NextID，還原與 Reset 時一併更新；
+//   - UUIDGen：隨機的 UUID v4，不透露帳戶數量、無法猜測，快照的 NextID 對它沒有意義（寫入 0）。
+//
+// 不論策略為何，帳戶 ID 原樣寫入快照並原樣還原；更換策略不影響既有帳戶。
+// 產生的 ID 若已被使用（例如由 UUID 切回計數器後序號從頭開始），開戶時會略過改取下一個。
+
+package bank
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// IDGen 產生新帳戶的 ID；開戶時於 mu 寫鎖內呼叫。
+type IDGen interface {
+	NewID() string
+}
+
+// CounterIDGen 以原子遞增產生 "1"、"2"… 的十進位 ID（NewBank 的預設策略）。
+type CounterIDGen struct {
+	n atomic.Int64 // 最近一次配發的序號
+}
+
+// NewID 回傳下一個序號。
+func (g *CounterIDGen) NewID() string {
+	return strconv.FormatInt(g.n.Add(1), 10)
+}
+
+// UUIDGen 產生隨機的 UUID v4 ID，例如 "0f8e6b1c-3d2a-4c5e-9f70-1a2b3c4d5e6f"。
+type UUIDGen struct{}
+
+// NewID 回傳新的 UUID v4（RFC 9562）。
+func (UUIDGen) NewID() string {
+	var u [16]byte
+	_, _ = rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40 // 版本 4
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 變體
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// maxIDAttempts 為開戶時連續取得已被使用的 ID 的容許次數，避免有缺陷的產生器造成無窮迴圈。
+const maxIDAttempts = 100
+
+// NewBankWithIDGen 建立以 gen 產生帳戶 ID 的銀行；gen 為 nil 等同 NewBank（CounterIDGen）。
+func NewBankWithIDGen(gen IDGen) *Bank {
+	b := NewBank()
+	if gen != nil {
+		b.ids = gen
+	}
+	return b
+}
+
+// SetIDGen 更換之後開戶使用的 ID 產生器；gen 為 nil 時恢復為從 0 起算的 CounterIDGen。
+// 應於開戶前設定（例如啟動時、Restore 之前）。
+func (b *Bank) SetIDGen(gen IDGen) {
+	if gen == nil {
+		gen = new(CounterIDGen)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ids = gen
+}
+
+// newID 向 ID 產生器取得尚未使用的帳戶 ID（不會是 SystemAccountID）；須持有 mu 寫鎖。
+func (b *Bank) newID() (string, error) {
+	for range maxIDAttempts {
+		id := b.ids.NewID()
+		if _, taken := b.accts[id]; !taken && id != "" && id != SystemAccountID {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("bank: id generator returned %d unusable ids in a row", maxIDAttempts)
+}
+
+// idSeq 回傳計數器策略目前的序號（寫入快照的 NextID）；其他策略回傳 0。
+func (b *Bank) idSeq() int64 {
+	if c, ok := b.ids.(*CounterIDGen); ok {
+		return c.n.Load()
+	}
+	return 0
+}
+
+// setIDSeq 設定計數器策略的序號（還原快照與 Reset 使用）；其他策略不受影響。
+func (b *Bank) setIDSeq(n int64) {
+	if c, ok := b.ids.(*CounterIDGen); ok {
+		c.n.Store(n)
+	}
+}
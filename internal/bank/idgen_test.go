@@ -0,0 +1,96 @@
+// internal/bank/idgen_test.go
+//
+// 測試帳戶 ID 產生策略。
+package bank
+
+import (
+	"regexp"
+	"sync"
+	"testing"
+)
+
+// TestCounterIDGen 驗證預設策略依序產生 "1"、"2"…，且序號隨快照還原。
+func TestCounterIDGen(t *testing.T) {
+	b := NewBank()
+	for _, want := range []string{"1", "2", "3"} {
+		a, err := b.Create("A", 0)
+		if err != nil || a.ID != want {
+			t.Fatalf("id=%q err=%v want %q", a.ID, err, want)
+		}
+	}
+	nb := NewBank()
+	nb.Restore(b.Snapshot())
+	if a, _ := nb.Create("B", 0); a.ID != "4" {
+		t.Fatalf("id after restore=%q want \"4\"", a.ID)
+	}
+}
+
+// TestUUIDGen 驗證 UUID 策略：格式為 UUID v4、並行開戶不重複，且 ID 經快照原樣保留。
+func TestUUIDGen(t *testing.T) {
+	uuidRE := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	b := NewBankWithIDGen(UUIDGen{})
+	const n = 500
+	ids := make(chan string, n)
+	var wg sync.WaitGroup
+	for range n {
+		wg.Go(func() {
+			a, err := b.Create("A", 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			ids <- a.ID
+		})
+	}
+	wg.Wait()
+	close(ids)
+	seen := make(map[string]bool, n)
+	for id := range ids {
+		if !uuidRE.MatchString(id) {
+			t.Fatalf("id %q is not a UUID v4", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %q", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != n {
+		t.Fatalf("got %d ids want %d", len(seen), n)
+	}
+
+	snap := b.Snapshot()
+	if snap.NextID != 0 {
+		t.Fatalf("NextID=%d want 0 for UUID ids", snap.NextID)
+	}
+	nb := NewBankWithIDGen(UUIDGen{})
+	nb.Restore(snap)
+	for id := range seen {
+		if a, err := nb.Get(id); err != nil || a.Balance != 1 {
+			t.Fatalf("restored %s=%+v err=%v", id, a, err)
+		}
+	}
+}
+
+// fixedIDGen 依序回傳預先給定的 ID，供測試碰撞處理。
+type fixedIDGen struct{ ids []string }
+
+func (g *fixedIDGen) NewID() string {
+	id := g.ids[0]
+	if len(g.ids) > 1 {
+		g.ids = g.ids[1:]
+	}
+	return id
+}
+
+// TestIDGenSkipsTakenIDs 驗證產生器回傳已使用的 ID 時改取下一個；始終無可用 ID 時開戶失敗。
+func TestIDGenSkipsTakenIDs(t *testing.T) {
+	b := NewBankWithIDGen(&fixedIDGen{ids: []string{"x", "x", SystemAccountID, "y"}})
+	first, _ := b.Create("A", 0)
+	second, err := b.Create("B", 0)
+	if err != nil || first.ID != "x" || second.ID != "y" {
+		t.Fatalf("ids=%q,%v err=%v want x,y", first.ID, second, err)
+	}
+	if _, err := b.Create("C", 0); err == nil {
+		t.Fatal("want error when the generator only returns taken ids")
+	}
+}
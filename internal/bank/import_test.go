@@ -0,0 +1,77 @@
+// internal/bank/import_test.go
+//
+// 測試以快照取代帳本的 Import。
+package bank
+
+import (
+	"errors"
+	"testing"
+
+	"banking/internal/storage"
+)
+
+// TestImport 驗證 Import 的版本檢查、非空帳本保護與 force 覆蓋。
+func TestImport(t *testing.T) {
+	src := NewBank()
+	a, _ := src.Create("A", 100)
+	snap := src.Snapshot()
+
+	dst := NewBank()
+	bad := snap
+	bad.Meta.Version = 0
+	if err := dst.Import(bad, false); !errors.Is(err, ErrSnapshotVersion) {
+		t.Fatalf("err=%v want ErrSnapshotVersion", err)
+	}
+	if err := dst.Import(snap, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := dst.Get(a.ID); err != nil || got.Balance != 100 {
+		t.Fatalf("imported=%+v err=%v", got, err)
+	}
+
+	_, _ = dst.Create("B", 1)
+	if err := dst.Import(snap, false); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("err=%v want ErrNotEmpty", err)
+	}
+	if len(dst.List()) != 2 {
+		t.Fatalf("rejected import changed the ledger: %d accounts", len(dst.List()))
+	}
+	if err := dst.Import(snap, true); err != nil {
+		t.Fatal(err)
+	}
+	if len(dst.List()) != 1 {
+		t.Fatalf("forced import: %d accounts want 1", len(dst.List()))
+	}
+}
+
+// TestImportSkipsOldJournal 驗證匯入後崩潰重啟：journal 中匯入前的項目（即使 TxID 序號大於匯入快照的 next_tx_id）
+// 不會被重播到匯入的帳本，匯入後的項目照常重播。
+func TestImportSkipsOldJournal(t *testing.T) {
+	src := NewBank()
+	a, _ := src.Create("A", 100)
+	snap := src.Snapshot() // next_tx_id 為 0
+
+	dst := NewBank()
+	d, _ := dst.Create("D", 500) // 與匯入帳戶同為 ID "1"
+	if d.ID != a.ID {
+		t.Fatalf("ids %q %q", d.ID, a.ID)
+	}
+	var entries []storage.JournalEntry
+	dst.SetJournal(func(e storage.JournalEntry) { entries = append(entries, e) })
+	_, _ = dst.Withdraw(d.ID, NewMoney(30, "USD"), "")
+	_, _ = dst.Withdraw(d.ID, NewMoney(20, "USD"), "")
+	if err := dst.Import(snap, true); err != nil {
+		t.Fatal(err)
+	}
+	after := dst.Snapshot()
+	_, _ = dst.Deposit(a.ID, NewMoney(5, "USD"), "")
+
+	fresh := NewBank()
+	fresh.Restore(after)
+	if n, err := fresh.ReplayJournal(entries); n != 1 || err != nil {
+		t.Fatalf("replayed=%d err=%v, want 1", n, err)
+	}
+	if got := get(t, fresh, a.ID).Balance; got != 105 {
+		t.Fatalf("balance=%d want 105", got)
+	}
+}
@@ -0,0 +1,86 @@
+// internal/bank/interest.go
+//
+// 本檔實作週期性計息：帳戶設定 InterestRateBps（每個計息週期的利率，單位為基點，1 bp = 0.01%）後，
+// 每次呼叫 AccrueInterest 即由系統帳戶支付一期利息（交易類型 TxInterest，見 system.go）。
+//   - 利息 = floor(餘額 × 利率 / 10000)，一律無條件捨去至最小貨幣單位，結果與呼叫順序、平台無關；
+//     不足 1 個單位的利息不入帳，也不累積到下一期；
+//   - 只有餘額為正、狀態為 active、未凍結且幣別未暫停的帳戶計息（透支中的負餘額不計息也不收息）；
+//   - 同一次計息在寫鎖內完成，所有帳戶依同一時點的餘額計算，不會與轉帳交錯；
+//   - 每筆利息與 PayInterest 相同經由 applyLocked 提交，因此會寫入 journal。
+//
+// 計息週期由呼叫端決定（見 cmd/server 的 BANK_INTEREST_INTERVAL）；本套件不啟動任何排程。
+
+package bank
+
+import (
+	"errors"
+	"fmt"
+)
+
+// bpsDenominator 為基點換算比例：利率 10000 bps = 100%。
+const bpsDenominator = 10000
+
+// SetInterestRate 設定帳戶每個計息週期的利率（基點，0–10000，0 為不計息）；
+// 超出範圍回傳 ErrBadAmount，帳戶不存在回傳 ErrNotFound，系統帳戶回傳 ErrSystemAccount。
+func (b *Bank) SetInterestRate(id string, bps int) error {
+	if bps < 0 || bps > bpsDenominator {
+		return ErrBadAmount
+	}
+	if id == SystemAccountID {
+		return ErrSystemAccount
+	}
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return ErrFrozen
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.InterestRateBps = bps
+	a.bump()
+	return nil
+}
+
+// AccrueInterest 對所有符合條件的帳戶入帳一期利息，依帳戶 ID 順序回傳已提交的交易。
+// 系統帳戶不存在時回傳 ErrNotFound、銀行凍結時回傳 ErrFrozen，皆不入帳任何利息；
+// 個別帳戶入帳失敗（例如 ErrOverflow、ErrNoteBudget）不影響其他帳戶，錯誤以 errors.Join 合併回傳。
+func (b *Bank) AccrueInterest() ([]Tx, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return nil, ErrFrozen
+	}
+	if _, ok := b.accts[SystemAccountID]; !ok {
+		return nil, fmt.Errorf("%w: system account", ErrNotFound)
+	}
+	var txs []Tx
+	var errs []error
+	for _, a := range b.sortedAccounts() {
+		if a.ID == SystemAccountID || a.checkActive() != nil || b.disabledCcy[a.Currency] {
+			continue
+		}
+		amt := interestFor(a.Balance, a.InterestRateBps)
+		if amt <= 0 {
+			continue
+		}
+		tx, err := b.applyLocked(Op{Type: TxInterest, Account: a.ID, Amount: amt})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", a.ID, err))
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	return txs, errors.Join(errs...)
+}
+
+// interestFor 回傳餘額 balance 以 bps 計息一期的利息（無條件捨去）；餘額或利率不為正時回傳 0。
+// 先拆分餘額再相乘，且利率以 10000 bps 為上限（快照中的值未經 SetInterestRate 檢查），任何 int64 餘額都不會溢位。
+func interestFor(balance int64, bps int) int64 {
+	if balance <= 0 || bps <= 0 {
+		return 0
+	}
+	r := int64(min(bps, bpsDenominator))
+	return balance/bpsDenominator*r + balance%bpsDenominator*r/bpsDenominator
+}
@@ -0,0 +1,143 @@
+// internal/bank/interest_test.go
+//
+// 測試週期性計息：不同利率的利息金額與捨去規則、零利率與不符條件的帳戶不受影響、
+// 利率設定的檢查，以及利率隨快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestInterestFor 驗證利息一律無條件捨去，且極大餘額不會溢位。
+func TestInterestFor(t *testing.T) {
+	cases := []struct {
+		balance int64
+		bps     int
+		want    int64
+	}{
+		{10_000, 100, 100}, // 1%
+		{12_345, 250, 308}, // 308.625 → 308
+		{9_999, 1, 0},      // 0.9999 → 0
+		{199, 50, 0},       // 0.995 → 0（不四捨五入）
+		{-5_000, 100, 0},   // 負餘額不計息
+		{10_000, 0, 0},
+		{10_000, 20_000, 10_000}, // 超過 10000 bps 以 100% 計
+		{1<<63 - 1, 10_000, 1<<63 - 1},
+	}
+	for _, c := range cases {
+		if got := interestFor(c.balance, c.bps); got != c.want {
+			t.Errorf("interestFor(%d, %d)=%d want %d", c.balance, c.bps, got, c.want)
+		}
+	}
+}
+
+// TestAccrueInterest 驗證：
+// 1️⃣ 兩種利率的帳戶各自入帳正確利息，日誌為 interest / in，對手為系統帳戶；
+// 2️⃣ 零利率、凍結與已關閉的帳戶不受影響（餘額、日誌、版本皆不變）；
+// 3️⃣ 利息由系統帳戶支出，全行總額守恆且 Verify 一致；第二期以新餘額計息。
+func TestAccrueInterest(t *testing.T) {
+	b := NewBank()
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 10_000) // 1%
+	c, _ := b.Create("C", 12_345) // 2.5%
+	z, _ := b.Create("Z", 50_000) // 0%
+	f, _ := b.Create("F", 10_000) // 凍結
+	x, _ := b.Create("X", 10_000) // 已關閉
+	for id, bps := range map[string]int{a.ID: 100, c.ID: 250, f.ID: 100, x.ID: 100} {
+		if err := b.SetInterestRate(id, bps); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := b.SetFrozen(f.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(x.ID, NewMoney(10_000, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Close(x.ID); err != nil {
+		t.Fatal(err)
+	}
+	before := map[string]*Account{}
+	for _, id := range []string{z.ID, f.ID, x.ID} {
+		before[id] = get(t, b, id)
+	}
+
+	txs, err := b.AccrueInterest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) != 2 || txs[0].To != a.ID || txs[0].Amount != 100 || txs[1].To != c.ID || txs[1].Amount != 308 {
+		t.Fatalf("txs=%+v", txs)
+	}
+	if got := get(t, b, a.ID).Balance; got != 10_100 {
+		t.Fatalf("A balance=%d want 10100", got)
+	}
+	if got := get(t, b, c.ID).Balance; got != 12_653 {
+		t.Fatalf("C balance=%d want 12653", got)
+	}
+	logs, _ := b.Logs(c.ID)
+	if l := logs[len(logs)-1]; l.Type != TxInterest || l.Note != "interest" || l.Direction != "in" || l.Amount != 308 || l.CounterID != SystemAccountID {
+		t.Fatalf("interest log=%+v", l)
+	}
+	for id, want := range before {
+		got := get(t, b, id)
+		if got.Balance != want.Balance || len(got.Logs) != len(want.Logs) || got.Version != want.Version {
+			t.Fatalf("account %s changed: %+v want %+v", id, got, want)
+		}
+	}
+	if got := get(t, b, SystemAccountID).Balance; got != -408 {
+		t.Fatalf("system balance=%d want -408", got)
+	}
+
+	if _, err := b.AccrueInterest(); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, a.ID).Balance; got != 10_201 { // 10100 × 1% = 101
+		t.Fatalf("A balance after second period=%d want 10201", got)
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestAccrueInterestRequiresSystemAccount 驗證系統帳戶不存在時不入帳任何利息。
+func TestAccrueInterestRequiresSystemAccount(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 10_000)
+	_ = b.SetInterestRate(a.ID, 100)
+	if _, err := b.AccrueInterest(); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if got := get(t, b, a.ID).Balance; got != 10_000 {
+		t.Fatalf("balance=%d want 10000", got)
+	}
+}
+
+// TestSetInterestRate 驗證利率範圍檢查、不存在的帳戶與系統帳戶，以及利率隨快照保存。
+func TestSetInterestRate(t *testing.T) {
+	b := NewBank()
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 0)
+	for _, bps := range []int{-1, 10_001} {
+		if err := b.SetInterestRate(a.ID, bps); !errors.Is(err, ErrBadAmount) {
+			t.Fatalf("rate %d: want ErrBadAmount, got %v", bps, err)
+		}
+	}
+	if err := b.SetInterestRate("nope", 100); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if err := b.SetInterestRate(SystemAccountID, 100); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("want ErrSystemAccount, got %v", err)
+	}
+	if err := b.SetInterestRate(a.ID, 125); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewBank()
+	r.Restore(b.Snapshot())
+	if got := get(t, r, a.ID).InterestRateBps; got != 125 {
+		t.Fatalf("restored rate=%d want 125", got)
+	}
+}
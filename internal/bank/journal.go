@@ -0,0 +1,103 @@
+// internal/bank/journal.go
+//
+// 本檔將成功提交的操作寫入 journal（見 storage.Journal），並於啟動時重播。
+//   - 記錄點在 applyLocked：所有存款、提款、轉帳、利息與手續費都經由此處；
+//     回呼在仍持有相關帳戶鎖時呼叫，因此同一帳戶的項目順序與實際提交順序一致，
+//     不同帳戶之間的操作互不影響，依 journal 順序重播即可得到相同結果。
+//   - 授權保留的建立與釋放不是交易，由 Hold / Release 另行記錄（沒有 TxID，重播時以保留 ID 判斷，見 authhold.go）；
+//     請款為 TxCapture 交易，與其他操作相同經由 applyLocked 記錄；
+//   - 轉帳並開戶會建立帳戶，不經由 applyLocked，由 TransferOrCreate 另行記錄（見 transfercreate.go）；
+//   - 提款去重命中（未再扣款）、待審核轉帳（待審核清單不持久化）不記錄；
+//     atomic 批次於整批提交後才記錄，回滾的操作不會出現在 journal。
+//   - 重播略過 TxID 序號不大於目前 nextTx 的項目（已包含在快照中），並沿用原本的時間與 TxID。
+//   - 每個項目帶有寫入時的帳本世代（epoch）。Reset 清空帳本時遞增世代，交易與帳戶序號雖然歸零，
+//     清空前的項目也不會在崩潰重啟後被重播到沿用相同 ID 的新帳戶上；
+//     反之，清空後的快照尚未寫入就崩潰時，只會重播清空前的項目，得到清空前的帳本。
+
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"banking/internal/storage"
+)
+
+// SetJournal 設定操作提交後的 journal 回呼；nil 為停用（預設）。
+// 回呼於持有帳戶鎖時同步呼叫，不得再呼叫 Bank 的方法。
+func (b *Bank) SetJournal(fn func(storage.JournalEntry)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.journal = fn
+}
+
+// record 將已提交的 op 寫入 journal；須在 mu 保護下呼叫。
+func (b *Bank) record(op Op, tx Tx) {
+	if tx.Status == TxPendingReview {
+		return
+	}
+	b.appendJournal(storage.JournalEntry{
+		TxID: tx.ID, Time: tx.Time, Type: op.Type,
+		Account: op.Account, From: op.From, To: op.To, Amount: op.Amount, RequestID: op.RequestID, Ref: op.Ref, Note: op.Note,
+	})
+}
+
+// appendJournal 以目前的帳本世代寫入一筆 journal 項目；未設定 journal 時不做任何事。須在 mu 保護下呼叫。
+func (b *Bank) appendJournal(e storage.JournalEntry) {
+	if b.journal == nil {
+		return
+	}
+	e.Epoch = b.epoch
+	b.journal(e)
+}
+
+// ReplayJournal 將 journal 項目依序重新套用到目前狀態（通常是剛 Restore 的快照），回傳實際套用的筆數。
+// 已包含在快照中的項目（TxID 序號 <= 快照的 next_tx_id）與其他世代的項目會略過；
+// 重播時交易時間採用項目中記錄的時間，TxID 盡量沿用原值（只會往前推進，不會重複配發）。
+// 個別項目失敗不會中止重播，所有錯誤合併後回傳。
+func (b *Bank) ReplayJournal(entries []storage.JournalEntry) (int, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	now, journal := b.now, b.journal
+	defer func() { b.now, b.journal = now, journal }()
+	b.journal = nil // 重播的操作已在 journal 中，不再重複寫入
+
+	base := b.nextTx
+	applied := 0
+	var errs []error
+	for _, e := range entries {
+		if e.Type == journalHold || e.Type == journalRelease {
+			if e.Epoch != b.epoch {
+				continue
+			}
+			b.now = func() time.Time { return e.Time }
+			ok, err := b.replayAuth(e)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("replay %s %s: %w", e.Type, e.Ref, err))
+			} else if ok {
+				applied++
+			}
+			continue
+		}
+		seq := txSeq(e.TxID)
+		if e.Epoch != b.epoch || seq <= base {
+			continue
+		}
+		b.nextTx = max(b.nextTx, seq-1)
+		b.now = func() time.Time { return e.Time }
+		var err error
+		if e.Type == journalTransferCreate {
+			_, _, err = b.transferOrCreate(e.From, e.To, e.Name, e.Amount)
+		} else {
+			op := Op{Type: e.Type, Account: e.Account, From: e.From, To: e.To, Amount: e.Amount, RequestID: e.RequestID, Ref: e.Ref, Note: e.Note}
+			_, err = b.applyLocked(op)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("replay %s: %w", e.TxID, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+// internal/bank/journal_test.go
+//
+// 崩潰復原：快照之後的操作只存在 journal 中，重播到由快照還原的新銀行後，
+// 狀態（餘額、日誌、TxID、交易序號）須與直接執行這些操作完全相同。
+
+package bank
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"banking/internal/storage"
+)
+
+func TestJournalReplayMatchesDirectOps(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(func() time.Time { clk.Advance(time.Second); return clk.Now() })
+	b.EnsureSystemAccount()
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 500)
+	_, _ = b.Deposit(a1.ID, NewMoney(1, "USD"), "")
+	snap := b.Snapshot() // 崩潰前最後一份快照
+
+	path := filepath.Join(t.TempDir(), "journal.ndjson")
+	j, err := storage.OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	b.SetJournal(func(e storage.JournalEntry) {
+		if err := j.Append(e); err != nil {
+			t.Errorf("append: %v", err)
+		}
+	})
+
+	// 1️⃣ 快照之後的各種操作（含失敗、去重命中與回滾的批次，這些不應寫入 journal）
+	_, _ = b.Deposit(a1.ID, NewMoney(200, "USD"), "")
+	_, _ = b.Withdraw(a2.ID, NewMoney(100, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(300, "USD"))
+	_, _ = b.Apply(Op{Type: TxWithdraw, Account: a2.ID, Amount: 50, RequestID: "r1"})
+	_, _ = b.Apply(Op{Type: TxWithdraw, Account: a2.ID, Amount: 50, RequestID: "r1"}) // 去重命中
+	_, _ = b.Withdraw(a2.ID, NewMoney(1_000_000, "USD"), "")                          // ❌ 餘額不足
+	_, _ = b.ApplyBatch([]Op{{Type: TxDeposit, Account: a1.ID, Amount: 5}, {Type: TxWithdraw, Account: a1.ID, Amount: 1_000_000}}, true)
+	_, _ = b.ApplyBatch([]Op{{Type: TxDeposit, Account: a1.ID, Amount: 7}, {Type: TxDeposit, Account: a2.ID, Amount: 8}}, true)
+	_, _ = b.PayInterest(a1.ID, 3)
+	back, _ := b.Apply(Op{Type: TxTransfer, From: a2.ID, To: a1.ID, Amount: 10})
+	_ = b.Reverse(back.ID)
+	_ = b.Reverse(back.ID) // ❌ 已沖正
+
+	entries, err := storage.ReadJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 9 {
+		t.Fatalf("journal entries=%d want 9: %+v", len(entries), entries)
+	}
+
+	// 2️⃣ 模擬重啟：由快照還原後重播 journal
+	fresh := NewBank()
+	fresh.Restore(snap)
+	n, err := fresh.ReplayJournal(entries)
+	if err != nil || n != 9 {
+		t.Fatalf("replayed=%d err=%v", n, err)
+	}
+	if want, got := b.Snapshot(), fresh.Snapshot(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("replayed state differs:\nwant %+v\ngot  %+v", want, got)
+	}
+
+	// 3️⃣ 已包含在狀態中的項目不會重複套用
+	if n, err := fresh.ReplayJournal(entries); n != 0 || err != nil {
+		t.Fatalf("second replay applied=%d err=%v, want 0", n, err)
+	}
+}
+
+// TestJournalReplayAcrossReset 驗證 Reset 之後崩潰重啟：
+// 1️⃣ 清空後的快照已寫入：只重播清空後的項目，清空前的操作不會落到沿用相同 ID 的新帳戶；
+// 2️⃣ 清空後的快照尚未寫入：只重播清空前的項目，得到清空前的帳本。
+func TestJournalReplayAcrossReset(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 100)
+	before := b.Snapshot()
+	var entries []storage.JournalEntry
+	b.SetJournal(func(e storage.JournalEntry) { entries = append(entries, e) })
+
+	_, _ = b.Deposit(a.ID, NewMoney(40, "USD"), "")
+	_, _ = b.Withdraw(a.ID, NewMoney(10, "USD"), "")
+	b.Reset()
+	n, _ := b.Create("N", 0) // 沿用 ID "1"
+	if n.ID != a.ID {
+		t.Fatalf("id after reset=%q want %q", n.ID, a.ID)
+	}
+	after := b.Snapshot() // 清空後（含新帳戶）的快照
+	_, _ = b.Deposit(n.ID, NewMoney(7, "USD"), "")
+
+	fresh := NewBank()
+	fresh.Restore(after)
+	if applied, err := fresh.ReplayJournal(entries); applied != 1 || err != nil {
+		t.Fatalf("replay after reset applied=%d err=%v, want 1", applied, err)
+	}
+	if got := get(t, fresh, n.ID).Balance; got != 7 {
+		t.Fatalf("balance=%d want 7 (pre-reset entries must not replay)", got)
+	}
+
+	old := NewBank()
+	old.Restore(before)
+	if applied, err := old.ReplayJournal(entries); applied != 2 || err != nil {
+		t.Fatalf("replay before reset applied=%d err=%v, want 2", applied, err)
+	}
+	if got := get(t, old, a.ID).Balance; got != 130 {
+		t.Fatalf("balance=%d want 130", got)
+	}
+}
@@ -0,0 +1,34 @@
+// internal/bank/limits.go
+//
+// 本檔集中管理交易金額相關的限制設定。
+//   - 最低轉帳金額：避免大量微額轉帳灌爆日誌與造成不成比例的負擔。
+//   - 最低存提款金額：可選，預設不限制。
+//
+// 未設定時沿用原本規則（金額 > 0 即可，相當於最低 1）。
+
+package bank
+
+import "fmt"
+
+// SetMinTransfer 設定最低轉帳金額；min <= 1 代表沿用預設（> 0 即可）。
+func (b *Bank) SetMinTransfer(min int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.minTransfer = min
+}
+
+// SetMinDepositWithdraw 設定最低存款 / 提款金額；min <= 1 代表沿用預設。
+func (b *Bank) SetMinDepositWithdraw(min int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.minCash = min
+}
+
+// checkMin 檢查金額是否達到下限；須在 mu 保護下呼叫。
+// 錯誤包裝 ErrBelowMinimum 並附上目前的下限，方便客戶端顯示。
+func checkMin(amt, min int64) error {
+	if min > 1 && amt < min {
+		return fmt.Errorf("%w: minimum is %d", ErrBelowMinimum, min)
+	}
+	return nil
+}
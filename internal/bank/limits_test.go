@@ -0,0 +1,41 @@
+// internal/bank/limits_test.go
+//
+// 測試交易金額限制設定。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestMinTransfer 驗證最低轉帳金額：低於下限被拒（ErrBelowMinimum），達到下限則成功。
+func TestMinTransfer(t *testing.T) {
+	b := NewBank()
+	b.SetMinTransfer(100)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(50, "USD")); !errors.Is(err, ErrBelowMinimum) {
+		t.Fatalf("want ErrBelowMinimum, got %v", err)
+	}
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(100, "USD")); err != nil {
+		t.Fatalf("transfer at minimum: %v", err)
+	}
+	// 未設定存提款下限時，小額存款不受影響
+	if _, err := b.Deposit(a2.ID, NewMoney(1, "USD"), ""); err != nil {
+		t.Fatalf("small deposit: %v", err)
+	}
+	// 0 仍然回傳 ErrBadAmount
+	if err := b.Transfer(a1.ID, a2.ID, NewMoney(0, "USD")); !errors.Is(err, ErrBadAmount) {
+		t.Fatalf("want ErrBadAmount, got %v", err)
+	}
+
+	b.SetMinDepositWithdraw(10)
+	if _, err := b.Withdraw(a2.ID, NewMoney(5, "USD"), ""); !errors.Is(err, ErrBelowMinimum) {
+		t.Fatalf("want ErrBelowMinimum, got %v", err)
+	}
+	if get(t, b, a2.ID).Balance != 101 {
+		t.Fatalf("balance=%d want 101", get(t, b, a2.ID).Balance)
+	}
+}
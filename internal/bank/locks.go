@@ -0,0 +1,100 @@
+// internal/bank/locks.go
+//
+// 本檔實作帳戶層級的細粒度鎖，讓不同帳戶上的存提款與轉帳可以平行執行。
+//
+// 鎖的層級（取鎖順序由上而下，不可反向）：
+//  1. Bank.mu（sync.RWMutex）：保護帳戶表與營運設定。
+//     - 寫鎖：開戶、刪除、關閉、改名、還原、快照、批次、設定變更等「結構性」或全行操作；
+//       持有寫鎖時不會有其他人持有任何帳戶鎖，可直接存取所有狀態。
+//     - 讀鎖：只涉及少數帳戶的快速路徑（Deposit、Withdraw、Transfer、Apply、Get、Logs…）。
+//  2. Account.mu（sync.RWMutex）：快速路徑依 ID 排序（lessID）鎖定所有參與的帳戶，避免轉帳雙向互鎖；
+//     轉帳收手續費、利息與手續費操作也會一併鎖定系統帳戶。
+//     查詢（Get、List、Logs…）只取讀鎖，彼此不互相阻塞；跨帳戶的查詢同樣依 ID 順序取鎖。
+//  3. Bank.txMu / Bank.viewMu：葉節點鎖，分別保護交易序號、TxID 索引與待審核清單，
+//     以及唯讀檢視的發布；持有期間不再取其他鎖。
+//
+// 因此核心邏輯中「須在 mu 保護下呼叫」的意思是：持有 mu 寫鎖，或持有 mu 讀鎖並鎖定了所有參與的帳戶。
+//
+// 帳戶鎖一律以阻塞的 Lock 依上述順序取得，不使用 TryLock：固定順序已排除死結，
+// 轉帳不會因「無法同時取得兩把鎖」而失敗，因此沒有取鎖重試，也沒有 ErrBusy 之類的錯誤；
+// 競爭時只會等待。高度競爭下的行為由 TestHighContentionTransfers 驗證。
+
+package bank
+
+import (
+	"slices"
+	"sort"
+)
+
+// lockAccounts 依 ID 排序鎖定（寫鎖）ids 中存在的帳戶，回傳已鎖定的帳戶；須持有 mu 讀鎖。
+// 不存在的帳戶交由核心邏輯回傳 ErrNotFound。
+func (b *Bank) lockAccounts(ids ...string) []*Account {
+	accts := b.accountsByID(ids)
+	for _, a := range accts {
+		a.mu.Lock()
+	}
+	return accts
+}
+
+// rlockAccounts 以讀鎖鎖定 accts（須已依 ID 排序，例如 sortedAccounts 的結果）；須持有 mu 讀鎖。
+func rlockAccounts(accts []*Account) {
+	for _, a := range accts {
+		a.mu.RLock()
+	}
+}
+
+// runlockAccounts 解除 rlockAccounts 取得的讀鎖。
+func runlockAccounts(accts []*Account) {
+	for _, a := range accts {
+		a.mu.RUnlock()
+	}
+}
+
+// accountsByID 回傳 ids 中存在的帳戶（去除重複、依 ID 排序）；須持有 mu。
+func (b *Bank) accountsByID(ids []string) []*Account {
+	accts := make([]*Account, 0, len(ids))
+	for _, id := range ids {
+		if a, ok := b.accts[id]; ok && !slices.Contains(accts, a) {
+			accts = append(accts, a)
+		}
+	}
+	sort.Slice(accts, func(i, j int) bool { return lessID(accts[i].ID, accts[j].ID) })
+	return accts
+}
+
+// unlockAccounts 結束快速路徑的臨界區：若啟用唯讀檢視則先發布這些帳戶的最新狀態，再逐一解鎖。
+// 會異動帳戶的快速路徑以 defer b.unlockAccounts(locked) 搭配 lockAccounts 使用。
+func (b *Bank) unlockAccounts(accts []*Account) {
+	if b.cow {
+		b.publishAccounts(accts)
+	}
+	for _, a := range accts {
+		a.mu.Unlock()
+	}
+}
+
+// opAccounts 回傳 op 會讀寫的帳戶 ID（供 lockAccounts 使用）。
+func (b *Bank) opAccounts(op Op) []string {
+	switch op.Type {
+	case TxDeposit, TxWithdraw:
+		return []string{op.Account}
+	case TxTransfer:
+		if b.transferFee > 0 {
+			return []string{op.From, op.To, SystemAccountID}
+		}
+		return []string{op.From, op.To}
+	case TxInterest, TxFee:
+		return []string{op.Account, SystemAccountID}
+	case TxReversal:
+		b.txMu.Lock()
+		defer b.txMu.Unlock()
+		return slices.Clone(b.txIndex[op.Ref])
+	case TxCapture:
+		if h := b.authHold(op.Ref); h != nil {
+			return []string{h.Account}
+		}
+		return nil
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+// internal/bank/locks_test.go
+//
+// 帳戶層級鎖的測試：反向轉帳不互鎖、手續費（鎖定系統帳戶）下資金守恆、
+// 唯讀檢視在並行增量發布後仍與帳本一致、查詢之間不互相阻塞，以及平行吞吐量的基準測試。
+
+package bank
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestOppositeTransfersNoDeadlock 多個 goroutine 同時在同一組帳戶間雙向轉帳（含手續費），
+// 驗證依 ID 順序取鎖不會互鎖，且全行總額守恆（手續費只是移入系統帳戶）。
+func TestOppositeTransfersNoDeadlock(t *testing.T) {
+	b := NewBankWithFee(1)
+	b.EnsureSystemAccount()
+	a1, _ := b.Create("A", 10000)
+	a2, _ := b.Create("B", 10000)
+	a3, _ := b.Create("C", 10000)
+	ids := []string{a1.ID, a2.ID, a3.ID}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 12; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				from, to := ids[(w+i)%3], ids[(w+i+1+w%2)%3]
+				_ = b.Transfer(from, to, NewMoney(5, "USD"))
+				_, _ = b.Deposit(from, NewMoney(1, "USD"), "")
+			}
+		}()
+	}
+	wg.Wait()
+
+	var deposits int64
+	for _, id := range ids {
+		for _, l := range get(t, b, id).Logs {
+			if l.Type == TxDeposit {
+				deposits += l.Amount
+			}
+		}
+	}
+	if got, want := b.TotalBalance(), int64(30000)+deposits; got != want {
+		t.Fatalf("total=%d want %d", got, want)
+	}
+}
+
+// TestParallelPublishKeepsView 啟用唯讀檢視時，並行寫入不同帳戶後，
+// 檢視中的每個帳戶都須反映最後一次異動（增量發布不得互相覆蓋）。
+func TestParallelPublishKeepsView(t *testing.T) {
+	b := NewBank()
+	b.SetReadSnapshot(true)
+	ids := make([]string, 8)
+	for i := range ids {
+		a, _ := b.Create("acct", 0)
+		ids[i] = a.ID
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				_, _ = b.Deposit(id, NewMoney(1, "USD"), "")
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, a := range b.List() {
+		if a.Balance != 100 || len(a.Logs) != 100 {
+			t.Fatalf("view of %s: balance=%d logs=%d, want 100", a.ID, a.Balance, len(a.Logs))
+		}
+	}
+}
+
+// TestReadsDoNotBlockEachOther 在持有銀行與帳戶讀鎖（模擬進行中的長查詢）時，
+// Get / List / Logs 仍須立即完成；存款則須等到讀鎖釋放。
+func TestReadsDoNotBlockEachOther(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 100)
+
+	b.mu.RLock()
+	b.accts[a.ID].mu.RLock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = b.Get(a.ID)
+		_ = b.List()
+		_, _ = b.Logs(a.ID)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reads blocked by another reader")
+	}
+
+	deposited := make(chan struct{})
+	go func() {
+		defer close(deposited)
+		_, _ = b.Deposit(a.ID, NewMoney(1, "USD"), "")
+	}()
+	select {
+	case <-deposited:
+		t.Fatal("deposit did not wait for the account read lock")
+	case <-time.After(20 * time.Millisecond):
+	}
+	b.accts[a.ID].mu.RUnlock()
+	b.mu.RUnlock()
+	<-deposited
+	if got := get(t, b, a.ID).Balance; got != 101 {
+		t.Fatalf("balance=%d want 101", got)
+	}
+}
+
+// getSink 保存基準測試的查詢結果，避免編譯器將拷貝最佳化掉。
+var getSink atomic.Pointer[Account]
+
+// benchmarkParallelGets 平行查詢同一組帳戶。
+// exclusive 為 true 時每次查詢改持 mu 寫鎖（相當於改用 RWMutex 之前的行為）作為對照組。
+func benchmarkParallelGets(bm *testing.B, exclusive bool) {
+	b := NewBank()
+	ids := make([]string, 16)
+	for i := range ids {
+		a, _ := b.Create("acct", 100)
+		ids[i] = a.ID
+	}
+
+	bm.ResetTimer()
+	bm.RunParallel(func(pb *testing.PB) {
+		var last *Account
+		for i := 0; pb.Next(); i++ {
+			id := ids[i%len(ids)]
+			if exclusive {
+				b.mu.Lock()
+				cp := *b.accts[id]
+				b.mu.Unlock()
+				last = &cp
+				continue
+			}
+			last, _ = b.Get(id)
+		}
+		getSink.Store(last)
+	})
+}
+
+func BenchmarkParallelGetRLock(bm *testing.B)     { benchmarkParallelGets(bm, false) }
+func BenchmarkParallelGetExclusive(bm *testing.B) { benchmarkParallelGets(bm, true) }
+
+// benchmarkParallelTransfers 讓每個 goroutine 在自己的一對帳戶間來回轉帳。
+// serial 為 true 時改經 ApplyBatch（持 mu 寫鎖，相當於舊的全域鎖）作為對照組。
+func benchmarkParallelTransfers(bm *testing.B, serial bool) {
+	b := NewBank()
+	var (
+		mu    sync.Mutex
+		pairs [][2]string
+	)
+	for i := 0; i < 256; i++ {
+		a1, _ := b.Create(fmt.Sprintf("a%d", i), 1_000_000)
+		a2, _ := b.Create(fmt.Sprintf("b%d", i), 1_000_000)
+		pairs = append(pairs, [2]string{a1.ID, a2.ID})
+	}
+
+	bm.ResetTimer()
+	bm.RunParallel(func(pb *testing.PB) {
+		mu.Lock()
+		p := pairs[0]
+		pairs = pairs[1:]
+		mu.Unlock()
+		for i := 0; pb.Next(); i++ {
+			from, to := p[i%2], p[(i+1)%2]
+			if serial {
+				_, _ = b.ApplyBatch([]Op{{Type: TxTransfer, From: from, To: to, Amount: 1}}, false)
+			} else {
+				_ = b.Transfer(from, to, NewMoney(1, "USD"))
+			}
+		}
+	})
+}
+
+func BenchmarkParallelTransfersPerAccount(bm *testing.B) { benchmarkParallelTransfers(bm, false) }
+func BenchmarkParallelTransfersGlobalLock(bm *testing.B) { benchmarkParallelTransfers(bm, true) }
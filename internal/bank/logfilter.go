@@ -0,0 +1,59 @@
+// internal/bank/logfilter.go
+//
+// 本檔提供帳戶日誌的條件篩選（稽核常用：例如某期間內的轉出紀錄）。
+// 篩選一律在日誌的拷貝上進行，不會暴露內部切片。
+
+package bank
+
+import (
+	"fmt"
+	"time"
+)
+
+// LogFilter 為日誌篩選條件；零值欄位代表不設限。
+//   - Direction：""（全部）、"in" 或 "out"。
+//   - Since / Until：期間 [Since, Until)，與 NetFlow 相同。
+type LogFilter struct {
+	Direction string
+	Since     time.Time
+	Until     time.Time
+}
+
+// match 回報日誌是否符合篩選條件。
+func (f LogFilter) match(l Log) bool {
+	if f.Direction != "" && l.Direction != f.Direction {
+		return false
+	}
+	if !f.Since.IsZero() && l.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !l.Time.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// FilterLogs 回傳帳戶中符合 opts 的日誌拷貝（依原順序；無符合時為空切片）。
+// Direction 不是 ""、"in"、"out" 時回傳 ErrBadFilter；帳戶不存在回傳 ErrNotFound。
+func (b *Bank) FilterLogs(id string, opts LogFilter) ([]Log, error) {
+	switch opts.Direction {
+	case "", "in", "out":
+	default:
+		return nil, fmt.Errorf("%w: direction must be in or out", ErrBadFilter)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	out := []Log{}
+	for _, l := range a.Logs {
+		if opts.match(l) {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
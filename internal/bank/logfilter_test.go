@@ -0,0 +1,79 @@
+// internal/bank/logfilter_test.go
+//
+// 測試日誌篩選：以注入的時鐘產生不同時間的存款、提款與轉帳，驗證方向與期間條件。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestFilterLogs 驗證方向、期間與組合條件選出正確的子集，且回傳的是拷貝。
+func TestFilterLogs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 12, 0, 0, 0, time.UTC) }
+	clk := &fakeClock{t: day(1)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("C", 100)
+
+	_, _ = b.Deposit(a.ID, NewMoney(10, "USD"), "") // 3/1 in
+	clk.t = day(2)
+	_, _ = b.Withdraw(a.ID, NewMoney(5, "USD"), "") // 3/2 out
+	clk.t = day(3)
+	_ = b.Transfer(a.ID, c.ID, NewMoney(7, "USD")) // 3/3 out
+	clk.t = day(4)
+	_ = b.Transfer(c.ID, a.ID, NewMoney(3, "USD")) // 3/4 in
+
+	amounts := func(logs []Log) []int64 {
+		out := []int64{}
+		for _, l := range logs {
+			out = append(out, l.Amount)
+		}
+		return out
+	}
+	cases := []struct {
+		name string
+		f    LogFilter
+		want []int64
+	}{
+		{"all", LogFilter{}, []int64{10, 5, 7, 3}},
+		{"out", LogFilter{Direction: "out"}, []int64{5, 7}},
+		{"in", LogFilter{Direction: "in"}, []int64{10, 3}},
+		{"since", LogFilter{Since: day(3)}, []int64{7, 3}},
+		{"until", LogFilter{Until: day(3)}, []int64{10, 5}},
+		{"out in window", LogFilter{Direction: "out", Since: day(2), Until: day(4)}, []int64{5, 7}},
+		{"empty window", LogFilter{Since: day(10)}, []int64{}},
+	}
+	for _, tc := range cases {
+		logs, err := b.FilterLogs(a.ID, tc.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got := amounts(logs)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %v want %v", tc.name, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Fatalf("%s: got %v want %v", tc.name, got, tc.want)
+			}
+		}
+	}
+
+	// 回傳拷貝：修改結果不影響內部日誌
+	logs, _ := b.FilterLogs(a.ID, LogFilter{})
+	logs[0].Amount = 999
+	if again, _ := b.FilterLogs(a.ID, LogFilter{}); again[0].Amount != 10 {
+		t.Fatal("FilterLogs must return a copy")
+	}
+
+	if _, err := b.FilterLogs(a.ID, LogFilter{Direction: "sideways"}); !errors.Is(err, ErrBadFilter) {
+		t.Fatalf("want ErrBadFilter, got %v", err)
+	}
+	if _, err := b.FilterLogs("999", LogFilter{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+// internal/bank/minbalance.go
+//
+// 本檔實作每帳戶的最低餘額（例如儲蓄帳戶須維持的存款下限）。
+// 設定 MinBalance = M（> 0）後，提款 / 轉出 / 手續費須滿足「餘額 - 保留 - 金額 >= M」，
+// 否則回傳 ErrInsufficient。預設 M = 0，即維持原本的規則（含透支額度，見 overdraft.go）；
+// 設定最低餘額的帳戶不適用透支額度。
+
+package bank
+
+// SetMinBalance 設定帳戶的最低餘額（>= 0，0 為不限制）；負值回傳 ErrBadAmount，帳戶不存在回傳 ErrNotFound。
+// 調高下限不影響目前餘額，只會讓後續扣款更早被拒。
+func (b *Bank) SetMinBalance(id string, min int64) error {
+	if min < 0 {
+		return ErrBadAmount
+	}
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return ErrFrozen
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.MinBalance = min
+	a.bump()
+	return nil
+}
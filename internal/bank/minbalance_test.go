@@ -0,0 +1,58 @@
+// internal/bank/minbalance_test.go
+//
+// 測試最低餘額：可提款至下限、再多一元即被拒、轉出同樣受限，設定經快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestMinBalance 驗證：
+// 1️⃣ 提款可剛好降至最低餘額；
+// 2️⃣ 再提一元（100 分）或轉出一分錢皆回傳 ErrInsufficient 且餘額不變；
+// 3️⃣ 最低餘額經 Snapshot / Restore 保留，調回 0 後恢復原規則。
+func TestMinBalance(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("Savings", 10_000)
+	other, _ := b.Create("Other", 0)
+	if err := b.SetMinBalance(a.ID, 2_500); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetMinBalance(a.ID, -1); !errors.Is(err, ErrBadAmount) {
+		t.Fatalf("negative min want ErrBadAmount, got %v", err)
+	}
+	if err := b.SetMinBalance("999", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	// 1️⃣ 提款至剛好等於最低餘額
+	if _, err := b.Withdraw(a.ID, NewMoney(7_500, "USD"), ""); err != nil {
+		t.Fatalf("withdraw down to minimum: %v", err)
+	}
+
+	// 2️⃣ 再多一元 / 一分錢都被拒
+	if _, err := b.Withdraw(a.ID, NewMoney(100, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("withdraw below minimum want ErrInsufficient, got %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(1, "USD")); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("transfer below minimum want ErrInsufficient, got %v", err)
+	}
+	if got := get(t, b, a.ID); got.Balance != 2_500 || got.MinBalance != 2_500 {
+		t.Fatalf("account=%+v want balance 2500, min 2500", got)
+	}
+
+	// 3️⃣ 快照保留設定；調回 0 後可全數提出
+	restored := NewBank()
+	restored.Restore(b.Snapshot())
+	if _, err := restored.Withdraw(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("restored withdraw want ErrInsufficient, got %v", err)
+	}
+	if err := b.SetMinBalance(a.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(a.ID, NewMoney(2_500, "USD"), ""); err != nil {
+		t.Fatalf("withdraw after clearing minimum: %v", err)
+	}
+}
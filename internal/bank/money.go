@@ -0,0 +1,79 @@
+// internal/bank/money.go
+//
+// 本檔定義 Money：帶幣別的金額（最小單位，例如分）。
+// 單純的 int64 金額容易誤用（混用幣別、忘記單位），Money 在型別層面將兩者綁在一起：
+//   - 算術（Add / Sub）遇到幣別不同時回傳 ErrCurrencyMismatch，結果超出 int64 範圍時回傳 ErrOverflow，
+//     而非默默相加或溢位。
+//   - JSON 形式為 {"amount": 100, "currency": "USD"}，與帳戶的 balance + currency 欄位相容。
+//   - 公開的 Deposit / Withdraw / Transfer 以 Money 指定金額，操作前核對金額幣別與帳戶幣別（見 bank.go）。
+//
+// Op 與 journal 仍以 int64 記錄金額（幣別即帳戶本身的幣別），JSON 格式不變。
+
+package bank
+
+import "fmt"
+
+// Money 為帶幣別的金額；Amount 以最小貨幣單位表示。
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney 建立金額；幣別會正規化（大寫，空值為 DefaultCurrency）。
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: normalizeCurrency(currency)}
+}
+
+// Add 回傳 m + o；幣別不同時回傳 ErrCurrencyMismatch，結果超出 int64 範圍時回傳 ErrOverflow。
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + o.Amount
+	if (o.Amount > 0 && sum < m.Amount) || (o.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub 回傳 m - o；幣別不同時回傳 ErrCurrencyMismatch，結果超出 int64 範圍時回傳 ErrOverflow。
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	diff := m.Amount - o.Amount
+	if (o.Amount > 0 && diff > m.Amount) || (o.Amount < 0 && diff < m.Amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{Amount: diff, Currency: m.Currency}, nil
+}
+
+// String 以「金額 幣別」格式輸出，例如 "1050 USD"（最小單位）。
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.Amount, m.Currency)
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if normalizeCurrency(m.Currency) != normalizeCurrency(o.Currency) {
+		return fmt.Errorf("%w: %s vs %s", ErrCurrencyMismatch, m.Currency, o.Currency)
+	}
+	return nil
+}
+
+// BalanceMoney 回傳帳戶餘額的 Money 形式。
+func (a Account) BalanceMoney() Money {
+	return NewMoney(a.Balance, a.Currency)
+}
+
+// checkCurrency 核對金額幣別與各帳戶幣別；須在 mu 保護下呼叫。
+// 不存在的帳戶略過，交由後續核心邏輯回傳 ErrNotFound。
+func (b *Bank) checkCurrency(m Money, ids ...string) error {
+	for _, id := range ids {
+		if a, ok := b.accts[id]; ok {
+			if err := m.sameCurrency(a.BalanceMoney()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
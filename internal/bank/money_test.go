@@ -0,0 +1,112 @@
+// internal/bank/money_test.go
+//
+// 測試 Money：跨幣別與溢位的算術被拒、JSON 往返不變，以及存款 / 提款 / 轉帳核對金額幣別。
+
+package bank
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := NewMoney(100, "usd")
+	if usd.Currency != "USD" {
+		t.Fatalf("currency=%q want USD", usd.Currency)
+	}
+	sum, err := usd.Add(NewMoney(50, "USD"))
+	if err != nil || sum.Amount != 150 {
+		t.Fatalf("sum=%v err=%v", sum, err)
+	}
+	if diff, _ := usd.Sub(NewMoney(30, "")); diff.Amount != 70 {
+		t.Fatalf("diff=%v want 70 USD", diff)
+	}
+
+	// ❌ 跨幣別算術
+	if _, err := usd.Add(NewMoney(1, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Add: want ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := usd.Sub(NewMoney(1, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("Sub: want ErrCurrencyMismatch, got %v", err)
+	}
+
+	// ❌ 超出 int64 範圍：回傳 ErrOverflow 而非繞回
+	big, small := NewMoney(math.MaxInt64, "USD"), NewMoney(math.MinInt64, "USD")
+	for name, err := range map[string]error{
+		"max+1":  second(big.Add(NewMoney(1, "USD"))),
+		"min-1":  second(small.Sub(NewMoney(1, "USD"))),
+		"min+-1": second(small.Add(NewMoney(-1, "USD"))),
+		"max--1": second(big.Sub(NewMoney(-1, "USD"))),
+	} {
+		if !errors.Is(err, ErrOverflow) {
+			t.Fatalf("%s: want ErrOverflow, got %v", name, err)
+		}
+	}
+	if got, err := big.Sub(NewMoney(1, "USD")); err != nil || got.Amount != math.MaxInt64-1 {
+		t.Fatalf("max-1=%v err=%v", got, err)
+	}
+	if got, err := small.Add(NewMoney(math.MaxInt64, "USD")); err != nil || got.Amount != -1 {
+		t.Fatalf("min+max=%v err=%v", got, err)
+	}
+}
+
+// second 回傳 (Money, error) 的錯誤部分。
+func second(_ Money, err error) error { return err }
+
+// TestMoneyJSON 驗證 Money 的 JSON 為整數金額加幣別，且往返不變；帳戶 JSON 格式不受影響。
+func TestMoneyJSON(t *testing.T) {
+	m := NewMoney(1050, "TWD")
+	j, _ := json.Marshal(m)
+	if string(j) != `{"amount":1050,"currency":"TWD"}` {
+		t.Fatalf("json=%s", j)
+	}
+	var back Money
+	if err := json.Unmarshal(j, &back); err != nil || back != m {
+		t.Fatalf("round trip=%v err=%v", back, err)
+	}
+
+	a := Account{ID: "1", Name: "A", Balance: 1050, Currency: "TWD", Status: StatusActive}
+	j, _ = json.Marshal(a)
+	if string(j) != `{"id":"1","name":"A","balance":1050,"currency":"TWD","status":"active","version":0}` {
+		t.Fatalf("account json=%s", j)
+	}
+	if a.BalanceMoney() != m {
+		t.Fatalf("BalanceMoney=%v", a.BalanceMoney())
+	}
+}
+
+// TestMoneyOperations 驗證存款 / 提款 / 轉帳會核對金額與帳戶的幣別，不符時不改變狀態。
+func TestMoneyOperations(t *testing.T) {
+	b := NewBank()
+	usd, _ := b.Open(AccountSpec{Name: "U", Balance: 100, Currency: "USD"})
+	eur, _ := b.Open(AccountSpec{Name: "E", Balance: 100, Currency: "EUR"})
+	usd2, _ := b.Create("U2", 0)
+
+	if _, err := b.Deposit(usd.ID, NewMoney(10, "EUR"), ""); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("want ErrCurrencyMismatch, got %v", err)
+	}
+	if err := b.Transfer(usd.ID, eur.ID, NewMoney(10, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("want ErrCurrencyMismatch, got %v", err)
+	}
+	if _, err := b.Withdraw(eur.ID, NewMoney(10, "USD"), ""); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Fatalf("want ErrCurrencyMismatch, got %v", err)
+	}
+	if get(t, b, usd.ID).Balance != 100 || get(t, b, eur.ID).Balance != 100 {
+		t.Fatal("balances changed on rejected operation")
+	}
+
+	if _, err := b.Deposit(usd.ID, NewMoney(10, "usd"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Withdraw(usd.ID, NewMoney(5, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Transfer(usd.ID, usd2.ID, NewMoney(5, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	if got := get(t, b, usd.ID).BalanceMoney(); got != NewMoney(100, "USD") {
+		t.Fatalf("balance=%v want 100 USD", got)
+	}
+}
@@ -0,0 +1,88 @@
+// internal/bank/names.go
+//
+// 本檔實作帳戶名稱的「禁用清單（denylist）」。
+// 合規需求下，營運方可禁止以保留字或不當字詞作為帳戶名稱。
+// 清單於啟動時載入，比對一律不分大小寫：
+//   - 一般項目：與名稱（去除前後空白後）完全相同即禁止。
+//   - 以斜線包住的項目（例如 "/^admin.*/"）：視為正規表示式，名稱中任一處符合即禁止。
+//
+// 更名（Rename）同樣受禁用清單約束。
+
+package bank
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nameRule 為一條已編譯的禁用規則；exact 與 re 擇一使用。
+type nameRule struct {
+	exact string
+	re    *regexp.Regexp
+}
+
+// SetNameDenylist 以新的清單取代目前的禁用規則。
+// 任一正規表示式無法編譯時回傳錯誤，且不變更既有規則。
+func (b *Bank) SetNameDenylist(entries []string) error {
+	rules := make([]nameRule, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		if len(e) >= 2 && strings.HasPrefix(e, "/") && strings.HasSuffix(e, "/") {
+			re, err := regexp.Compile("(?i)" + e[1:len(e)-1])
+			if err != nil {
+				return fmt.Errorf("denylist entry %q: %w", e, err)
+			}
+			rules = append(rules, nameRule{re: re})
+			continue
+		}
+		rules = append(rules, nameRule{exact: e})
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.denylist = rules
+	return nil
+}
+
+// Rename 更正帳戶名稱（去除前後空白後儲存）並回傳值拷貝。
+// 名稱為空或僅含空白回傳 ErrBadName；命中禁用清單回傳 ErrNameNotAllowed。
+func (b *Bank) Rename(id, newName string) (*Account, error) {
+	newName = strings.TrimSpace(newName)
+	if newName == "" {
+		return nil, ErrBadName
+	}
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return nil, ErrFrozen
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if err := b.checkName(newName); err != nil {
+		return nil, err
+	}
+	a.Name = newName
+	a.bump()
+	cp := *a
+	return &cp, nil
+}
+
+// checkName 檢查名稱是否命中禁用清單；須在 mu 保護下呼叫。
+func (b *Bank) checkName(name string) error {
+	name = strings.TrimSpace(name)
+	for _, r := range b.denylist {
+		if r.re != nil && r.re.MatchString(name) {
+			return ErrNameNotAllowed
+		}
+		if r.re == nil && strings.EqualFold(r.exact, name) {
+			return ErrNameNotAllowed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// internal/bank/names_test.go
+//
+// 測試帳戶名稱禁用清單：完全比對與正規表示式皆不分大小寫。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNameDenylist 驗證命中清單的名稱於建立時被拒，其餘名稱正常建立。
+func TestNameDenylist(t *testing.T) {
+	b := NewBank()
+	if err := b.SetNameDenylist([]string{"root", "/^admin/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// ❌ 完全比對（不分大小寫、忽略前後空白）
+	for _, name := range []string{"root", "ROOT", " Root "} {
+		if _, err := b.Create(name, 0); !errors.Is(err, ErrNameNotAllowed) {
+			t.Fatalf("Create(%q) want ErrNameNotAllowed, got %v", name, err)
+		}
+	}
+	// ❌ 正規表示式（不分大小寫）
+	if _, err := b.Create("Administrator", 0); !errors.Is(err, ErrNameNotAllowed) {
+		t.Fatalf("regex entry should deny, got %v", err)
+	}
+	// ✅ 未命中的名稱
+	if _, err := b.Create("rooted", 0); err != nil {
+		t.Fatalf("allowed name rejected: %v", err)
+	}
+	if n := len(b.List()); n != 1 {
+		t.Fatalf("accounts=%d want 1", n)
+	}
+
+	// 無效的正規表示式不得覆蓋既有規則
+	if err := b.SetNameDenylist([]string{"/(/"}); err == nil {
+		t.Fatal("want compile error")
+	}
+	if _, err := b.Create("root", 0); !errors.Is(err, ErrNameNotAllowed) {
+		t.Fatalf("previous rules should remain, got %v", err)
+	}
+}
+
+// TestRename 驗證更名：成功時去除空白並回傳新名稱；空名稱、禁用名稱與不存在的帳戶被拒。
+func TestRename(t *testing.T) {
+	b := NewBank()
+	if err := b.SetNameDenylist([]string{"root"}); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := b.Create("Alcie", 10)
+
+	got, err := b.Rename(a.ID, "  Alice ")
+	if err != nil || got.Name != "Alice" || get(t, b, a.ID).Name != "Alice" {
+		t.Fatalf("rename=%+v err=%v", got, err)
+	}
+	for _, name := range []string{"", "   "} {
+		if _, err := b.Rename(a.ID, name); !errors.Is(err, ErrBadName) {
+			t.Fatalf("Rename(%q) want ErrBadName, got %v", name, err)
+		}
+	}
+	if _, err := b.Rename(a.ID, "ROOT"); !errors.Is(err, ErrNameNotAllowed) {
+		t.Fatalf("want ErrNameNotAllowed, got %v", err)
+	}
+	if _, err := b.Rename("999", "Bob"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	if get(t, b, a.ID).Name != "Alice" {
+		t.Fatal("failed renames must not change the name")
+	}
+}
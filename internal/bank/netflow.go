@@ -0,0 +1,36 @@
+// internal/bank/netflow.go
+//
+// 本檔提供帳戶在指定期間內的現金流彙總（流入、流出、淨變動），供現金流分析使用。
+// 計算完全依據日誌：Direction "in" 計入流入，"out" 計入流出。
+
+package bank
+
+import "time"
+
+// NetFlow 統計帳戶在 [from, to) 期間內的總流入、總流出與淨變動（in - out）。
+// from 或 to 為零值時代表該端不設限；期間內無交易時三者皆為 0。
+// 帳戶不存在時回傳 ErrNotFound。
+func (b *Bank) NetFlow(id string, from, to time.Time) (in, out, net int64, err error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	a, ok := b.accts[id]
+	if !ok {
+		return 0, 0, 0, ErrNotFound
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, l := range a.Logs {
+		if !from.IsZero() && l.Time.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !l.Time.Before(to) {
+			continue
+		}
+		if l.Direction == "out" {
+			out += l.Amount
+		} else {
+			in += l.Amount
+		}
+	}
+	return in, out, in - out, nil
+}
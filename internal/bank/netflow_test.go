@@ -0,0 +1,54 @@
+// internal/bank/netflow_test.go
+//
+// 測試期間現金流：混合存款、提款、轉帳，驗證期間篩選與流入 / 流出 / 淨變動。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNetFlow(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 9, 0, 0, 0, time.UTC) }
+	clk := &fakeClock{t: day(1)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	a, _ := b.Create("A", 1000)
+	c, _ := b.Create("B", 1000)
+
+	_, _ = b.Deposit(a.ID, NewMoney(500, "USD"), "") // 3/1 → 期間外
+	clk.t = day(2)
+	_, _ = b.Deposit(a.ID, NewMoney(100, "USD"), "") // 3/2 in 100
+	_, _ = b.Withdraw(a.ID, NewMoney(30, "USD"), "") // 3/2 out 30
+	clk.t = day(3)
+	_ = b.Transfer(a.ID, c.ID, NewMoney(50, "USD")) // 3/3 out 50
+	_ = b.Transfer(c.ID, a.ID, NewMoney(20, "USD")) // 3/3 in 20
+	clk.t = day(4)
+	_, _ = b.Withdraw(a.ID, NewMoney(7, "USD"), "") // 3/4 → 期間外（to 不含）
+
+	// 1️⃣ [3/2, 3/4)
+	in, out, net, err := b.NetFlow(a.ID, day(2), day(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in != 120 || out != 80 || net != 40 {
+		t.Fatalf("in=%d out=%d net=%d want 120/80/40", in, out, net)
+	}
+
+	// 2️⃣ 不設限：涵蓋全部日誌
+	if in, out, net, _ = b.NetFlow(a.ID, time.Time{}, time.Time{}); in != 620 || out != 87 || net != 533 {
+		t.Fatalf("all: in=%d out=%d net=%d", in, out, net)
+	}
+
+	// ✅ 空期間全為 0
+	if in, out, net, _ = b.NetFlow(a.ID, day(10), day(11)); in != 0 || out != 0 || net != 0 {
+		t.Fatalf("empty: in=%d out=%d net=%d", in, out, net)
+	}
+
+	// ❌ 帳戶不存在
+	if _, _, _, err := b.NetFlow("999", time.Time{}, time.Time{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
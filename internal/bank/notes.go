@@ -0,0 +1,63 @@
+// internal/bank/notes.go
+//
+// 本檔實作「每帳戶日誌備註（note）總位元組上限」。
+// 日誌條數之外，備註內容本身也可能被濫用而佔用大量記憶體；
+// 透過累計每個帳戶所有日誌 Note 的位元組數，超過上限後依設定的策略處理：
+//   - NoteTruncate：截斷新備註（必要時截為空字串），交易仍照常完成。
+//   - NoteReject：拒絕整筆交易並回傳 ErrNoteBudget，餘額不變。
+
+package bank
+
+import "unicode/utf8"
+
+// NotePolicy 定義備註超過帳戶上限時的處理策略。
+type NotePolicy int
+
+const (
+	// NoteTruncate 截斷超出部分，交易照常完成（預設）。
+	NoteTruncate NotePolicy = iota
+	// NoteReject 拒絕交易並回傳 ErrNoteBudget。
+	NoteReject
+)
+
+// SetNoteBudget 設定每帳戶備註的累計位元組上限與超限策略。
+// maxBytes <= 0 表示不限制（預設）。
+func (b *Bank) SetNoteBudget(maxBytes int, policy NotePolicy) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.noteCap = maxBytes
+	b.notePolicy = policy
+}
+
+// fitNote 依帳戶剩餘額度調整備註；須在 mu 保護下呼叫，且不修改任何狀態。
+// 回傳實際應寫入的備註，或在 NoteReject 策略下回傳 ErrNoteBudget。
+func (b *Bank) fitNote(a *Account, note string) (string, error) {
+	if b.noteCap <= 0 {
+		return note, nil
+	}
+	remaining := b.noteCap - a.noteBytes
+	if len(note) <= remaining {
+		return note, nil
+	}
+	if b.notePolicy == NoteReject {
+		return "", ErrNoteBudget
+	}
+	return truncateUTF8(note, max(remaining, 0)), nil
+}
+
+// appendLog 追加日誌並累計備註位元組數；須在 mu 保護下呼叫。
+func appendLog(a *Account, l Log) {
+	a.Logs = append(a.Logs, l)
+	a.noteBytes += len(l.Note)
+}
+
+// truncateUTF8 將字串截斷至最多 n 個位元組，且不切斷多位元組字元。
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,108 @@
+// internal/bank/notes_test.go
+//
+// 測試每帳戶備註位元組上限：超過上限後依策略截斷或拒絕，且餘額始終正確。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNoteBudgetTruncate 驗證 NoteTruncate 策略：
+// 超限後備註被截斷（最終為空字串），交易仍完成、餘額正確。
+func TestNoteBudgetTruncate(t *testing.T) {
+	b := NewBank()
+	b.SetNoteBudget(10, NoteTruncate)
+	a, _ := b.Create("A", 0)
+
+	// "deposit" 7 bytes → 剩 3 bytes → 第二筆截為 "dep" → 第三筆截為 ""
+	for i := 0; i < 3; i++ {
+		if _, err := b.Deposit(a.ID, NewMoney(10, "USD"), ""); err != nil {
+			t.Fatalf("deposit #%d: %v", i, err)
+		}
+	}
+	logs, _ := b.Logs(a.ID)
+	if len(logs) != 3 {
+		t.Fatalf("logs len=%d want 3", len(logs))
+	}
+	if logs[0].Note != "deposit" || logs[1].Note != "dep" || logs[2].Note != "" {
+		t.Fatalf("notes=%q %q %q", logs[0].Note, logs[1].Note, logs[2].Note)
+	}
+	if bal := get(t, b, a.ID).Balance; bal != 30 {
+		t.Fatalf("balance=%d want 30", bal)
+	}
+}
+
+// TestNoteBudgetReject 驗證 NoteReject 策略：
+// 超限的交易被拒絕（ErrNoteBudget），餘額與日誌皆不變；轉帳任一方超限也不得部分成功。
+func TestNoteBudgetReject(t *testing.T) {
+	b := NewBank()
+	b.SetNoteBudget(8, NoteReject)
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 100)
+
+	if _, err := b.Deposit(a1.ID, NewMoney(10, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Deposit(a1.ID, NewMoney(10, "USD"), ""); !errors.Is(err, ErrNoteBudget) {
+		t.Fatalf("want ErrNoteBudget, got %v", err)
+	}
+	if bal := get(t, b, a1.ID).Balance; bal != 110 {
+		t.Fatalf("balance=%d want 110", bal)
+	}
+
+	// a1 額度已滿 → 轉帳被拒，雙方餘額不變
+	if err := b.Transfer(a2.ID, a1.ID, NewMoney(50, "USD")); !errors.Is(err, ErrNoteBudget) {
+		t.Fatalf("want ErrNoteBudget, got %v", err)
+	}
+	if get(t, b, a1.ID).Balance != 110 || get(t, b, a2.ID).Balance != 100 {
+		t.Fatalf("balances changed on rejected transfer")
+	}
+	if logs, _ := b.Logs(a2.ID); len(logs) != 0 {
+		t.Fatalf("a2 logs=%d want 0", len(logs))
+	}
+}
+
+// TestDepositWithdrawNote 驗證存款 / 提款的自訂備註寫入日誌的 Note（Type 不變），
+// 未指定時沿用預設的 "deposit" / "withdraw"，且自訂備註同樣受備註額度限制。
+func TestDepositWithdrawNote(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 100)
+	_, _ = b.Deposit(a.ID, NewMoney(50, "USD"), "cash deposit branch 12")
+	_, _ = b.Withdraw(a.ID, NewMoney(20, "USD"), "chargeback")
+	_, _ = b.Deposit(a.ID, NewMoney(5, "USD"), "")
+	_, _ = b.Withdraw(a.ID, NewMoney(5, "USD"), "")
+
+	logs, _ := b.Logs(a.ID)
+	want := []struct{ typ, note string }{
+		{TxDeposit, "cash deposit branch 12"},
+		{TxWithdraw, "chargeback"},
+		{TxDeposit, "deposit"},
+		{TxWithdraw, "withdraw"},
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("logs=%d want %d", len(logs), len(want))
+	}
+	for i, w := range want {
+		if logs[i].Type != w.typ || logs[i].Note != w.note {
+			t.Errorf("log %d type=%q note=%q want %q/%q", i, logs[i].Type, logs[i].Note, w.typ, w.note)
+		}
+	}
+
+	b.SetNoteBudget(len("cash deposit branch 12")+len("chargeback")+len("deposit")+len("withdraw")+4, NoteTruncate)
+	_, _ = b.Deposit(a.ID, NewMoney(1, "USD"), "long manual adjustment")
+	if logs, _ := b.Logs(a.ID); logs[len(logs)-1].Note != "long" {
+		t.Fatalf("note=%q want truncated to budget", logs[len(logs)-1].Note)
+	}
+}
+
+// TestTruncateUTF8 確認截斷不會切斷多位元組字元。
+func TestTruncateUTF8(t *testing.T) {
+	if got := truncateUTF8("存款", 4); got != "存" {
+		t.Fatalf("got %q want 存", got)
+	}
+	if got := truncateUTF8("abc", 5); got != "abc" {
+		t.Fatalf("got %q", got)
+	}
+}
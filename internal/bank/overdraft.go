@@ -0,0 +1,43 @@
+// internal/bank/overdraft.go
+//
+// 本檔實作每帳戶的透支額度（例如信用額度帳戶）。
+// 設定 OverdraftLimit = L 後，提款 / 轉出 / 手續費只要滿足「餘額 - 保留 - 金額 >= -L」即可成功，
+// 超過下限仍回傳 ErrInsufficient。預設 L = 0，即維持餘額不得為負的原規則。
+
+package bank
+
+import "math"
+
+// SetOverdraft 設定帳戶的透支額度（>= 0）；負值回傳 ErrBadAmount，帳戶不存在回傳 ErrNotFound。
+// 調降額度不影響已發生的透支，只會讓後續扣款更早觸及下限。
+func (b *Bank) SetOverdraft(id string, limit int64) error {
+	if limit < 0 {
+		return ErrBadAmount
+	}
+	b.mu.Lock()
+	defer b.unlock()
+	if b.frozen {
+		return ErrFrozen
+	}
+	a, ok := b.accts[id]
+	if !ok {
+		return ErrNotFound
+	}
+	a.OverdraftLimit = limit
+	a.bump()
+	return nil
+}
+
+// canDebit 回報扣款 amt 後是否仍在下限之內。
+// 設有最低餘額（見 minbalance.go）時，可動用額度 = 可用餘額 - 最低餘額；
+// 否則可動用額度 = 可用餘額 + 透支額度，相加溢位時視為 math.MaxInt64。
+func (a *Account) canDebit(amt int64) bool {
+	if a.MinBalance > 0 {
+		return a.available() >= a.MinBalance && amt <= a.available()-a.MinBalance
+	}
+	room := a.available() + a.OverdraftLimit
+	if room < a.available() {
+		room = math.MaxInt64
+	}
+	return amt <= room
+}
@@ -0,0 +1,70 @@
+// internal/bank/overdraft_test.go
+//
+// 測試透支額度：可透支至下限、超過下限被拒、額度經快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestOverdraft 驗證：
+// 1️⃣ 提款可透支至 -limit；
+// 2️⃣ 超過下限的提款與轉出回傳 ErrInsufficient 且餘額不變；
+// 3️⃣ 額度經 Snapshot / Restore 保留。
+func TestOverdraft(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("Credit", 50)
+	other, _ := b.Create("Other", 0)
+	if err := b.SetOverdraft(a.ID, 100); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetOverdraft(a.ID, -1); !errors.Is(err, ErrBadAmount) {
+		t.Fatalf("negative limit want ErrBadAmount, got %v", err)
+	}
+	if err := b.SetOverdraft("999", 10); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	// 1️⃣ 透支至 -80
+	if _, err := b.Withdraw(a.ID, NewMoney(130, "USD"), ""); err != nil {
+		t.Fatalf("withdraw into overdraft: %v", err)
+	}
+	if got := get(t, b, a.ID).Balance; got != -80 {
+		t.Fatalf("balance=%d want -80", got)
+	}
+
+	// 2️⃣ 超過 -100 的下限
+	if _, err := b.Withdraw(a.ID, NewMoney(21, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("withdraw past limit want ErrInsufficient, got %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(21, "USD")); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("transfer past limit want ErrInsufficient, got %v", err)
+	}
+	if err := b.Transfer(a.ID, other.ID, NewMoney(20, "USD")); err != nil {
+		t.Fatalf("transfer down to the floor: %v", err)
+	}
+	if got := get(t, b, a.ID).Balance; got != -100 {
+		t.Fatalf("balance=%d want -100", got)
+	}
+
+	// 3️⃣ 快照保存額度
+	b2 := NewBank()
+	b2.Restore(b.Snapshot())
+	if got := get(t, b2, a.ID); got.OverdraftLimit != 100 || got.Balance != -100 {
+		t.Fatalf("restored=%+v", got)
+	}
+	if _, err := b2.Withdraw(a.ID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("restored floor not enforced: %v", err)
+	}
+}
+
+// TestNoOverdraftByDefault 驗證未設定額度的帳戶維持不得為負的原規則。
+func TestNoOverdraftByDefault(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 10)
+	if _, err := b.Withdraw(a.ID, NewMoney(11, "USD"), ""); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// internal/bank/readview.go
+//
+// 本檔實作選用的「copy-on-write 唯讀檢視」。
+// 讀多寫少的部署中，查詢（Get、GetMany、List）與異動競爭同一把 mu 會拖慢讀取。
+// 啟用後，每次異動結束前（仍持有 mu）會複製一份不可變的帳戶檢視，並以原子指標替換；
+// 查詢直接讀取目前的檢視，完全不取鎖。
+//
+// 代價是每次寫入都需複製帳戶表（O(n)），適合帳戶數量適中的情境。
+// 只鎖定少數帳戶的快速路徑（見 locks.go）以 publishAccounts 增量發布：沿用前一份檢視，只替換異動的帳戶。
+// 檢視只在臨界區結束時發布，因此讀者永遠看到某次異動「完成後」的一致狀態，
+// 不會看到轉帳只扣款未入帳、或批次回滾前的中間狀態。
+//
+// 日誌切片與帳本共用底層陣列：寫入端只會在切片長度之後追加，檢視內的元素永不被改寫。
+
+package bank
+
+import (
+	"maps"
+	"slices"
+)
+
+// readView 為某一時間點的帳戶不可變快照。
+type readView struct {
+	byID   map[string]*Account
+	sorted []*Account
+}
+
+// SetReadSnapshot 啟用或停用 copy-on-write 唯讀檢視（預設停用）。
+func (b *Bank) SetReadSnapshot(enabled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cow = enabled
+	if enabled {
+		b.publish()
+	} else {
+		b.view.Store(nil)
+	}
+}
+
+// unlock 結束會異動帳戶的臨界區：若啟用唯讀檢視則先發布最新狀態，再釋放 mu。
+// 所有會改變帳戶的公開方法皆以 defer b.unlock() 取代 defer b.mu.Unlock()。
+func (b *Bank) unlock() {
+	if b.cow {
+		b.publish()
+	}
+	b.mu.Unlock()
+}
+
+// publish 依目前帳本建立新的唯讀檢視並原子替換；須在 mu 保護下呼叫。
+func (b *Bank) publish() {
+	v := &readView{byID: make(map[string]*Account, len(b.accts))}
+	for _, a := range b.sortedAccounts() {
+		cp := *a
+		v.byID[cp.ID] = &cp
+		v.sorted = append(v.sorted, &cp)
+	}
+	b.view.Store(v)
+}
+
+// publishAccounts 以目前檢視為基礎，只替換 accts 的副本後發布；須持有 mu 讀鎖並鎖定 accts。
+// viewMu 序列化並行的發布，確保後發布者不會蓋掉先前其他帳戶的更新。
+func (b *Bank) publishAccounts(accts []*Account) {
+	b.viewMu.Lock()
+	defer b.viewMu.Unlock()
+	old := b.view.Load()
+	if old == nil {
+		return
+	}
+	v := &readView{byID: maps.Clone(old.byID), sorted: slices.Clone(old.sorted)}
+	for _, a := range accts {
+		cp := *a
+		v.byID[cp.ID] = &cp
+	}
+	for i, a := range v.sorted {
+		v.sorted[i] = v.byID[a.ID]
+	}
+	b.view.Store(v)
+}
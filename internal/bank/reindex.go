@@ -0,0 +1,76 @@
+// internal/bank/reindex.go
+//
+// 本檔提供索引重建（Reindex），作為還原或合併資料後的修復工具。
+// 帳戶與日誌為權威資料；次要索引（目前為 TxID → 帳戶索引與交易序號）皆可由其重建。
+// 重建時同時檢查並回報異常，例如索引缺漏 / 多餘、同一帳戶內重複的 TxID、交易序號落後等。
+
+package bank
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// Reindex 由帳戶日誌重建所有次要索引，並回傳發現的異常描述（依字典序排序；無異常時為空切片）。
+func (b *Bank) Reindex() []string {
+	b.mu.Lock()
+	defer b.unlock()
+
+	var anomalies []string
+	index := make(map[string][]string)
+	var maxSeq int64
+	for _, a := range b.sortedAccounts() {
+		seen := make(map[string]bool)
+		for _, l := range a.Logs {
+			if l.TxID == "" {
+				continue
+			}
+			if seen[l.TxID] {
+				anomalies = append(anomalies, fmt.Sprintf("duplicate tx_id %s in account %s", l.TxID, a.ID))
+				continue
+			}
+			seen[l.TxID] = true
+			index[l.TxID] = append(index[l.TxID], a.ID)
+			maxSeq = max(maxSeq, txSeq(l.TxID))
+		}
+	}
+
+	for txID, ids := range index {
+		if len(ids) > 2 {
+			anomalies = append(anomalies, fmt.Sprintf("tx_id %s shared by %d accounts %v", txID, len(ids), ids))
+		}
+		old := slices.Clone(b.txIndex[txID])
+		slices.SortFunc(old, cmpID)
+		if !slices.Equal(old, ids) {
+			anomalies = append(anomalies, fmt.Sprintf("tx index for %s was %v, rebuilt as %v", txID, old, ids))
+		}
+	}
+	for txID := range b.txIndex {
+		if _, ok := index[txID]; !ok {
+			anomalies = append(anomalies, fmt.Sprintf("tx index had stale entry %s", txID))
+		}
+	}
+	if b.nextTx < maxSeq {
+		anomalies = append(anomalies, fmt.Sprintf("tx sequence %d behind highest tx_id %d", b.nextTx, maxSeq))
+		b.nextTx = maxSeq
+	}
+
+	b.txIndex = index
+	sort.Strings(anomalies)
+	if anomalies == nil {
+		anomalies = []string{}
+	}
+	return anomalies
+}
+
+// cmpID 為 lessID 的三向比較版本，供 slices.SortFunc 使用。
+func cmpID(a, b string) int {
+	switch {
+	case lessID(a, b):
+		return -1
+	case lessID(b, a):
+		return 1
+	}
+	return 0
+}
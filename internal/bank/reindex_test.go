@@ -0,0 +1,49 @@
+// internal/bank/reindex_test.go
+//
+// 測試索引重建：破壞 TxID 索引與交易序號後，Reindex 回報異常且查詢恢復正常。
+
+package bank
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReindex(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	tx, _ := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 10})
+
+	// ✅ 健康狀態下無異常
+	if got := b.Reindex(); len(got) != 0 {
+		t.Fatalf("anomalies=%v want none", got)
+	}
+
+	// 1️⃣ 破壞索引：刪除真實條目、加入多餘條目、讓序號落後
+	b.mu.Lock()
+	delete(b.txIndex, tx.ID)
+	b.txIndex["tx-99"] = []string{a1.ID}
+	b.nextTx = 0
+	b.mu.Unlock()
+	if _, err := b.FindTx(tx.ID); err == nil {
+		t.Fatal("corrupted index should hide the transaction")
+	}
+
+	// 2️⃣ 重建並檢查回報
+	got := b.Reindex()
+	joined := strings.Join(got, "\n")
+	for _, want := range []string{"tx index for " + tx.ID, "stale entry tx-99", "tx sequence 0 behind"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("anomalies=%v missing %q", got, want)
+		}
+	}
+
+	// ✅ 查詢恢復、新交易不會重用既有序號
+	if found, err := b.FindTx(tx.ID); err != nil || found.From != a1.ID {
+		t.Fatalf("FindTx after reindex: %+v %v", found, err)
+	}
+	if next, _ := b.Apply(Op{Type: TxDeposit, Account: a1.ID, Amount: 1}); next.ID != "tx-2" {
+		t.Fatalf("next tx=%q want tx-2", next.ID)
+	}
+}
@@ -0,0 +1,42 @@
+// internal/bank/reset.go
+//
+// 本檔實作 Reset：清空帳本，供 CI 與展示環境在不重啟程序的情況下重新開始。
+//   - 清空帳戶、交易索引、待審核轉帳與授權保留，帳戶與交易序號歸零（下一個新帳戶 ID 為 "1"）；
+//   - 帳本世代（epoch）遞增：journal 中清空前的項目於崩潰重啟後不會被重播到新帳本（見 journal.go）；
+//   - 系統帳戶原本存在時以零餘額重建，利息與手續費仍有對手帳戶（見 system.go）；
+//   - 所有日誌訂閱隨之結束（channel 被關閉），避免舊訂閱收到新帳戶沿用同一 ID 的日誌；
+//   - 營運設定（手續費、限額、全行凍結、日誌保留上限等）維持不變。
+
+package bank
+
+// Reset 清空所有帳戶並將 ID 序號歸零。
+func (b *Bank) Reset() {
+	b.mu.Lock()
+	defer b.unlock()
+	_, hadSystem := b.accts[SystemAccountID]
+	b.accts = make(map[string]*Account)
+	b.setIDSeq(0)
+	b.nextTx = 0
+	b.logSeq = 0
+	b.txIndex = make(map[string][]string)
+	b.pending = make(map[string]*pendingHold)
+	b.auths = make(map[string]*AuthHold)
+	b.nextHold = 0
+	b.epoch++
+	if hadSystem {
+		b.accts[SystemAccountID] = newSystemAccount()
+	}
+	b.closeSubs()
+}
+
+// closeSubs 結束所有日誌訂閱（關閉其 channel）。
+func (b *Bank) closeSubs() {
+	b.subMu.Lock()
+	defer b.subMu.Unlock()
+	for _, chans := range b.subs {
+		for ch := range chans {
+			close(ch)
+		}
+	}
+	b.subs = nil
+}
@@ -0,0 +1,62 @@
+// internal/bank/reset_test.go
+//
+// 測試 Reset：清空帳本、ID 序號重新由 "1" 開始、系統帳戶以零餘額重建、訂閱被關閉。
+
+package bank
+
+import "testing"
+
+// TestReset 驗證：
+// 1️⃣ Reset 後沒有任何一般帳戶，交易查詢不到；
+// 2️⃣ 新開戶的 ID 重新由 "1" 開始；
+// 3️⃣ 原有的系統帳戶以零餘額保留；
+// 4️⃣ 既有訂閱的 channel 被關閉。
+func TestReset(t *testing.T) {
+	b := NewBank()
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("C", 0)
+	tx, err := b.Apply(Op{Type: TxTransfer, From: a.ID, To: c.ID, Amount: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PayInterest(a.ID, 5); err != nil {
+		t.Fatal(err)
+	}
+	events, _, err := b.Subscribe(a.ID, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b.Reset()
+
+	// 1️⃣ 只剩系統帳戶
+	if list := b.List(); len(list) != 1 || list[0].ID != SystemAccountID {
+		t.Fatalf("after reset List=%+v want only the system account", list)
+	}
+	if _, err := b.Get(a.ID); err == nil {
+		t.Fatalf("account %s still exists after reset", a.ID)
+	}
+	if err := b.Reverse(tx.ID); err == nil {
+		t.Fatalf("transaction %s still reversible after reset", tx.ID)
+	}
+
+	// 2️⃣ ID 重新編號
+	n, _ := b.Create("New", 0)
+	if n.ID != "1" {
+		t.Fatalf("first id after reset=%q want \"1\"", n.ID)
+	}
+
+	// 3️⃣ 系統帳戶歸零
+	if sys, _ := b.Get(SystemAccountID); sys.Balance != 0 || len(sys.Logs) != 0 {
+		t.Fatalf("system account after reset=%+v", sys)
+	}
+
+	// 4️⃣ 訂閱已結束
+	if _, ok := <-events; ok {
+		t.Fatal("subscription channel still open after reset")
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify after reset: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+// internal/bank/retention.go
+//
+// 本檔實作每帳戶的日誌保留上限（SetMaxLogs）。
+// 長期使用的帳戶日誌會無限增長，拖累記憶體與快照大小；設定上限 n 後，
+// 每次寫入日誌若超過 n 筆即丟棄最舊的日誌，只保留最新的 n 筆（順序不變）。
+//   - 丟棄日誌的淨額併入帳戶的期初餘額（opening），Verify 的一致性檢查不受影響（見 verify.go）；
+//   - 備註累計位元組數（noteBytes）同步扣除（見 notes.go）；
+//   - 以重新切片丟棄、不改寫底層陣列，唯讀檢視與 atomic 批次的回滾副本仍然有效（見 readview.go、batch.go）；
+//   - 被丟棄的交易不再出現在查詢、對帳單與現金流中，也無法沖正；需要完整歷史時請啟用 journal（見 journal.go）。
+//
+// 還原快照時同樣套用上限（見 Restore）。
+
+package bank
+
+// SetMaxLogs 設定每個帳戶最多保留的日誌筆數，並立即套用至既有帳戶；n <= 0 代表不限制（預設）。
+func (b *Bank) SetMaxLogs(n int) {
+	b.mu.Lock()
+	defer b.unlock()
+	b.maxLogs = max(n, 0)
+	for _, a := range b.accts {
+		trimLogs(a, b.maxLogs)
+	}
+}
+
+// MaxLogs 回傳目前的每帳戶日誌保留上限（0 代表不限制）。
+func (b *Bank) MaxLogs() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.maxLogs
+}
+
+// trimLogs 丟棄 a 最舊的日誌，使其不超過 limit 筆；limit <= 0 時不做任何事。
+// 須在 a 的寫入保護下呼叫（mu 寫鎖，或 mu 讀鎖加帳戶鎖）。
+func trimLogs(a *Account, limit int) {
+	drop := len(a.Logs) - limit
+	if limit <= 0 || drop <= 0 {
+		return
+	}
+	dropped := a.Logs[:drop]
+	a.opening += logsNet(dropped)
+	for _, l := range dropped {
+		a.noteBytes -= len(l.Note)
+	}
+	a.Logs = a.Logs[drop:]
+}
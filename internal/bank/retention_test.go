@@ -0,0 +1,84 @@
+// internal/bank/retention_test.go
+//
+// 測試日誌保留上限：超過上限只保留最新的日誌（順序不變），帳本仍一致，快照與還原沿用裁切後的日誌。
+
+package bank
+
+import (
+	"testing"
+)
+
+// TestMaxLogs 驗證：
+// 1️⃣ 上限 3 時連續存款、提款與轉帳，每個帳戶只保留最新的 3 筆日誌，順序不變；
+// 2️⃣ 裁切後 Verify 仍一致（丟棄的淨額併入期初餘額）；
+// 3️⃣ 快照只含保留的日誌，還原至未設上限的銀行後仍一致；
+// 4️⃣ 對既有帳戶呼叫 SetMaxLogs 立即裁切，快照還原時亦套用上限。
+func TestMaxLogs(t *testing.T) {
+	b := NewBank()
+	b.SetMaxLogs(3)
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("C", 0)
+	for i := int64(1); i <= 5; i++ {
+		if _, err := b.Deposit(a.ID, NewMoney(i, "USD"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, _ = b.Withdraw(a.ID, NewMoney(10, "USD"), "")
+	if _, err := b.Apply(Op{Type: TxTransfer, From: a.ID, To: c.ID, Amount: 20}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 只保留最新的 3 筆：存款 5、提款 10、轉出 20
+	logs, err := b.Logs(a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		typ string
+		amt int64
+	}{{TxDeposit, 5}, {TxWithdraw, 10}, {TxTransfer, 20}}
+	if len(logs) != len(want) {
+		t.Fatalf("len(logs)=%d want %d: %+v", len(logs), len(want), logs)
+	}
+	for i, w := range want {
+		if logs[i].Type != w.typ || logs[i].Amount != w.amt {
+			t.Fatalf("logs[%d]=%s %d want %s %d", i, logs[i].Type, logs[i].Amount, w.typ, w.amt)
+		}
+	}
+
+	// 2️⃣ 帳本一致
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify after trimming: %v", err)
+	}
+
+	// 3️⃣ 快照只含保留的日誌，還原後仍一致
+	snap := b.Snapshot()
+	for _, pa := range snap.Accounts {
+		if len(pa.Logs) > 3 {
+			t.Fatalf("snapshot account %s has %d logs want <= 3", pa.ID, len(pa.Logs))
+		}
+	}
+	r := NewBank()
+	r.Restore(snap)
+	if err := r.Verify(); err != nil {
+		t.Fatalf("Verify after restore: %v", err)
+	}
+
+	// 4️⃣ 對既有帳戶立即裁切；還原時套用上限
+	r.SetMaxLogs(1)
+	if logs, _ := r.Logs(a.ID); len(logs) != 1 || logs[0].Type != TxTransfer {
+		t.Fatalf("after SetMaxLogs(1): %+v", logs)
+	}
+	s := NewBank()
+	s.SetMaxLogs(2)
+	s.Restore(snap)
+	if logs, _ := s.Logs(a.ID); len(logs) != 2 || logs[0].Type != TxWithdraw {
+		t.Fatalf("restore with cap 2: %+v", logs)
+	}
+	if err := r.Verify(); err != nil {
+		t.Fatalf("Verify after SetMaxLogs: %v", err)
+	}
+	if err := s.Verify(); err != nil {
+		t.Fatalf("Verify after capped restore: %v", err)
+	}
+}
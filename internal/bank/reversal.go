@@ -0,0 +1,99 @@
+// internal/bank/reversal.go
+//
+// 本檔實作轉帳沖正（reversal）：誤轉的款項由原收款方退回原付款方，並留下稽核軌跡。
+//   - 沖正是一筆新的交易（Type 為 TxReversal，配發新的 TxID），雙邊日誌的 Ref 指向原交易的 TxID；
+//     原交易的日誌保持不變。
+//   - 只有已過帳的轉帳可沖正；存款、提款、利息、手續費與沖正交易本身回傳 ErrNotReversible。
+//   - 每筆轉帳最多沖正一次，是否已沖正由原收款方日誌中是否有 Ref 指向它判斷（因此隨快照保存）。
+//   - 原收款方的可動用額度不足（規則同提款，見 overdraft.go）時回傳 ErrInsufficient；
+//     任一方已關閉或凍結時同樣拒絕。原交易收取的手續費不退還。
+//
+// 沖正經由 Apply（Op{Type: TxReversal, Ref: 原 TxID}），因此同樣寫入 journal、可重播。
+
+package bank
+
+import "slices"
+
+// Reverse 沖正 txID 指定的轉帳：將原金額由原收款方退回原付款方。
+// 交易不存在回傳 ErrTxNotFound；不是轉帳回傳 ErrNotReversible；已沖正過回傳 ErrAlreadyReversed。
+func (b *Bank) Reverse(txID string) error {
+	_, err := b.Apply(Op{Type: TxReversal, Ref: txID})
+	return err
+}
+
+// reverse 為沖正核心邏輯，回傳已提交的沖正交易；須在 mu 保護下呼叫（並鎖定原交易的雙方帳戶）。
+func (b *Bank) reverse(ref string) (Tx, error) {
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
+	orig, ok := b.lookupTx(ref)
+	if !ok {
+		return Tx{}, ErrTxNotFound
+	}
+	if orig.Type != TxTransfer {
+		return Tx{}, ErrNotReversible
+	}
+	from, ok1 := b.accts[orig.To] // 沖正的付款方 = 原收款方
+	to, ok2 := b.accts[orig.From]
+	if !ok1 || !ok2 {
+		return Tx{}, ErrNotFound
+	}
+	if reversed(from, ref) {
+		return Tx{}, ErrAlreadyReversed
+	}
+	if err := from.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if err := to.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	b.expireHoldsFor(from)
+	if !from.canDebit(orig.Amount) {
+		return Tx{}, ErrInsufficient
+	}
+	if err := to.checkCredit(orig.Amount); err != nil {
+		return Tx{}, err
+	}
+	fromNote, err := b.fitNote(from, TxReversal)
+	if err != nil {
+		return Tx{}, err
+	}
+	toNote, err := b.fitNote(to, TxReversal)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	tx := b.newTx(TxReversal)
+	tx.From, tx.To, tx.Amount, tx.Ref = from.ID, to.ID, orig.Amount, ref
+	from.Balance -= tx.Amount
+	to.Balance += tx.Amount
+	b.addLog(from, Log{Time: tx.Time, TxID: tx.ID, Type: TxReversal, Amount: tx.Amount, Direction: "out", CounterID: to.ID, Note: fromNote, Ref: ref})
+	b.addLog(to, Log{Time: tx.Time, TxID: tx.ID, Type: TxReversal, Amount: tx.Amount, Direction: "in", CounterID: from.ID, Note: toNote, Ref: ref})
+	b.indexTx(tx.ID, from.ID, to.ID)
+	return tx, nil
+}
+
+// lookupTx 同 FindTx，但不取鎖；須在 mu 保護下呼叫（並鎖定該交易的帳戶）。
+func (b *Bank) lookupTx(txID string) (Tx, bool) {
+	b.txMu.Lock()
+	ids := slices.Clone(b.txIndex[txID])
+	b.txMu.Unlock()
+	for _, id := range ids {
+		if a, ok := b.accts[id]; ok {
+			if tx, ok := txFromLogs(a, txID); ok {
+				return tx, true
+			}
+		}
+	}
+	return Tx{}, false
+}
+
+// reversed 回報 a 的日誌中是否已有沖正 ref 的紀錄。
+func reversed(a *Account, ref string) bool {
+	for i := len(a.Logs) - 1; i >= 0; i-- {
+		if l := a.Logs[i]; l.Type == TxReversal && l.Ref == ref {
+			return true
+		}
+	}
+	return false
+}
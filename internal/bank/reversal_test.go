@@ -0,0 +1,96 @@
+// internal/bank/reversal_test.go
+//
+// 測試轉帳沖正：成功退回並留下指向原交易的日誌、不可重複沖正、非轉帳不可沖正，
+// 以及原收款方餘額不足時拒絕且不改變任何狀態。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestReverse(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+	tr, err := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 沖正：款項退回原付款方
+	if err := b.Reverse(tr.ID); err != nil {
+		t.Fatalf("reverse: %v", err)
+	}
+	if g1, g2 := get(t, b, a1.ID).Balance, get(t, b, a2.ID).Balance; g1 != 1000 || g2 != 0 {
+		t.Fatalf("balances=%d/%d want 1000/0", g1, g2)
+	}
+
+	// 2️⃣ 雙邊新增沖正日誌，Ref 指向原交易；原交易日誌不變
+	logs1, _ := b.Logs(a1.ID)
+	logs2, _ := b.Logs(a2.ID)
+	in, out := logs1[len(logs1)-1], logs2[len(logs2)-1]
+	if in.Type != TxReversal || in.Direction != "in" || in.Ref != tr.ID || in.Amount != 300 || in.CounterID != a2.ID {
+		t.Fatalf("payer reversal log=%+v", in)
+	}
+	if out.Type != TxReversal || out.Direction != "out" || out.Ref != tr.ID || out.TxID != in.TxID {
+		t.Fatalf("receiver reversal log=%+v", out)
+	}
+	if orig, _ := b.FindTx(tr.ID); orig.Type != TxTransfer || orig.From != a1.ID {
+		t.Fatalf("original tx=%+v", orig)
+	}
+	if rev, _ := b.FindTx(in.TxID); rev.Type != TxReversal || rev.Ref != tr.ID || rev.From != a2.ID || rev.To != a1.ID {
+		t.Fatalf("reversal tx=%+v", rev)
+	}
+
+	// ❌ 重複沖正、沖正沖正交易、沖正存款、不存在的交易
+	if err := b.Reverse(tr.ID); !errors.Is(err, ErrAlreadyReversed) {
+		t.Fatalf("second reverse want ErrAlreadyReversed, got %v", err)
+	}
+	if err := b.Reverse(in.TxID); !errors.Is(err, ErrNotReversible) {
+		t.Fatalf("reverse of reversal want ErrNotReversible, got %v", err)
+	}
+	dep, _ := b.Apply(Op{Type: TxDeposit, Account: a1.ID, Amount: 1})
+	if err := b.Reverse(dep.ID); !errors.Is(err, ErrNotReversible) {
+		t.Fatalf("reverse deposit want ErrNotReversible, got %v", err)
+	}
+	if err := b.Reverse("tx-999"); !errors.Is(err, ErrTxNotFound) {
+		t.Fatalf("want ErrTxNotFound, got %v", err)
+	}
+
+	// ✅ 已沖正狀態隨快照保存
+	restored := NewBank()
+	restored.Restore(b.Snapshot())
+	if err := restored.Reverse(tr.ID); !errors.Is(err, ErrAlreadyReversed) {
+		t.Fatalf("restored reverse want ErrAlreadyReversed, got %v", err)
+	}
+}
+
+// TestReverseInsufficientFunds 驗證原收款方已將款項轉出時，沖正被拒且不改變任何狀態；補足後可沖正。
+func TestReverseInsufficientFunds(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 500)
+	a2, _ := b.Create("B", 0)
+	tr, _ := b.Apply(Op{Type: TxTransfer, From: a1.ID, To: a2.ID, Amount: 200})
+	if _, err := b.Withdraw(a2.ID, NewMoney(150, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	before, _ := b.Logs(a2.ID)
+
+	if err := b.Reverse(tr.ID); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+	after, _ := b.Logs(a2.ID)
+	if g1, g2 := get(t, b, a1.ID).Balance, get(t, b, a2.ID).Balance; g1 != 300 || g2 != 50 || len(after) != len(before) {
+		t.Fatalf("state changed: balances=%d/%d logs=%d->%d", g1, g2, len(before), len(after))
+	}
+
+	_, _ = b.Deposit(a2.ID, NewMoney(150, "USD"), "")
+	if err := b.Reverse(tr.ID); err != nil {
+		t.Fatalf("reverse after top-up: %v", err)
+	}
+	if g1 := get(t, b, a1.ID).Balance; g1 != 500 {
+		t.Fatalf("payer balance=%d want 500", g1)
+	}
+}
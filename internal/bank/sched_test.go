@@ -0,0 +1,192 @@
+// internal/bank/sched_test.go
+//
+// 測試用的決定性排程器：以種子（seed）決定多個 worker goroutine 的操作交錯順序，
+// 讓並行測試中的偶發失敗可以重現。
+//
+// 每個 worker 各自在 goroutine 中執行，但必須取得排程器發出的「輪次」才能執行下一個操作；
+// 排程器以 seed 初始化的亂數選擇下一個 worker，因此同一 seed 必定產生相同的交錯與最終狀態。
+// 測試失敗時會印出 seed，可用 BANK_TEST_SEED=<seed> go test -run <Test> 重播。
+
+package bank
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// testSeed 取得本次測試的 seed：優先使用 BANK_TEST_SEED，否則以目前時間產生；
+// 測試失敗時自動印出 seed 以便重播。
+func testSeed(t *testing.T) uint64 {
+	t.Helper()
+	seed := uint64(time.Now().UnixNano())
+	if v := os.Getenv("BANK_TEST_SEED"); v != "" {
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			t.Fatalf("BANK_TEST_SEED=%q: %v", v, err)
+		}
+		seed = n
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("reproduce with: BANK_TEST_SEED=%d go test -run '^%s$' ./internal/bank", seed, t.Name())
+		}
+	})
+	return seed
+}
+
+// randomOps 以 rng 產生 n 個在 ids 之間的隨機存款 / 提款 / 轉帳操作。
+func randomOps(rng *rand.Rand, ids []string, n int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		amt := rng.Int64N(50) + 1
+		switch rng.IntN(3) {
+		case 0:
+			ops[i] = Op{Type: TxDeposit, Account: ids[rng.IntN(len(ids))], Amount: amt}
+		case 1:
+			ops[i] = Op{Type: TxWithdraw, Account: ids[rng.IntN(len(ids))], Amount: amt}
+		default:
+			from, to := rng.IntN(len(ids)), rng.IntN(len(ids)-1)
+			if to >= from {
+				to++
+			}
+			ops[i] = Op{Type: TxTransfer, From: ids[from], To: ids[to], Amount: amt}
+		}
+	}
+	return ops
+}
+
+// step 為排程軌跡中的一步：哪個 worker 執行了哪個操作，以及結果（空字串為成功）。
+type step struct {
+	Worker int
+	Op     Op
+	Err    string
+}
+
+// runScheduled 讓每個 worker 在自己的 goroutine 中依序執行 queues[w]，
+// 由 seed 決定每一步輪到哪個 worker；回傳實際執行順序的軌跡（含每步結果）。
+func runScheduled(b *Bank, seed uint64, queues [][]Op) []step {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	turns := make([]chan struct{}, len(queues))
+	done := make(chan step)
+	for w, q := range queues {
+		turns[w] = make(chan struct{})
+		go func() {
+			for _, op := range q {
+				<-turns[w]
+				st := step{Worker: w, Op: op}
+				if _, err := b.Apply(op); err != nil {
+					st.Err = err.Error()
+				}
+				done <- st
+			}
+		}()
+	}
+
+	remaining := make([]int, len(queues))
+	live := make([]int, 0, len(queues))
+	for w, q := range queues {
+		remaining[w] = len(q)
+		if len(q) > 0 {
+			live = append(live, w)
+		}
+	}
+	var trace []step
+	for len(live) > 0 {
+		i := rng.IntN(len(live))
+		w := live[i]
+		turns[w] <- struct{}{}
+		trace = append(trace, <-done)
+		if remaining[w]--; remaining[w] == 0 {
+			live = append(live[:i], live[i+1:]...)
+		}
+	}
+	return trace
+}
+
+// scheduledRun 以 seed 建立銀行、產生各 worker 的操作並排程執行，回傳軌跡與最終快照。
+// 時鐘固定推進，使交易時間也與 seed 一致。
+func scheduledRun(seed uint64) ([]step, any) {
+	clk := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(func() time.Time { clk.Advance(time.Second); return clk.Now() })
+	ids := make([]string, 4)
+	for i := range ids {
+		a, _ := b.Create(fmt.Sprintf("acct%d", i), 500)
+		ids[i] = a.ID
+	}
+
+	rng := rand.New(rand.NewPCG(seed, ^seed))
+	queues := make([][]Op, 8)
+	for w := range queues {
+		queues[w] = randomOps(rng, ids, 40)
+	}
+	return runScheduled(b, seed, queues), b.Snapshot()
+}
+
+// TestScheduledInterleavingsConserveFunds 以隨機 seed 交錯執行多個 worker 的操作，
+// 驗證原子性不變量：無負餘額、轉帳前後資金守恆（僅存提款改變總額）。
+func TestScheduledInterleavingsConserveFunds(t *testing.T) {
+	seed := testSeed(t)
+	b := NewBank()
+	ids := make([]string, 5)
+	for i := range ids {
+		a, _ := b.Create("acct", 200)
+		ids[i] = a.ID
+	}
+	rng := rand.New(rand.NewPCG(seed, ^seed))
+	queues := make([][]Op, 6)
+	var want int64 = 5 * 200
+	for w := range queues {
+		queues[w] = randomOps(rng, ids, 50)
+	}
+
+	// 依軌跡中成功的存提款推算預期總額
+	for _, st := range runScheduled(b, seed, queues) {
+		if st.Err != "" {
+			continue
+		}
+		switch st.Op.Type {
+		case TxDeposit:
+			want += st.Op.Amount
+		case TxWithdraw:
+			want -= st.Op.Amount
+		}
+	}
+
+	var total int64
+	for _, a := range b.List() {
+		if a.Balance < 0 {
+			t.Fatalf("negative balance: %+v", a)
+		}
+		total += a.Balance
+	}
+	if total != want {
+		t.Fatalf("total=%d want %d", total, want)
+	}
+}
+
+// TestSchedulerSeedReproducible 為排程器本身的測試：
+// 1️⃣ 同一 seed 執行兩次，操作順序與最終狀態完全相同；
+// 2️⃣ 不同 seed 產生不同的交錯順序。
+func TestSchedulerSeedReproducible(t *testing.T) {
+	seed := testSeed(t)
+
+	trace1, snap1 := scheduledRun(seed)
+	trace2, snap2 := scheduledRun(seed)
+	if !reflect.DeepEqual(trace1, trace2) {
+		t.Fatalf("same seed produced different order:\n%v\n%v", trace1, trace2)
+	}
+	if !reflect.DeepEqual(snap1, snap2) {
+		t.Fatalf("same seed produced different final state:\n%+v\n%+v", snap1, snap2)
+	}
+
+	trace3, _ := scheduledRun(seed + 1)
+	if reflect.DeepEqual(trace1, trace3) {
+		t.Fatalf("different seeds produced identical order")
+	}
+}
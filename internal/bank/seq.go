@@ -0,0 +1,58 @@
+// internal/bank/seq.go
+//
+// 本檔實作日誌序號（Log.Seq）：每筆新日誌在寫入時（持有帳戶鎖）配發一個全行遞增的序號。
+// 時間戳可能因時鐘解析度而相同，甚至因注入的時鐘倒退；序號則提供與牆鐘無關的全序：
+//   - 全行唯一、嚴格遞增；同一帳戶內的日誌序號依寫入順序遞增；
+//   - 轉帳的雙邊日誌時間相同，但序號相鄰（先轉出、後轉入）；
+//   - atomic 批次回滾時連同序號一併還原（持有 mu 寫鎖，不會有其他寫入者），不留空缺；
+//   - 最近配發的序號隨快照保存（next_seq）；舊版快照中沒有序號的日誌於還原時依時間補發（見 restoreSeqs）。
+// Logs、對帳單與全行交易流皆依序號排序。
+
+package bank
+
+import (
+	"slices"
+	"sort"
+	"sync/atomic"
+)
+
+// nextLogSeq 配發下一個日誌序號；快速路徑下多個帳戶可同時寫入，因此以原子遞增。
+func (b *Bank) nextLogSeq() int64 {
+	return atomic.AddInt64(&b.logSeq, 1)
+}
+
+// restoreSeqs 於 Restore 後校正序號：logSeq 不小於任何既有日誌的序號；
+// 沒有序號的日誌（舊版快照）依時間排序後補發，時間相同者維持帳戶 ID 與原始順序。須持有 mu 寫鎖。
+func (b *Bank) restoreSeqs() {
+	var missing []*Log
+	for _, a := range b.sortedAccounts() {
+		for i := range a.Logs {
+			l := &a.Logs[i]
+			if l.Seq == 0 {
+				missing = append(missing, l)
+			}
+			b.logSeq = max(b.logSeq, l.Seq)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool { return missing[i].Time.Before(missing[j].Time) })
+	for _, l := range missing {
+		b.logSeq++
+		l.Seq = b.logSeq
+	}
+}
+
+// sortBySeq 將日誌依序號由小到大排序。
+func sortBySeq(logs []Log) {
+	slices.SortFunc(logs, func(x, y Log) int { return cmpInt64(x.Seq, y.Seq) })
+}
+
+// cmpInt64 為 int64 的三向比較。
+func cmpInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
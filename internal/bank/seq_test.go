@@ -0,0 +1,111 @@
+// internal/bank/seq_test.go
+//
+// 測試日誌序號：大量並發操作後序號全行唯一、各帳戶內嚴格遞增，全行交易流依序號排序；舊版快照還原時補發序號。
+
+package bank
+
+import (
+	"sync"
+	"testing"
+
+	"banking/internal/storage"
+)
+
+// TestLogSeqConcurrent 以多個 goroutine 同時存款、提款與轉帳，驗證：
+// 1️⃣ 所有日誌的序號不重複，且每個帳戶內嚴格遞增；
+// 2️⃣ AllLogs 依序號嚴格遞增輸出；
+// 3️⃣ 快照還原後新日誌的序號接續在最大序號之後。
+func TestLogSeqConcurrent(t *testing.T) {
+	b := NewBank()
+	var ids []string
+	for i := 0; i < 4; i++ {
+		a, _ := b.Create("acct", 1_000_000)
+		ids = append(ids, a.ID)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				from, to := ids[(g+i)%len(ids)], ids[(g+i+1)%len(ids)]
+				switch i % 3 {
+				case 0:
+					_, _ = b.Deposit(from, NewMoney(1, "USD"), "")
+				case 1:
+					_, _ = b.Withdraw(from, NewMoney(1, "USD"), "")
+				default:
+					_ = b.Transfer(from, to, NewMoney(1, "USD"))
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	// 1️⃣ 唯一且帳戶內遞增
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		logs, _ := b.Logs(id)
+		for i, l := range logs {
+			if l.Seq <= 0 || seen[l.Seq] {
+				t.Fatalf("account %s log %d: seq %d is zero or duplicated", id, i, l.Seq)
+			}
+			seen[l.Seq] = true
+			if i > 0 && l.Seq <= logs[i-1].Seq {
+				t.Fatalf("account %s: seq %d after %d", id, l.Seq, logs[i-1].Seq)
+			}
+		}
+	}
+	// 8 × 200 次操作：存款與提款各 1 筆日誌，轉帳 2 筆
+	if want := 8 * (67 + 67 + 66*2); len(seen) != want {
+		t.Fatalf("distinct seqs=%d want %d", len(seen), want)
+	}
+
+	// 2️⃣ 全行交易流依序號嚴格遞增
+	feed, err := b.AllLogs(FeedOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(feed); i++ {
+		if feed[i].Seq <= feed[i-1].Seq {
+			t.Fatalf("feed[%d].Seq=%d after %d", i, feed[i].Seq, feed[i-1].Seq)
+		}
+	}
+
+	// 3️⃣ 還原後接續配發
+	r := NewBank()
+	r.Restore(b.Snapshot())
+	_, _ = r.Deposit(ids[0], NewMoney(1, "USD"), "")
+	logs, _ := r.Logs(ids[0])
+	if last := logs[len(logs)-1].Seq; last != int64(len(seen))+1 {
+		t.Fatalf("seq after restore=%d want %d", last, len(seen)+1)
+	}
+}
+
+// TestRestoreAssignsMissingSeqs 確認沒有序號的舊版快照日誌於還原時依時間補發序號。
+func TestRestoreAssignsMissingSeqs(t *testing.T) {
+	snap := storage.Snapshot{NextID: 2, Accounts: []storage.PersistAccount{
+		{ID: "1", Name: "A", Balance: 30, Logs: []any{
+			map[string]any{"time": "2025-01-01T00:00:01Z", "type": "deposit", "amount": 10, "direction": "in"},
+			map[string]any{"time": "2025-01-01T00:00:03Z", "type": "deposit", "amount": 10, "direction": "in"},
+		}},
+		{ID: "2", Name: "B", Balance: 10, Logs: []any{
+			map[string]any{"time": "2025-01-01T00:00:02Z", "type": "deposit", "amount": 10, "direction": "in"},
+		}},
+	}}
+	b := NewBank()
+	b.Restore(snap)
+	feed, _ := b.AllLogs(FeedOptions{})
+	want := []struct {
+		acct string
+		seq  int64
+	}{{"1", 1}, {"2", 2}, {"1", 3}}
+	if len(feed) != len(want) {
+		t.Fatalf("feed=%+v", feed)
+	}
+	for i, w := range want {
+		if feed[i].AccountID != w.acct || feed[i].Seq != w.seq {
+			t.Fatalf("feed[%d]=%s seq %d want %s seq %d", i, feed[i].AccountID, feed[i].Seq, w.acct, w.seq)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// internal/bank/snapshot_test.go
+//
+// 測試 Snapshot 的持鎖範圍：只在值拷貝期間持鎖，轉換為儲存格式時異動可照常進行，
+// 且之後的異動不會改變已取得的快照；並行轉帳期間取得的每份快照都自身一致。
+
+package bank
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestSnapshotDoesNotBlockMutations 驗證：
+// 1️⃣ 持鎖拷貝完成後（snapshotCopied 掛勾內），存款與轉帳不會被進行中的快照阻擋；
+// 2️⃣ 快照內容為拷貝當下的狀態，不含掛勾內完成的異動；
+// 3️⃣ 帳本本身包含這些異動，且 Verify 一致。
+func TestSnapshotDoesNotBlockMutations(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1000)
+	c, _ := b.Create("C", 0)
+	for range 10 {
+		if _, err := b.Deposit(a.ID, NewMoney(1, "USD"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshotCopied = func() {
+		done := make(chan error, 1)
+		go func() {
+			if _, err := b.Deposit(a.ID, NewMoney(5, "USD"), ""); err != nil {
+				done <- err
+				return
+			}
+			done <- b.Transfer(a.ID, c.ID, NewMoney(100, "USD"))
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("mutation during snapshot: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("mutations blocked while the snapshot was being encoded")
+		}
+	}
+	defer func() { snapshotCopied = nil }()
+	snap := b.Snapshot()
+
+	got := snap.Accounts[0]
+	if got.ID != a.ID || got.Balance != 1010 || len(got.Logs) != 10 {
+		t.Fatalf("snapshot account=%s balance=%d logs=%d want %s/1010/10", got.ID, got.Balance, len(got.Logs), a.ID)
+	}
+	if snap.Accounts[1].Balance != 0 || len(snap.Accounts[1].Logs) != 0 {
+		t.Fatalf("snapshot C=%+v want untouched", snap.Accounts[1])
+	}
+	if acc := get(t, b, a.ID); acc.Balance != 915 || len(acc.Logs) != 12 {
+		t.Fatalf("live A balance=%d logs=%d want 915/12", acc.Balance, len(acc.Logs))
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSnapshotConsistentDuringTransfers 於多個 goroutine 持續轉帳時反覆取得快照，驗證每份快照自身一致：
+// 1️⃣ 每個帳戶的餘額 = 開戶餘額 + 自身日誌淨額（日誌與餘額取自同一時點）；
+// 2️⃣ 所有帳戶餘額總和不變（轉帳的雙邊不會只有一邊進入快照）；
+// 3️⃣ 結束後帳本本身 Verify 一致。
+func TestSnapshotConsistentDuringTransfers(t *testing.T) {
+	const (
+		accounts = 8
+		initial  = 10_000
+		workers  = 4
+	)
+	b := NewBank()
+	var ids []string
+	for i := range accounts {
+		a, _ := b.Create(fmt.Sprintf("a%d", i), initial)
+		ids = append(ids, a.ID)
+	}
+
+	var stop atomic.Bool
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; !stop.Load(); i++ {
+				from, to := ids[i%accounts], ids[(i*3+1)%accounts]
+				if from == to {
+					continue
+				}
+				_ = b.Transfer(from, to, NewMoney(int64(i%50+1), "USD")) // 餘額不足時失敗，不影響一致性
+			}
+		}()
+	}
+
+	for n := range 200 {
+		snap := b.Snapshot()
+		var total int64
+		for _, pa := range snap.Accounts {
+			logs := make([]Log, len(pa.Logs))
+			for i, v := range pa.Logs {
+				logs[i] = v.(Log)
+			}
+			if got := *pa.OpeningBalance + logsNet(logs); got != pa.Balance {
+				t.Errorf("snapshot %d account %s: opening+logs=%d balance=%d", n, pa.ID, got, pa.Balance)
+			}
+			total += pa.Balance
+		}
+		if total != accounts*initial {
+			t.Errorf("snapshot %d total balance=%d want %d", n, total, accounts*initial)
+		}
+	}
+	stop.Store(true)
+	wg.Wait()
+	if err := b.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// BenchmarkDepositDuringSnapshot 量測大型帳本不斷保存快照時的存款延遲。
+// 持鎖期間只做值拷貝，存款僅需等待拷貝，而非整個轉換過程。
+func BenchmarkDepositDuringSnapshot(bm *testing.B) {
+	b := NewBank()
+	var ids []string
+	for i := range 2000 {
+		a, _ := b.Create(fmt.Sprintf("a%d", i), 0)
+		for range 50 {
+			_, _ = b.Deposit(a.ID, NewMoney(1, "USD"), "")
+		}
+		ids = append(ids, a.ID)
+	}
+
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for !stop.Load() {
+			_ = b.Snapshot()
+		}
+	}()
+
+	bm.ResetTimer()
+	for i := 0; i < bm.N; i++ {
+		_, _ = b.Deposit(ids[i%len(ids)], NewMoney(1, "USD"), "")
+	}
+	bm.StopTimer()
+	stop.Store(true)
+	<-done
+}
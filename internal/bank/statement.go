@@ -0,0 +1,85 @@
+// internal/bank/statement.go
+//
+// 本檔提供帳戶對帳單：指定期間 [from, to) 的期初 / 期末餘額、各類交易合計與期間內的日誌。
+// 計算完全依據日誌（同 netflow.go），以目前餘額往回推算：
+//   - 期末餘額 = 目前餘額 - to 之後所有交易的淨變動；
+//   - 期初餘額 = 期末餘額 - 期間內的淨變動。
+//
+// 開戶時的初始餘額沒有日誌，因此早於開戶的期間其期初餘額即為初始餘額。
+// 利息、手續費與沖正不屬於存提款或一般轉帳，分別計入 OtherIn / OtherOut，
+// 確保「期初 + 各項流入 - 各項流出 = 期末」恆成立。
+
+package bank
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statement 為帳戶在期間 [From, To) 的對帳單；From / To 為零值代表該端不設限。
+type Statement struct {
+	Account        string    `json:"account"`
+	From           time.Time `json:"from,omitzero"`
+	To             time.Time `json:"to,omitzero"`
+	OpeningBalance int64     `json:"opening_balance"`
+	ClosingBalance int64     `json:"closing_balance"`
+	Deposits       int64     `json:"total_deposits"`
+	Withdrawals    int64     `json:"total_withdrawals"`
+	TransfersIn    int64     `json:"total_transferred_in"`
+	TransfersOut   int64     `json:"total_transferred_out"`
+	OtherIn        int64     `json:"other_in"`  // 利息、沖正等其他流入
+	OtherOut       int64     `json:"other_out"` // 手續費、沖正等其他流出
+	Logs           []Log     `json:"logs"`      // 期間內的日誌（依時間順序）
+}
+
+// Statement 計算帳戶 id 在 [from, to) 期間的對帳單。
+// from 晚於 to 時回傳 ErrBadFilter；帳戶不存在回傳 ErrNotFound。
+func (b *Bank) Statement(id string, from, to time.Time) (Statement, error) {
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return Statement{}, fmt.Errorf("%w: from is after to", ErrBadFilter)
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	a, ok := b.accts[id]
+	if !ok {
+		return Statement{}, ErrNotFound
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	st := Statement{Account: id, From: from, To: to, Logs: []Log{}}
+	window := LogFilter{Since: from, Until: to}
+	var after int64 // to 之後的淨變動
+	for _, l := range a.Logs {
+		signed := l.Amount
+		if l.Direction == "out" {
+			signed = -l.Amount
+		}
+		if !to.IsZero() && !l.Time.Before(to) {
+			after += signed
+			continue
+		}
+		if !window.match(l) {
+			continue
+		}
+		st.Logs = append(st.Logs, l)
+		switch {
+		case l.Type == TxDeposit:
+			st.Deposits += l.Amount
+		case l.Type == TxWithdraw:
+			st.Withdrawals += l.Amount
+		case l.Type == TxTransfer && l.Direction == "in":
+			st.TransfersIn += l.Amount
+		case l.Type == TxTransfer:
+			st.TransfersOut += l.Amount
+		case l.Direction == "in":
+			st.OtherIn += l.Amount
+		default:
+			st.OtherOut += l.Amount
+		}
+	}
+	sortBySeq(st.Logs)
+	st.ClosingBalance = a.Balance - after
+	st.OpeningBalance = st.ClosingBalance - (st.Deposits + st.TransfersIn + st.OtherIn) + (st.Withdrawals + st.TransfersOut + st.OtherOut)
+	return st, nil
+}
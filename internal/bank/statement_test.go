@@ -0,0 +1,70 @@
+// internal/bank/statement_test.go
+//
+// 測試對帳單：以已知的操作序列驗證期間內各項合計、期初 / 期末餘額與日誌篩選。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatement(t *testing.T) {
+	clk := &fakeClock{t: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)}
+	b := NewBank()
+	b.SetClock(clk.Now)
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 1000)
+	other, _ := b.Create("B", 500)
+
+	// 3/1：期間前
+	_, _ = b.Deposit(a.ID, NewMoney(100, "USD"), "") // 1100
+
+	// 3/2 ~ 3/3：期間內
+	clk.Advance(24 * time.Hour)
+	_, _ = b.Deposit(a.ID, NewMoney(200, "USD"), "")     // 1300
+	_, _ = b.Withdraw(a.ID, NewMoney(50, "USD"), "")     // 1250
+	_ = b.Transfer(a.ID, other.ID, NewMoney(300, "USD")) // 950
+	clk.Advance(24 * time.Hour)
+	_ = b.Transfer(other.ID, a.ID, NewMoney(120, "USD")) // 1070
+	_, _ = b.PayInterest(a.ID, 5)                        // 1075
+
+	// 3/4：期間後
+	clk.Advance(24 * time.Hour)
+	_, _ = b.Withdraw(a.ID, NewMoney(75, "USD"), "") // 1000
+
+	from := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	st, err := b.Statement(a.ID, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 各項合計
+	if st.Deposits != 200 || st.Withdrawals != 50 || st.TransfersIn != 120 || st.TransfersOut != 300 || st.OtherIn != 5 || st.OtherOut != 0 {
+		t.Fatalf("totals=%+v", st)
+	}
+	// 2️⃣ 期初 / 期末餘額
+	if st.OpeningBalance != 1100 || st.ClosingBalance != 1075 {
+		t.Fatalf("opening=%d closing=%d want 1100/1075", st.OpeningBalance, st.ClosingBalance)
+	}
+	// 3️⃣ 只含期間內的日誌
+	if len(st.Logs) != 5 || st.Logs[0].Amount != 200 || st.Logs[4].Type != TxInterest {
+		t.Fatalf("logs=%+v", st.Logs)
+	}
+
+	// ✅ 不設限的期間：期初為開戶餘額，期末為目前餘額
+	all, _ := b.Statement(a.ID, time.Time{}, time.Time{})
+	if all.OpeningBalance != 1000 || all.ClosingBalance != 1000 || len(all.Logs) != 7 {
+		t.Fatalf("unbounded statement opening=%d closing=%d logs=%d", all.OpeningBalance, all.ClosingBalance, len(all.Logs))
+	}
+
+	// ❌ 期間顛倒、帳戶不存在
+	if _, err := b.Statement(a.ID, to, from); !errors.Is(err, ErrBadFilter) {
+		t.Fatalf("want ErrBadFilter, got %v", err)
+	}
+	if _, err := b.Statement("999", from, to); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+// internal/bank/subscribe.go
+//
+// 本檔實作帳戶日誌的訂閱（pub/sub），供即時儀表板等串流介面使用（見 server 的 /accounts/{id}/events）。
+//   - Subscribe 為單一帳戶註冊一個有緩衝的 channel；之後寫入該帳戶的每筆日誌都會送入 channel；
+//   - 送出時不阻塞：訂閱者的緩衝已滿（消費太慢）時直接丟棄該事件，交易處理不受影響；
+//   - atomic 批次（見 batch.go）的日誌待整批提交後才送出，回滾的操作不會出現在訂閱中；
+//   - 取消訂閱會關閉 channel；訂閱只存在於記憶體，不寫入快照。
+
+package bank
+
+// defaultSubscribeBuffer 為 Subscribe 未指定緩衝大小時的預設值。
+const defaultSubscribeBuffer = 64
+
+// Subscribe 訂閱帳戶 id 之後新增的日誌，回傳接收用 channel 與取消函式（可重複呼叫）。
+// buf 為緩衝大小（<= 0 時使用 defaultSubscribeBuffer）；帳戶不存在回傳 ErrNotFound。
+// 呼叫端不再讀取時務必呼叫取消函式，否則訂閱會一直保留。
+func (b *Bank) Subscribe(id string, buf int) (<-chan Log, func(), error) {
+	b.mu.RLock()
+	_, ok := b.accts[id]
+	b.mu.RUnlock()
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	if buf <= 0 {
+		buf = defaultSubscribeBuffer
+	}
+	ch := make(chan Log, buf)
+	b.subMu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[string]map[chan Log]struct{})
+	}
+	if b.subs[id] == nil {
+		b.subs[id] = make(map[chan Log]struct{})
+	}
+	b.subs[id][ch] = struct{}{}
+	b.subMu.Unlock()
+
+	cancel := func() {
+		b.subMu.Lock()
+		defer b.subMu.Unlock()
+		if _, ok := b.subs[id][ch]; !ok {
+			return
+		}
+		delete(b.subs[id], ch)
+		if len(b.subs[id]) == 0 {
+			delete(b.subs, id)
+		}
+		close(ch)
+	}
+	return ch, cancel, nil
+}
+
+// addLog 寫入日誌並通知該帳戶的訂閱者；須在 mu 保護下呼叫（並鎖定 a）。
+// 還原快照等非新交易的情境改用 appendLog，不通知訂閱者。
+func (b *Bank) addLog(a *Account, l Log) {
+	l.Seq = b.nextLogSeq()
+	appendLog(a, l)
+	trimLogs(a, b.maxLogs)
+	a.bump()
+	if b.deferLogs != nil { // atomic 批次進行中（持有 mu 寫鎖）：提交後才送出
+		*b.deferLogs = append(*b.deferLogs, accountLog{id: a.ID, log: l})
+		return
+	}
+	b.notify(a.ID, l)
+}
+
+// notify 將 l 送給帳戶 id 的所有訂閱者；緩衝已滿的訂閱者直接略過。
+func (b *Bank) notify(id string, l Log) {
+	b.subMu.Lock()
+	defer b.subMu.Unlock()
+	for ch := range b.subs[id] {
+		select {
+		case ch <- l:
+		default: // 消費太慢：丟棄，不阻塞交易
+		}
+	}
+}
+
+// accountLog 為 atomic 批次中暫存、待提交後送出的日誌。
+type accountLog struct {
+	id  string
+	log Log
+}
@@ -0,0 +1,80 @@
+// internal/bank/subscribe_test.go
+//
+// 測試帳戶日誌訂閱：收到新日誌、慢速訂閱者被丟棄而不阻塞、取消後關閉 channel、
+// atomic 批次回滾時不送出通知。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	b := NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	if _, _, err := b.Subscribe("999", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+	ch, cancel, err := b.Subscribe(a2.ID, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 存款與轉入都會通知；其他帳戶的異動不會
+	_, _ = b.Deposit(a2.ID, NewMoney(5, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, NewMoney(10, "USD"))
+	_, _ = b.Deposit(a1.ID, NewMoney(1, "USD"), "")
+	if l := <-ch; l.Type != TxDeposit || l.Amount != 5 || l.Direction != "in" {
+		t.Fatalf("first event=%+v", l)
+	}
+	if l := <-ch; l.Type != TxTransfer || l.Amount != 10 || l.CounterID != a1.ID {
+		t.Fatalf("second event=%+v", l)
+	}
+	select {
+	case l := <-ch:
+		t.Fatalf("unexpected event %+v", l)
+	default:
+	}
+
+	// 2️⃣ atomic 批次回滾：不送出任何通知
+	_, err = b.ApplyBatch([]Op{
+		{Type: TxDeposit, Account: a2.ID, Amount: 1},
+		{Type: TxWithdraw, Account: a2.ID, Amount: 1_000_000},
+	}, true)
+	if !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("batch want ErrInsufficient, got %v", err)
+	}
+	select {
+	case l := <-ch:
+		t.Fatalf("rolled-back batch leaked event %+v", l)
+	default:
+	}
+
+	// 3️⃣ 取消後 channel 關閉；重複取消無副作用
+	cancel()
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+// TestSubscribeSlowConsumer 驗證緩衝已滿的訂閱者只會遺失事件，不會阻塞交易。
+func TestSubscribeSlowConsumer(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 0)
+	ch, cancel, _ := b.Subscribe(a.ID, 1)
+	defer cancel()
+	for i := 1; i <= 5; i++ {
+		if _, err := b.Deposit(a.ID, NewMoney(int64(i), "USD"), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if l := <-ch; l.Amount != 1 {
+		t.Fatalf("event=%+v want the first deposit", l)
+	}
+	if got := get(t, b, a.ID).Balance; got != 15 {
+		t.Fatalf("balance=%d want 15", got)
+	}
+}
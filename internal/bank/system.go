@@ -0,0 +1,107 @@
+// internal/bank/system.go
+//
+// 本檔實作「系統帳戶」：利息與手續費的資金來源 / 去處。
+// 利息入帳與手續費扣款都需要一個真實的對手帳戶，帳務才能平衡（全行總額守恆）。
+//   - 系統帳戶使用保留 ID（SystemAccountID），一般開戶永遠不會配發此 ID。
+//   - 由 EnsureSystemAccount 於啟動時建立（若快照中已存在則沿用）。
+//   - 系統帳戶可為負餘額：負值即代表銀行累計支出的淨利息。
+//   - 系統帳戶不得被關閉、提款或作為一般轉帳的轉出方（ErrSystemAccount），
+//     只能經由 PayInterest / ChargeFee 異動。
+
+package bank
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// SystemAccountID 為系統帳戶的保留 ID；一般帳戶 ID 由 1 起遞增，不會與之衝突。
+const SystemAccountID = "0"
+
+// EnsureSystemAccount 確保系統帳戶存在（不存在時建立），並回傳其快照。
+func (b *Bank) EnsureSystemAccount() *Account {
+	b.mu.Lock()
+	defer b.unlock()
+	a, ok := b.accts[SystemAccountID]
+	if !ok {
+		a = newSystemAccount()
+		b.accts[SystemAccountID] = a
+	}
+	cp := *a
+	return &cp
+}
+
+// newSystemAccount 建立零餘額的系統帳戶。
+func newSystemAccount() *Account {
+	return &Account{ID: SystemAccountID, Name: "system", Currency: DefaultCurrency, Status: StatusActive, mu: new(sync.RWMutex)}
+}
+
+// PayInterest 由系統帳戶支付利息給 id（交易類型 TxInterest）。
+func (b *Bank) PayInterest(id string, amt int64) (Tx, error) {
+	return b.Apply(Op{Type: TxInterest, Account: id, Amount: amt})
+}
+
+// ChargeFee 由 id 扣收手續費至系統帳戶（交易類型 TxFee）；餘額不足回傳 ErrInsufficient。
+func (b *Bank) ChargeFee(id string, amt int64) (Tx, error) {
+	return b.Apply(Op{Type: TxFee, Account: id, Amount: amt})
+}
+
+// systemMove 在客戶帳戶與系統帳戶之間移轉資金；須在 mu 保護下呼叫。
+// 利息：系統 → 客戶，系統帳戶不檢查餘額；手續費：客戶 → 系統，客戶須有足夠餘額。
+func (b *Bank) systemMove(typ, id string, amt int64) (Tx, error) {
+	if b.frozen {
+		return Tx{}, ErrFrozen
+	}
+	if amt <= 0 {
+		return Tx{}, ErrBadAmount
+	}
+	if id == SystemAccountID {
+		return Tx{}, ErrSystemAccount
+	}
+	sys, ok := b.accts[SystemAccountID]
+	if !ok {
+		return Tx{}, fmt.Errorf("%w: system account", ErrNotFound)
+	}
+	cust, ok := b.accts[id]
+	if !ok {
+		return Tx{}, ErrNotFound
+	}
+	if err := cust.checkActive(); err != nil {
+		return Tx{}, err
+	}
+	if b.disabledCcy[cust.Currency] {
+		return Tx{}, ErrCurrencyDisabled
+	}
+	from, to := sys, cust
+	if typ == TxFee {
+		from, to = cust, sys
+		b.expireHoldsFor(cust)
+		if !cust.canDebit(amt) {
+			return Tx{}, ErrInsufficient
+		}
+	}
+	if err := to.checkCredit(amt); err != nil {
+		return Tx{}, err
+	}
+	if from == sys && sys.Balance < math.MinInt64+amt {
+		return Tx{}, ErrOverflow // 系統帳戶可為負，但不得低於 int64 下限
+	}
+	fromNote, err := b.fitNote(from, typ)
+	if err != nil {
+		return Tx{}, err
+	}
+	toNote, err := b.fitNote(to, typ)
+	if err != nil {
+		return Tx{}, err
+	}
+
+	tx := b.newTx(typ)
+	tx.From, tx.To, tx.Amount = from.ID, to.ID, amt
+	from.Balance -= amt
+	to.Balance += amt
+	b.addLog(from, Log{Time: tx.Time, TxID: tx.ID, Type: typ, Amount: amt, Direction: "out", CounterID: to.ID, Note: fromNote})
+	b.addLog(to, Log{Time: tx.Time, TxID: tx.ID, Type: typ, Amount: amt, Direction: "in", CounterID: from.ID, Note: toNote})
+	b.indexTx(tx.ID, from.ID, to.ID)
+	return tx, nil
+}
@@ -0,0 +1,89 @@
+// internal/bank/system_test.go
+//
+// 測試系統帳戶：利息由系統帳戶支出、手續費收入系統帳戶、全行總額守恆，
+// 以及系統帳戶不得被關閉、提款或一般轉出。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSystemAccountInterestAndFee(t *testing.T) {
+	b := NewBank()
+	sys := b.EnsureSystemAccount()
+	if sys.ID != SystemAccountID {
+		t.Fatalf("system id=%q", sys.ID)
+	}
+	a, _ := b.Create("A", 100)
+	if a.ID == SystemAccountID {
+		t.Fatal("regular account got reserved id")
+	}
+
+	// 1️⃣ 利息：系統帳戶扣款（可為負），客戶入帳，雙邊日誌互為對手
+	tx, err := b.PayInterest(a.ID, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != SystemAccountID || tx.To != a.ID || tx.Type != TxInterest {
+		t.Fatalf("tx=%+v", tx)
+	}
+	if got := get(t, b, SystemAccountID).Balance; got != -7 {
+		t.Fatalf("system balance=%d want -7", got)
+	}
+	logs, _ := b.Logs(a.ID)
+	if l := logs[len(logs)-1]; l.CounterID != SystemAccountID || l.Direction != "in" {
+		t.Fatalf("log=%+v", l)
+	}
+
+	// 2️⃣ 手續費：客戶扣款，系統帳戶入帳；餘額不足被拒
+	if _, err := b.ChargeFee(a.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ChargeFee(a.ID, 1000); !errors.Is(err, ErrInsufficient) {
+		t.Fatalf("want ErrInsufficient, got %v", err)
+	}
+	if get(t, b, a.ID).Balance != 105 || get(t, b, SystemAccountID).Balance != -5 {
+		t.Fatal("unexpected balances after fee")
+	}
+
+	// ✅ 全行總額守恆（系統帳戶的負值抵銷利息）
+	var total int64
+	for _, acc := range b.List() {
+		total += acc.Balance
+	}
+	if total != 100 {
+		t.Fatalf("total=%d want 100", total)
+	}
+}
+
+func TestSystemAccountProtected(t *testing.T) {
+	b := NewBank()
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 10)
+	_, _ = b.ChargeFee(a.ID, 5) // 讓系統帳戶有正餘額
+
+	if _, err := b.Close(SystemAccountID); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("close: want ErrSystemAccount, got %v", err)
+	}
+	if _, err := b.Withdraw(SystemAccountID, NewMoney(1, "USD"), ""); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("withdraw: want ErrSystemAccount, got %v", err)
+	}
+	if err := b.Transfer(SystemAccountID, a.ID, NewMoney(1, "USD")); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("transfer: want ErrSystemAccount, got %v", err)
+	}
+	if _, err := b.PayInterest(SystemAccountID, 1); !errors.Is(err, ErrSystemAccount) {
+		t.Fatalf("interest to system: want ErrSystemAccount, got %v", err)
+	}
+
+	// ❌ 未建立系統帳戶時無法支付利息
+	if _, err := NewBank().PayInterest("1", 1); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	// ✅ EnsureSystemAccount 冪等，不覆寫既有餘額
+	if got := b.EnsureSystemAccount(); got.Balance != 5 {
+		t.Fatalf("system balance=%d want 5", got.Balance)
+	}
+}
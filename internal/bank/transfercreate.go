@@ -0,0 +1,82 @@
+// internal/bank/transfercreate.go
+//
+// 本檔實作「轉帳並開立收款帳戶」：在同一把寫鎖內開立新帳戶並自轉出帳戶轉入初始資金，
+// 呼叫端不必先開戶再轉帳，也就不會在兩者之間與其他請求交錯（例如轉出帳戶的餘額已被動用）。
+//   - 新帳戶的幣別與轉出帳戶相同，開戶餘額為 0，資金全部來自這筆轉帳（因此 Verify 一致）；
+//   - 轉帳的檢查、手續費、審核門檻與 Transfer 完全相同；任何檢查失敗都不留下新帳戶，也不佔用帳戶序號；
+//   - 啟用開戶審核時新帳戶須先核准才能入帳，因此回傳 ErrPendingApproval；
+//   - 成功提交的轉帳以 journalTransferCreate 項目寫入 journal（含新帳戶的 ID 與名稱），
+//     重播時以相同 ID 重新開戶並轉帳，之後的項目（例如對新帳戶的存款）才能照常重播；
+//     待審核的轉帳與其他待審核轉帳相同不記錄（見 journal.go）。
+
+package bank
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"banking/internal/storage"
+)
+
+// journalTransferCreate 為轉帳並開戶的 journal 項目類型：To 為新帳戶 ID、Name 為其名稱，TxID 為轉帳交易。
+const journalTransferCreate = "transfer_create"
+
+// TransferOrCreate 開立名為 toName 的新帳戶，並自 fromID 轉入 amount，回傳新帳戶與已提交的交易。
+// 雙邊日誌共用同一個 TxID；金額超過審核門檻時交易為 TxPendingReview，新帳戶於核准前餘額為 0。
+func (b *Bank) TransferOrCreate(fromID, toName string, amount int64) (*Account, Tx, error) {
+	b.mu.Lock()
+	defer b.unlock()
+	to, tx, err := b.transferOrCreate(fromID, "", toName, amount)
+	if err != nil {
+		return nil, Tx{}, err
+	}
+	if tx.Status != TxPendingReview {
+		b.appendJournal(storage.JournalEntry{
+			TxID: tx.ID, Time: tx.Time, Type: journalTransferCreate,
+			From: fromID, To: to.ID, Name: toName, Amount: amount,
+		})
+	}
+	cp := *to
+	return &cp, tx, nil
+}
+
+// transferOrCreate 為 TransferOrCreate 的本體；id 為空時配發新 ID，否則沿用 id（重播 journal 時）。
+// 須持有 mu 寫鎖。
+func (b *Bank) transferOrCreate(fromID, id, toName string, amount int64) (*Account, Tx, error) {
+	if b.frozen {
+		return nil, Tx{}, ErrFrozen
+	}
+	from, ok := b.accts[fromID]
+	if !ok {
+		return nil, Tx{}, ErrNotFound
+	}
+	if err := b.checkName(toName); err != nil {
+		return nil, Tx{}, err
+	}
+	seq := b.idSeq()
+	if id == "" {
+		var err error
+		if id, err = b.newID(); err != nil {
+			return nil, Tx{}, err
+		}
+	} else if _, taken := b.accts[id]; taken {
+		return nil, Tx{}, fmt.Errorf("account %s already exists", id)
+	}
+	to := &Account{ID: id, Name: toName, Currency: from.Currency, Status: StatusActive, mu: new(sync.RWMutex)}
+	if b.approval {
+		to.Status = StatusPendingApproval
+	}
+	b.accts[id] = to
+	tx, err := b.transfer(fromID, id, amount)
+	if err != nil {
+		delete(b.accts, id)
+		b.setIDSeq(seq) // 未開成的帳戶不佔用序號
+		return nil, Tx{}, err
+	}
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil && n > seq {
+		b.setIDSeq(n) // 重播沿用的 ID 之後，計數器從其後繼續配發
+	}
+	b.recordBalances(&tx)
+	return to, tx, nil
+}
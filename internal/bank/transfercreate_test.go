@@ -0,0 +1,108 @@
+// internal/bank/transfercreate_test.go
+//
+// 測試轉帳並開立收款帳戶的 TransferOrCreate，以及其 journal 重播。
+package bank
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"banking/internal/storage"
+)
+
+// TestTransferOrCreate 驗證成功時新帳戶以轉入金額開立、雙邊日誌共用 TxID，且帳本一致。
+func TestTransferOrCreate(t *testing.T) {
+	b := NewBank()
+	from, _ := b.Open(AccountSpec{Name: "A", Balance: 100, Currency: "EUR"})
+	to, tx, err := b.TransferOrCreate(from.ID, "Bob", 40)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to.ID != "2" || to.Name != "Bob" || to.Balance != 40 || to.Currency != "EUR" || to.Status != StatusActive {
+		t.Fatalf("new account=%+v", to)
+	}
+	if tx.From != from.ID || tx.To != to.ID || tx.Amount != 40 {
+		t.Fatalf("tx=%+v", tx)
+	}
+	a, _ := b.Get(from.ID)
+	got, _ := b.Get(to.ID)
+	if a.Balance != 60 || len(got.Logs) != 1 || got.Logs[0].TxID != tx.ID || a.Logs[0].TxID != tx.ID {
+		t.Fatalf("from=%+v to=%+v", a, got)
+	}
+	if err := b.Verify(); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+}
+
+// TestTransferOrCreateFailureLeavesNoAccount 驗證轉出失敗時不留下新帳戶，也不佔用帳戶序號。
+func TestTransferOrCreateFailureLeavesNoAccount(t *testing.T) {
+	b := NewBank()
+	from, _ := b.Create("A", 10)
+	_ = b.SetNameDenylist([]string{"mallory"})
+	cases := []struct {
+		from, name string
+		amount     int64
+		want       error
+	}{
+		{from.ID, "Bob", 11, ErrInsufficient},
+		{from.ID, "Bob", 0, ErrBadAmount},
+		{from.ID, "Mallory", 5, ErrNameNotAllowed},
+		{"999", "Bob", 5, ErrNotFound},
+	}
+	for _, c := range cases {
+		if _, _, err := b.TransferOrCreate(c.from, c.name, c.amount); !errors.Is(err, c.want) {
+			t.Fatalf("%+v: err=%v want %v", c, err, c.want)
+		}
+	}
+	if n := len(b.List()); n != 1 {
+		t.Fatalf("accounts=%d want 1", n)
+	}
+	if a, _ := b.Get(from.ID); a.Balance != 10 || len(a.Logs) != 0 {
+		t.Fatalf("sender=%+v", a)
+	}
+	if a, _ := b.Create("C", 0); a.ID != "2" {
+		t.Fatalf("next id=%q want \"2\"", a.ID)
+	}
+}
+
+// TestTransferOrCreateJournalReplay 驗證轉帳並開戶寫入 journal：由快照加 journal 重播後，
+// 新帳戶以相同 ID 存在，對它的後續存款與之後開立的帳戶序號、交易序號都與原帳本一致。
+func TestTransferOrCreateJournalReplay(t *testing.T) {
+	b := NewBankWithFee(1)
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 1000)
+	snap := b.Snapshot()
+	var entries []storage.JournalEntry
+	b.SetJournal(func(e storage.JournalEntry) { entries = append(entries, e) })
+
+	to, _, err := b.TransferOrCreate(a.ID, "Bob", 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.Deposit(to.ID, NewMoney(50, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := b.TransferOrCreate(to.ID, "Carol", 100); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Type != journalTransferCreate || entries[0].To != to.ID || entries[0].Name != "Bob" {
+		t.Fatalf("entries=%+v", entries)
+	}
+
+	fresh := NewBankWithFee(1)
+	fresh.Restore(snap)
+	if n, err := fresh.ReplayJournal(entries); n != 3 || err != nil {
+		t.Fatalf("replayed=%d err=%v want 3", n, err)
+	}
+	if want, got := b.Snapshot(), fresh.Snapshot(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("replayed state differs:\nwant %+v\ngot  %+v", want, got)
+	}
+	if n, err := fresh.ReplayJournal(entries); n != 0 || err != nil {
+		t.Fatalf("second replay applied=%d err=%v, want 0", n, err)
+	}
+	want, _ := b.Create("D", 0)
+	if got, _ := fresh.Create("D", 0); got.ID != want.ID {
+		t.Fatalf("next account id=%s want %s", got.ID, want.ID)
+	}
+}
@@ -0,0 +1,199 @@
+// internal/bank/tx.go
+//
+// 本檔定義「交易（Tx）」的跨帳戶視角與統一的操作分派入口。
+//   - 每筆提交成功的存款、提款、轉帳都會取得唯一的 TxID，並寫入所有相關帳戶的日誌。
+//   - Tx 是由日誌組合出的唯讀檢視，可依 TxID 查回（FindTx），供收據、稽核等功能使用。
+//   - Apply 以 Op 描述操作並分派到對應的核心邏輯，讓 HTTP 層、批次或重播只需一個入口。
+
+package bank
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 交易類型；同時用於 Op.Type、Tx.Type 與 Log.Type。
+const (
+	TxDeposit  = "deposit"
+	TxWithdraw = "withdraw"
+	TxTransfer = "transfer"
+	TxInterest = "interest" // 系統帳戶 → 客戶（見 system.go）
+	TxFee      = "fee"      // 客戶 → 系統帳戶
+	TxReversal = "reversal" // 沖正轉帳：原收款方 → 原付款方（見 reversal.go）
+	TxCapture  = "capture"  // 授權保留請款（見 authhold.go）；Op.Ref 為保留 ID
+)
+
+// TxPendingReview 為 Tx.Status 的值：大額轉帳已保留來源資金，等待審核（見 hold.go）。
+const TxPendingReview = "pending_review"
+
+// Tx 為一筆已提交交易的摘要。
+// 存款 / 提款使用 Account；轉帳、利息與手續費使用 From / To。
+type Tx struct {
+	ID      string    `json:"tx_id"`
+	Type    string    `json:"type"`
+	Account string    `json:"account,omitempty"`
+	From    string    `json:"from,omitempty"`
+	To      string    `json:"to,omitempty"`
+	Amount  int64     `json:"amount"`
+	Time    time.Time `json:"time"`
+	Status  string    `json:"status,omitempty"`    // 空值代表已提交；TxPendingReview 代表審核中
+	Ref     string    `json:"ref_tx_id,omitempty"` // 沖正交易所沖正的原 TxID
+
+	// Fee 為本次轉帳實際收取的手續費，僅於提交當下回傳；不由日誌重建（FindTx 為 0），也不納入收據簽章。
+	Fee int64 `json:"-"`
+	// Balances 為相關帳戶（Account / From / To）於交易提交當下的餘額，僅於提交當下回傳；
+	// 提款去重命中與由日誌重建的交易為 nil。
+	Balances map[string]int64 `json:"-"`
+}
+
+// Op 描述一個待執行的操作，欄位語意同 Tx；利息與手續費以 Account 指定客戶帳戶。
+type Op struct {
+	Type    string `json:"type"`
+	Account string `json:"account,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Amount  int64  `json:"amount"`
+
+	RequestID string `json:"request_id,omitempty"` // 提款去重用的用戶端請求 ID（見 dedup.go）
+	Ref       string `json:"ref,omitempty"`        // TxReversal：要沖正的原 TxID（From / To / Amount 由原交易決定）；TxCapture：保留 ID
+	Note      string `json:"note,omitempty"`       // TxDeposit / TxWithdraw：日誌備註；空值採預設（交易類型）
+}
+
+// Apply 於單一臨界區內執行一個操作並回傳已提交的交易；只鎖定操作涉及的帳戶（見 locks.go）。
+// 錯誤語意與 Deposit / Withdraw / Transfer 相同；未知的 Op.Type 回傳 ErrBadOp。
+func (b *Bank) Apply(op Op) (Tx, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.lockAccounts(b.opAccounts(op)...)
+	defer b.unlockAccounts(locked)
+	return b.applyLocked(op)
+}
+
+// applyLocked 執行 op，成功提交新交易時寫入 journal（見 journal.go）；須在 mu 保護下呼叫。
+// Deposit / Withdraw / Transfer 等公開方法皆經由此處，確保 journal 不會漏記。
+func (b *Bank) applyLocked(op Op) (Tx, error) {
+	tx, fresh, err := b.dispatch(op)
+	if err == nil && fresh {
+		b.record(op, tx)
+	}
+	return tx, err
+}
+
+// dispatch 為操作的分派邏輯；fresh 為 false 代表回傳的是先前已提交的交易（提款去重命中）。
+// 須在 mu 保護下呼叫。
+func (b *Bank) dispatch(op Op) (tx Tx, fresh bool, err error) {
+	switch op.Type {
+	case TxDeposit:
+		tx, err = b.deposit(op.Account, op.Amount, op.Note)
+	case TxWithdraw:
+		return b.withdrawOnce(op.Account, op.Amount, op.RequestID, op.Note)
+	case TxTransfer:
+		tx, err = b.transfer(op.From, op.To, op.Amount)
+	case TxInterest, TxFee:
+		tx, err = b.systemMove(op.Type, op.Account, op.Amount)
+	case TxReversal:
+		tx, err = b.reverse(op.Ref)
+	case TxCapture:
+		tx, err = b.capture(op.Ref)
+	default:
+		err = ErrBadOp
+	}
+	if err == nil {
+		b.recordBalances(&tx)
+	}
+	return tx, err == nil, err
+}
+
+// recordBalances 將 tx 相關帳戶目前的餘額記入 tx.Balances；須在 mu 保護下、交易提交後立即呼叫（帳戶仍鎖定中）。
+func (b *Bank) recordBalances(tx *Tx) {
+	tx.Balances = make(map[string]int64, 2)
+	for _, id := range []string{tx.Account, tx.From, tx.To} {
+		if a, ok := b.accts[id]; ok && id != "" {
+			tx.Balances[id] = a.Balance
+		}
+	}
+}
+
+// FindTx 依 TxID 由日誌重建交易摘要；不存在時回傳 ErrTxNotFound。
+func (b *Bank) FindTx(txID string) (Tx, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	b.txMu.Lock()
+	ids := slices.Clone(b.txIndex[txID])
+	b.txMu.Unlock()
+	for _, id := range ids {
+		a, ok := b.accts[id]
+		if !ok {
+			continue
+		}
+		if tx, ok := findTxLog(a, txID); ok {
+			return tx, nil
+		}
+	}
+	return Tx{}, ErrTxNotFound
+}
+
+// findTxLog 由新到舊在 a 的日誌中尋找 txID 並重建交易摘要；期間短暫鎖定 a。
+func findTxLog(a *Account, txID string) (Tx, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return txFromLogs(a, txID)
+}
+
+// txFromLogs 同 findTxLog，但不取鎖；呼叫端須已鎖定 a（或持有 mu 寫鎖）。
+func txFromLogs(a *Account, txID string) (Tx, bool) {
+	for i := len(a.Logs) - 1; i >= 0; i-- {
+		l := a.Logs[i]
+		if l.TxID != txID {
+			continue
+		}
+		tx := Tx{ID: txID, Type: l.Type, Amount: l.Amount, Time: l.Time, Ref: l.Ref}
+		switch {
+		case l.CounterID == "":
+			tx.Account = a.ID
+		case l.Direction == "out":
+			tx.From, tx.To = a.ID, l.CounterID
+		default:
+			tx.From, tx.To = l.CounterID, a.ID
+		}
+		return tx, true
+	}
+	return Tx{}, false
+}
+
+// newTx 配發新的 TxID 並記錄提交時間；須在 mu 保護下呼叫。
+func (b *Bank) newTx(typ string) Tx {
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	b.nextTx++
+	return Tx{ID: fmt.Sprintf("tx-%d", b.nextTx), Type: typ, Time: b.now()}
+}
+
+// indexTx 將 TxID 對應到參與的帳戶；須在 mu 保護下呼叫。
+func (b *Bank) indexTx(txID string, accountIDs ...string) {
+	b.txMu.Lock()
+	defer b.txMu.Unlock()
+	b.txIndex[txID] = append(b.txIndex[txID], accountIDs...)
+}
+
+// unindexTx 自 TxID 索引移除指定帳戶（刪除帳戶時使用）；須持有 mu 寫鎖。
+func (b *Bank) unindexTx(txID, accountID string) {
+	ids := slices.DeleteFunc(b.txIndex[txID], func(id string) bool { return id == accountID })
+	if len(ids) == 0 {
+		delete(b.txIndex, txID)
+		return
+	}
+	b.txIndex[txID] = ids
+}
+
+// txSeq 解析 "tx-<n>" 形式的序號；格式不符時回傳 0。
+func txSeq(txID string) int64 {
+	n, err := strconv.ParseInt(strings.TrimPrefix(txID, "tx-"), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
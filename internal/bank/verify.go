@@ -0,0 +1,75 @@
+// internal/bank/verify.go
+//
+// 本檔提供帳本一致性檢查（Verify），用於還原快照或匯入資料後偵測損毀。
+// 每個帳戶的餘額必須等於「開戶餘額 + 所有入帳日誌 - 所有出帳日誌」；
+// 存款、提款、轉帳、利息、手續費與沖正都會寫入日誌，因此任何不經日誌的餘額變動都會被發現。
+//
+// 日誌因保留上限被丟棄時，其淨額併入開戶餘額（見 retention.go），等式仍然成立。
+// 開戶餘額隨快照保存（opening_balance）；舊版快照沒有此欄位時，Restore 以當下的餘額與日誌反推，
+// 因此這類帳戶在還原當下視為一致，之後的異動仍會被檢查。
+
+package bank
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Mismatch 描述一個餘額與日誌不符的帳戶。
+type Mismatch struct {
+	Account  string `json:"account"`
+	Balance  int64  `json:"balance"`  // 帳戶目前記錄的餘額
+	Expected int64  `json:"expected"` // 由開戶餘額與日誌重算的餘額
+}
+
+// VerifyError 為 Verify 發現不一致時回傳的錯誤，列出所有不符的帳戶（依 ID 排序）。
+// errors.Is(err, ErrInconsistent) 為 true。
+type VerifyError struct {
+	Mismatches []Mismatch
+}
+
+func (e *VerifyError) Error() string {
+	parts := make([]string, len(e.Mismatches))
+	for i, m := range e.Mismatches {
+		parts[i] = fmt.Sprintf("account %s balance %d, logs imply %d", m.Account, m.Balance, m.Expected)
+	}
+	return fmt.Sprintf("%s: %s", ErrInconsistent, strings.Join(parts, "; "))
+}
+
+// Is 讓 errors.Is(err, ErrInconsistent) 成立。
+func (e *VerifyError) Is(target error) bool {
+	return target == ErrInconsistent
+}
+
+// Verify 以讀鎖鎖定所有帳戶，逐一由開戶餘額與日誌重算餘額並與記錄的餘額比對。
+// 全部一致時回傳 nil；否則回傳 *VerifyError。
+func (b *Bank) Verify() error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	accts := b.sortedAccounts()
+	rlockAccounts(accts)
+	defer runlockAccounts(accts)
+	var bad []Mismatch
+	for _, a := range accts {
+		if want := a.opening + logsNet(a.Logs); want != a.Balance {
+			bad = append(bad, Mismatch{Account: a.ID, Balance: a.Balance, Expected: want})
+		}
+	}
+	if bad != nil {
+		return &VerifyError{Mismatches: bad}
+	}
+	return nil
+}
+
+// logsNet 回傳日誌的淨變動（入帳減出帳）。
+func logsNet(logs []Log) int64 {
+	var net int64
+	for _, l := range logs {
+		if l.Direction == "out" {
+			net -= l.Amount
+		} else {
+			net += l.Amount
+		}
+	}
+	return net
+}
@@ -0,0 +1,72 @@
+// internal/bank/verify_test.go
+//
+// 測試帳本一致性檢查：各類交易後帳本一致，還原後被竄改的餘額會被回報，舊版快照視為一致。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestVerify 驗證：
+// 1️⃣ 開戶、存提款、轉帳（含手續費）、利息與沖正後 Verify 回傳 nil；
+// 2️⃣ 快照經 Restore 後仍一致；竄改快照中的餘額再還原，Verify 回報該帳戶與正確的重算餘額；
+// 3️⃣ 沒有 opening_balance 的舊版快照還原後視為一致。
+func TestVerify(t *testing.T) {
+	b := NewBankWithFee(2)
+	b.EnsureSystemAccount()
+	a, _ := b.Create("A", 1_000)
+	c, _ := b.Create("C", 50)
+	_, _ = b.Deposit(a.ID, NewMoney(200, "USD"), "")
+	_, _ = b.Withdraw(c.ID, NewMoney(20, "USD"), "")
+	tx, err := b.Apply(Op{Type: TxTransfer, From: a.ID, To: c.ID, Amount: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.PayInterest(c.ID, 7); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Reverse(tx.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 正常操作後一致
+	if err := b.Verify(); err != nil {
+		t.Fatalf("Verify after normal operations: %v", err)
+	}
+
+	// 2️⃣ 還原後一致；竄改餘額後被回報
+	snap := b.Snapshot()
+	restored := NewBank()
+	restored.Restore(snap)
+	if err := restored.Verify(); err != nil {
+		t.Fatalf("Verify after restore: %v", err)
+	}
+	for i := range snap.Accounts {
+		if snap.Accounts[i].ID == c.ID {
+			snap.Accounts[i].Balance += 500
+		}
+	}
+	corrupt := NewBank()
+	corrupt.Restore(snap)
+	err = corrupt.Verify()
+	var ve *VerifyError
+	if !errors.Is(err, ErrInconsistent) || !errors.As(err, &ve) {
+		t.Fatalf("Verify on corrupted snapshot err=%v want VerifyError", err)
+	}
+	want := get(t, b, c.ID).Balance
+	if len(ve.Mismatches) != 1 || ve.Mismatches[0] != (Mismatch{Account: c.ID, Balance: want + 500, Expected: want}) {
+		t.Fatalf("mismatches=%+v want account %s balance %d expected %d", ve.Mismatches, c.ID, want+500, want)
+	}
+
+	// 3️⃣ 舊版快照（無開戶餘額）以還原當下的狀態為準
+	for i := range snap.Accounts {
+		snap.Accounts[i].OpeningBalance = nil
+	}
+	legacy := NewBank()
+	legacy.Restore(snap)
+	if err := legacy.Verify(); err != nil {
+		t.Fatalf("Verify on legacy snapshot: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// internal/bank/version.go
+//
+// 本檔實作帳戶版本號與條件式異動（樂觀並行控制）。
+// 每次改變帳戶狀態（餘額、日誌、保留金額、名稱、狀態、各項限額或凍結）時 Version 遞增，
+// 用戶端可先讀取帳戶取得版本，再以 ApplyIfVersion 要求「版本未變才執行」，
+// 避免多個用戶端同時讀取 → 計算 → 寫入時互相覆蓋。版本號寫入快照，重啟後延續。
+
+package bank
+
+// bump 遞增帳戶版本號；須在帳戶已鎖定（或持有 mu 寫鎖）時呼叫。
+func (a *Account) bump() {
+	a.Version++
+}
+
+// ApplyIfVersion 同 Apply，但只在操作的主要帳戶目前版本等於 version 時才執行；
+// 主要帳戶為存提款的 Account、轉帳的 From（付款方）。
+// 版本不符回傳 ErrVersionMismatch 且不改變任何狀態；帳戶不存在回傳 ErrNotFound。
+// 沒有主要帳戶的操作（例如沖正）不支援條件式執行，回傳 ErrBadOp。
+func (b *Bank) ApplyIfVersion(op Op, version int64) (Tx, error) {
+	id := versionAccount(op)
+	if id == "" {
+		return Tx{}, ErrBadOp
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	locked := b.lockAccounts(b.opAccounts(op)...)
+	defer b.unlockAccounts(locked)
+	a, ok := b.accts[id]
+	if !ok {
+		return Tx{}, ErrNotFound
+	}
+	if a.Version != version {
+		return Tx{}, ErrVersionMismatch
+	}
+	return b.applyLocked(op)
+}
+
+// versionAccount 回傳條件式異動比對版本的帳戶 ID；不支援時回傳空字串。
+func versionAccount(op Op) string {
+	switch op.Type {
+	case TxDeposit, TxWithdraw, TxInterest, TxFee:
+		return op.Account
+	case TxTransfer:
+		return op.From
+	}
+	return ""
+}
@@ -0,0 +1,71 @@
+// internal/bank/version_test.go
+//
+// 測試帳戶版本號：每次異動遞增、條件式異動在版本相符時執行、過期版本被拒且不改變狀態，
+// 版本號經快照保存。
+
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestApplyIfVersion 驗證：
+// 1️⃣ 新帳戶版本為 0，存款、轉帳（雙方）與設定變更都會遞增版本；
+// 2️⃣ 以目前版本提款成功，再以同一（已過期）版本提款回傳 ErrVersionMismatch，餘額與版本不變；
+// 3️⃣ 轉帳比對付款方版本；
+// 4️⃣ 版本號經 Snapshot / Restore 保留。
+func TestApplyIfVersion(t *testing.T) {
+	b := NewBank()
+	a, _ := b.Create("A", 1_000)
+	c, _ := b.Create("C", 0)
+	if a.Version != 0 {
+		t.Fatalf("new account version=%d want 0", a.Version)
+	}
+
+	// 1️⃣ 各種異動皆遞增版本
+	if _, err := b.Deposit(a.ID, NewMoney(100, "USD"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Transfer(a.ID, c.ID, NewMoney(100, "USD")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetDailyLimit(c.ID, 500); err != nil {
+		t.Fatal(err)
+	}
+	if v := get(t, b, a.ID).Version; v != 2 {
+		t.Fatalf("A version=%d want 2", v)
+	}
+	if v := get(t, b, c.ID).Version; v != 2 {
+		t.Fatalf("C version=%d want 2", v)
+	}
+
+	// 2️⃣ 目前版本 → 成功；過期版本 → ErrVersionMismatch
+	if _, err := b.ApplyIfVersion(Op{Type: TxWithdraw, Account: a.ID, Amount: 50}, 2); err != nil {
+		t.Fatalf("withdraw with current version: %v", err)
+	}
+	if _, err := b.ApplyIfVersion(Op{Type: TxWithdraw, Account: a.ID, Amount: 50}, 2); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("stale version want ErrVersionMismatch, got %v", err)
+	}
+	if got := get(t, b, a.ID); got.Balance != 950 || got.Version != 3 {
+		t.Fatalf("account=%+v want balance 950, version 3", got)
+	}
+
+	// 3️⃣ 轉帳比對付款方（From）的版本
+	if _, err := b.ApplyIfVersion(Op{Type: TxTransfer, From: a.ID, To: c.ID, Amount: 10}, 2); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("transfer with receiver's version want ErrVersionMismatch, got %v", err)
+	}
+	if _, err := b.ApplyIfVersion(Op{Type: TxTransfer, From: a.ID, To: c.ID, Amount: 10}, 3); err != nil {
+		t.Fatalf("transfer with current version: %v", err)
+	}
+	if _, err := b.ApplyIfVersion(Op{Type: TxDeposit, Account: "999", Amount: 1}, 0); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("missing account want ErrNotFound, got %v", err)
+	}
+
+	// 4️⃣ 快照保留版本號
+	restored := NewBank()
+	restored.Restore(b.Snapshot())
+	if v := get(t, restored, a.ID).Version; v != 4 {
+		t.Fatalf("restored version=%d want 4", v)
+	}
+}
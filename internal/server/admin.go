@@ -0,0 +1,219 @@
+// internal/server/admin.go
+//
+// 本檔提供營運管理用的 /admin 端點。
+// 這些端點多半變更「營運設定」而非帳本資料，因此不觸發 persist；
+// 例外是開戶核准（改變帳戶狀態）、重置帳本與匯入快照，成功後會寫入快照。
+package server
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"banking/internal/bank"
+	"banking/internal/storage"
+)
+
+// maxActivity 為 /admin/activity 單次回傳的筆數上限。
+const maxActivity = 500
+
+// adminCurrencies 處理：
+//   - GET  /admin/currencies → 列出被暫停的幣別
+//   - POST /admin/currencies → {"disable":["RUB"], "enable":["EUR"]}，回傳更新後的清單
+func (s *Server) adminCurrencies(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req struct {
+			Disable []string `json:"disable"`
+			Enable  []string `json:"enable"`
+		}
+		if !decodeBody(w, r, &req) {
+			return
+		}
+		s.Bank.DisableCurrencies(req.Disable...)
+		s.Bank.EnableCurrencies(req.Enable...)
+	default:
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"disabled": s.Bank.DisabledCurrencies()})
+}
+
+// adminActivity 處理 GET /admin/activity?minutes=5：
+// 回傳最近 N 分鐘（預設 5）內全行提交的交易，依時間排序，最多 maxActivity 筆（保留最新）。
+// 可選 ?offset=&limit= 分頁（見 paging.go），此時回應另含 total 與 links。
+func (s *Server) adminActivity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	minutes := 5
+	if v := r.URL.Query().Get("minutes"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			writeErr(w, errors.New("minutes must be a positive integer"), http.StatusBadRequest)
+			return
+		}
+		minutes = n
+	}
+	p, paged, err := parsePage(r)
+	if err != nil {
+		writeErr(w, err, http.StatusBadRequest)
+		return
+	}
+	feed := s.Bank.Activity(time.Duration(minutes)*time.Minute, maxActivity)
+	resp := map[string]any{"minutes": minutes, "transactions": feed}
+	if paged {
+		resp["total"] = len(feed)
+		resp["transactions"] = paginate(feed, p)
+		resp["links"] = pageLinks(r, p, len(feed))
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// adminFreeze 處理 POST /admin/freeze-all 與 POST /admin/unfreeze-all：
+// 開啟 / 解除全行凍結，回傳 {"frozen": bool}。凍結期間所有異動回傳 503，查詢不受影響。
+func (s *Server) adminFreeze(frozen bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+			return
+		}
+		if frozen {
+			s.Bank.FreezeAll()
+		} else {
+			s.Bank.UnfreezeAll()
+		}
+		writeJSON(w, http.StatusOK, map[string]bool{"frozen": s.Bank.Frozen()})
+	}
+}
+
+// adminReindex 處理 POST /admin/reindex：由帳戶日誌重建次要索引，回傳 {"anomalies": [...]}。
+func (s *Server) adminReindex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]string{"anomalies": s.Bank.Reindex()})
+}
+
+// adminReset 處理 POST /admin/reset：清空帳本並將帳戶 ID 重新由 "1" 編號（見 bank.Reset），
+// 供 CI 與展示環境在不重啟的情況下重新開始；成功後清除冪等紀錄（舊 key 不得重播舊帳本的回應）、
+// 寫入快照並回傳 204。
+func (s *Server) adminReset(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	s.Bank.Reset()
+	s.idem.clear()
+	if !s.persisted(w) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminExport 處理 GET /admin/export：回傳完整的帳本快照（與 bank.Snapshot 相同的 storage.Snapshot 結構），
+// 供搬遷與除錯使用；回應可原樣送回 POST /admin/import。
+func (s *Server) adminExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.Bank.Snapshot())
+}
+
+// adminImport 處理 POST /admin/import[?force=true]：以請求主體的快照取代帳本（見 bank.Import）。
+// 主體以 storage.DecodeSnapshot 解析（保留未知欄位，匯出時保存的 Extra 可原樣送回），不套用 decodeBody 的未知欄位檢查。
+// 快照版本不符回傳 400；帳本已有帳戶且未指定 force 回傳 409。成功後清除冪等紀錄、寫入快照並回傳 204。
+func (s *Server) adminImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	force := false
+	if v := r.URL.Query().Get("force"); v != "" {
+		f, err := strconv.ParseBool(v)
+		if err != nil {
+			writeErr(w, errors.New("force must be true or false"), http.StatusBadRequest)
+			return
+		}
+		force = f
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		err, code := bodyErr(err)
+		writeErr(w, err, code)
+		return
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		writeErr(w, errors.New("request body must not be empty"), http.StatusBadRequest)
+		return
+	}
+	snap, err := storage.DecodeSnapshot(data)
+	if err != nil {
+		writeErr(w, err, http.StatusBadRequest)
+		return
+	}
+	if err := s.Bank.Import(snap, force); err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	s.idem.clear()
+	if !s.persisted(w) {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// adminVerify 處理 GET /admin/verify：檢查每個帳戶的餘額是否與其日誌一致（見 bank.Verify）。
+// 一致時回傳 200 {"consistent": true, "mismatches": []}；
+// 否則回傳 409（code ledger_inconsistent），mismatches 列出每個不符帳戶的記錄餘額與重算餘額。
+func (s *Server) adminVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	err := s.Bank.Verify()
+	var ve *bank.VerifyError
+	switch {
+	case err == nil:
+		writeJSON(w, http.StatusOK, map[string]any{"consistent": true, "mismatches": []bank.Mismatch{}})
+	case errors.As(err, &ve):
+		writeJSON(w, http.StatusConflict, map[string]any{
+			"consistent": false,
+			"error":      err.Error(),
+			"code":       errorCode(err, http.StatusConflict),
+			"mismatches": ve.Mismatches,
+		})
+	default:
+		writeErr(w, err, http.StatusInternalServerError)
+	}
+}
+
+// adminAccounts 處理 POST /admin/accounts/{id}/approve：核准待審核帳戶並回傳最新狀態。
+func (s *Server) adminAccounts(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/admin/accounts/"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "approve" {
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	a, err := s.Bank.ApproveAccount(parts[0])
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	if !s.persisted(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.viewAccount(a))
+}
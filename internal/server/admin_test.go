@@ -0,0 +1,274 @@
+// internal/server/admin_test.go
+//
+// 測試 /admin 營運端點。
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// TestAdminCurrencies 驗證經由 API 暫停幣別後，該幣別交易回傳 409；恢復後回到 200。
+func TestAdminCurrencies(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Open(bank.AccountSpec{Name: "A", Balance: 100, Currency: "RUB"})
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Disabled []string `json:"disabled"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/admin/currencies", map[string]any{"disable": []string{"RUB"}}, 200, &resp)
+	if len(resp.Disabled) != 1 || resp.Disabled[0] != "RUB" {
+		t.Fatalf("disabled=%v", resp.Disabled)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 10}, 409, nil)
+
+	doJSON(t, cli, "POST", ts.URL+"/admin/currencies", map[string]any{"enable": []string{"rub"}}, 200, &resp)
+	if len(resp.Disabled) != 0 {
+		t.Fatalf("disabled=%v want empty", resp.Disabled)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 10}, 200, nil)
+}
+
+// TestAdminActivity 驗證 /admin/activity 依注入的時鐘只回傳時間窗內的交易，並拒絕非法參數。
+func TestAdminActivity(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := bank.NewBank()
+	b.SetClock(func() time.Time { return now })
+	a, _ := b.Create("A", 0)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(1, "USD"), "") // 12:00
+	now = now.Add(30 * time.Minute)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(2, "USD"), "") // 12:30
+	now = now.Add(time.Minute)
+
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Transactions []bank.FeedEntry `json:"transactions"`
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/activity?minutes=5", nil, 200, &resp)
+	if len(resp.Transactions) != 1 || resp.Transactions[0].Amount != 2 || resp.Transactions[0].AccountID != a.ID {
+		t.Fatalf("transactions=%+v", resp.Transactions)
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/activity?minutes=60", nil, 200, &resp)
+	if len(resp.Transactions) != 2 {
+		t.Fatalf("60-minute window entries=%d want 2", len(resp.Transactions))
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/activity?minutes=-1", nil, 400, nil)
+}
+
+// TestAdminFreezeAll 驗證全行凍結期間所有異動端點回傳 503、查詢仍為 200，解除後恢復。
+func TestAdminFreezeAll(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Frozen bool `json:"frozen"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/admin/freeze-all", nil, 200, &resp)
+	if !resp.Frozen {
+		t.Fatal("want frozen=true")
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "X", "balance": 0}, 503, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 503, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/withdraw", map[string]any{"amount": 1}, 503, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a.ID, "To": c.ID, "Amount": 1}, 503, nil)
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID, nil, 200, nil)
+
+	doJSON(t, cli, "POST", ts.URL+"/admin/unfreeze-all", nil, 200, &resp)
+	if resp.Frozen {
+		t.Fatal("want frozen=false")
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 200, nil)
+}
+
+// TestAdminReindex 驗證 POST /admin/reindex 於健康狀態下回傳空的異常清單。
+func TestAdminReindex(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(5, "USD"), "")
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Anomalies []string `json:"anomalies"`
+	}
+	doJSON(t, ts.Client(), "POST", ts.URL+"/admin/reindex", nil, 200, &resp)
+	if resp.Anomalies == nil || len(resp.Anomalies) != 0 {
+		t.Fatalf("anomalies=%v want []", resp.Anomalies)
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/reindex", nil, 405, nil)
+}
+
+// TestAdminApproveAccount 驗證待審核帳戶存款回傳 409，經 /admin/accounts/{id}/approve 核准後可存款。
+func TestAdminApproveAccount(t *testing.T) {
+	b := bank.NewBank()
+	b.SetOpeningApproval(true)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var a bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 0}, 201, &a)
+	if a.Status != bank.StatusPendingApproval {
+		t.Fatalf("status=%q", a.Status)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 409, nil)
+	doJSON(t, cli, "POST", ts.URL+"/admin/accounts/"+a.ID+"/approve", nil, 200, &a)
+	if a.Status != bank.StatusActive {
+		t.Fatalf("status=%q after approve", a.Status)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/admin/accounts/999/approve", nil, 404, nil)
+}
+
+// TestAdminVerify 驗證 /admin/verify：一致時 200；還原被竄改的快照後 409 並列出不符帳戶。
+func TestAdminVerify(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(50, "USD"), "")
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Consistent bool            `json:"consistent"`
+		Code       string          `json:"code"`
+		Mismatches []bank.Mismatch `json:"mismatches"`
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/verify", nil, 200, &resp)
+	if !resp.Consistent || len(resp.Mismatches) != 0 {
+		t.Fatalf("resp=%+v want consistent", resp)
+	}
+
+	snap := b.Snapshot()
+	snap.Accounts[0].Balance = 1
+	b.Restore(snap)
+	doJSON(t, ts.Client(), "GET", ts.URL+"/admin/verify", nil, 409, &resp)
+	if resp.Consistent || resp.Code != "ledger_inconsistent" || len(resp.Mismatches) != 1 || resp.Mismatches[0].Expected != 150 {
+		t.Fatalf("resp=%+v want one mismatch expecting 150", resp)
+	}
+}
+
+// TestAdminReset 驗證 POST /admin/reset 清空帳本、觸發保存，且之後新開戶的 ID 重新由 "1" 開始。
+func TestAdminReset(t *testing.T) {
+	b := bank.NewBank()
+	_, _ = b.Create("A", 100)
+	_, _ = b.Create("B", 50)
+	saves := 0
+	ts := httptest.NewServer(NewServer(b, func() error { saves++; return nil }).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	if code, _, _ := postWithKey(t, ts.URL+"/accounts/1/deposit", "dep-1", map[string]any{"amount": 5}); code != 200 {
+		t.Fatalf("deposit before reset code=%d", code)
+	}
+	saves = 0
+
+	doJSON(t, cli, "GET", ts.URL+"/admin/reset", nil, 405, nil)
+	doJSON(t, cli, "POST", ts.URL+"/admin/reset", nil, 204, nil)
+	if saves != 1 {
+		t.Fatalf("persist calls=%d want 1", saves)
+	}
+	var list []bank.Account
+	doJSON(t, cli, "GET", ts.URL+"/accounts", nil, 200, &list)
+	if len(list) != 0 {
+		t.Fatalf("accounts after reset=%+v want none", list)
+	}
+	var created bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "C", "balance": 10}, 201, &created)
+	if created.ID != "1" {
+		t.Fatalf("first id after reset=%q want \"1\"", created.ID)
+	}
+
+	// 重置前使用過的 Idempotency-Key 不會重播舊帳本的回應，而是對新帳戶實際入帳
+	if code, _, replay := postWithKey(t, ts.URL+"/accounts/1/deposit", "dep-1", map[string]any{"amount": 5}); code != 200 || replay {
+		t.Fatalf("deposit after reset code=%d replay=%v, want 200 executed", code, replay)
+	}
+	if got := get(t, b, "1").Balance; got != 15 {
+		t.Fatalf("balance after reset deposit=%d want 15", got)
+	}
+}
+
+// TestAdminExportImport 驗證 GET /admin/export 匯出的快照可經 POST /admin/import 匯入另一個伺服器，
+// 餘額與日誌一致；目標已有帳戶時需 ?force=true，快照版本不符回傳 400。
+func TestAdminExportImport(t *testing.T) {
+	src := bank.NewBank()
+	a1, _ := src.Create("A", 100)
+	a2, _ := src.Create("B", 50)
+	_ = src.Transfer(a1.ID, a2.ID, bank.NewMoney(30, "USD"))
+	_, _ = src.Deposit(a2.ID, bank.NewMoney(5, "USD"), "")
+	srcTS := httptest.NewServer(NewServer(src, nil).Router())
+	defer srcTS.Close()
+
+	var raw json.RawMessage
+	doJSON(t, srcTS.Client(), "GET", srcTS.URL+"/admin/export", nil, 200, &raw)
+	doJSON(t, srcTS.Client(), "POST", srcTS.URL+"/admin/export", nil, 405, nil)
+
+	dst := bank.NewBank()
+	saves := 0
+	dstTS := httptest.NewServer(NewServer(dst, func() error { saves++; return nil }).Router())
+	defer dstTS.Close()
+	cli := dstTS.Client()
+
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import", raw, 204, nil)
+	if saves != 1 {
+		t.Fatalf("persist calls=%d want 1", saves)
+	}
+	for _, want := range src.List() {
+		var got bank.Account
+		doJSON(t, cli, "GET", dstTS.URL+"/accounts/"+want.ID, nil, 200, &got)
+		if got.Balance != want.Balance || got.Name != want.Name {
+			t.Fatalf("account %s=%+v want %+v", want.ID, got, *want)
+		}
+		if got, _ := dst.Get(want.ID); len(got.Logs) != len(want.Logs) {
+			t.Fatalf("account %s logs=%d want %d", want.ID, len(got.Logs), len(want.Logs))
+		}
+	}
+
+	// ❌ 目標已有帳戶：未指定 force 回傳 409，帳本不變
+	_, _ = dst.Deposit(a1.ID, bank.NewMoney(1, "USD"), "")
+	var e errorBody
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import", raw, 409, &e)
+	if e.Code != "bank_not_empty" {
+		t.Fatalf("code=%q", e.Code)
+	}
+	if got, _ := dst.Get(a1.ID); got.Balance != 71 {
+		t.Fatalf("balance after rejected import=%d want 71", got.Balance)
+	}
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import?force=yes", raw, 400, nil)
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import?force=true", raw, 204, nil)
+	if got, _ := dst.Get(a1.ID); got.Balance != 70 {
+		t.Fatalf("balance after forced import=%d want 70", got.Balance)
+	}
+
+	// 較新版本寫入的未知欄位（快照頂層與帳戶）不會被拒絕，且原樣保留於帳本，之後的匯出照樣帶出
+	var snap map[string]any
+	_ = json.Unmarshal(raw, &snap)
+	snap["future_top"] = "x"
+	snap["accounts"].([]any)[0].(map[string]any)["future_acct"] = 1
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import?force=true", snap, 204, nil)
+	var exported map[string]any
+	doJSON(t, cli, "GET", dstTS.URL+"/admin/export", nil, 200, &exported)
+	if exported["future_top"] != "x" || exported["accounts"].([]any)[0].(map[string]any)["future_acct"] != float64(1) {
+		t.Fatalf("unknown fields not preserved: %v", exported)
+	}
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import?force=true", nil, 400, nil)
+
+	// ❌ 快照版本不符
+	snap["_meta"].(map[string]any)["version"] = 99
+	doJSON(t, cli, "POST", dstTS.URL+"/admin/import?force=true", snap, 400, &e)
+	if e.Code != "unsupported_snapshot_version" {
+		t.Fatalf("code=%q", e.Code)
+	}
+}
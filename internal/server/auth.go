@@ -0,0 +1,168 @@
+// internal/server/auth.go
+//
+// 本檔實作請求驗證與權限範圍（scope）控管，支援兩種憑證：
+//   - API Key（X-API-Key 標頭）：每把 key 對應一組允許的操作範圍；
+//   - Bearer token（Authorization: Bearer <token>）：有效的 token 擁有完整權限（等同 admin）。
+//
+// 操作範圍於中介層統一檢查：
+//   - read：查詢類請求（GET / HEAD，以及唯讀的 POST /accounts/get、POST /accounts/logs、POST /receipts/verify）
+//   - write：其餘會變更帳本的請求（開戶、存提款、轉帳、關戶…）
+//   - admin：/admin/* 營運端點、/transfers/* 轉帳審核與帳戶凍結 / 解除凍結；admin 同時涵蓋 read 與 write
+//
+// 未設定任何 key 與 token 時不啟用驗證（維持本地開發的便利）。
+// 啟用後：缺少或無效的憑證回傳 401，範圍不足回傳 403；GET /health（含 /health/live、/health/ready）永遠開放給監控探針。
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// 權限範圍。
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// apiKeyHeader 為攜帶 API Key 的請求標頭。
+const apiKeyHeader = "X-API-Key"
+
+// bearerPrefix 為 Authorization 標頭中 Bearer token 的前綴。
+const bearerPrefix = "Bearer "
+
+// fullAccess 為有效 Bearer token 擁有的權限範圍。
+var fullAccess = map[string]bool{ScopeRead: true, ScopeWrite: true, ScopeAdmin: true}
+
+var (
+	errUnauthorized = errors.New("missing or invalid credentials")
+	errForbidden    = errors.New("API key not allowed for this operation")
+)
+
+// WithAPIKeys 啟用 API Key 驗證；keys 為 key → 允許的 scope 清單。
+func WithAPIKeys(keys map[string][]string) Option {
+	return func(s *Server) {
+		s.apiKeys = make(map[string]map[string]bool, len(keys))
+		for k, scopes := range keys {
+			set := make(map[string]bool, len(scopes))
+			for _, sc := range scopes {
+				set[sc] = true
+			}
+			s.apiKeys[k] = set
+		}
+	}
+}
+
+// WithBearerTokens 啟用 Bearer token 驗證；tokens 為有效的 token 清單（空字串會被忽略）。
+// 可與 WithAPIKeys 並用，任一種憑證有效即可通過。
+func WithBearerTokens(tokens []string) Option {
+	return func(s *Server) {
+		s.bearerTokens = make(map[string]bool, len(tokens))
+		for _, tok := range tokens {
+			if tok != "" {
+				s.bearerTokens[tok] = true
+			}
+		}
+	}
+}
+
+// ParseBearerTokens 解析以逗號分隔的 token 清單，忽略空白項目。
+func ParseBearerTokens(spec string) []string {
+	var out []string
+	for _, tok := range strings.Split(spec, ",") {
+		if tok = strings.TrimSpace(tok); tok != "" {
+			out = append(out, tok)
+		}
+	}
+	return out
+}
+
+// ParseAPIKeys 解析設定字串，格式為以逗號分隔的 "key:scope+scope"，
+// 例如 "k1:read,k2:read+write,k3:admin"。未知的 scope 回傳錯誤。
+func ParseAPIKeys(spec string) (map[string][]string, error) {
+	out := make(map[string][]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, scopes, ok := strings.Cut(entry, ":")
+		if !ok || key == "" || scopes == "" {
+			return nil, fmt.Errorf("api key entry %q: want key:scope[+scope]", entry)
+		}
+		for _, sc := range strings.Split(scopes, "+") {
+			switch sc {
+			case ScopeRead, ScopeWrite, ScopeAdmin:
+				out[key] = append(out[key], sc)
+			default:
+				return nil, fmt.Errorf("api key entry %q: unknown scope %q", entry, sc)
+			}
+		}
+	}
+	return out, nil
+}
+
+// credentialScopes 回傳請求憑證對應的權限範圍；ok 為 false 代表缺少或無效的憑證。
+// 帶有 Authorization: Bearer 時只檢查 token，否則檢查 X-API-Key。
+func (s *Server) credentialScopes(r *http.Request) (scopes map[string]bool, ok bool) {
+	if auth := r.Header.Get("Authorization"); len(auth) >= len(bearerPrefix) && strings.EqualFold(auth[:len(bearerPrefix)], bearerPrefix) {
+		if s.bearerTokens[strings.TrimSpace(auth[len(bearerPrefix):])] {
+			return fullAccess, true
+		}
+		return nil, false
+	}
+	scopes, ok = s.apiKeys[r.Header.Get(apiKeyHeader)]
+	return scopes, ok
+}
+
+// authMiddleware 依 s.apiKeys 與 s.bearerTokens 驗證請求並檢查權限範圍。
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	if len(s.apiKeys) == 0 && len(s.bearerTokens) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := apiPath(r.URL.Path)
+		if isHealthPath(path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scopes, ok := s.credentialScopes(r)
+		if !ok {
+			if len(s.bearerTokens) > 0 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			writeErr(w, errUnauthorized, http.StatusUnauthorized)
+			return
+		}
+		if !scopes[ScopeAdmin] && !scopes[requiredScope(r.Method, path)] {
+			writeErr(w, errForbidden, http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requiredScope 判斷請求所需的權限範圍。
+// 路徑與 accountSubroutes / transactionSubroutes 相同先去除前後斜線再切段，
+// 因此 "/accounts/1/freeze/" 這類多一個斜線的路徑同樣需要 admin。
+func requiredScope(method, path string) string {
+	seg := strings.Split(strings.Trim(path, "/"), "/")
+	last := seg[len(seg)-1]
+	switch {
+	case seg[0] == "admin", seg[0] == "transfers":
+		return ScopeAdmin
+	case seg[0] == "accounts" && len(seg) > 2 && (last == "freeze" || last == "unfreeze"):
+		return ScopeAdmin
+	case seg[0] == "transactions" && len(seg) > 1 && last == "reverse":
+		return ScopeAdmin
+	case method == http.MethodGet || method == http.MethodHead:
+		return ScopeRead
+	}
+	switch strings.Join(seg, "/") {
+	case "accounts/get", "accounts/logs", "receipts/verify":
+		return ScopeRead
+	}
+	return ScopeWrite
+}
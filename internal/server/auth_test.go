@@ -0,0 +1,201 @@
+// internal/server/auth_test.go
+//
+// 測試 API Key 權限範圍：唯讀 key 可查詢但不可異動、admin key 可操作 /admin、/health 免驗證；
+// 凍結與沖正即使多一個結尾斜線也需要 admin；以及 Bearer token：有效 token 通過、無效或缺少 token 回傳 401 JSON。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestAPIKeyScopes(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	s := NewServer(b, nil, WithAPIKeys(map[string][]string{
+		"reader": {ScopeRead},
+		"teller": {ScopeRead, ScopeWrite},
+		"ops":    {ScopeAdmin},
+	}))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	// call 以指定的 key 發送請求並檢查狀態碼
+	call := func(key, method, path, body string, want int) {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(apiKeyHeader, key)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s key=%q: code=%d want %d", method, path, key, resp.StatusCode, want)
+		}
+	}
+
+	// 1️⃣ 唯讀 key：可查詢、不可存款、不可進 /admin
+	call("reader", "GET", "/accounts", "", 200)
+	call("reader", "GET", "/api/v1/accounts/"+a.ID, "", 200)
+	call("reader", "POST", "/accounts/"+a.ID+"/deposit", `{"amount":1}`, 403)
+	call("reader", "POST", "/admin/freeze-all", "", 403)
+
+	// 2️⃣ 讀寫 key：可存款，但不可進 /admin
+	call("teller", "POST", "/accounts/"+a.ID+"/deposit", `{"amount":1}`, 200)
+	call("teller", "POST", "/api/v1/admin/freeze-all", "", 403)
+
+	// 3️⃣ admin key：可操作 /admin，且涵蓋讀寫
+	call("ops", "POST", "/admin/freeze-all", "", 200)
+	call("ops", "POST", "/admin/unfreeze-all", "", 200)
+	call("ops", "GET", "/accounts", "", 200)
+
+	// 4️⃣ 凍結帳戶需要 admin；多一個結尾斜線（路由會去除）也一樣
+	for _, path := range []string{"/accounts/" + a.ID + "/freeze", "/accounts/" + a.ID + "/freeze/", "/api/v1/accounts/" + a.ID + "/freeze/", "/accounts/" + a.ID + "/unfreeze/"} {
+		call("teller", "POST", path, "", 403)
+	}
+	if got := get(t, b, a.ID); got.Frozen {
+		t.Fatalf("account frozen by non-admin key")
+	}
+	call("ops", "POST", "/accounts/"+a.ID+"/freeze/", "", 200)
+	call("ops", "POST", "/accounts/"+a.ID+"/unfreeze/", "", 200)
+
+	// 5️⃣ 沖正需要 admin；結尾斜線同樣不能繞過
+	c, _ := b.Create("C", 0)
+	tx, err := b.Apply(bank.Op{Type: bank.TxTransfer, From: a.ID, To: c.ID, Amount: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	call("teller", "POST", "/transactions/"+tx.ID+"/reverse/", "", 403)
+	call("teller", "POST", "/api/v1/transactions/"+tx.ID+"/reverse/", "", 403)
+	if got := get(t, b, c.ID).Balance; got != 10 {
+		t.Fatalf("receiver balance=%d want 10 (not reversed)", got)
+	}
+	call("ops", "POST", "/transactions/"+tx.ID+"/reverse/", "", 200)
+
+	// ❌ 缺少或未知的 key → 401；✅ /health 免驗證
+	call("", "GET", "/accounts", "", 401)
+	call("nope", "GET", "/accounts", "", 401)
+	call("", "GET", "/health", "", 200)
+}
+
+// TestRequiredScope 驗證權限判定與路由一致：前後多餘的斜線不影響所需的權限範圍。
+func TestRequiredScope(t *testing.T) {
+	cases := []struct{ method, path, want string }{
+		{"POST", "/accounts/1/freeze", ScopeAdmin},
+		{"POST", "/accounts/1/unfreeze/", ScopeAdmin},
+		{"POST", "/transactions/tx-1/reverse", ScopeAdmin},
+		{"POST", "/transactions/tx-1/reverse/", ScopeAdmin},
+		{"POST", "/transfers/tx-1/approve/", ScopeAdmin},
+		{"GET", "/admin/verify", ScopeAdmin},
+		{"GET", "/transactions/tx-1/receipt/", ScopeRead},
+		{"POST", "/accounts/get", ScopeRead},
+		{"POST", "/accounts/1/deposit/", ScopeWrite},
+		{"POST", "/transfer", ScopeWrite},
+	}
+	for _, c := range cases {
+		if got := requiredScope(c.method, c.path); got != c.want {
+			t.Errorf("requiredScope(%s %s)=%q want %q", c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestBearerTokens(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	s := NewServer(b, nil, WithBearerTokens([]string{"secret-1", "secret-2"}))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	// call 以指定的 Authorization 標頭發送請求並檢查狀態碼，回傳回應
+	call := func(auth, method, path, body string, want int) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s auth=%q: code=%d want %d", method, path, auth, resp.StatusCode, want)
+		}
+		if want == http.StatusUnauthorized {
+			var e errorBody
+			if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Code != "unauthorized" {
+				t.Fatalf("401 body=%+v err=%v", e, err)
+			}
+		}
+		return resp
+	}
+
+	// ✅ 有效 token：可查詢、異動與操作 /admin
+	call("Bearer secret-1", "GET", "/accounts", "", 200)
+	call("Bearer secret-2", "POST", "/api/v1/accounts/"+a.ID+"/deposit", `{"amount":1}`, 200)
+	call("bearer secret-1", "POST", "/admin/freeze-all", "", 200)
+
+	// ❌ 缺少、無效或格式錯誤的 token → 401 JSON，並提示 Bearer 驗證
+	if resp := call("", "GET", "/accounts", "", 401); resp.Header.Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("WWW-Authenticate=%q", resp.Header.Get("WWW-Authenticate"))
+	}
+	call("Bearer nope", "GET", "/accounts", "", 401)
+	call("Bearer ", "GET", "/accounts", "", 401)
+	call("secret-1", "GET", "/accounts", "", 401)
+
+	// ✅ /health 免驗證
+	call("", "GET", "/health", "", 200)
+	call("", "GET", "/api/v1/health", "", 200)
+}
+
+// TestBearerTokensWithAPIKeys 驗證兩種憑證並用：API Key 仍依 scope 檢查，Bearer token 擁有完整權限。
+func TestBearerTokensWithAPIKeys(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil,
+		WithAPIKeys(map[string][]string{"reader": {ScopeRead}}),
+		WithBearerTokens(ParseBearerTokens(" tok , ,")),
+	)
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	do := func(header, value string) int {
+		req, _ := http.NewRequest("POST", ts.URL+"/admin/freeze-all", nil)
+		req.Header.Set(header, value)
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if code := do(apiKeyHeader, "reader"); code != http.StatusForbidden {
+		t.Fatalf("read key on admin code=%d want 403", code)
+	}
+	if code := do("Authorization", "Bearer tok"); code != http.StatusOK {
+		t.Fatalf("bearer on admin code=%d want 200", code)
+	}
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("k1:read, k2:read+write ,k3:admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 3 || len(keys["k2"]) != 2 || keys["k3"][0] != ScopeAdmin {
+		t.Fatalf("keys=%v", keys)
+	}
+	for _, bad := range []string{"k1", "k1:", "k1:root"} {
+		if _, err := ParseAPIKeys(bad); err == nil {
+			t.Fatalf("%q: want error", bad)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// internal/server/batch.go
+//
+// 本檔提供 POST /batch：一次送出多筆存款 / 提款 / 轉帳。
+// 請求：{"mode": "atomic" | "partial", "ops": [{"type":"transfer","from":"1","to":"2","amount":5}, ...]}
+//   - atomic（預設）：全有或全無；任一筆失敗整批回滾，回應該筆的錯誤與對應狀態碼。
+//   - partial：逐筆獨立套用，回應 200 與每筆結果（成功附交易、失敗附錯誤與狀態碼），適合略過壞資料列的大量匯入。
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"banking/internal/bank"
+)
+
+// maxBatchOps 為單一批次的筆數上限。
+const maxBatchOps = 1000
+
+// batchLeg 為批次中單筆操作的回應。
+type batchLeg struct {
+	Index  int      `json:"index"`
+	OK     bool     `json:"ok"`
+	Tx     *bank.Tx `json:"tx,omitempty"`
+	Error  string   `json:"error,omitempty"`
+	Status int      `json:"status,omitempty"` // 失敗時對應的 HTTP 狀態碼
+}
+
+// batch 處理 POST /batch。
+func (s *Server) batch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Mode string    `json:"mode"`
+		Ops  []bank.Op `json:"ops"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = "atomic"
+	}
+	if req.Mode != "atomic" && req.Mode != "partial" {
+		writeErr(w, errors.New(`mode must be "atomic" or "partial"`), http.StatusBadRequest)
+		return
+	}
+	if len(req.Ops) == 0 || len(req.Ops) > maxBatchOps {
+		writeErr(w, errors.New("ops must contain between 1 and 1000 operations"), http.StatusBadRequest)
+		return
+	}
+
+	if err := checkCtx(r.Context()); err != nil {
+		writeErr(w, err, http.StatusServiceUnavailable)
+		return
+	}
+	results, err := s.Bank.ApplyBatch(req.Ops, req.Mode == "atomic")
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+
+	legs := make([]batchLeg, len(results))
+	committed := 0
+	for i, res := range results {
+		legs[i] = batchLeg{Index: i, OK: res.Err == nil}
+		if res.Err != nil {
+			legs[i].Error, legs[i].Status = res.Err.Error(), httpStatusFor(res.Err)
+			continue
+		}
+		tx := res.Tx
+		legs[i].Tx = &tx
+		committed++
+	}
+	if committed > 0 && !s.persisted(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"mode": req.Mode, "committed": committed, "results": legs})
+}
@@ -0,0 +1,76 @@
+// internal/server/batch_test.go
+//
+// 測試 POST /batch：partial 模式逐筆回報且成功者已提交；atomic 模式失敗時整批不生效。
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestBatchPartial(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	persisted := 0
+	ts := httptest.NewServer(NewServer(b, func() error { persisted++; return nil }).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Committed int        `json:"committed"`
+		Results   []batchLeg `json:"results"`
+	}
+	doJSON(t, ts.Client(), "POST", ts.URL+"/batch", map[string]any{
+		"mode": "partial",
+		"ops": []map[string]any{
+			{"type": "transfer", "from": a1.ID, "to": a2.ID, "amount": 40},
+			{"type": "transfer", "from": a1.ID, "to": "999", "amount": 1},
+			{"type": "withdraw", "account": a2.ID, "amount": 500},
+			{"type": "deposit", "account": a2.ID, "amount": 2},
+		},
+	}, 200, &resp)
+
+	if resp.Committed != 2 || len(resp.Results) != 4 {
+		t.Fatalf("resp=%+v", resp)
+	}
+	want := []struct {
+		ok     bool
+		status int
+	}{{true, 0}, {false, 404}, {false, 409}, {true, 0}}
+	for i, w := range want {
+		got := resp.Results[i]
+		if got.OK != w.ok || got.Status != w.status || (w.ok && got.Tx == nil) || (!w.ok && got.Error == "") {
+			t.Fatalf("leg %d=%+v want ok=%v status=%d", i, got, w.ok, w.status)
+		}
+	}
+	if a, _ := b.Get(a2.ID); a.Balance != 42 {
+		t.Fatalf("balance=%d want 42", a.Balance)
+	}
+	if persisted != 1 {
+		t.Fatalf("persisted=%d want 1", persisted)
+	}
+}
+
+func TestBatchAtomic(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	ops := []map[string]any{
+		{"type": "transfer", "from": a1.ID, "to": a2.ID, "amount": 40},
+		{"type": "withdraw", "account": a2.ID, "amount": 500},
+	}
+	doJSON(t, cli, "POST", ts.URL+"/batch", map[string]any{"ops": ops}, 409, nil)
+	if a, _ := b.Get(a2.ID); a.Balance != 0 {
+		t.Fatalf("balance=%d want 0 after rollback", a.Balance)
+	}
+
+	// ❌ 非法參數
+	doJSON(t, cli, "POST", ts.URL+"/batch", map[string]any{"ops": []any{}}, 400, nil)
+	doJSON(t, cli, "POST", ts.URL+"/batch", map[string]any{"mode": "maybe", "ops": ops}, 400, nil)
+}
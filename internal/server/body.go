@@ -0,0 +1,59 @@
+// internal/server/body.go
+//
+// 本檔提供 sizedBody：依回應大小自動選擇傳輸方式的輸出緩衝。
+//   - 小回應（<= maxBufferedBody）：完整緩衝後一次送出，並設定正確的 Content-Length，
+//     部分客戶端（或代理）對沒有長度的回應處理不佳。
+//   - 大回應：超過門檻即送出標頭並改為串流，不設 Content-Length，
+//     由 net/http 使用 chunked transfer encoding，記憶體用量維持在門檻以內。
+//
+// 匯出類 handler（CSV、NDJSON、OFX）皆先寫入 sizedBody，最後呼叫 Close 完成回應。
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+)
+
+// maxBufferedBody 為會被完整緩衝並附上 Content-Length 的回應大小上限。
+const maxBufferedBody = 64 << 10
+
+// sizedBody 為 io.Writer；標頭（Content-Type 等）須於第一次 Write 前設定完成。
+type sizedBody struct {
+	w         http.ResponseWriter
+	code      int
+	buf       bytes.Buffer
+	streaming bool
+}
+
+// newSizedBody 建立以 code 為狀態碼的輸出緩衝。
+func newSizedBody(w http.ResponseWriter, code int) *sizedBody {
+	return &sizedBody{w: w, code: code}
+}
+
+func (b *sizedBody) Write(p []byte) (int, error) {
+	if b.streaming {
+		return b.w.Write(p)
+	}
+	b.buf.Write(p)
+	if b.buf.Len() > maxBufferedBody {
+		b.streaming = true
+		b.w.WriteHeader(b.code)
+		if _, err := b.w.Write(b.buf.Bytes()); err != nil {
+			return 0, err
+		}
+		b.buf.Reset()
+	}
+	return len(p), nil
+}
+
+// Close 完成回應：仍在緩衝中則附上 Content-Length 一次送出。
+func (b *sizedBody) Close() error {
+	if b.streaming {
+		return nil
+	}
+	b.w.Header().Set("Content-Length", strconv.Itoa(b.buf.Len()))
+	b.w.WriteHeader(b.code)
+	_, err := b.w.Write(b.buf.Bytes())
+	return err
+}
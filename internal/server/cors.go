@@ -0,0 +1,83 @@
+// internal/server/cors.go
+//
+// 本檔實作 CORS（跨來源資源共用），讓其他來源的瀏覽器前端可以直接呼叫 API：
+//   - 一般請求：Origin 在允許清單內時加上 Access-Control-Allow-Origin；
+//   - 預檢（OPTIONS）：直接回傳 204 與允許的方法、標頭，不進入路由與驗證。
+//
+// 允許的來源以 WithCORSOrigins 設定，預設為 "*"（方便本地開發）；
+// 指定來源時回應原樣帶回請求的 Origin，並加上 Vary: Origin 避免快取混用。
+//
+// 中介層在路由外側，/api/v1 與根路徑兩種掛載方式都會經過；
+// 位於 API Key 驗證之外，預檢請求（瀏覽器不會附帶驗證標頭）不會被擋下，401 / 403 回應也能被前端讀取。
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// defaultCORSOrigins 為未設定時允許的來源。
+var defaultCORSOrigins = []string{"*"}
+
+// corsAllowMethods 與 corsAllowHeaders 為預檢回應宣告允許的方法與請求標頭。
+const (
+	corsAllowMethods = "GET, POST, PATCH, DELETE, OPTIONS"
+	corsAllowHeaders = "Content-Type, Authorization, " + apiKeyHeader + ", " + IdempotencyHeader + ", If-Match"
+)
+
+// corsMaxAge 為瀏覽器可快取預檢結果的秒數。
+const corsMaxAge = "600"
+
+// WithCORSOrigins 設定允許的來源（例如 "https://app.example.com"），"*" 代表任何來源；
+// 傳入空清單則停用 CORS。
+func WithCORSOrigins(origins []string) Option {
+	return func(s *Server) { s.corsOrigins = origins }
+}
+
+// ParseCORSOrigins 解析以逗號分隔的來源清單，忽略空白項目。
+func ParseCORSOrigins(spec string) []string {
+	var out []string
+	for _, o := range strings.Split(spec, ",") {
+		if o = strings.TrimSpace(o); o != "" {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// allowOrigin 回傳應寫入 Access-Control-Allow-Origin 的值；不允許時回傳空字串。
+func (s *Server) allowOrigin(origin string) string {
+	for _, o := range s.corsOrigins {
+		if o == "*" {
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// corsMiddleware 加上 CORS 標頭並處理預檢請求。
+func (s *Server) corsMiddleware(next http.Handler) http.Handler {
+	if len(s.corsOrigins) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if allow := s.allowOrigin(r.Header.Get("Origin")); allow != "" {
+			h.Set("Access-Control-Allow-Origin", allow)
+			if allow != "*" {
+				h.Add("Vary", "Origin")
+			}
+		}
+		if r.Method != http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.Set("Access-Control-Allow-Methods", corsAllowMethods)
+		h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+		h.Set("Access-Control-Max-Age", corsMaxAge)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,76 @@
+// internal/server/cors_test.go
+//
+// 測試 CORS：預檢請求回傳 204 與允許的方法 / 標頭，且不受 API Key 驗證影響；
+// 指定來源時只允許清單內的 Origin，/api/v1 前綴同樣適用。
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	// 預設允許任何來源；同時啟用 API Key，確認預檢不需驗證
+	s := NewServer(bank.NewBank(), nil, WithAPIKeys(map[string][]string{"k": {ScopeRead}}))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	for _, path := range []string{"/accounts", "/api/v1/accounts"} {
+		req, _ := http.NewRequest(http.MethodOptions, ts.URL+path, nil)
+		req.Header.Set("Origin", "http://localhost:3000")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNoContent {
+			t.Fatalf("%s preflight code=%d want 204", path, resp.StatusCode)
+		}
+		if got := resp.Header.Get("Access-Control-Allow-Origin"); got != "*" {
+			t.Fatalf("%s allow-origin=%q want *", path, got)
+		}
+		if m := resp.Header.Get("Access-Control-Allow-Methods"); !strings.Contains(m, "POST") {
+			t.Fatalf("%s allow-methods=%q", path, m)
+		}
+		if h := resp.Header.Get("Access-Control-Allow-Headers"); !strings.Contains(h, "Content-Type") {
+			t.Fatalf("%s allow-headers=%q", path, h)
+		}
+	}
+}
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil, WithCORSOrigins([]string{"https://app.example.com"}))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	get := func(origin string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, ts.URL+"/accounts", nil)
+		req.Header.Set("Origin", origin)
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	// ✅ 清單內的來源：原樣帶回並加上 Vary: Origin，一般請求照常處理
+	resp := get("https://app.example.com")
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Fatalf("allowed origin: code=%d allow=%q", resp.StatusCode, resp.Header.Get("Access-Control-Allow-Origin"))
+	}
+	if resp.Header.Get("Vary") != "Origin" {
+		t.Fatalf("vary=%q want Origin", resp.Header.Get("Vary"))
+	}
+
+	// ❌ 其他來源：不加 CORS 標頭（由瀏覽器阻擋）
+	if got := get("https://evil.example.com").Header.Get("Access-Control-Allow-Origin"); got != "" {
+		t.Fatalf("foreign origin allow=%q want empty", got)
+	}
+}
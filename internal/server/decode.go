@@ -0,0 +1,89 @@
+// internal/server/decode.go
+//
+// 本檔集中處理請求本體的驗證與 JSON 解碼：
+//   - bodyLimitMiddleware：帶本體的 POST / PATCH / PUT 須為 application/json（否則 415），
+//     且本體不得超過 s.maxBody 位元組（否則 413）；超過宣告長度的直接拒絕，
+//     未宣告長度（chunked）的以 http.MaxBytesReader 限制，讀取超限時由 decodeBody 回應 413。
+//   - decodeBody：以 DisallowUnknownFields 解碼，拼錯的欄位（例如 "ammount"）回傳 400 而不是被忽略。
+//
+// 沒有本體的 POST（例如 /accounts/{id}/close）不受 Content-Type 限制。
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// defaultMaxBody 為未設定 WithMaxBodyBytes 時的請求本體上限（1 MiB）。
+const defaultMaxBody = 1 << 20
+
+// 請求本體錯誤。
+var (
+	errBodyTooLarge     = errors.New("request body too large")
+	errUnsupportedMedia = errors.New("Content-Type must be application/json")
+)
+
+// WithMaxBodyBytes 設定請求本體的位元組上限；n <= 0 代表使用預設值 defaultMaxBody。
+func WithMaxBodyBytes(n int64) Option {
+	return func(s *Server) {
+		if n <= 0 {
+			n = defaultMaxBody
+		}
+		s.maxBody = n
+	}
+}
+
+// bodyLimitMiddleware 檢查帶本體請求的 Content-Type 與大小。
+func (s *Server) bodyLimitMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPatch, http.MethodPut:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+			writeErr(w, errUnsupportedMedia, http.StatusUnsupportedMediaType)
+			return
+		}
+		if r.ContentLength > s.maxBody {
+			writeErr(w, fmt.Errorf("%w: limit is %d bytes", errBodyTooLarge, s.maxBody), http.StatusRequestEntityTooLarge)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, s.maxBody)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// bodyErr 將讀取或解碼本體的錯誤轉為回應用的錯誤與狀態碼：超過上限為 413，其餘為 400。
+func bodyErr(err error) (error, int) {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		return fmt.Errorf("%w: limit is %d bytes", errBodyTooLarge, mbe.Limit), http.StatusRequestEntityTooLarge
+	}
+	return err, http.StatusBadRequest
+}
+
+// decodeBody 將請求本體解碼至 v（不允許未知欄位）；失敗時寫出錯誤回應並回傳 false，呼叫端應直接返回。
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, io.EOF) {
+		err = errors.New("request body must not be empty")
+	}
+	err, code := bodyErr(err)
+	writeErr(w, err, code)
+	return false
+}
@@ -0,0 +1,70 @@
+// internal/server/decode_test.go
+//
+// 測試請求本體檢查：超過上限回傳 413、非 JSON 的 Content-Type 回傳 415、未知欄位回傳 400。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestRequestBodyChecks 逐一發送有問題的請求本體，確認狀態碼、錯誤代碼，且餘額未被異動。
+func TestRequestBodyChecks(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	ts := httptest.NewServer(NewServer(b, nil, WithMaxBodyBytes(64)).Router())
+	defer ts.Close()
+
+	url := ts.URL + "/accounts/" + a.ID + "/deposit"
+	for _, tc := range []struct {
+		name, ctype, body string
+		chunked           bool
+		want              int
+		code              string
+	}{
+		{"oversized", "application/json", `{"amount":1,"pad":"` + strings.Repeat("x", 100) + `"}`, false, 413, "too_large"},
+		{"oversized chunked", "application/json", `{"amount":1` + strings.Repeat(" ", 100) + `}`, true, 413, "too_large"},
+		{"wrong content-type", "text/plain", `{"amount":1}`, false, 415, "unsupported_media_type"},
+		{"missing content-type", "", `{"amount":1}`, false, 415, "unsupported_media_type"},
+		{"unknown field", "application/json", `{"ammount":1}`, false, 400, "bad_request"},
+	} {
+		req, _ := http.NewRequest("POST", url, bytes.NewBufferString(tc.body))
+		if tc.chunked {
+			req.ContentLength = -1 // 未宣告長度，由 MaxBytesReader 於讀取時攔截
+		}
+		if tc.ctype != "" {
+			req.Header.Set("Content-Type", tc.ctype)
+		}
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var e errorBody
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+		resp.Body.Close()
+		if resp.StatusCode != tc.want || e.Code != tc.code {
+			t.Fatalf("%s: code=%d body=%+v want %d %s", tc.name, resp.StatusCode, e, tc.want, tc.code)
+		}
+	}
+
+	// ✅ 帶 charset 參數的 application/json 仍可接受
+	req, _ := http.NewRequest("POST", url, strings.NewReader(`{"amount":5}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("charset: code=%d want 200", resp.StatusCode)
+	}
+	if got, _ := b.Get(a.ID); got.Balance != 105 {
+		t.Fatalf("balance=%d want 105", got.Balance)
+	}
+}
@@ -0,0 +1,71 @@
+// internal/server/events.go
+//
+// 本檔實作 GET /accounts/{id}/events：以 Server-Sent Events 即時串流帳戶的新日誌，供即時儀表板使用。
+//   - 連線建立後先送出一行註解（": connected"），之後每筆新日誌送出一個 SSE 事件，data 為 bank.Log 的 JSON；
+//   - 閒置時每 sseHeartbeat 送出註解行，避免代理伺服器因逾時切斷連線；
+//   - 用戶端斷線（r.Context().Done()）或伺服器關閉（CloseStreams）時取消訂閱並結束；
+//   - 訂閱緩衝已滿（用戶端讀取太慢）時事件直接丟棄，不會拖慢交易處理（見 bank.Subscribe）。
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// sseHeartbeat 為閒置連線的心跳間隔。
+const sseHeartbeat = 15 * time.Second
+
+// errStreamingUnsupported 代表 ResponseWriter 不支援 Flush，無法串流。
+var errStreamingUnsupported = errors.New("streaming unsupported")
+
+// CloseStreams 結束所有進行中的事件串流（可重複呼叫）。
+// http.Server.Shutdown 不會中斷長連線，因此關機時須先呼叫（例如以 RegisterOnShutdown 註冊）。
+func (s *Server) CloseStreams() {
+	s.streamsOnce.Do(func() { close(s.streamsDone) })
+}
+
+// accountEvents 處理 GET /accounts/{id}/events。
+func (s *Server) accountEvents(w http.ResponseWriter, r *http.Request, id string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeErr(w, errStreamingUnsupported, http.StatusInternalServerError)
+		return
+	}
+	events, cancel, err := s.Bank.Subscribe(id, 0)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	defer cancel()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no") // 停用 nginx 等代理的回應緩衝
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-s.streamsDone:
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case l, ok := <-events:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(l)
+			fmt.Fprintf(w, "id: %s\ndata: %s\n\n", l.TxID, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,98 @@
+// internal/server/events_test.go
+//
+// 測試 GET /accounts/{id}/events：訂閱後存款，串流中收到對應的 SSE data 事件；
+// CloseStreams 會結束進行中的串流；不存在的帳戶回傳 404。
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// readSSELine 讀取下一行（去除換行）；逾時則測試失敗。
+func readSSELine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+	select {
+	case l, ok := <-lines:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return l
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for SSE line")
+	}
+	return ""
+}
+
+func TestAccountEvents(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	s := NewServer(b, nil)
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL+"/accounts/"+a.ID+"/events", nil)
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("code=%d content-type=%q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+
+	// 1️⃣ 連線建立（已訂閱）後存款
+	if l := readSSELine(t, lines); l != ": connected" {
+		t.Fatalf("first line=%q", l)
+	}
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 42}, 200, nil)
+
+	// 2️⃣ 收到 id: / data: 事件
+	_ = readSSELine(t, lines) // 空行（": connected" 的結尾）
+	idLine := readSSELine(t, lines)
+	dataLine := readSSELine(t, lines)
+	var got bank.Log
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(dataLine, "data: ")), &got); err != nil {
+		t.Fatalf("data line %q: %v", dataLine, err)
+	}
+	if got.Type != bank.TxDeposit || got.Amount != 42 || idLine != "id: "+got.TxID {
+		t.Fatalf("event id=%q log=%+v", idLine, got)
+	}
+
+	// 3️⃣ CloseStreams 結束串流
+	s.CloseStreams()
+	for {
+		select {
+		case _, ok := <-lines:
+			if !ok {
+				return
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream not closed after CloseStreams")
+		}
+	}
+}
+
+func TestAccountEventsNotFound(t *testing.T) {
+	ts := httptest.NewServer(NewServer(bank.NewBank(), nil).Router())
+	defer ts.Close()
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts/999/events", nil, 404, nil)
+}
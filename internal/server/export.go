@@ -0,0 +1,232 @@
+// internal/server/export.go
+//
+// 本檔提供交易日誌的「匯出格式」實作，供個人理財軟體或稽核工具匯入。
+// 目前支援：
+//   - OFX 2.2（Open Financial Exchange，XML 版）：GET /accounts/{id}/logs.ofx
+//   - CSV：GET /accounts/{id}/logs.csv；帳戶清單 GET /accounts.csv
+//   - NDJSON（每行一筆 JSON）：GET /accounts/{id}/logs.ndjson
+//
+// 小型匯出附上正確的 Content-Length，大型匯出則以 chunked 串流輸出（見 body.go）。
+//
+// 匯出僅為 bank 層資料的另一種呈現方式，不改變任何狀態，因此不觸發 persist。
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// ofxTimeLayout 為 OFX 規範的日期時間格式（YYYYMMDDHHMMSS）。
+const ofxTimeLayout = "20060102150405"
+
+// ofxHeader 為 OFX 2.x 的 XML 宣告與處理指令（processing instruction）。
+const ofxHeader = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n" +
+	`<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n"
+
+// ofxDoc 及以下型別對應 OFX 文件中實際用到的最小元素集合。
+type ofxDoc struct {
+	XMLName xml.Name     `xml:"OFX"`
+	SignOn  ofxSignOn    `xml:"SIGNONMSGSRSV1>SONRS"`
+	Stmt    ofxStmtTrnRs `xml:"BANKMSGSRSV1>STMTTRNRS"`
+}
+
+type ofxStatus struct {
+	Code     int    `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+type ofxSignOn struct {
+	Status   ofxStatus `xml:"STATUS"`
+	DTServer string    `xml:"DTSERVER"`
+	Language string    `xml:"LANGUAGE"`
+}
+
+type ofxStmtTrnRs struct {
+	TrnUID string    `xml:"TRNUID"`
+	Status ofxStatus `xml:"STATUS"`
+	StmtRs ofxStmtRs `xml:"STMTRS"`
+}
+
+type ofxStmtRs struct {
+	CurDef   string       `xml:"CURDEF"`
+	AcctFrom ofxAcctFrom  `xml:"BANKACCTFROM"`
+	TranList ofxTranList  `xml:"BANKTRANLIST"`
+	Ledger   ofxLedgerBal `xml:"LEDGERBAL"`
+}
+
+type ofxAcctFrom struct {
+	BankID   string `xml:"BANKID"`
+	AcctID   string `xml:"ACCTID"`
+	AcctType string `xml:"ACCTTYPE"`
+}
+
+type ofxTranList struct {
+	DTStart string       `xml:"DTSTART"`
+	DTEnd   string       `xml:"DTEND"`
+	Trans   []ofxStmtTrn `xml:"STMTTRN"`
+}
+
+type ofxStmtTrn struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DTPosted string `xml:"DTPOSTED"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FITID    string `xml:"FITID"`
+	Name     string `xml:"NAME"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+type ofxLedgerBal struct {
+	BalAmt string `xml:"BALAMT"`
+	DTAsOf string `xml:"DTASOF"`
+}
+
+// buildOFX 將帳戶與其日誌轉換為 OFX 文件結構。
+// - 轉入（Direction "in"，例如存款、轉入）→ CREDIT，金額為正。
+// - 轉出（Direction "out"，例如提款、轉出）→ DEBIT，金額為負。
+// - FITID 需在同一帳戶內唯一：優先使用 TxID，舊資料無 TxID 時以「帳戶 ID + 日誌序號」組成。
+func buildOFX(a *bank.Account, logs []bank.Log, now time.Time) ofxDoc {
+	ok := ofxStatus{Code: 0, Severity: "INFO"}
+	doc := ofxDoc{
+		SignOn: ofxSignOn{Status: ok, DTServer: now.UTC().Format(ofxTimeLayout), Language: "ENG"},
+		Stmt: ofxStmtTrnRs{
+			TrnUID: "0",
+			Status: ok,
+			StmtRs: ofxStmtRs{
+				CurDef:   "USD",
+				AcctFrom: ofxAcctFrom{BankID: "SIMPLEBANK", AcctID: a.ID, AcctType: "CHECKING"},
+				Ledger:   ofxLedgerBal{BalAmt: formatMinor(a.Balance), DTAsOf: now.UTC().Format(ofxTimeLayout)},
+			},
+		},
+	}
+
+	list := &doc.Stmt.StmtRs.TranList
+	list.DTEnd = now.UTC().Format(ofxTimeLayout)
+	list.DTStart = list.DTEnd
+	for i, l := range logs {
+		if i == 0 {
+			list.DTStart = l.Time.UTC().Format(ofxTimeLayout)
+		}
+		trnType, amt := "CREDIT", l.Amount
+		if l.Direction == "out" {
+			trnType, amt = "DEBIT", -l.Amount
+		}
+		fitID := l.TxID
+		if fitID == "" {
+			fitID = fmt.Sprintf("%s-%d", a.ID, i+1)
+		}
+		memo := ""
+		if l.CounterID != "" {
+			memo = "counter account " + l.CounterID
+		}
+		list.Trans = append(list.Trans, ofxStmtTrn{
+			TrnType:  trnType,
+			DTPosted: l.Time.UTC().Format(ofxTimeLayout),
+			TrnAmt:   formatMinor(amt),
+			FITID:    fitID,
+			Name:     l.Note,
+			Memo:     memo,
+		})
+	}
+	return doc
+}
+
+// formatMinor 將最小貨幣單位（分）轉為兩位小數字串，例如 -1234 → "-12.34"。
+func formatMinor(v int64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/100, v%100)
+}
+
+// logsOFX 處理 GET /accounts/{id}/logs.ofx，輸出 OFX 2.2 文件。
+func (s *Server) logsOFX(w http.ResponseWriter, id string) {
+	a, err := s.Bank.Get(id)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	logs, err := s.Bank.Logs(id)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	out, err := xml.MarshalIndent(buildOFX(a, logs, time.Now()), "", "  ")
+	if err != nil {
+		writeErr(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ofx")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.ofx"`, id))
+	body := newSizedBody(w, http.StatusOK)
+	_, _ = body.Write([]byte(ofxHeader))
+	_, _ = body.Write(out)
+	_ = body.Close()
+}
+
+// logsCSV 處理 GET /accounts/{id}/logs.csv，每筆日誌一列（含標題列）。
+func (s *Server) logsCSV(w http.ResponseWriter, id string) {
+	logs, err := s.Bank.Logs(id)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="account-%s.csv"`, id))
+	body := newSizedBody(w, http.StatusOK)
+	cw := csv.NewWriter(body)
+	_ = cw.Write([]string{"time", "tx_id", "type", "direction", "amount", "counter_account", "note"})
+	for _, l := range logs {
+		_ = cw.Write([]string{
+			l.Time.UTC().Format(time.RFC3339Nano), l.TxID, l.Type, l.Direction,
+			strconv.FormatInt(l.Amount, 10), l.CounterID, l.Note,
+		})
+	}
+	cw.Flush()
+	_ = body.Close()
+}
+
+// accountsCSV 處理 GET /accounts.csv，每個帳戶一列（含標題列，無帳戶時仍輸出標題列）。
+func (s *Server) accountsCSV(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="accounts.csv"`)
+	body := newSizedBody(w, http.StatusOK)
+	cw := csv.NewWriter(body)
+	_ = cw.Write([]string{"id", "name", "currency", "balance", "held", "overdraft_limit", "status"})
+	for _, a := range s.Bank.List() {
+		_ = cw.Write([]string{
+			a.ID, a.Name, a.Currency, strconv.FormatInt(a.Balance, 10),
+			strconv.FormatInt(a.Held, 10), strconv.FormatInt(a.OverdraftLimit, 10), a.Status,
+		})
+	}
+	cw.Flush()
+	_ = body.Close()
+}
+
+// logsNDJSON 處理 GET /accounts/{id}/logs.ndjson，每行一筆 JSON 日誌（application/x-ndjson）。
+func (s *Server) logsNDJSON(w http.ResponseWriter, id string) {
+	logs, err := s.Bank.Logs(id)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	body := newSizedBody(w, http.StatusOK)
+	enc := json.NewEncoder(body)
+	for _, l := range logs {
+		_ = enc.Encode(l)
+	}
+	_ = body.Close()
+}
@@ -0,0 +1,178 @@
+// internal/server/export_test.go
+//
+// 測試交易日誌匯出格式（OFX、CSV）。
+// 驗證 OFX 輸出為格式正確（well-formed）的 XML，且每筆日誌對應一個 STMTTRN 元素；
+// CSV 輸出可被 encoding/csv 解析並含標題列。
+package server
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestLogsOFXExport 驗證 GET /accounts/{id}/logs.ofx：
+//   - Content-Type 為 application/x-ofx
+//   - 文件可被 XML 解析
+//   - 存款 → CREDIT（正數）、提款與轉出 → DEBIT（負數）
+func TestLogsOFXExport(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+	_, _ = b.Deposit(a1.ID, bank.NewMoney(250, "USD"), "")
+	_, _ = b.Withdraw(a1.ID, bank.NewMoney(100, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, bank.NewMoney(300, "USD"))
+
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/accounts/" + a1.ID + "/logs.ofx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("code=%d want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/x-ofx" {
+		t.Fatalf("content-type=%q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `OFXHEADER="200"`) {
+		t.Fatalf("missing OFX header: %s", body)
+	}
+
+	var doc ofxDoc
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("OFX is not well-formed XML: %v", err)
+	}
+	trans := doc.Stmt.StmtRs.TranList.Trans
+	if len(trans) != 3 {
+		t.Fatalf("STMTTRN count=%d want 3", len(trans))
+	}
+	want := []struct{ typ, amt string }{{"CREDIT", "2.50"}, {"DEBIT", "-1.00"}, {"DEBIT", "-3.00"}}
+	for i, w := range want {
+		if trans[i].TrnType != w.typ || trans[i].TrnAmt != w.amt || trans[i].FITID == "" {
+			t.Fatalf("trans[%d]=%+v want %s %s", i, trans[i], w.typ, w.amt)
+		}
+	}
+	if doc.Stmt.StmtRs.Ledger.BalAmt != "8.50" {
+		t.Fatalf("ledger balance=%s want 8.50", doc.Stmt.StmtRs.Ledger.BalAmt)
+	}
+
+	// 不存在的帳戶 → 404
+	resp2, _ := ts.Client().Get(ts.URL + "/accounts/999/logs.ofx")
+	if resp2.StatusCode != http.StatusNotFound {
+		t.Fatalf("missing account code=%d want 404", resp2.StatusCode)
+	}
+}
+
+// TestCSVExport 驗證 CSV 匯出：
+//   - GET /accounts/{id}/logs.csv 含標題列與已知的日誌列，並以附件形式下載
+//   - 沒有日誌的帳戶仍輸出標題列
+//   - GET /accounts.csv 列出所有帳戶
+func TestCSVExport(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("Alice", 0)
+	a2, _ := b.Create("Bob", 0)
+	_, _ = b.Deposit(a1.ID, bank.NewMoney(250, "USD"), "")
+
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	get := func(path string) (*http.Response, [][]string) {
+		t.Helper()
+		resp, body := getRaw(t, ts.URL+path)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s code=%d", path, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+			t.Fatalf("%s content-type=%q", path, ct)
+		}
+		rows, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+		if err != nil {
+			t.Fatalf("%s invalid CSV: %v", path, err)
+		}
+		return resp, rows
+	}
+
+	// 1️⃣ 日誌 CSV：標題列 + 一筆存款
+	resp, rows := get("/accounts/" + a1.ID + "/logs.csv")
+	if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="account-`+a1.ID+`.csv"` {
+		t.Fatalf("content-disposition=%q", cd)
+	}
+	if len(rows) != 2 || strings.Join(rows[0], ",") != "time,tx_id,type,direction,amount,counter_account,note" {
+		t.Fatalf("logs rows=%v", rows)
+	}
+	if r := rows[1]; r[2] != "deposit" || r[3] != "in" || r[4] != "250" {
+		t.Fatalf("deposit row=%v", r)
+	}
+
+	// 2️⃣ 無日誌的帳戶：只有標題列
+	if _, rows := get("/accounts/" + a2.ID + "/logs.csv"); len(rows) != 1 {
+		t.Fatalf("empty logs rows=%v want header only", rows)
+	}
+
+	// 3️⃣ 帳戶清單 CSV
+	resp, rows = get("/accounts.csv")
+	if cd := resp.Header.Get("Content-Disposition"); cd != `attachment; filename="accounts.csv"` {
+		t.Fatalf("content-disposition=%q", cd)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != "id,name,currency,balance,held,overdraft_limit,status" {
+		t.Fatalf("accounts rows=%v", rows)
+	}
+	if r := rows[1]; r[0] != a1.ID || r[1] != "Alice" || r[2] != "USD" || r[3] != "250" || r[6] != bank.StatusActive {
+		t.Fatalf("account row=%v", r)
+	}
+}
+
+// getRaw 以不壓縮的方式取得回應，便於檢查 Content-Length 與傳輸編碼。
+func getRaw(t *testing.T, url string) (*http.Response, []byte) {
+	t.Helper()
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("Accept-Encoding", "identity")
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return resp, body
+}
+
+// TestExportContentLength 驗證小型匯出附上正確的 Content-Length，大型匯出改用 chunked 串流。
+func TestExportContentLength(t *testing.T) {
+	b := bank.NewBank()
+	small, _ := b.Create("small", 0)
+	large, _ := b.Create("large", 0)
+	_, _ = b.Deposit(small.ID, bank.NewMoney(10, "USD"), "")
+	for i := 0; i < 2000; i++ {
+		_, _ = b.Deposit(large.ID, bank.NewMoney(1, "USD"), "")
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	for _, ext := range []string{"csv", "ndjson", "ofx"} {
+		// 1️⃣ 小型匯出：Content-Length 與實際長度一致
+		resp, body := getRaw(t, ts.URL+"/accounts/"+small.ID+"/logs."+ext)
+		if resp.StatusCode != 200 || resp.ContentLength != int64(len(body)) || len(resp.TransferEncoding) != 0 {
+			t.Fatalf("%s small: code=%d len=%d body=%d te=%v", ext, resp.StatusCode, resp.ContentLength, len(body), resp.TransferEncoding)
+		}
+	}
+	for _, ext := range []string{"csv", "ndjson"} {
+		// 2️⃣ 大型匯出：無 Content-Length、使用 chunked
+		resp, body := getRaw(t, ts.URL+"/accounts/"+large.ID+"/logs."+ext)
+		if resp.ContentLength != -1 || len(resp.TransferEncoding) == 0 || resp.TransferEncoding[0] != "chunked" {
+			t.Fatalf("%s large: len=%d te=%v", ext, resp.ContentLength, resp.TransferEncoding)
+		}
+		if lines := strings.Count(string(body), "\n"); lines < 2000 {
+			t.Fatalf("%s large: lines=%d want >= 2000", ext, lines)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// internal/server/feed.go
+//
+// 本檔提供 GET /transactions：全行交易流（見 bank.AllLogs），跨帳戶合併所有日誌並依時間排序，
+// 每筆附 account_id 與 account_name，供後台監控使用。
+// 查詢參數（皆為選填）：
+//   - limit：最多回傳幾筆（保留最新），預設 defaultPageLimit，上限 maxPageLimit；
+//   - since：只列出此時間之後（含）的日誌，格式同 netflow（RFC 3339 或日期）；
+//   - account：只列出此帳戶的日誌，帳戶不存在回傳 404。
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"banking/internal/bank"
+)
+
+// transactions 處理 GET /transactions。
+func (s *Server) transactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	q := r.URL.Query()
+	opts := bank.FeedOptions{Account: q.Get("account"), Limit: defaultPageLimit}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > maxPageLimit {
+			writeErr(w, fmt.Errorf("limit must be between 1 and %d", maxPageLimit), http.StatusBadRequest)
+			return
+		}
+		opts.Limit = n
+	}
+	var err error
+	if opts.Since, err = parseTimeParam(q.Get("since")); err != nil {
+		writeErr(w, fmt.Errorf("since: %w", err), http.StatusBadRequest)
+		return
+	}
+	feed, err := s.Bank.AllLogs(opts)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"transactions": s.viewFeed(feed)})
+}
@@ -0,0 +1,49 @@
+// internal/server/feed_test.go
+//
+// 測試 GET /transactions：合併兩個帳戶的日誌並依時間排序，account / limit 篩選與參數錯誤。
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestTransactionsFeed(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	_, _ = b.Deposit(a1.ID, bank.NewMoney(50, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, bank.NewMoney(30, "USD"))
+	_, _ = b.Withdraw(a2.ID, bank.NewMoney(10, "USD"), "")
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Transactions []bank.FeedEntry `json:"transactions"`
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/transactions", nil, 200, &resp)
+	if len(resp.Transactions) != 4 {
+		t.Fatalf("feed=%+v want 4 entries", resp.Transactions)
+	}
+	for i := 1; i < len(resp.Transactions); i++ {
+		if resp.Transactions[i].Time.Before(resp.Transactions[i-1].Time) {
+			t.Fatalf("feed not time-ordered at %d: %+v", i, resp.Transactions)
+		}
+	}
+	if last := resp.Transactions[3]; last.AccountID != a2.ID || last.Type != bank.TxWithdraw {
+		t.Fatalf("last entry=%+v want B's withdrawal", last)
+	}
+
+	resp.Transactions = nil
+	doJSON(t, ts.Client(), "GET", ts.URL+"/api/v1/transactions?account="+a2.ID+"&limit=1", nil, 200, &resp)
+	if len(resp.Transactions) != 1 || resp.Transactions[0].AccountID != a2.ID || resp.Transactions[0].Amount != 10 {
+		t.Fatalf("filtered feed=%+v", resp.Transactions)
+	}
+
+	doJSON(t, ts.Client(), "GET", ts.URL+"/transactions?account=999", nil, 404, nil)
+	doJSON(t, ts.Client(), "GET", ts.URL+"/transactions?limit=0", nil, 400, nil)
+	doJSON(t, ts.Client(), "GET", ts.URL+"/transactions?since=yesterday", nil, 400, nil)
+	doJSON(t, ts.Client(), "POST", ts.URL+"/transactions", nil, 405, nil)
+}
@@ -0,0 +1,148 @@
+// internal/server/gzip.go
+//
+// 本檔實作 gzip 回應壓縮中介層。
+// 帳戶列表、日誌與匯出等回應可能很大，壓縮可大幅降低頻寬；小回應則不值得額外的 CPU 成本。
+// 規則：
+//   - 僅在請求帶有 Accept-Encoding: gzip 時壓縮。
+//   - 回應主體累積達門檻（預設 1024 bytes）才壓縮，未達門檻則原樣輸出。
+//   - 已設定 Content-Encoding 或屬於已壓縮格式（圖片、zip 等）的回應不再壓縮。
+//   - handler 在達門檻前呼叫 Flush（串流回應）時，直接改為不壓縮的直通模式，
+//     確保串流資料能即時送達客戶端。
+package server
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultGzipMinSize 為預設的壓縮門檻（bytes）。
+const defaultGzipMinSize = 1024
+
+// WithGzipMinSize 設定回應壓縮門檻；n < 0 代表停用壓縮。
+func WithGzipMinSize(n int) Option {
+	return func(s *Server) { s.gzipMin = n }
+}
+
+// gzipMiddleware 依 s.gzipMin 對回應進行 gzip 壓縮。
+func (s *Server) gzipMiddleware(next http.Handler) http.Handler {
+	if s.gzipMin < 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		gw := &gzipWriter{ResponseWriter: w, min: s.gzipMin, code: http.StatusOK}
+		defer gw.finish()
+		next.ServeHTTP(gw, r)
+	})
+}
+
+// acceptsGzip 解析 Accept-Encoding，判斷客戶端是否接受 gzip（q=0 視為拒絕）。
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+		if v, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok {
+			q, err := strconv.ParseFloat(v, 64)
+			return err == nil && q > 0
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter 先緩衝回應主體，待確定是否壓縮後才送出標頭。
+type gzipWriter struct {
+	http.ResponseWriter
+	min     int
+	code    int
+	buf     []byte
+	gz      *gzip.Writer
+	decided bool
+}
+
+// WriteHeader 僅記錄狀態碼；實際標頭延後至決定壓縮與否時送出。
+func (g *gzipWriter) WriteHeader(code int) {
+	if !g.decided {
+		g.code = code
+	}
+}
+
+func (g *gzipWriter) Write(p []byte) (int, error) {
+	if !g.decided {
+		g.buf = append(g.buf, p...)
+		if len(g.buf) >= g.min {
+			if err := g.decide(true); err != nil {
+				return 0, err
+			}
+		}
+		return len(p), nil
+	}
+	if g.gz != nil {
+		return g.gz.Write(p)
+	}
+	return g.ResponseWriter.Write(p)
+}
+
+// Flush 讓串流 handler 可即時送出資料；尚未決定時以直通模式輸出。
+func (g *gzipWriter) Flush() {
+	if !g.decided {
+		_ = g.decide(false)
+	}
+	if g.gz != nil {
+		_ = g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide 送出標頭與已緩衝的主體；compress 為 true 且內容可壓縮時啟用 gzip。
+func (g *gzipWriter) decide(compress bool) error {
+	g.decided = true
+	h := g.Header()
+	if compress && compressible(h) {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", "gzip")
+		h.Add("Vary", "Accept-Encoding")
+		g.ResponseWriter.WriteHeader(g.code)
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+		_, err := g.gz.Write(g.buf)
+		g.buf = nil
+		return err
+	}
+	g.ResponseWriter.WriteHeader(g.code)
+	_, err := g.ResponseWriter.Write(g.buf)
+	g.buf = nil
+	return err
+}
+
+// finish 於 handler 結束後收尾：輸出未達門檻的緩衝內容，或關閉 gzip 串流。
+func (g *gzipWriter) finish() {
+	if !g.decided {
+		_ = g.decide(false)
+	}
+	if g.gz != nil {
+		_ = g.gz.Close()
+	}
+}
+
+// compressible 判斷回應是否適合壓縮：未另行編碼且非已壓縮格式。
+func compressible(h http.Header) bool {
+	if h.Get("Content-Encoding") != "" {
+		return false
+	}
+	ct := strings.ToLower(h.Get("Content-Type"))
+	for _, prefix := range []string{"image/", "video/", "audio/", "application/zip", "application/gzip", "application/x-gzip"} {
+		if strings.HasPrefix(ct, prefix) {
+			return false
+		}
+	}
+	return true
+}
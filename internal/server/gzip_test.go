@@ -0,0 +1,80 @@
+// internal/server/gzip_test.go
+//
+// 測試 gzip 回應壓縮：大回應壓縮、小回應與未宣告 gzip 的請求原樣輸出。
+package server
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestGzipLargeList 以 Accept-Encoding: gzip 請求大量帳戶列表，
+// 確認回應經 gzip 編碼且解壓後為合法 JSON。
+func TestGzipLargeList(t *testing.T) {
+	b := bank.NewBank()
+	for i := 0; i < 200; i++ {
+		_, _ = b.Create(fmt.Sprintf("user-%d", i), int64(i))
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	// 手動設定 Accept-Encoding 時，http.Transport 不會自動解壓，便於檢查原始回應。
+	req, _ := http.NewRequest("GET", ts.URL+"/accounts", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding=%q want gzip", got)
+	}
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var list []bank.Account
+	if err := json.NewDecoder(zr).Decode(&list); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(list) != 200 {
+		t.Fatalf("len=%d want 200", len(list))
+	}
+}
+
+// TestGzipSkipsSmallAndUnaccepted 確認小回應與未宣告 gzip 的請求不會被壓縮。
+func TestGzipSkipsSmallAndUnaccepted(t *testing.T) {
+	b := bank.NewBank()
+	for i := 0; i < 200; i++ {
+		_, _ = b.Create("x", 0)
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	for _, tc := range []struct{ path, enc string }{
+		{"/health", "gzip"}, // 小於門檻
+		{"/accounts", ""},   // 未宣告
+		{"/accounts", "gzip;q=0"},
+	} {
+		req, _ := http.NewRequest("GET", ts.URL+tc.path, nil)
+		req.Header.Set("Accept-Encoding", tc.enc)
+		resp, err := ts.Client().Transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v any
+		if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+			t.Fatalf("%s (%q): %v", tc.path, tc.enc, err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get("Content-Encoding"); got != "" {
+			t.Fatalf("%s (%q): Content-Encoding=%q want none", tc.path, tc.enc, got)
+		}
+	}
+}
@@ -7,7 +7,8 @@
 //  1. 接收與驗證 HTTP 請求
 //  2. 呼叫 bank 層執行商業邏輯
 //  3. 回傳標準化 JSON 回應
-//  4. 成功變更狀態後呼叫 s.persist()，將當前銀行狀態寫入 JSON 快照
+//  4. 成功變更狀態後、寫出回應前呼叫 s.persisted(w)，將當前銀行狀態寫入 JSON 快照；
+//     寫入失敗時改回 500（見 persisted），用戶端不會誤以為變更已持久化
 //
 // 此設計使邏輯分層清晰：
 //   - bank：純商業邏輯，與 HTTP 無關。
@@ -20,8 +21,15 @@ package server
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"banking/internal/bank"
 )
@@ -29,147 +37,538 @@ import (
 // Server 為 HTTP 層核心結構：
 // - Bank：注入商業邏輯層（銀行核心）。
 // - persist：注入持久化鉤子，讓 server 不需關心儲存實作細節（可替換為 DB）。
+// - receiptKey：交易收據的 HMAC 金鑰（見 receipt.go），空值代表不啟用。
+// - gzipMin：回應壓縮門檻（見 gzip.go），負值代表停用。
+// - logger / logEvery / logSlow：請求日誌與取樣設定（見 logging.go）。
+// - apiKeys：API Key → 允許的權限範圍（見 auth.go），空值代表不啟用驗證。
+// - bearerTokens：有效的 Bearer token（見 auth.go），與 apiKeys 皆為空時不啟用驗證。
+// - corsOrigins：允許跨來源呼叫的 Origin（見 cors.go），空值代表不啟用 CORS。
+// - limiter：每個用戶端 IP 的速率限制（見 ratelimit.go），nil 代表不限制。
+// - routes：每條路由的請求統計（見 routes.go）。
+// - metrics：Prometheus 指標（見 metrics.go）。
+// - idem：Idempotency-Key 回應紀錄（見 idempotency.go）。
+// - webhook：交易事件通知（見 webhook.go），nil 代表不啟用。
+// - maxBody：請求本體位元組上限（見 decode.go）。
+// - timeout：每個請求的時限，<= 0 代表不限制（見 timeout.go）。
+// - amountScale：回應金額的表示方式（見 money.go）。
+// - streamsDone / streamsOnce：關閉所有事件串流（見 events.go）。
+// - ready：最近一次 persist 的結果，供 /health/ready 判斷（見 health.go）。
 type Server struct {
 	Bank    *bank.Bank
 	persist func() error
+
+	receiptKey []byte
+	gzipMin    int
+	apiKeys    map[string]map[string]bool
+
+	bearerTokens map[string]bool
+	corsOrigins  []string
+	limiter      *rateLimiter
+
+	logger   *log.Logger
+	logEvery int
+	logSlow  time.Duration
+	logSeq   atomic.Uint64
+
+	maxBody int64         // 請求本體位元組上限（見 decode.go）
+	timeout time.Duration // 每個請求的時限（見 timeout.go）
+
+	amountScale int // 回應金額的小數位數，<= 0 為整數輸出（見 money.go）
+
+	routes  *routeStats // 每條路由的請求統計（見 routes.go）
+	metrics *metrics    // Prometheus 指標（見 metrics.go）
+	idem    *idemStore  // Idempotency-Key 回應紀錄（見 idempotency.go）
+	webhook *webhook    // 交易事件通知（見 webhook.go）
+
+	streamsDone chan struct{} // 關閉時結束所有事件串流（見 events.go）
+	streamsOnce sync.Once
+
+	ready healthState // 最近一次 persist 的結果（見 health.go）
 }
 
 // NewServer 建立新的 HTTP 伺服器。
-// persist 可為 nil；若提供則會於每次成功操作後觸發。
-func NewServer(b *bank.Bank, persist func() error) *Server {
-	return &Server{Bank: b, persist: persist}
+// persist 可為 nil；若提供則會於每次成功操作後、回應前觸發（失敗時見 persisted）。
+// opts 為可選設定（見 options.go）。
+func NewServer(b *bank.Bank, persist func() error, opts ...Option) *Server {
+	s := &Server{
+		Bank: b, persist: persist, gzipMin: defaultGzipMinSize, corsOrigins: defaultCORSOrigins, maxBody: defaultMaxBody, timeout: defaultRequestTimeout,
+		routes: newRouteStats(), metrics: newMetrics(b), idem: newIdemStore(),
+		streamsDone: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// errNotPersisted 表示變更已套用到記憶體，但 persist 鉤子回報寫入失敗。
+var errNotPersisted = errors.New("applied but not persisted")
+
+// persisted 於變更成功後呼叫 persist 鉤子；須在寫出成功回應之前呼叫。
+// 寫入失敗時回應 500（code not_persisted）並回傳 false，呼叫端應直接返回。
+// 鉤子為合併寫入（storage.Persister.MarkDirty）時，回報的是最近一次背景保存的失敗：
+// 保存恢復之前的變更都回應 not_persisted，而不是回報已保存。
+//
+// 記憶體中的變更不回滾：交易可能已寫入 journal、送出 webhook 或被其他請求讀取，
+// 回滾反而會造成不一致；下一次成功的 persist 會連同這筆變更一起寫入。
+// 因此用戶端收到 not_persisted 時不應直接重送（會重複套用），而應查詢帳戶確認狀態；
+// 帶 Idempotency-Key 的請求會保存此回應，重送時重播而不再執行（見 idempotency.go）。
+func (s *Server) persisted(w http.ResponseWriter) bool {
+	if s.persist == nil {
+		return true
+	}
+	err := s.persist()
+	s.ready.record(err)
+	if err == nil {
+		return true
+	}
+	if cw, ok := w.(*captureWriter); ok {
+		cw.applied = true
+	}
+	writeErr(w, fmt.Errorf("%w: %v", errNotPersisted, err), http.StatusInternalServerError)
+	return false
+}
+
+// accountWithLinks 為建立帳戶的回應：帳戶欄位加上 links（self 為新帳戶的路徑）。
+type accountWithLinks struct {
+	accountView
+	Links map[string]string `json:"links"`
+}
+
+// accountWithLogs 為 GET /accounts/{id}?include=logs 的回應：帳戶欄位加上一頁日誌、日誌總筆數與分頁連結。
+type accountWithLogs struct {
+	accountView
+	Logs      []logView         `json:"logs"`
+	LogsTotal int               `json:"logs_total"`
+	Links     map[string]string `json:"links"`
+}
+
+// includeOptions 為 ?include= 可接受的值。
+var includeOptions = []string{"logs"}
+
+// parseInclude 解析以逗號分隔的 ?include= 參數；未知的值回傳錯誤（對應 400）。
+func parseInclude(r *http.Request) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for _, v := range strings.Split(r.URL.Query().Get("include"), ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !slices.Contains(includeOptions, v) {
+			return nil, fmt.Errorf("include must be one of %s", strings.Join(includeOptions, ", "))
+		}
+		out[v] = true
+	}
+	return out, nil
 }
 
 // accounts 處理：
-//   - POST /accounts  → 建立帳戶
-//   - GET  /accounts  → 列出所有帳戶
+//   - POST /accounts  → 建立帳戶（201，附 Location 標頭）
+//   - GET  /accounts  → 列出所有帳戶（可選 ?offset=&limit= 分頁；?ref= 依外部參照查詢單一帳戶）
 func (s *Server) accounts(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
 		var req struct {
-			Name    string `json:"name"`
-			Balance int64  `json:"balance"`
+			Name        string `json:"name"`
+			Balance     int64  `json:"balance"`
+			Currency    string `json:"currency"`     // 選填，ISO-4217；預設 USD
+			ExternalRef string `json:"external_ref"` // 選填，整合方自訂的外部參照，須全行唯一
 		}
 		// 解析請求內容
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+		if !decodeBody(w, r, &req) {
 			return
 		}
 		// 呼叫 Bank 層建立帳戶
-		a, err := s.Bank.Create(req.Name, req.Balance)
+		a, err := s.Bank.Open(bank.AccountSpec{Name: req.Name, Balance: req.Balance, Currency: req.Currency, ExternalRef: req.ExternalRef})
 		if err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
-		// 建立成功 → 回傳 201 Created
-		writeJSON(w, http.StatusCreated, a)
-
-		// 持久化快照（非阻塞）
-		if s.persist != nil {
-			_ = s.persist()
+		// 建立成功 → 回傳 201 Created，Location 與 links.self 指向新帳戶（保留 /api/v1 前綴）
+		if !s.persisted(w) {
+			return
 		}
+		self := accountLink(r, a.ID)
+		w.Header().Set("Location", self)
+		writeJSON(w, http.StatusCreated, accountWithLinks{accountView: s.viewAccount(a), Links: map[string]string{"self": self}})
 
 	case http.MethodGet:
-		// 列出所有帳戶
-		writeJSON(w, http.StatusOK, s.Bank.List())
+		// 帶 ref 時改為依外部參照查詢單一帳戶（查無回傳 404）
+		if q := r.URL.Query(); q.Has("ref") {
+			a, err := s.Bank.GetByExternalRef(q.Get("ref"))
+			if err != nil {
+				writeErr(w, err, httpStatusFor(err))
+				return
+			}
+			writeJSON(w, http.StatusOK, s.viewAccount(a))
+			return
+		}
+		// 列出所有帳戶；帶 offset / limit / sort 時改為分頁回應（見 paging.go）
+		p, paged, err := parsePage(r)
+		if err != nil {
+			writeErr(w, err, http.StatusBadRequest)
+			return
+		}
+		sortBy := r.URL.Query().Get("sort")
+		if !paged && !r.URL.Query().Has("sort") {
+			writeJSON(w, http.StatusOK, s.viewAccounts(s.Bank.List()))
+			return
+		}
+		if !paged {
+			p = page{limit: defaultPageLimit}
+		}
+		list, total, err := s.Bank.ListPage(p.limit, p.offset, sortBy)
+		if err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"total":    total,
+			"accounts": s.viewAccounts(list),
+			"links":    pageLinks(r, p, total),
+		})
 	default:
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
 	}
 }
 
+// accountsGet 處理 POST /accounts/get：批次查詢多個帳戶。
+// 請求：{"ids": ["1","2"], "strict": false}
+// 回應：{"accounts": {"1": {...}}, "missing": ["2"]}；strict 模式下有任一 ID 不存在則回傳 404。
+func (s *Server) accountsGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		IDs    []string `json:"ids"`
+		Strict bool     `json:"strict"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	accts, err := s.Bank.GetMany(req.IDs, req.Strict)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	missing := []string{}
+	for _, id := range req.IDs {
+		if _, ok := accts[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	views := make(map[string]accountView, len(accts))
+	for id, a := range accts {
+		views[id] = s.viewAccount(a)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"accounts": views, "missing": missing})
+}
+
+// accountsLogs 處理 POST /accounts/logs：批次查詢多個帳戶的日誌，供儀表板一次取得。
+// 請求：{"ids": ["1","2"]}
+// 回應：{"logs": {"1": [...], "2": []}, "missing": ["3"]}；不存在的 ID 列於 missing，不影響其他帳戶。
+func (s *Server) accountsLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		IDs []string `json:"ids"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	logs, err := s.Bank.LogsMulti(req.IDs)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	missing := []string{}
+	for _, id := range req.IDs {
+		if _, ok := logs[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	views := make(map[string][]logView, len(logs))
+	for id, l := range logs {
+		views[id] = s.viewLogs(l)
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"logs": views, "missing": missing})
+}
+
+// accountRouteMethods 為 /accounts/{id} 之下各子路徑允許的方法（空字串為 /accounts/{id} 本身）；
+// 不在表中的子路徑回傳 404，方法不符回傳 405 並以 Allow 標頭列出允許的方法。
+// /hold 之下的 /{holdID}/capture、/{holdID}/release 由 accountHold 進一步判定。
+var accountRouteMethods = map[string][]string{
+	"":            {http.MethodGet, http.MethodPatch, http.MethodDelete},
+	"deposit":     {http.MethodPost},
+	"withdraw":    {http.MethodPost},
+	"close":       {http.MethodPost},
+	"freeze":      {http.MethodPost},
+	"unfreeze":    {http.MethodPost},
+	"hold":        {http.MethodPost},
+	"balance":     {http.MethodGet},
+	"logs":        {http.MethodGet},
+	"events":      {http.MethodGet},
+	"logs.ofx":    {http.MethodGet},
+	"logs.csv":    {http.MethodGet},
+	"logs.ndjson": {http.MethodGet},
+	"netflow":     {http.MethodGet},
+	"statement":   {http.MethodGet},
+}
+
 // accountSubroutes 處理子路徑：
 //
 //	GET  /accounts/{id}           → 查詢帳戶
 //	POST /accounts/{id}/deposit   → 存款
 //	POST /accounts/{id}/withdraw  → 提款
-//	GET  /accounts/{id}/logs      → 交易日誌查詢
+//	POST /accounts/{id}/close     → 關閉帳戶（餘額須為 0）
+//	POST /accounts/{id}/freeze    → 凍結帳戶（拒絕一切資金異動，仍可查詢）
+//	POST /accounts/{id}/unfreeze  → 解除凍結
+//	GET  /accounts/{id}/balance   → 僅查詢餘額（{"id","balance"}）
+//	GET  /accounts/{id}/logs      → 交易日誌查詢（可選 ?offset=&limit= 分頁）
+//	GET  /accounts/{id}/logs.ofx  → 交易日誌匯出（OFX）
+//	GET  /accounts/{id}/logs.csv  → 交易日誌匯出（CSV）
+//	GET  /accounts/{id}/logs.ndjson → 交易日誌匯出（NDJSON）
+//	GET  /accounts/{id}/netflow   → 期間現金流彙總（?from=&to=）
 func (s *Server) accountSubroutes(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/accounts/")
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 	if len(parts) == 0 || parts[0] == "" {
-		http.NotFound(w, r)
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
 		return
 	}
 	id := parts[0]
 
-	// GET /accounts/{id}
+	// 先依路由表判定：未知子路徑 404，已知子路徑但方法不符 405（附 Allow 標頭）
+	sub := strings.Join(parts[1:], "/")
+	if len(parts) > 2 && parts[1] == "hold" {
+		sub = "hold"
+	}
+	allowed, ok := accountRouteMethods[sub]
+	if !ok {
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+		return
+	}
+	if !slices.Contains(allowed, r.Method) {
+		methodNotAllowed(w, allowed...)
+		return
+	}
+
+	// GET / PATCH / DELETE /accounts/{id}
 	if len(parts) == 1 {
-		if r.Method != http.MethodGet {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-			return
-		}
-		a, err := s.Bank.Get(id)
-		if err != nil {
-			writeErr(w, err, http.StatusNotFound)
-			return
+		switch r.Method {
+		case http.MethodGet:
+			include, err := parseInclude(r)
+			if err != nil {
+				writeErr(w, err, http.StatusBadRequest)
+				return
+			}
+			a, err := s.Bank.Get(id)
+			if err != nil {
+				writeErr(w, err, httpStatusFor(err))
+				return
+			}
+			if !include["logs"] {
+				setETag(w, a)
+				writeJSON(w, http.StatusOK, s.viewAccount(a))
+				return
+			}
+			// ?include=logs：內嵌日誌，分頁參數與 /accounts/{id}/logs 相同（預設 offset=0、limit=defaultPageLimit）；
+			// 日誌取自同一份帳戶快照，與餘額一致
+			p, paged, err := parsePage(r)
+			if err != nil {
+				writeErr(w, err, http.StatusBadRequest)
+				return
+			}
+			if !paged {
+				p = page{limit: defaultPageLimit}
+			}
+			setETag(w, a)
+			writeJSON(w, http.StatusOK, accountWithLogs{
+				accountView: s.viewAccount(a),
+				Logs:        s.viewLogs(paginate(a.Logs, p)),
+				LogsTotal:   len(a.Logs),
+				Links:       pageLinks(r, p, len(a.Logs)),
+			})
+		case http.MethodPatch:
+			// 更名：{"name":"..."}；空白名稱 400、帳戶不存在 404
+			var req struct {
+				Name string `json:"name"`
+			}
+			if !decodeBody(w, r, &req) {
+				return
+			}
+			a, err := s.Bank.Rename(id, req.Name)
+			if err != nil {
+				writeErr(w, err, httpStatusFor(err))
+				return
+			}
+			if !s.persisted(w) {
+				return
+			}
+			writeJSON(w, http.StatusOK, s.viewAccount(a))
+		case http.MethodDelete:
+			// 刪除帳戶：餘額須為零；成功回傳 204 並持久化
+			if err := s.Bank.Delete(id); err != nil {
+				writeErr(w, err, httpStatusFor(err))
+				return
+			}
+			if !s.persisted(w) {
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
 		}
-		writeJSON(w, http.StatusOK, a)
 		return
 	}
 
 	// 其他子操作
 	switch parts[1] {
 	case "deposit": // POST /accounts/{id}/deposit
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		// 先確認帳戶存在再解析請求主體：帳戶不存在一律 404，不必讀取（可能很大的）主體
+		if _, err := s.Bank.Get(id); err != nil {
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
 		var req struct {
-			Amount int64 `json:"amount"`
+			Amount int64  `json:"amount"`
+			Note   string `json:"note"` // 選填：寫入日誌的備註，預設 "deposit"
 		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+		if !decodeBody(w, r, &req) {
 			return
 		}
-		a, err := s.Bank.Deposit(id, req.Amount)
+		tx, err := s.applyIfMatch(r, bank.Op{Type: bank.TxDeposit, Account: id, Amount: req.Amount, Note: req.Note})
 		if err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
-		// 存款成功後
-		writeJSON(w, http.StatusOK, a)
-		// 資料持久化
-		if s.persist != nil {
-			_ = s.persist()
+		// 存款成功後回傳最新帳戶狀態（若啟用則附上收據）
+		a, _ := s.Bank.Get(id)
+		if !s.persisted(w) {
+			return
 		}
+		setETag(w, a)
+		writeJSON(w, http.StatusOK, accountWithReceipt{accountView: s.viewAccount(a), Receipt: s.receipt(tx)})
 
 	case "withdraw": // POST /accounts/{id}/withdraw
-		if r.Method != http.MethodPost {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		// 先確認帳戶存在再解析請求主體：帳戶不存在一律 404，不必讀取（可能很大的）主體
+		if _, err := s.Bank.Get(id); err != nil {
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
 		var req struct {
-			Amount int64 `json:"amount"`
+			Amount    int64  `json:"amount"`
+			RequestID string `json:"request_id"` // 選填：重送相同 request_id 不會重複扣款
+			Note      string `json:"note"`       // 選填：寫入日誌的備註，預設 "withdraw"
 		}
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+		if !decodeBody(w, r, &req) {
 			return
 		}
-		a, err := s.Bank.Withdraw(id, req.Amount)
+		tx, err := s.applyIfMatch(r, bank.Op{Type: bank.TxWithdraw, Account: id, Amount: req.Amount, RequestID: req.RequestID, Note: req.Note})
 		if err != nil {
-			writeErr(w, err, http.StatusBadRequest)
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
-		// 提款成功後
-		writeJSON(w, http.StatusOK, a)
-		// 資料持久化
-		if s.persist != nil {
-			_ = s.persist()
+		// 提款成功後回傳最新帳戶狀態（若啟用則附上收據）
+		a, _ := s.Bank.Get(id)
+		if !s.persisted(w) {
+			return
 		}
+		setETag(w, a)
+		writeJSON(w, http.StatusOK, accountWithReceipt{accountView: s.viewAccount(a), Receipt: s.receipt(tx)})
+
+	case "close": // POST /accounts/{id}/close
+		a, err := s.Bank.Close(id)
+		if err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		if !s.persisted(w) {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.viewAccount(a))
+
+	case "freeze", "unfreeze": // POST /accounts/{id}/freeze、/unfreeze（管理者）
+		if err := s.Bank.SetFrozen(id, parts[1] == "freeze"); err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		a, _ := s.Bank.Get(id)
+		if !s.persisted(w) {
+			return
+		}
+		writeJSON(w, http.StatusOK, s.viewAccount(a))
+
+	case "hold": // POST /accounts/{id}/hold、/hold/{holdID}/capture、/hold/{holdID}/release（見 holds.go）
+		s.accountHold(w, r, id, parts[2:])
+
+	case "balance": // GET /accounts/{id}/balance：只回傳餘額，供高頻輪詢使用
+		a, err := s.Bank.Get(id)
+		if err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, struct {
+			ID      string `json:"id"`
+			Balance amount `json:"balance"`
+		}{a.ID, s.money(a.Balance)})
 
 	case "logs": // GET /accounts/{id}/logs
-		if r.Method != http.MethodGet {
-			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		// 日誌一律分頁：未帶參數時為 offset=0、limit=defaultPageLimit
+		p, paged, err := parsePage(r)
+		if err != nil {
+			writeErr(w, err, http.StatusBadRequest)
+			return
+		}
+		if !paged {
+			p = page{limit: defaultPageLimit}
+		}
+		// 可選篩選：?direction=in|out&since=&until=（見 logsFilter）
+		f, filtered, err := logsFilter(r)
+		if err != nil {
+			writeErr(w, err, http.StatusBadRequest)
 			return
 		}
-		logs, err := s.Bank.Logs(id)
+		var logs []bank.Log
+		var total int
+		if filtered {
+			if logs, err = s.Bank.FilterLogs(id, f); err == nil {
+				total = len(logs)
+				logs = paginate(logs, p)
+			}
+		} else {
+			logs, total, err = s.Bank.LogsPage(id, p.limit, p.offset)
+		}
 		if err != nil {
-			writeErr(w, err, http.StatusNotFound)
+			writeErr(w, err, httpStatusFor(err))
 			return
 		}
-		writeJSON(w, http.StatusOK, logs)
+		writeJSON(w, http.StatusOK, map[string]any{
+			"total": total,
+			"logs":  s.viewLogs(logs),
+			"links": pageLinks(r, p, total),
+		})
+
+	case "events": // GET /accounts/{id}/events（Server-Sent Events）
+		s.accountEvents(w, r, id)
+
+	case "logs.ofx": // GET /accounts/{id}/logs.ofx
+		s.logsOFX(w, id)
+
+	case "logs.csv": // GET /accounts/{id}/logs.csv
+		s.logsCSV(w, id)
+
+	case "logs.ndjson": // GET /accounts/{id}/logs.ndjson
+		s.logsNDJSON(w, id)
+
+	case "netflow": // GET /accounts/{id}/netflow?from=&to=
+		s.netflow(w, r, id)
+
+	case "statement": // GET /accounts/{id}/statement?from=&to=
+		s.statement(w, r, id)
 	default:
-		http.NotFound(w, r)
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
 	}
 }
 
@@ -178,45 +577,143 @@ func (s *Server) accountSubroutes(w http.ResponseWriter, r *http.Request) {
 //	POST /transfer  → JSON {From, To, Amount}
 //
 // 對應題目功能「Able to transfer money from one account to another account」。
-// 成功後同時回傳兩帳戶最新餘額。
+// 成功後同時回傳交易 ID 與兩帳戶最新餘額（若啟用則附上收據）。
 func (s *Server) transfer(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
 		return
 	}
+	createTo := false
+	if v := r.URL.Query().Get("create_to"); v != "" {
+		c, err := strconv.ParseBool(v)
+		if err != nil {
+			writeErr(w, errors.New("create_to must be true or false"), http.StatusBadRequest)
+			return
+		}
+		createTo = c
+	}
 	var req struct {
 		From   string `json:"From"`
 		To     string `json:"To"`
 		Amount int64  `json:"Amount"`
+		ToName string `json:"to_name"` // 僅 ?create_to=true：新收款帳戶的名稱
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if createTo {
+		s.transferOrCreate(w, r, req.From, req.To, req.ToName, req.Amount)
+		return
 	}
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeErr(w, err, http.StatusBadRequest)
+	if req.ToName != "" {
+		writeErr(w, errors.New("to_name requires create_to=true"), http.StatusBadRequest)
 		return
 	}
 	// 呼叫 bank 層執行原子轉帳
-	if err := s.Bank.Transfer(req.From, req.To, req.Amount); err != nil {
-		code := http.StatusBadRequest
-		if errors.Is(err, bank.ErrInsufficient) {
-			code = http.StatusConflict
-		}
-		writeErr(w, err, code)
+	tx, ok := s.execTransfer(w, r, req.From, req.To, req.Amount)
+	if !ok {
 		return
 	}
 
 	// 回傳轉帳後的最新帳戶狀態
-	fromAcc, _ := s.Bank.Get(req.From)
-	toAcc, _ := s.Bank.Get(req.To)
+	fromAcc, toAcc := s.viewCurrent(req.From), s.viewCurrent(req.To)
+
+	// 大額轉帳進入審核：來源資金已保留、尚未過帳 → 202 Accepted（見 transfers.go）；
+	// 當日累計與交易序號已更新，與其他異動相同須先保存
+	if tx.Status == bank.TxPendingReview {
+		if !s.persisted(w) {
+			return
+		}
+		writeJSON(w, http.StatusAccepted, map[string]any{
+			"message": "transfer held for review",
+			"tx_id":   tx.ID,
+			"status":  tx.Status,
+			"from":    fromAcc,
+			"to":      toAcc,
+		})
+		return
+	}
 
 	// 轉帳成功後
-	writeJSON(w, http.StatusOK, map[string]any{
+	resp := map[string]any{
 		"message": "transfer success",
+		"tx_id":   tx.ID,
 		"from":    fromAcc,
 		"to":      toAcc,
-	})
-	// 轉帳成功 → 寫入快照
-	if s.persist != nil {
-		_ = s.persist()
 	}
+	if rc := s.receipt(tx); rc != nil {
+		resp["receipt"] = rc
+	}
+	if !s.persisted(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// transferOrCreate 處理 POST /transfer?create_to=true：開立名為 toName 的新帳戶並轉入 Amount（見 bank.TransferOrCreate）。
+// 成功回傳 201（Location 指向新帳戶）；進入審核時回傳 202。To 必須留空，錯誤依 httpStatusFor 回應。
+func (s *Server) transferOrCreate(w http.ResponseWriter, r *http.Request, from, to, toName string, amount int64) {
+	if to != "" {
+		writeErr(w, errors.New("To must be empty when create_to=true; use to_name"), http.StatusBadRequest)
+		return
+	}
+	if err := checkCtx(r.Context()); err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	created, tx, err := s.Bank.TransferOrCreate(from, toName, amount)
+	if _, err = s.observe(bank.Op{Type: bank.TxTransfer, From: from, Amount: amount}, tx, err); err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	fromAcc, toAcc := s.viewCurrent(from), s.viewCurrent(created.ID)
+	if !s.persisted(w) {
+		return
+	}
+	w.Header().Set("Location", accountLink(r, created.ID))
+	if tx.Status == bank.TxPendingReview {
+		writeJSON(w, http.StatusAccepted, map[string]any{
+			"message": "transfer held for review",
+			"tx_id":   tx.ID,
+			"status":  tx.Status,
+			"from":    fromAcc,
+			"to":      toAcc,
+		})
+		return
+	}
+	resp := map[string]any{
+		"message": "transfer success",
+		"tx_id":   tx.ID,
+		"from":    fromAcc,
+		"to":      toAcc,
+	}
+	if rc := s.receipt(tx); rc != nil {
+		resp["receipt"] = rc
+	}
+	writeJSON(w, http.StatusCreated, resp)
+}
+
+// execTransfer 執行轉帳（v1 與 v2 共用，見 v2.go）：支援 If-Match，錯誤依 httpStatusFor 回應；
+// 失敗時已寫出錯誤回應並回傳 false，呼叫端應直接返回。
+func (s *Server) execTransfer(w http.ResponseWriter, r *http.Request, from, to string, amount int64) (bank.Tx, bool) {
+	tx, err := s.applyIfMatch(r, bank.Op{Type: bank.TxTransfer, From: from, To: to, Amount: amount})
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return tx, false
+	}
+	return tx, true
+}
+
+// stats 處理 GET /stats：回傳帳戶數與全行餘額總和，供儀表板與批次作業後的對帳檢查。
+func (s *Server) stats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int64{
+		"accounts":      int64(s.Bank.AccountCount()),
+		"total_balance": s.Bank.TotalBalance(),
+	})
 }
 
 // health 提供健康檢查端點：GET /health。
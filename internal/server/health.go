@@ -0,0 +1,71 @@
+// internal/server/health.go
+//
+// 本檔提供分離的健康檢查端點，供容器編排的探針使用：
+//   - GET /health/live：行程存活即回 200（與舊有的 /health 相同）；
+//   - GET /health/ready：最近一次 persist 成功（或尚未保存過、未設定 persist）時回 200，
+//     最近一次失敗時回 503，附上錯誤訊息與失敗時間；下一次保存成功後自動恢復。
+//
+// 就緒狀態由 persisted 於每次變更後、以及 RecordPersist（背景保存的結果，見 storage.Persister.OnSave）更新，
+// 不另外發出寫入探測，避免探針本身造成磁碟負擔。
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// healthState 記錄最近一次 persist 的結果。
+type healthState struct {
+	mu       sync.Mutex
+	err      error     // 最近一次 persist 的錯誤；nil 代表成功或尚未保存
+	failedAt time.Time // err 發生的時間
+}
+
+// record 記錄一次 persist 的結果。
+func (h *healthState) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.err = err
+	if err != nil {
+		h.failedAt = time.Now()
+	}
+}
+
+// last 回傳最近一次 persist 失敗的時間與錯誤；錯誤為 nil 代表就緒。
+func (h *healthState) last() (time.Time, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.failedAt, h.err
+}
+
+// RecordPersist 記錄一次實際保存的結果，供 /health/ready 判斷。
+// persist 鉤子只排程寫入（合併模式）時，由保存端於每次寫入後呼叫，例如 persister.OnSave(s.RecordPersist)。
+func (s *Server) RecordPersist(err error) {
+	s.ready.record(err)
+}
+
+// isHealthPath 回報 path（已去除 API 版本前綴）是否為健康檢查端點；驗證與速率限制對其放行。
+func isHealthPath(path string) bool {
+	return path == "/health" || strings.HasPrefix(path, "/health/")
+}
+
+// healthLive 處理 GET /health/live：行程可回應請求即為存活。
+func (s *Server) healthLive(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// healthReady 處理 GET /health/ready：最近一次 persist 失敗時回 503。
+func (s *Server) healthReady(w http.ResponseWriter, r *http.Request) {
+	at, err := s.ready.last()
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{
+			"status":            "unavailable",
+			"persist_error":     err.Error(),
+			"persist_failed_at": at.UTC().Format(time.RFC3339),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
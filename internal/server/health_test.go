@@ -0,0 +1,122 @@
+// internal/server/health_test.go
+//
+// 測試分離的健康檢查：/health/live 永遠 200；/health/ready 隨 persist 鉤子（或合併寫入的背景保存）的結果
+// 在 200 與 503 之間切換，且兩者在啟用驗證與速率限制時仍對探針開放。
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+	"banking/internal/storage"
+)
+
+// TestHealthReady 驗證：
+// 1️⃣ 尚未保存過時 ready 為 200；
+// 2️⃣ persist 鉤子開始失敗後，ready 轉為 503 並附上錯誤，live 與 /health 仍為 200；
+// 3️⃣ 鉤子恢復、下一次保存成功後 ready 回到 200。
+func TestHealthReady(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	var fail atomic.Bool
+	ts := httptest.NewServer(NewServer(b, func() error {
+		if fail.Load() {
+			return errors.New("disk full")
+		}
+		return nil
+	}).Router())
+	defer ts.Close()
+	cli := ts.Client()
+	deposit := func(code int) {
+		t.Helper()
+		doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, code, nil)
+	}
+
+	// 1️⃣ 尚未保存、保存成功：就緒
+	doJSON(t, cli, "GET", ts.URL+"/health/ready", nil, http.StatusOK, nil)
+	deposit(http.StatusOK)
+	doJSON(t, cli, "GET", ts.URL+"/api/v1/health/ready", nil, http.StatusOK, nil)
+
+	// 2️⃣ 保存失敗：ready → 503，live 不受影響
+	fail.Store(true)
+	deposit(http.StatusInternalServerError)
+	var body map[string]string
+	doJSON(t, cli, "GET", ts.URL+"/health/ready", nil, http.StatusServiceUnavailable, &body)
+	if body["status"] != "unavailable" || !strings.Contains(body["persist_error"], "disk full") || body["persist_failed_at"] == "" {
+		t.Fatalf("ready body=%v", body)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/health/live", nil, http.StatusOK, nil)
+	doJSON(t, cli, "GET", ts.URL+"/health", nil, http.StatusOK, nil)
+
+	// 3️⃣ 恢復：下一次保存成功後重新就緒
+	fail.Store(false)
+	deposit(http.StatusOK)
+	doJSON(t, cli, "GET", ts.URL+"/health/ready", nil, http.StatusOK, nil)
+}
+
+// TestHealthReadyDebounced 驗證合併寫入（預設設定）下，背景保存失敗同樣讓 ready 轉為 503，恢復後回到 200。
+func TestHealthReadyDebounced(t *testing.T) {
+	b := bank.NewBank()
+	var fail atomic.Bool
+	fail.Store(true)
+	p := storage.NewPersister(func() error {
+		if fail.Load() {
+			return errors.New("disk full")
+		}
+		return nil
+	}, 5*time.Millisecond)
+	defer p.Close()
+	s := NewServer(b, p.MarkDirty)
+	p.OnSave(s.RecordPersist)
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	// waitReady 等待 ready 轉為 code（背景保存於合併間隔後才執行）
+	waitReady := func(code int) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			resp, err := cli.Get(ts.URL + "/health/ready")
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode == code {
+				return
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("ready=%d want %d", resp.StatusCode, code)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A"}, http.StatusCreated, nil)
+	waitReady(http.StatusServiceUnavailable)
+
+	fail.Store(false)
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	waitReady(http.StatusOK)
+}
+
+// TestHealthProbesBypassAuthAndRateLimit 驗證 /health/live 與 /health/ready 免驗證、不受速率限制。
+func TestHealthProbesBypassAuthAndRateLimit(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil, WithAPIKeys(map[string][]string{"admin-key": {"admin"}}), WithRateLimit(1, 1, false))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	for range 3 {
+		for _, path := range []string{"/health/live", "/health/ready", "/api/v1/health/ready"} {
+			doJSON(t, ts.Client(), "GET", ts.URL+path, nil, http.StatusOK, nil)
+		}
+	}
+}
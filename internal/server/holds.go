@@ -0,0 +1,88 @@
+// internal/server/holds.go
+//
+// 本檔提供授權保留（預先授權，見 bank.Hold）的端點：
+//   - POST /accounts/{id}/hold                    → 保留 {"amount":N}，201 回傳保留與最新帳戶
+//   - POST /accounts/{id}/hold/{holdID}/capture   → 請款，回傳最新帳戶（若啟用則附收據）
+//   - POST /accounts/{id}/hold/{holdID}/release   → 取消保留，回傳最新帳戶
+//
+// 保留不存在、已請款、已釋放、已逾期或不屬於該帳戶一律 404（code authorization_not_found）。
+package server
+
+import (
+	"net/http"
+
+	"banking/internal/bank"
+)
+
+// holdResponse 為建立授權保留的回應。
+type holdResponse struct {
+	bank.AuthHold
+	Account accountView `json:"account"`
+}
+
+// accountHold 處理 /accounts/{id}/hold 與其子路徑（方法已由 accountSubroutes 檢查，皆為 POST）；rest 為 "hold" 之後的路徑段。
+func (s *Server) accountHold(w http.ResponseWriter, r *http.Request, id string, rest []string) {
+	if len(rest) != 0 && len(rest) != 2 {
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+		return
+	}
+	if len(rest) == 0 {
+		s.placeHold(w, r, id)
+		return
+	}
+
+	holdID := rest[0]
+	if h, err := s.Bank.GetHold(holdID); err != nil || h.Account != id {
+		writeErr(w, bank.ErrAuthHoldNotFound, http.StatusNotFound)
+		return
+	}
+	switch rest[1] {
+	case "capture":
+		tx, err := s.Bank.Capture(holdID)
+		if err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		s.notifyTx(tx)
+		a, _ := s.Bank.Get(id)
+		if !s.persisted(w) {
+			return
+		}
+		setETag(w, a)
+		writeJSON(w, http.StatusOK, accountWithReceipt{accountView: s.viewAccount(a), Receipt: s.receipt(tx)})
+	case "release":
+		if err := s.Bank.Release(holdID); err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		a, _ := s.Bank.Get(id)
+		if !s.persisted(w) {
+			return
+		}
+		setETag(w, a)
+		writeJSON(w, http.StatusOK, s.viewAccount(a))
+	default:
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+	}
+}
+
+// placeHold 處理 POST /accounts/{id}/hold：可動用餘額不足 409，其餘錯誤依 opStatus。
+func (s *Server) placeHold(w http.ResponseWriter, r *http.Request, id string) {
+	var req struct {
+		Amount int64 `json:"amount"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	holdID, err := s.Bank.Hold(id, req.Amount)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	h, _ := s.Bank.GetHold(holdID)
+	a, _ := s.Bank.Get(id)
+	if !s.persisted(w) {
+		return
+	}
+	writeJSON(w, http.StatusCreated, holdResponse{AuthHold: h, Account: s.viewAccount(a)})
+}
@@ -0,0 +1,76 @@
+// internal/server/holds_test.go
+//
+// 測試授權保留端點：保留後 held 增加、balance 不變；請款扣款並附收據；釋放恢復可動用餘額；
+// 不存在或不屬於該帳戶的保留回傳 404。
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestAccountHoldCaptureRelease 驗證：
+// 1️⃣ POST /hold → 201，回傳 hold_id 與帳戶（balance 不變、held 增加）；超過可動用餘額的提款被拒；
+// 2️⃣ capture → balance 減少、held 歸零，日誌新增一筆 capture；
+// 3️⃣ 另一筆保留 release → held 歸零、balance 不變；
+// 4️⃣ 已完成、不存在或不屬於該帳戶的保留 → 404（authorization_not_found）；金額不合法 400。
+func TestAccountHoldCaptureRelease(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 1000)
+	other, _ := b.Create("B", 100)
+	ts := httptest.NewServer(NewServer(b, nil, WithReceiptKey([]byte("k"))).Router())
+	defer ts.Close()
+	cli := ts.Client()
+	base := ts.URL + "/accounts/" + a.ID
+
+	// 1️⃣ 保留
+	var held struct {
+		HoldID  string         `json:"hold_id"`
+		Amount  int64          `json:"amount"`
+		Account map[string]any `json:"account"`
+	}
+	doJSON(t, cli, "POST", base+"/hold", map[string]any{"amount": 300}, http.StatusCreated, &held)
+	if held.HoldID == "" || held.Amount != 300 || held.Account["balance"] != float64(1000) || held.Account["held"] != float64(300) {
+		t.Fatalf("hold resp=%+v", held)
+	}
+	doJSON(t, cli, "POST", base+"/withdraw", map[string]any{"amount": 701}, http.StatusConflict, nil)
+
+	// 2️⃣ 請款
+	var captured map[string]any
+	doJSON(t, cli, "POST", base+"/hold/"+held.HoldID+"/capture", nil, http.StatusOK, &captured)
+	if captured["balance"] != float64(700) || captured["held"] != nil || captured["receipt"] == nil {
+		t.Fatalf("capture resp=%v want balance 700, no held, receipt", captured)
+	}
+	var logs struct {
+		Logs []map[string]any `json:"logs"`
+	}
+	doJSON(t, cli, "GET", base+"/logs", nil, http.StatusOK, &logs)
+	if len(logs.Logs) != 1 || logs.Logs[0]["type"] != bank.TxCapture || logs.Logs[0]["amount"] != float64(300) {
+		t.Fatalf("logs=%v want one capture of 300", logs.Logs)
+	}
+
+	// 3️⃣ 釋放
+	doJSON(t, cli, "POST", base+"/hold", map[string]any{"amount": 200}, http.StatusCreated, &held)
+	var released map[string]any
+	doJSON(t, cli, "POST", base+"/hold/"+held.HoldID+"/release", nil, http.StatusOK, &released)
+	if released["balance"] != float64(700) || released["held"] != nil {
+		t.Fatalf("release resp=%v want balance 700, no held", released)
+	}
+
+	// 4️⃣ 錯誤
+	var eb errorBody
+	doJSON(t, cli, "POST", base+"/hold/"+held.HoldID+"/capture", nil, http.StatusNotFound, &eb)
+	if eb.Code != "authorization_not_found" {
+		t.Fatalf("finished hold code=%q want authorization_not_found", eb.Code)
+	}
+	doJSON(t, cli, "POST", base+"/hold/hold-999/release", nil, http.StatusNotFound, nil)
+	doJSON(t, cli, "POST", base+"/hold", map[string]any{"amount": 50}, http.StatusCreated, &held)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+other.ID+"/hold/"+held.HoldID+"/capture", nil, http.StatusNotFound, nil)
+	doJSON(t, cli, "POST", base+"/hold", map[string]any{"amount": 0}, http.StatusBadRequest, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/999/hold", map[string]any{"amount": 10}, http.StatusNotFound, nil)
+	doJSON(t, cli, "POST", base+"/hold", map[string]any{"amount": 10_000}, http.StatusConflict, nil)
+	doJSON(t, cli, "GET", base+"/hold", nil, http.StatusMethodNotAllowed, nil)
+}
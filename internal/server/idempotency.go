@@ -0,0 +1,181 @@
+// internal/server/idempotency.go
+//
+// 本檔實作 Idempotency-Key 標頭，讓客戶端可安全重送存款、提款與轉帳請求。
+//
+// 規則：
+//   - 僅作用於 POST /accounts/{id}/deposit、POST /accounts/{id}/withdraw 與 POST /transfer；
+//     未帶標頭的請求照常處理。
+//   - 同一個 key 第一次處理後，保存狀態碼、Content-Type 與回應本文；之後帶同一 key 的請求
+//     直接重播保存的回應（附 Idempotent-Replayed: true），不再呼叫 Bank。
+//   - key 綁定第一次使用時的 API 版本與路徑（含帳戶 ID 與操作類型）與請求本文；
+//     用於不同版本、不同操作或不同參數時回傳 409（v1 與 v2 的回應格式不同，不得互相重播）。
+//   - 5xx 回應（例如全行凍結的 503）不保存，客戶端可於恢復後以同一 key 重試；
+//     唯一例外是「已套用但未持久化」的 500（見 persisted），變更已發生，重送不得再次執行。
+//   - 相同 key 的並行請求只會執行一次，其餘等待第一個完成後重播。
+//   - handler panic 時不保存任何回應，紀錄一併移除，之後以同一 key 重送會重新執行。
+//
+// 紀錄僅存於記憶體（程序重啟、重置帳本與匯入快照時即清空），最多保留 maxIdempotencyKeys 筆，超過時淘汰最舊的紀錄。
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// IdempotencyHeader 為冪等鍵的請求標頭名稱。
+const IdempotencyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeys 為保留的冪等紀錄上限。
+const maxIdempotencyKeys = 10000
+
+var (
+	errIdemScope  = errors.New("Idempotency-Key already used for a different operation")
+	errIdemParams = errors.New("Idempotency-Key already used with different parameters")
+)
+
+// idemStore 保存冪等鍵 → 回應紀錄；以自己的 mutex 保護，與 Bank 的鎖無關。
+type idemStore struct {
+	mu      sync.Mutex
+	entries map[string]*idemEntry
+	order   []string // 插入順序，用於淘汰最舊紀錄
+}
+
+// idemEntry 為單一冪等鍵的紀錄；done 關閉後 code / ctype / body 才可讀取。
+type idemEntry struct {
+	scope  string   // API 版本 + 方法 + 路徑
+	digest [32]byte // 請求本文的 SHA-256
+	done   chan struct{}
+	stored bool // false 代表回應未保存（5xx），等待者須自行重試
+
+	code  int
+	ctype string
+	body  []byte
+}
+
+func newIdemStore() *idemStore {
+	return &idemStore{entries: make(map[string]*idemEntry)}
+}
+
+// idempotentRoute 回報路徑是否支援 Idempotency-Key（路徑已去除 /api/v1 或 /api/v2 前綴）。
+func idempotentRoute(path string) bool {
+	return path == "/transfer" ||
+		(strings.HasPrefix(path, "/accounts/") && (strings.HasSuffix(path, "/deposit") || strings.HasSuffix(path, "/withdraw")))
+}
+
+// idempotent 包裝 handler：請求帶有 Idempotency-Key 且路由支援時，套用上述重播規則。
+// version 為掛載的 API 版本（"v1" / "v2"）；根路徑與 /api/v1 共用同一個 v1 mux，因此共用範圍。
+func (s *Server) idempotent(version string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyHeader)
+		if key == "" || r.Method != http.MethodPost || !idempotentRoute(r.URL.Path) {
+			next(w, r)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			err, code := bodyErr(err)
+			writeErr(w, err, code)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		scope, digest := version+" "+r.Method+" "+r.URL.Path, sha256.Sum256(body)
+
+		for {
+			e, first := s.idem.claim(key, scope, digest)
+			switch {
+			case e.scope != scope:
+				writeErr(w, errIdemScope, http.StatusConflict)
+				return
+			case e.digest != digest:
+				writeErr(w, errIdemParams, http.StatusConflict)
+				return
+			case first:
+				s.idem.finish(key, e, w, r, next)
+				return
+			}
+			<-e.done
+			if e.stored {
+				w.Header().Set("Content-Type", e.ctype)
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(e.code)
+				_, _ = w.Write(e.body)
+				return
+			}
+			// 第一個請求以 5xx 結束且未保存：重新爭取執行權
+		}
+	}
+}
+
+// claim 取得 key 的紀錄；不存在時建立新的進行中紀錄並回傳 first = true。
+func (st *idemStore) claim(key, scope string, digest [32]byte) (e *idemEntry, first bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if e, ok := st.entries[key]; ok {
+		return e, false
+	}
+	e = &idemEntry{scope: scope, digest: digest, done: make(chan struct{})}
+	st.entries[key] = e
+	st.order = append(st.order, key)
+	if len(st.order) > maxIdempotencyKeys {
+		old := st.order[0]
+		st.order = st.order[1:]
+		if oe := st.entries[old]; oe != nil && oe != e {
+			delete(st.entries, old)
+		}
+	}
+	return e, true
+}
+
+// clear 移除所有紀錄（帳本被清空或取代時呼叫）；進行中的請求照常完成，但其結果不再保存。
+func (st *idemStore) clear() {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.entries = make(map[string]*idemEntry)
+	st.order = nil
+}
+
+// finish 執行 handler 並同時擷取回應；非 5xx 或變更已套用時保存，否則移除紀錄讓之後的請求重新執行。
+// handler panic 時回應不完整（由 recover.go 回 500），一律移除紀錄後繼續向外 panic。
+func (st *idemStore) finish(key string, e *idemEntry, w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	rec := &captureWriter{ResponseWriter: w, code: http.StatusOK}
+	returned := false
+	defer func() {
+		st.mu.Lock()
+		if returned && (rec.code < 500 || rec.applied) {
+			e.code, e.ctype, e.body, e.stored = rec.code, rec.Header().Get("Content-Type"), rec.body.Bytes(), true
+		} else if st.entries[key] == e {
+			delete(st.entries, key)
+		}
+		st.mu.Unlock()
+		close(e.done)
+	}()
+	next(rec, r)
+	returned = true
+}
+
+// captureWriter 在寫出回應的同時保存狀態碼與本文。
+type captureWriter struct {
+	http.ResponseWriter
+	code        int
+	wroteHeader bool
+	body        bytes.Buffer
+	applied     bool // 變更已套用但未持久化（見 persisted），5xx 也須保存
+}
+
+func (c *captureWriter) WriteHeader(code int) {
+	if !c.wroteHeader {
+		c.code, c.wroteHeader = code, true
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *captureWriter) Write(p []byte) (int, error) {
+	c.wroteHeader = true
+	c.body.Write(p)
+	return c.ResponseWriter.Write(p)
+}
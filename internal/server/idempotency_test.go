@@ -0,0 +1,164 @@
+// internal/server/idempotency_test.go
+//
+// 測試 Idempotency-Key：重送不重複入帳、重播原始回應、key 不得跨操作或跨參數重用，並行重送只執行一次，
+// handler panic 時不保存回應。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// postWithKey 以指定的 Idempotency-Key 送出 JSON POST，回傳狀態碼、本文與是否為重播。
+func postWithKey(t *testing.T, url, key string, body any) (int, string, bool) {
+	t.Helper()
+	buf, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(buf))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyHeader, key)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	out, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(out), resp.Header.Get("Idempotent-Replayed") == "true"
+}
+
+func TestIdempotentDeposit(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	persisted := 0
+	ts := httptest.NewServer(NewServer(b, func() error { persisted++; return nil }).Router())
+	defer ts.Close()
+	url := ts.URL + "/accounts/" + a.ID + "/deposit"
+
+	// 1️⃣ 同一 key 送兩次：只入帳一次，第二次重播相同回應
+	code1, body1, replay1 := postWithKey(t, url, "dep-1", map[string]any{"amount": 50})
+	code2, body2, replay2 := postWithKey(t, url, "dep-1", map[string]any{"amount": 50})
+	if code1 != 200 || code2 != 200 || body1 != body2 || replay1 || !replay2 {
+		t.Fatalf("first=%d %q replay=%v second=%d %q replay=%v", code1, body1, replay1, code2, body2, replay2)
+	}
+	if got := get(t, b, a.ID).Balance; got != 150 {
+		t.Fatalf("balance=%d want 150 (deposited once)", got)
+	}
+	if persisted != 1 {
+		t.Fatalf("persisted=%d want 1", persisted)
+	}
+
+	// 2️⃣ 經 /api/v1 前綴重送同一 key 也是重播
+	if code, _, replay := postWithKey(t, ts.URL+"/api/v1/accounts/"+a.ID+"/deposit", "dep-1", map[string]any{"amount": 50}); code != 200 || !replay {
+		t.Fatalf("v1 replay code=%d replay=%v", code, replay)
+	}
+
+	// ❌ 同一 key 用於其他操作或不同參數 → 409
+	if code, _, _ := postWithKey(t, ts.URL+"/accounts/"+a.ID+"/withdraw", "dep-1", map[string]any{"amount": 50}); code != 409 {
+		t.Fatalf("cross-operation reuse code=%d want 409", code)
+	}
+	if code, _, _ := postWithKey(t, url, "dep-1", map[string]any{"amount": 60}); code != 409 {
+		t.Fatalf("different params code=%d want 409", code)
+	}
+
+	// 3️⃣ 錯誤回應（4xx）同樣被保存並重播
+	code, _, _ := postWithKey(t, ts.URL+"/accounts/"+a.ID+"/withdraw", "wd-big", map[string]any{"amount": 10_000})
+	_, _ = b.Deposit(a.ID, bank.NewMoney(10_000, "USD"), "")
+	if again, _, replay := postWithKey(t, ts.URL+"/accounts/"+a.ID+"/withdraw", "wd-big", map[string]any{"amount": 10_000}); again != code || !replay {
+		t.Fatalf("4xx replay=%d/%v want %d/true", again, replay, code)
+	}
+
+	if got := get(t, b, a.ID).Balance; got != 10_150 {
+		t.Fatalf("balance=%d want 10150", got)
+	}
+}
+
+// TestIdempotentConcurrentTransfer 驗證同一 key 的並行轉帳只執行一次。
+func TestIdempotentConcurrentTransfer(t *testing.T) {
+	b := bank.NewBank()
+	from, _ := b.Create("A", 1000)
+	to, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if code, _, _ := postWithKey(t, ts.URL+"/transfer", "tr-1", map[string]any{"From": from.ID, "To": to.ID, "Amount": 10}); code != 200 {
+				t.Errorf("code=%d", code)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := get(t, b, to.ID).Balance; got != 10 {
+		t.Fatalf("receiver balance=%d want 10", got)
+	}
+}
+
+// TestIdempotencyNotCachedOn5xx 驗證凍結期間（503）的回應不被保存，解凍後同一 key 可成功執行。
+func TestIdempotencyNotCachedOn5xx(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	url := ts.URL + "/accounts/" + a.ID + "/deposit"
+
+	b.FreezeAll()
+	if code, _, _ := postWithKey(t, url, "k", map[string]any{"amount": 5}); code != 503 {
+		t.Fatalf("frozen code=%d want 503", code)
+	}
+	b.UnfreezeAll()
+	if code, _, replay := postWithKey(t, url, "k", map[string]any{"amount": 5}); code != 200 || replay {
+		t.Fatalf("after unfreeze code=%d replay=%v", code, replay)
+	}
+	if got := get(t, b, a.ID).Balance; got != 5 {
+		t.Fatalf("balance=%d want 5", got)
+	}
+}
+
+// TestIdempotencyNotCachedOnPanic 驗證 handler panic 時（recover 回 500）不保存回應，
+// 同一 key 重送會重新執行 handler，而不是重播空白的 200。
+func TestIdempotencyNotCachedOnPanic(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil)
+	calls := 0
+	h := s.recoverMiddleware(s.idempotent("v1", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			panic("boom")
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"message": "ok"})
+	}))
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transfer", strings.NewReader(`{}`))
+		req.Header.Set(IdempotencyHeader, "k")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusInternalServerError {
+		t.Fatalf("panic code=%d want 500", rec.Code)
+	}
+	rec := send()
+	if rec.Code != http.StatusOK || calls != 2 || rec.Header().Get("Idempotent-Replayed") != "" || !strings.Contains(rec.Body.String(), "ok") {
+		t.Fatalf("retry code=%d calls=%d replayed=%q body=%q", rec.Code, calls, rec.Header().Get("Idempotent-Replayed"), rec.Body.String())
+	}
+}
+
+// get 取得帳戶目前狀態；不存在時讓測試失敗。
+func get(t *testing.T, b *bank.Bank, id string) *bank.Account {
+	t.Helper()
+	a, err := b.Get(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
@@ -0,0 +1,125 @@
+// internal/server/logging_test.go
+//
+// 測試請求日誌：每行含請求 ID、方法、路徑、狀態碼與耗時，請求 ID 以 X-Request-ID 回傳；
+// 取樣規則：錯誤一律記錄、成功請求依比例取樣、慢請求一律記錄。
+package server
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// syncBuffer 為可並行寫入的緩衝區（log.Logger 本身已序列化寫入，此處保護讀取）。
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSpace(b.buf.String()), "\n")
+}
+
+// TestRequestLogLine 驗證單筆請求的日誌內容與 X-Request-ID。
+func TestRequestLogLine(t *testing.T) {
+	out := &syncBuffer{}
+	s := NewServer(bank.NewBank(), nil, WithLogger(log.New(out, "", 0)))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	// 1️⃣ 未帶請求 ID：伺服器產生並回傳，日誌中的 req_id 相同
+	resp, err := ts.Client().Get(ts.URL + "/accounts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	id := resp.Header.Get(RequestIDHeader)
+	if len(id) != 16 {
+		t.Fatalf("X-Request-ID=%q want 16 hex chars", id)
+	}
+	line := out.lines()[0]
+	for _, want := range []string{"req_id=" + id, "method=GET", "path=/accounts", "status=200", "latency="} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("log line %q missing %q", line, want)
+		}
+	}
+
+	// 2️⃣ 用戶端帶合法的請求 ID：沿用；含空白或控制字元的則改為自行產生
+	for _, tc := range []struct {
+		sent string
+		keep bool
+	}{{"client-abc-123", true}, {"has space", false}} {
+		req, _ := http.NewRequest("GET", ts.URL+"/health", nil)
+		req.Header.Set(RequestIDHeader, tc.sent)
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if got := resp.Header.Get(RequestIDHeader); (got == tc.sent) != tc.keep || got == "" {
+			t.Fatalf("sent %q got %q keep=%v", tc.sent, got, tc.keep)
+		}
+	}
+}
+
+// TestLogSampling 以 1/10 取樣：100 筆成功請求約記錄 10 筆，20 筆 404 全部記錄。
+func TestLogSampling(t *testing.T) {
+	out := &syncBuffer{}
+	s := NewServer(bank.NewBank(), nil, WithLogger(log.New(out, "", 0)), WithLogSampling(10, 0))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	for i := 0; i < 100; i++ {
+		doJSON(t, cli, "GET", ts.URL+"/health", nil, 200, nil)
+	}
+	for i := 0; i < 20; i++ {
+		doJSON(t, cli, "GET", ts.URL+"/accounts/nope", nil, 404, nil)
+	}
+
+	var ok, errs int
+	for _, l := range out.lines() {
+		switch {
+		case strings.Contains(l, " status=200 "):
+			ok++
+		case strings.Contains(l, " status=404 "):
+			errs++
+		}
+	}
+	if errs != 20 {
+		t.Fatalf("error lines=%d want 20 (errors must never be sampled out)", errs)
+	}
+	if ok < 5 || ok > 15 {
+		t.Fatalf("success lines=%d want ~10", ok)
+	}
+}
+
+// TestLogSlowAlwaysLogged 慢請求門檻極低時，即使取樣比例很大，每筆仍會被記錄。
+func TestLogSlowAlwaysLogged(t *testing.T) {
+	out := &syncBuffer{}
+	s := NewServer(bank.NewBank(), nil, WithLogger(log.New(out, "", 0)), WithLogSampling(1000, time.Nanosecond))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	for i := 0; i < 5; i++ {
+		doJSON(t, ts.Client(), "GET", ts.URL+"/health", nil, 200, nil)
+	}
+	if n := len(out.lines()); n != 5 {
+		t.Fatalf("lines=%d want 5", n)
+	}
+}
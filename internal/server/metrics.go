@@ -0,0 +1,96 @@
+// internal/server/metrics.go
+//
+// 本檔以 Prometheus 格式提供營運指標：GET /metrics。
+//   - bank_operations_total{type, result}：存款 / 提款 / 轉帳的次數，result 為 success 或 failure；
+//   - bank_total_balance、bank_accounts：全行餘額總和與帳戶數（抓取時即時讀取 Bank）；
+//   - http_request_duration_seconds{route, code}：HTTP 請求耗時分布，route 為路由樣板（見 routes.go）。
+//
+// 指標登記在每個 Server 自己的 registry，不使用全域的 prometheus.DefaultRegisterer，
+// 測試中建立多個 Server 也不會重複註冊。
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"banking/internal/bank"
+)
+
+// metrics 為 Server 的 Prometheus 指標。
+type metrics struct {
+	reg     *prometheus.Registry
+	ops     *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+}
+
+// newMetrics 建立指標並登記到新的 registry；餘額與帳戶數於抓取時由 b 讀取。
+func newMetrics(b *bank.Bank) *metrics {
+	m := &metrics{
+		reg: prometheus.NewRegistry(),
+		ops: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "bank_operations_total",
+			Help: "Deposits, withdrawals and transfers processed, by type and result.",
+		}, []string{"type", "result"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route template and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "code"}),
+	}
+	m.reg.MustRegister(
+		m.ops,
+		m.latency,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "bank_total_balance",
+			Help: "Sum of all account balances in minor units.",
+		}, func() float64 { return float64(b.TotalBalance()) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "bank_accounts",
+			Help: "Number of accounts.",
+		}, func() float64 { return float64(b.AccountCount()) }),
+	)
+	return m
+}
+
+// observeOp 記錄一次存款 / 提款 / 轉帳的結果。
+func (m *metrics) observeOp(typ string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	m.ops.WithLabelValues(typ, result).Inc()
+}
+
+// observeRequest 記錄一次 HTTP 請求的耗時。
+func (m *metrics) observeRequest(route string, code int, d time.Duration) {
+	m.latency.WithLabelValues(route, strconv.Itoa(code)).Observe(d.Seconds())
+}
+
+// apply 呼叫 Bank.Apply 並記錄操作指標，成功時送出 webhook 事件（見 webhook.go）；
+// 存款、提款與轉帳 handler 皆經由此處；ctx 為請求 context，已逾時或取消時不套用（見 checkCtx）。
+func (s *Server) apply(ctx context.Context, op bank.Op) (bank.Tx, error) {
+	if err := checkCtx(ctx); err != nil {
+		return bank.Tx{}, err
+	}
+	tx, err := s.Bank.Apply(op)
+	return s.observe(op, tx, err)
+}
+
+// observe 記錄 op 的結果指標，成功時送出 webhook 事件；原樣回傳 tx 與 err。
+func (s *Server) observe(op bank.Op, tx bank.Tx, err error) (bank.Tx, error) {
+	s.metrics.observeOp(op.Type, err)
+	if err == nil {
+		s.notifyTx(tx)
+	}
+	return tx, err
+}
+
+// metricsHandler 處理 GET /metrics：輸出 Prometheus 文字格式。
+func (s *Server) metricsHandler() http.Handler {
+	return promhttp.HandlerFor(s.metrics.reg, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,58 @@
+// internal/server/metrics_test.go
+//
+// 測試 GET /metrics：執行幾筆存款、提款、轉帳（含一筆失敗）後，
+// Prometheus 文字輸出中的計數器、餘額 / 帳戶數量表與請求耗時直方圖皆反映這些操作。
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	b := bank.NewBank()
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	// 1️⃣ 幾筆操作：存款 ×1、提款 ×1、轉帳成功 ×1 與失敗（餘額不足）×1
+	var a1, a2 bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 1000}, 201, &a1)
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "B", "balance": 0}, 201, &a2)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/deposit", map[string]any{"amount": 200}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 100}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 300}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a2.ID, "To": a1.ID, "Amount": 999999}, 409, nil)
+
+	// 2️⃣ 抓取指標
+	resp, err := cli.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("metrics code=%d", resp.StatusCode)
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	out := string(raw)
+
+	for _, want := range []string{
+		`bank_operations_total{result="success",type="deposit"} 1`,
+		`bank_operations_total{result="success",type="withdraw"} 1`,
+		`bank_operations_total{result="success",type="transfer"} 1`,
+		`bank_operations_total{result="failure",type="transfer"} 1`,
+		"bank_total_balance 1100",
+		"bank_accounts 2",
+		`http_request_duration_seconds_count{code="200",route="POST /transfer"} 1`,
+		`http_request_duration_seconds_count{code="409",route="POST /transfer"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("metrics output missing %q:\n%s", want, out)
+		}
+	}
+}
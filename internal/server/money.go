@@ -0,0 +1,162 @@
+// internal/server/money.go
+//
+// 本檔為回應中的金額提供兩種表示方式（WithDecimalAmounts）：
+//   - 整數模式（預設，向下相容）：金額為最小貨幣單位的整數，例如 "balance": 1200。
+//   - 小數模式（scale > 0）：金額為依 scale 位小數格式化的字串，例如 scale 2 時 "balance": "12.00"；
+//     以字串表示可避免用戶端以浮點數解析造成誤差。
+//
+// bank 層與儲存一律維持 int64；轉換只發生在回應的 DTO（accountView、logView、feedEntryView），
+// 請求中的金額仍為最小單位的整數。整數模式下 DTO 輸出的 JSON 與 bank.Account / bank.Log 完全相同，
+// 因此兩者欄位（順序與標籤）須保持一致。
+package server
+
+import (
+	"strconv"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// WithDecimalAmounts 以 scale 位小數的字串輸出回應中的金額（例如 2 代表以「分」為最小單位）；
+// scale <= 0 代表維持整數輸出。
+func WithDecimalAmounts(scale int) Option {
+	return func(s *Server) { s.amountScale = scale }
+}
+
+// amount 為回應中的金額：Value 為最小貨幣單位，Scale > 0 時輸出為小數字串。
+type amount struct {
+	Value int64
+	Scale int
+}
+
+// IsZero 讓 omitzero 欄位在金額為 0 時省略（對應 bank 層的 omitempty）。
+func (m amount) IsZero() bool { return m.Value == 0 }
+
+// MarshalJSON 依 Scale 輸出整數或小數字串。
+func (m amount) MarshalJSON() ([]byte, error) {
+	if m.Scale <= 0 {
+		return strconv.AppendInt(nil, m.Value, 10), nil
+	}
+	return strconv.AppendQuote(nil, formatAmount(m.Value, m.Scale)), nil
+}
+
+// formatAmount 將最小單位的 v 格式化為 scale 位小數的字串，例如 (1205, 2) → "12.05"、(-5, 2) → "-0.05"。
+func formatAmount(v int64, scale int) string {
+	neg := v < 0
+	u := uint64(v)
+	if neg {
+		u = -u
+	}
+	digits := strconv.FormatUint(u, 10)
+	for len(digits) <= scale {
+		digits = "0" + digits
+	}
+	cut := len(digits) - scale
+	out := digits[:cut] + "." + digits[cut:]
+	if neg {
+		out = "-" + out
+	}
+	return out
+}
+
+// money 以伺服器設定的表示方式包裝金額。
+func (s *Server) money(v int64) amount {
+	return amount{Value: v, Scale: s.amountScale}
+}
+
+// accountView 為帳戶的回應 DTO，欄位與 bank.Account 一致，金額欄位改為 amount。
+type accountView struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Balance         amount `json:"balance"`
+	Currency        string `json:"currency"`
+	Status          string `json:"status"`
+	Held            amount `json:"held,omitzero"`
+	ExternalRef     string `json:"external_ref,omitempty"`
+	OverdraftLimit  amount `json:"overdraft_limit,omitzero"`
+	MinBalance      amount `json:"min_balance,omitzero"`
+	DailyLimit      amount `json:"daily_limit,omitzero"`
+	InterestRateBps int    `json:"interest_rate_bps,omitempty"`
+	Frozen          bool   `json:"frozen,omitempty"`
+	Version         int64  `json:"version"`
+}
+
+// viewAccount 將帳戶轉為回應 DTO。
+func (s *Server) viewAccount(a *bank.Account) accountView {
+	return accountView{
+		ID:              a.ID,
+		Name:            a.Name,
+		Balance:         s.money(a.Balance),
+		Currency:        a.Currency,
+		Status:          a.Status,
+		Held:            s.money(a.Held),
+		ExternalRef:     a.ExternalRef,
+		OverdraftLimit:  s.money(a.OverdraftLimit),
+		MinBalance:      s.money(a.MinBalance),
+		DailyLimit:      s.money(a.DailyLimit),
+		InterestRateBps: a.InterestRateBps,
+		Frozen:          a.Frozen,
+		Version:         a.Version,
+	}
+}
+
+// viewCurrent 回傳帳戶 id 目前狀態的回應 DTO；帳戶已不存在（例如異動提交後遭並行的重置或匯入移除）時回傳 nil（JSON 為 null）。
+// 供異動已提交後回報最新狀態使用：此時查詢失敗不得中斷回應，否則已成功的異動會被回報為失敗且略過保存。
+func (s *Server) viewCurrent(id string) *accountView {
+	a, err := s.Bank.Get(id)
+	if err != nil {
+		return nil
+	}
+	v := s.viewAccount(a)
+	return &v
+}
+
+// viewAccounts 將帳戶列表轉為回應 DTO，保留順序。
+func (s *Server) viewAccounts(list []*bank.Account) []accountView {
+	out := make([]accountView, len(list))
+	for i, a := range list {
+		out[i] = s.viewAccount(a)
+	}
+	return out
+}
+
+// logView 為交易日誌的回應 DTO，欄位與 bank.Log 一致，Amount 改為 amount。
+type logView struct {
+	Seq       int64     `json:"seq"`
+	Time      time.Time `json:"time"`
+	TxID      string    `json:"tx_id,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Amount    amount    `json:"amount"`
+	Direction string    `json:"direction"`
+	CounterID string    `json:"counter_account,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Ref       string    `json:"ref_tx_id,omitempty"`
+}
+
+// viewLogs 將日誌轉為回應 DTO，保留順序。
+func (s *Server) viewLogs(logs []bank.Log) []logView {
+	out := make([]logView, len(logs))
+	for i, l := range logs {
+		out[i] = logView{
+			Seq: l.Seq, Time: l.Time, TxID: l.TxID, Type: l.Type, Amount: s.money(l.Amount),
+			Direction: l.Direction, CounterID: l.CounterID, Note: l.Note, Ref: l.Ref,
+		}
+	}
+	return out
+}
+
+// feedEntryView 為全行交易流（GET /transactions）的回應 DTO，對應 bank.FeedEntry。
+type feedEntryView struct {
+	AccountID   string `json:"account_id"`
+	AccountName string `json:"account_name"`
+	logView
+}
+
+// viewFeed 將全行交易流轉為回應 DTO，保留順序。
+func (s *Server) viewFeed(feed []bank.FeedEntry) []feedEntryView {
+	out := make([]feedEntryView, len(feed))
+	for i, e := range feed {
+		out[i] = feedEntryView{AccountID: e.AccountID, AccountName: e.AccountName, logView: s.viewLogs([]bank.Log{e.Log})[0]}
+	}
+	return out
+}
@@ -0,0 +1,107 @@
+// internal/server/money_test.go
+//
+// 測試回應金額的兩種表示方式：整數模式與 bank 型別的 JSON 完全相同；小數模式輸出小數字串。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestFormatAmount 涵蓋補零、負數與 scale 0。
+func TestFormatAmount(t *testing.T) {
+	for _, tc := range []struct {
+		v     int64
+		scale int
+		want  string
+	}{
+		{1200, 2, "12.00"},
+		{1205, 2, "12.05"},
+		{5, 2, "0.05"},
+		{0, 2, "0.00"},
+		{-5, 2, "-0.05"},
+		{-1234, 2, "-12.34"},
+		{7, 3, "0.007"},
+	} {
+		if got := formatAmount(tc.v, tc.scale); got != tc.want {
+			t.Fatalf("formatAmount(%d, %d)=%q want %q", tc.v, tc.scale, got, tc.want)
+		}
+	}
+}
+
+// TestAmountRendering 以兩種模式查詢帳戶與日誌：
+//   - 整數模式：帳戶 DTO 與 bank.Account 的 JSON 逐位元組相同（向下相容），日誌金額為數字；
+//   - 小數模式：balance 與日誌 amount 為小數字串。
+func TestAmountRendering(t *testing.T) {
+	get := func(ts *httptest.Server, path string) []byte {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var buf bytes.Buffer
+		_, _ = buf.ReadFrom(resp.Body)
+		return bytes.TrimSpace(buf.Bytes())
+	}
+
+	b := bank.NewBank()
+	a, _ := b.Create("A", 1200)
+	_ = b.SetOverdraft(a.ID, 500)
+	_, _ = b.Apply(bank.Op{Type: bank.TxDeposit, Account: a.ID, Amount: 5})
+
+	// 1️⃣ 整數模式（預設）
+	raw := httptest.NewServer(NewServer(b, nil).Router())
+	defer raw.Close()
+	cur, _ := b.Get(a.ID)
+	want, _ := json.Marshal(cur)
+	if got := get(raw, "/accounts/"+a.ID); !bytes.Equal(got, want) {
+		t.Fatalf("raw account:\n got %s\nwant %s", got, want)
+	}
+	var rawLogs struct {
+		Logs []map[string]any `json:"logs"`
+	}
+	_ = json.Unmarshal(get(raw, "/accounts/"+a.ID+"/logs"), &rawLogs)
+	if len(rawLogs.Logs) != 1 || rawLogs.Logs[0]["amount"] != float64(5) {
+		t.Fatalf("raw logs=%v", rawLogs.Logs)
+	}
+
+	// 2️⃣ 小數模式（scale 2）
+	dec := httptest.NewServer(NewServer(b, nil, WithDecimalAmounts(2)).Router())
+	defer dec.Close()
+	var acct map[string]any
+	_ = json.Unmarshal(get(dec, "/accounts/"+a.ID), &acct)
+	if acct["balance"] != "12.05" || acct["overdraft_limit"] != "5.00" {
+		t.Fatalf("decimal account=%v", acct)
+	}
+	var decLogs struct {
+		Logs []map[string]any `json:"logs"`
+	}
+	_ = json.Unmarshal(get(dec, "/accounts/"+a.ID+"/logs"), &decLogs)
+	if len(decLogs.Logs) != 1 || decLogs.Logs[0]["amount"] != "0.05" {
+		t.Fatalf("decimal logs=%v", decLogs.Logs)
+	}
+}
+
+// TestViewCurrent 驗證異動提交後查詢帳戶狀態：帳戶存在時回傳目前狀態，已被移除時回傳 nil（JSON null）而非 panic。
+func TestViewCurrent(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	s := NewServer(b, nil)
+	if v := s.viewCurrent(a.ID); v == nil || v.ID != a.ID || v.Balance.Value != 100 {
+		t.Fatalf("viewCurrent=%+v", v)
+	}
+	b.Reset()
+	v := s.viewCurrent(a.ID)
+	if v != nil {
+		t.Fatalf("viewCurrent after reset=%+v want nil", v)
+	}
+	out, _ := json.Marshal(map[string]any{"from": v})
+	if string(out) != `{"from":null}` {
+		t.Fatalf("json=%s", out)
+	}
+}
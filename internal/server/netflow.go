@@ -0,0 +1,49 @@
+// internal/server/netflow.go
+//
+// 本檔提供 GET /accounts/{id}/netflow?from=&to=：帳戶在期間內的流入、流出與淨變動。
+// from / to 接受 RFC 3339（2025-03-01T00:00:00Z）或日期（2025-03-01，UTC 零時）；
+// 省略時該端不設限。期間為 [from, to)。
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// netflow 處理 GET /accounts/{id}/netflow。
+func (s *Server) netflow(w http.ResponseWriter, r *http.Request, id string) {
+	q := r.URL.Query()
+	from, err := parseTimeParam(q.Get("from"))
+	if err != nil {
+		writeErr(w, fmt.Errorf("from: %w", err), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(q.Get("to"))
+	if err != nil {
+		writeErr(w, fmt.Errorf("to: %w", err), http.StatusBadRequest)
+		return
+	}
+	in, out, net, err := s.Bank.NetFlow(id, from, to)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"account": id,
+		"inflow":  in,
+		"outflow": out,
+		"net":     net,
+	})
+}
+
+// parseTimeParam 解析時間查詢參數；空字串回傳零值（不設限）。
+func parseTimeParam(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
@@ -0,0 +1,15 @@
+// internal/server/options.go
+//
+// 本檔定義 Server 的可選設定（functional options）。
+// NewServer 的必要依賴（Bank、persist）維持為位置參數，其餘功能開關一律以 Option 注入，
+// 讓新增設定時不必修改既有呼叫端（main.go 與測試）。
+package server
+
+// Option 為 NewServer 的可選設定。
+type Option func(*Server)
+
+// WithReceiptKey 啟用交易收據，並以 key 作為 HMAC-SHA256 簽章金鑰。
+// 未設定時不產生收據，相關端點回傳 501。
+func WithReceiptKey(key []byte) Option {
+	return func(s *Server) { s.receiptKey = key }
+}
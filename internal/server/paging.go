@@ -0,0 +1,129 @@
+// internal/server/paging.go
+//
+// 本檔實作列表端點的分頁（offset / limit）與分頁連結。
+// 分頁為選用：請求未帶 offset 或 limit 時，端點維持原本的完整回應格式，
+// 帶任一參數時改回傳 {"total": N, "<items>": [...], "links": {...}}。
+// 例外：帳戶日誌（GET /accounts/{id}/logs）一律分頁，預設 offset=0、limit=defaultPageLimit。
+// links 包含：
+//   - self：目前頁面
+//   - next：下一頁（已是最後一頁時省略）
+//   - prev：上一頁（第一頁時省略）
+//
+// 帳戶列表另可帶 sort=id|name|balance（見 bank.ListPage），帶 sort 時同樣改為分頁回應。
+// 日誌另可帶篩選參數（見 logsFilter），篩選後再分頁，total 為篩選後的筆數。
+//
+// 連結以請求原始路徑產生（含 /api/v1 前綴），並保留其他查詢參數，客戶端可直接跟隨。
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"banking/internal/bank"
+)
+
+// 分頁預設值與上限。
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+)
+
+// page 為解析後的分頁參數。
+type page struct {
+	offset, limit int
+}
+
+// parsePage 解析 offset / limit 查詢參數。
+// ok 為 false 代表請求未要求分頁；參數不合法時回傳錯誤（對應 400）。
+func parsePage(r *http.Request) (p page, ok bool, err error) {
+	q := r.URL.Query()
+	if !q.Has("offset") && !q.Has("limit") {
+		return page{}, false, nil
+	}
+	p = page{limit: defaultPageLimit}
+	if v := q.Get("offset"); v != "" {
+		if p.offset, err = strconv.Atoi(v); err != nil || p.offset < 0 {
+			return page{}, false, errors.New("offset must be a non-negative integer")
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if p.limit, err = strconv.Atoi(v); err != nil || p.limit <= 0 {
+			return page{}, false, errors.New("limit must be a positive integer")
+		}
+	}
+	p.limit = min(p.limit, maxPageLimit)
+	return p, true, nil
+}
+
+// paginate 回傳 items 中屬於 p 的區段；超出範圍時回傳空切片（非 nil，JSON 為 []）。
+func paginate[T any](items []T, p page) []T {
+	if p.offset >= len(items) {
+		return []T{}
+	}
+	return items[p.offset:min(p.offset+p.limit, len(items))]
+}
+
+// pageLinks 依目前請求與總筆數產生 self / next / prev 連結。
+func pageLinks(r *http.Request, p page, total int) map[string]string {
+	// StripPrefix 會改寫 r.URL.Path，因此優先使用原始 RequestURI 保留 /api/v1 前綴。
+	base := *r.URL
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil {
+		base = *u
+	}
+	link := func(offset int) string {
+		q := base.Query()
+		q.Set("offset", strconv.Itoa(offset))
+		q.Set("limit", strconv.Itoa(p.limit))
+		u := base
+		u.RawQuery = q.Encode()
+		return u.RequestURI()
+	}
+
+	links := map[string]string{"self": link(p.offset)}
+	if p.offset+p.limit < total {
+		links["next"] = link(p.offset + p.limit)
+	}
+	if p.offset > 0 {
+		links["prev"] = link(max(p.offset-p.limit, 0))
+	}
+	return links
+}
+
+// mountPrefix 回傳請求的掛載前綴（經 /api/v1 進入時為 "/api/v1"，根路徑為 ""），
+// 由原始 RequestURI 與 StripPrefix 後的 r.URL.Path 比對而得。
+func mountPrefix(r *http.Request) string {
+	u, err := url.ParseRequestURI(r.RequestURI)
+	if err != nil || !strings.HasSuffix(u.Path, r.URL.Path) {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, r.URL.Path)
+}
+
+// accountLink 回傳帳戶資源的路徑（含請求的掛載前綴），例如 /api/v1/accounts/3。
+func accountLink(r *http.Request, id string) string {
+	return mountPrefix(r) + "/accounts/" + url.PathEscape(id)
+}
+
+// logsFilter 解析日誌篩選參數 direction（in / out）、since、until（RFC 3339 或日期，期間為 [since, until)）。
+// ok 為 false 代表未帶任何篩選參數；參數不合法時回傳錯誤（對應 400）。
+func logsFilter(r *http.Request) (f bank.LogFilter, ok bool, err error) {
+	q := r.URL.Query()
+	if !q.Has("direction") && !q.Has("since") && !q.Has("until") {
+		return bank.LogFilter{}, false, nil
+	}
+	f.Direction = q.Get("direction")
+	if f.Direction != "" && f.Direction != "in" && f.Direction != "out" {
+		return bank.LogFilter{}, false, errors.New("direction must be in or out")
+	}
+	if f.Since, err = parseTimeParam(q.Get("since")); err != nil {
+		return bank.LogFilter{}, false, fmt.Errorf("since: %w", err)
+	}
+	if f.Until, err = parseTimeParam(q.Get("until")); err != nil {
+		return bank.LogFilter{}, false, fmt.Errorf("until: %w", err)
+	}
+	return f, true, nil
+}
@@ -0,0 +1,212 @@
+// internal/server/paging_test.go
+//
+// 測試列表分頁與 links：next 正確推進 offset、最後一頁省略 next、保留 /api/v1 前綴。
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+func TestAccountsPaginationLinks(t *testing.T) {
+	b := bank.NewBank()
+	for i := 0; i < 5; i++ {
+		_, _ = b.Create("u", 0)
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	type pageResp struct {
+		Total    int               `json:"total"`
+		Accounts []bank.Account    `json:"accounts"`
+		Links    map[string]string `json:"links"`
+	}
+
+	// 1️⃣ 第一頁：有 next、無 prev
+	var p1 pageResp
+	doJSON(t, cli, "GET", ts.URL+"/api/v1/accounts?limit=2", nil, 200, &p1)
+	if p1.Total != 5 || len(p1.Accounts) != 2 || p1.Accounts[0].ID != "1" {
+		t.Fatalf("page1=%+v", p1)
+	}
+	if p1.Links["next"] != "/api/v1/accounts?limit=2&offset=2" {
+		t.Fatalf("next=%q", p1.Links["next"])
+	}
+	if _, ok := p1.Links["prev"]; ok {
+		t.Fatalf("first page must not have prev: %v", p1.Links)
+	}
+
+	// 2️⃣ 跟隨 next 兩次到最後一頁：offset 正確推進，最後一頁省略 next
+	var p2, p3 pageResp
+	doJSON(t, cli, "GET", ts.URL+p1.Links["next"], nil, 200, &p2)
+	if len(p2.Accounts) != 2 || p2.Accounts[0].ID != "3" {
+		t.Fatalf("page2=%+v", p2)
+	}
+	doJSON(t, cli, "GET", ts.URL+p2.Links["next"], nil, 200, &p3)
+	if len(p3.Accounts) != 1 || p3.Accounts[0].ID != "5" {
+		t.Fatalf("page3=%+v", p3)
+	}
+	if _, ok := p3.Links["next"]; ok {
+		t.Fatalf("last page must not have next: %v", p3.Links)
+	}
+	if p3.Links["prev"] != "/api/v1/accounts?limit=2&offset=2" {
+		t.Fatalf("prev=%q", p3.Links["prev"])
+	}
+
+	// ❌ 不合法參數
+	doJSON(t, cli, "GET", ts.URL+"/accounts?limit=0", nil, 400, nil)
+	doJSON(t, cli, "GET", ts.URL+"/accounts?offset=-1", nil, 400, nil)
+
+	// ✅ 未帶分頁參數時維持原本的陣列格式
+	var all []bank.Account
+	doJSON(t, cli, "GET", ts.URL+"/accounts", nil, 200, &all)
+	if len(all) != 5 {
+		t.Fatalf("len=%d want 5", len(all))
+	}
+}
+
+// TestAccountsSort 驗證 GET /accounts?sort=：依餘額排序後分頁、links 保留 sort，不合法的排序鍵回傳 400。
+func TestAccountsSort(t *testing.T) {
+	b := bank.NewBank()
+	for _, bal := range []int64{30, 10, 20} {
+		_, _ = b.Create("u", bal)
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Total    int               `json:"total"`
+		Accounts []bank.Account    `json:"accounts"`
+		Links    map[string]string `json:"links"`
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts?sort=balance&limit=2", nil, 200, &resp)
+	if resp.Total != 3 || len(resp.Accounts) != 2 || resp.Accounts[0].Balance != 10 || resp.Accounts[1].Balance != 20 {
+		t.Fatalf("sorted page=%+v", resp)
+	}
+	if resp.Links["next"] != "/accounts?limit=2&offset=2&sort=balance" {
+		t.Fatalf("next=%q", resp.Links["next"])
+	}
+
+	// ✅ 只帶 sort 也回傳分頁格式
+	doJSON(t, cli, "GET", ts.URL+"/accounts?sort=name", nil, 200, &resp)
+	if resp.Total != 3 || len(resp.Accounts) != 3 {
+		t.Fatalf("sort only=%+v", resp)
+	}
+
+	// ❌ 不支援的排序鍵
+	var e errorBody
+	doJSON(t, cli, "GET", ts.URL+"/accounts?sort=created", nil, 400, &e)
+	if e.Code != "bad_sort" {
+		t.Fatalf("code=%q", e.Code)
+	}
+}
+
+// TestLogsDefaultPage 驗證日誌未帶參數時預設回傳第一頁（limit 50），
+// 並涵蓋中間頁、超出範圍的 offset 與不合法參數。
+func TestLogsDefaultPage(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	for i := 1; i <= 120; i++ {
+		_, _ = b.Deposit(a.ID, bank.NewMoney(int64(i), "USD"), "")
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	type logsPage struct {
+		Total int        `json:"total"`
+		Logs  []bank.Log `json:"logs"`
+	}
+
+	// 1️⃣ 第一頁（預設）
+	var first logsPage
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs", nil, 200, &first)
+	if first.Total != 120 || len(first.Logs) != 50 || first.Logs[0].Amount != 1 {
+		t.Fatalf("first page total=%d len=%d", first.Total, len(first.Logs))
+	}
+
+	// 2️⃣ 中間頁
+	var mid logsPage
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?offset=50&limit=10", nil, 200, &mid)
+	if mid.Total != 120 || len(mid.Logs) != 10 || mid.Logs[0].Amount != 51 || mid.Logs[9].Amount != 60 {
+		t.Fatalf("middle page=%+v", mid)
+	}
+
+	// 3️⃣ 超出範圍 → 空陣列（非 null）
+	raw := map[string]any{}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?offset=500", nil, 200, &raw)
+	if logs, ok := raw["logs"].([]any); !ok || len(logs) != 0 || raw["total"] != float64(120) {
+		t.Fatalf("out of range=%v", raw)
+	}
+
+	// ❌ 負數或非數字參數
+	for _, q := range []string{"offset=-1", "limit=-5", "limit=abc", "offset=x"} {
+		doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?"+q, nil, 400, nil)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/999/logs", nil, 404, nil)
+}
+
+// TestLogsPagination 驗證日誌分頁與最後一頁的 links。
+func TestLogsPagination(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	for i := 0; i < 3; i++ {
+		_, _ = b.Deposit(a.ID, bank.NewMoney(1, "USD"), "")
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Total int               `json:"total"`
+		Logs  []bank.Log        `json:"logs"`
+		Links map[string]string `json:"links"`
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts/"+a.ID+"/logs?offset=2&limit=2", nil, 200, &resp)
+	if resp.Total != 3 || len(resp.Logs) != 1 {
+		t.Fatalf("resp=%+v", resp)
+	}
+	if _, ok := resp.Links["next"]; ok {
+		t.Fatalf("last page must not have next: %v", resp.Links)
+	}
+	if resp.Links["prev"] != "/accounts/"+a.ID+"/logs?limit=2&offset=0" {
+		t.Fatalf("prev=%q", resp.Links["prev"])
+	}
+}
+
+// TestLogsFilter 驗證日誌篩選參數：方向與期間條件，以及不合法參數回傳 400。
+func TestLogsFilter(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := bank.NewBank()
+	b.SetClock(func() time.Time { return now })
+	a, _ := b.Create("A", 100)
+	c, _ := b.Create("C", 0)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(10, "USD"), "") // 3/1 in
+	now = now.AddDate(0, 0, 1)
+	_ = b.Transfer(a.ID, c.ID, bank.NewMoney(7, "USD")) // 3/2 out
+	now = now.AddDate(0, 0, 1)
+	_, _ = b.Withdraw(a.ID, bank.NewMoney(5, "USD"), "") // 3/3 out
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Total int        `json:"total"`
+		Logs  []bank.Log `json:"logs"`
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?direction=out&since=2025-03-02T00:00:00Z&until=2025-03-03T00:00:00Z", nil, 200, &resp)
+	if resp.Total != 1 || len(resp.Logs) != 1 || resp.Logs[0].Amount != 7 {
+		t.Fatalf("filtered=%+v", resp)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?direction=out&limit=1&offset=1", nil, 200, &resp)
+	if resp.Total != 2 || len(resp.Logs) != 1 || resp.Logs[0].Amount != 5 {
+		t.Fatalf("filtered page=%+v", resp)
+	}
+
+	for _, q := range []string{"direction=up", "since=yesterday", "until=2025-13-01"} {
+		doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/logs?"+q, nil, 400, nil)
+	}
+}
@@ -0,0 +1,154 @@
+// internal/server/ratelimit.go
+//
+// 本檔實作以用戶端 IP 為單位的請求速率限制（token bucket），避免失控的迴圈狂打 /transfer 等端點：
+//   - 每個 IP 一個桶，容量為 burst，以每秒 rate 個的速度補充；每個請求消耗一個 token；
+//   - 桶空時回傳 429，附 Retry-After（秒）與 JSON 錯誤（code: rate_limited）；
+//   - 閒置超過 idle 的桶（早已補滿，與新建無異）會在後續請求時順帶清除，狀態不會無限成長。
+//
+// 用戶端 IP 預設取自 RemoteAddr；部署在反向代理之後時可信任 X-Forwarded-For 的第一個位址。
+// X-Forwarded-For 可由用戶端任意偽造，只有代理會覆寫此標頭時才應啟用。
+//
+// GET /health（含 /health/live、/health/ready）不受限制，避免監控探針被誤擋。
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errRateLimited 代表請求超過速率限制。
+var errRateLimited = errors.New("rate limit exceeded")
+
+// minRateIdle 為清除閒置桶的最短閒置時間。
+const minRateIdle = time.Minute
+
+// WithRateLimit 啟用每個用戶端 IP 的速率限制：平均每秒 rps 個請求、最多連續 burst 個。
+// trustForwarded 為 true 時以 X-Forwarded-For 的第一個位址識別用戶端。
+// rps <= 0 代表不限制（預設）。
+func WithRateLimit(rps float64, burst int, trustForwarded bool) Option {
+	return func(s *Server) {
+		if rps <= 0 {
+			s.limiter = nil
+			return
+		}
+		s.limiter = newRateLimiter(rps, burst, time.Now)
+		s.limiter.trustForwarded = trustForwarded
+	}
+}
+
+// tokenBucket 為單一用戶端的桶。
+type tokenBucket struct {
+	tokens float64
+	last   time.Time // 上次補充 token 的時間
+}
+
+// rateLimiter 保存所有用戶端的桶；以自己的 mutex 保護，與 Bank 的鎖無關。
+type rateLimiter struct {
+	rate           float64
+	burst          float64
+	idle           time.Duration // 閒置超過此時間的桶會被清除
+	trustForwarded bool
+	now            func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+// newRateLimiter 建立速率限制器；burst < 1 時視為 1。
+func newRateLimiter(rps float64, burst int, now func() time.Time) *rateLimiter {
+	burst = max(burst, 1)
+	// 閒置時間至少要讓桶完全補滿，清除後重建才不會多給 token
+	idle := max(minRateIdle, time.Duration(float64(burst)/rps*float64(time.Second)))
+	return &rateLimiter{
+		rate: rps, burst: float64(burst), idle: idle, now: now,
+		buckets: make(map[string]*tokenBucket), lastPrune: now(),
+	}
+}
+
+// allow 為 key 消耗一個 token；不足時回傳 false 與建議的重試等待時間。
+func (l *rateLimiter) allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed.Seconds()*l.rate)
+		b.last = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// prune 每隔 idle 清除一次閒置超過 idle 的桶；須在 mu 保護下呼叫。
+func (l *rateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < l.idle {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= l.idle {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// size 回傳目前保存的桶數量（供測試使用）。
+func (l *rateLimiter) size() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// clientIP 取得請求的用戶端 IP。
+func (l *rateLimiter) clientIP(r *http.Request) string {
+	if l.trustForwarded {
+		if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+			first, _, _ := strings.Cut(xff, ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// rateLimitMiddleware 對超過速率限制的請求回傳 429。
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	l := s.limiter
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isHealthPath(apiPath(r.URL.Path)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if ok, wait := l.allow(l.clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeErr(w, errRateLimited, http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,127 @@
+// internal/server/ratelimit_test.go
+//
+// 測試速率限制：
+//   - 短時間內超過 burst 的請求收到 429、Retry-After 與 JSON 錯誤，/health 不受限制；
+//   - 不同 IP 各自計算，X-Forwarded-For 只在信任代理時生效；
+//   - token 依時間補充，閒置的桶會被清除。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+func TestRateLimitRejectsBursts(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 1_000_000)
+	a2, _ := b.Create("B", 0)
+	s := NewServer(b, nil, WithRateLimit(1, 5, false))
+	ts := httptest.NewServer(s.Router())
+	defer ts.Close()
+
+	// 1️⃣ 並行送出遠超過 burst 的轉帳
+	var (
+		mu       sync.Mutex
+		ok, over int
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := []byte(`{"From":"` + a1.ID + `","To":"` + a2.ID + `","Amount":1}`)
+			resp, err := ts.Client().Post(ts.URL+"/transfer", "application/json", bytes.NewReader(buf))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			mu.Lock()
+			defer mu.Unlock()
+			switch resp.StatusCode {
+			case http.StatusOK:
+				ok++
+			case http.StatusTooManyRequests:
+				over++
+				if sec, err := strconv.Atoi(resp.Header.Get("Retry-After")); err != nil || sec < 1 {
+					t.Errorf("Retry-After=%q", resp.Header.Get("Retry-After"))
+				}
+				var e errorBody
+				if err := json.NewDecoder(resp.Body).Decode(&e); err != nil || e.Code != "rate_limited" {
+					t.Errorf("429 body=%+v err=%v", e, err)
+				}
+			default:
+				t.Errorf("unexpected code=%d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+	if over == 0 || ok < 5 || ok > 6 {
+		t.Fatalf("ok=%d over=%d, want ~5 ok and the rest 429", ok, over)
+	}
+
+	// 2️⃣ /health 不受限制
+	resp, err := ts.Client().Get(ts.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("health code=%d want 200", resp.StatusCode)
+	}
+}
+
+func TestRateLimiterBuckets(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2, 2, func() time.Time { return now })
+
+	// 1️⃣ burst 用完後拒絕，並回報需等待的時間
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.allow("1.1.1.1"); !ok {
+			t.Fatalf("request %d rejected within burst", i)
+		}
+	}
+	if ok, wait := l.allow("1.1.1.1"); ok || wait != 500*time.Millisecond {
+		t.Fatalf("over burst: ok=%v wait=%s, want rejected with 500ms", ok, wait)
+	}
+	// 其他 IP 各自計算
+	if ok, _ := l.allow("2.2.2.2"); !ok {
+		t.Fatal("other client rejected")
+	}
+
+	// 2️⃣ 時間經過後補充 token
+	now = now.Add(500 * time.Millisecond)
+	if ok, _ := l.allow("1.1.1.1"); !ok {
+		t.Fatal("token not refilled")
+	}
+
+	// 3️⃣ 閒置超過 idle 的桶會被清除
+	now = now.Add(l.idle)
+	_, _ = l.allow("3.3.3.3")
+	if n := l.size(); n != 1 {
+		t.Fatalf("buckets after prune=%d want 1", n)
+	}
+}
+
+func TestRateLimitClientIP(t *testing.T) {
+	r := httptest.NewRequest("GET", "/accounts", nil)
+	r.RemoteAddr = "10.0.0.1:5555"
+	r.Header.Set("X-Forwarded-For", "203.0.113.7, 10.0.0.1")
+
+	if ip := newRateLimiter(1, 1, time.Now).clientIP(r); ip != "10.0.0.1" {
+		t.Fatalf("untrusted ip=%q want RemoteAddr host", ip)
+	}
+	l := newRateLimiter(1, 1, time.Now)
+	l.trustForwarded = true
+	if ip := l.clientIP(r); ip != "203.0.113.7" {
+		t.Fatalf("trusted ip=%q want first X-Forwarded-For", ip)
+	}
+}
@@ -0,0 +1,118 @@
+// internal/server/receipt.go
+//
+// 本檔實作交易收據（receipt）：以伺服器金鑰對交易摘要做 HMAC-SHA256 簽章，
+// 讓客戶端日後可證明「伺服器確實提交過這筆交易」（不可否認性）。
+//
+// 簽章內容為 bank.Tx 的 JSON 編碼（時間統一轉為 UTC），
+// 因此收據可隨時由帳本資料重新產生，不需額外儲存。
+//
+// 端點：
+//   - GET  /transactions/{txID}/receipt → 重新取得某筆交易的收據
+//   - POST /receipts/verify             → 驗證收據簽章，回傳 {"valid": bool}
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"banking/internal/bank"
+)
+
+// errReceiptsDisabled 代表伺服器未設定收據金鑰。
+var errReceiptsDisabled = errors.New("receipts are not enabled")
+
+// Receipt 為已簽章的交易摘要。
+type Receipt struct {
+	bank.Tx
+	Signature string `json:"signature"`
+}
+
+// accountWithReceipt 為存款 / 提款的回應：帳戶欄位攤平於最外層，並附上收據（若啟用）。
+type accountWithReceipt struct {
+	accountView
+	Receipt *Receipt `json:"receipt,omitempty"`
+}
+
+// signTx 計算交易摘要的 HMAC-SHA256 簽章（十六進位字串）。
+func signTx(key []byte, tx bank.Tx) string {
+	tx.Time = tx.Time.UTC()
+	payload, _ := json.Marshal(tx)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyReceipt 以 key 驗證收據簽章；使用常數時間比較避免時序攻擊。
+func verifyReceipt(key []byte, r Receipt) bool {
+	want, err := hex.DecodeString(signTx(key, r.Tx))
+	if err != nil {
+		return false
+	}
+	got, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(want, got)
+}
+
+// receipt 為交易產生收據；未啟用收據時回傳 nil。
+func (s *Server) receipt(tx bank.Tx) *Receipt {
+	if len(s.receiptKey) == 0 {
+		return nil
+	}
+	return &Receipt{Tx: tx, Signature: signTx(s.receiptKey, tx)}
+}
+
+// transactionSubroutes 處理子路徑：
+//
+//	GET  /transactions/{txID}/receipt → 取得交易收據
+//	POST /transactions/{txID}/reverse → 沖正轉帳（見 reverseTx）
+func (s *Server) transactionSubroutes(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/transactions/")
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || (parts[1] != "receipt" && parts[1] != "reverse") {
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+		return
+	}
+	if parts[1] == "reverse" {
+		s.reverseTx(w, r, parts[0])
+		return
+	}
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	if len(s.receiptKey) == 0 {
+		writeErr(w, errReceiptsDisabled, http.StatusNotImplemented)
+		return
+	}
+	tx, err := s.Bank.FindTx(parts[0])
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.receipt(tx))
+}
+
+// verifyReceiptHandler 處理 POST /receipts/verify：
+// 請求本體為收據 JSON，回傳 {"valid": true|false}。
+func (s *Server) verifyReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	if len(s.receiptKey) == 0 {
+		writeErr(w, errReceiptsDisabled, http.StatusNotImplemented)
+		return
+	}
+	var rc Receipt
+	if !decodeBody(w, r, &rc) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"valid": verifyReceipt(s.receiptKey, rc)})
+}
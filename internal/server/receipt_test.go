@@ -0,0 +1,91 @@
+// internal/server/receipt_test.go
+//
+// 測試交易收據：操作回應附帶收據、可依 TxID 重新取得，
+// 且簽章以正確金鑰驗證成功、竄改內容或換用金鑰則驗證失敗。
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestReceiptsSignAndVerify 驗證收據簽章的完整流程。
+func TestReceiptsSignAndVerify(t *testing.T) {
+	key := []byte("test-secret")
+	b := bank.NewBank()
+	ts := httptest.NewServer(NewServer(b, nil, WithReceiptKey(key)).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var a1, a2 bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 1000}, 201, &a1)
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "B", "balance": 0}, 201, &a2)
+
+	// 1️⃣ 存款回應附帶收據，且帳戶欄位維持在最外層
+	var dep struct {
+		bank.Account
+		Receipt Receipt `json:"receipt"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/deposit", map[string]any{"amount": 100}, 200, &dep)
+	if dep.Balance != 1100 || dep.Receipt.ID == "" || dep.Receipt.Signature == "" {
+		t.Fatalf("deposit response=%+v", dep)
+	}
+	if dep.Receipt.Type != bank.TxDeposit || dep.Receipt.Account != a1.ID || dep.Receipt.Amount != 100 {
+		t.Fatalf("receipt content=%+v", dep.Receipt)
+	}
+
+	// 2️⃣ 轉帳回應附帶收據
+	var tr struct {
+		TxID    string  `json:"tx_id"`
+		Receipt Receipt `json:"receipt"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 300}, 200, &tr)
+	if tr.Receipt.ID != tr.TxID || tr.Receipt.From != a1.ID || tr.Receipt.To != a2.ID {
+		t.Fatalf("transfer receipt=%+v", tr)
+	}
+
+	// 3️⃣ 依 TxID 重新取得的收據與原收據一致
+	var again Receipt
+	doJSON(t, cli, "GET", ts.URL+"/transactions/"+tr.TxID+"/receipt", nil, 200, &again)
+	if again.Signature != tr.Receipt.Signature {
+		t.Fatalf("re-fetched signature %s != %s", again.Signature, tr.Receipt.Signature)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/transactions/tx-999/receipt", nil, 404, nil)
+
+	// 4️⃣ 驗證端點：原收據有效、竄改金額後無效
+	var v struct {
+		Valid bool `json:"valid"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/receipts/verify", tr.Receipt, 200, &v)
+	if !v.Valid {
+		t.Fatal("genuine receipt should verify")
+	}
+	tampered := tr.Receipt
+	tampered.Amount = 3000
+	doJSON(t, cli, "POST", ts.URL+"/receipts/verify", tampered, 200, &v)
+	if v.Valid {
+		t.Fatal("tampered receipt should not verify")
+	}
+
+	// 5️⃣ 換用其他金鑰驗證失敗
+	if verifyReceipt([]byte("other-key"), tr.Receipt) {
+		t.Fatal("receipt should not verify with a different key")
+	}
+}
+
+// TestReceiptsDisabled 驗證未設定金鑰時不產生收據，收據端點回傳 501。
+func TestReceiptsDisabled(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var dep map[string]any
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 200, &dep)
+	if _, ok := dep["receipt"]; ok {
+		t.Fatalf("unexpected receipt: %v", dep)
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/transactions/tx-1/receipt", nil, 501, nil)
+}
@@ -0,0 +1,50 @@
+// internal/server/recover.go
+//
+// 本檔實作 panic 復原中介層：handler 發生 panic 時記錄堆疊並回傳 500 JSON，
+// 不讓單一請求的錯誤影響其他請求，也不把內部細節（panic 內容、堆疊）洩漏給用戶端。
+//   - 堆疊寫入請求日誌（WithLogger，見 logging.go）；未設定時寫入標準 log。
+//   - http.ErrAbortHandler 為 net/http 中止回應的正常手段，原樣再次 panic，交由 net/http 處理。
+//   - 回應已開始送出（標頭已寫出）時無法改寫狀態碼，改以 http.ErrAbortHandler 中斷連線，
+//     讓用戶端察覺回應不完整。
+package server
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// errInternal 為 panic 時回傳給用戶端的錯誤（不含 panic 內容）。
+var errInternal = errors.New("internal server error")
+
+// recoverMiddleware 攔截 next 中的 panic 並回傳 500。
+func (s *Server) recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			s.logPanic(r, v, debug.Stack())
+			if rec.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			writeErr(rec, errInternal, http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(rec, r)
+	})
+}
+
+// logPanic 將 panic 內容與堆疊寫入請求日誌（未設定 WithLogger 時寫入標準 log）。
+func (s *Server) logPanic(r *http.Request, v any, stack []byte) {
+	logf := log.Printf
+	if s.logger != nil {
+		logf = s.logger.Printf
+	}
+	logf("panic: req_id=%s method=%s path=%s: %v\n%s", RequestID(r.Context()), r.Method, r.URL.RequestURI(), v, stack)
+}
@@ -0,0 +1,74 @@
+// internal/server/recover_test.go
+//
+// 測試 panic 復原中介層：panic 回傳 500 JSON 且不洩漏內部訊息，伺服器仍可繼續服務。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// TestRecoverPanic 以會 panic 的測試 handler 經過 recoverMiddleware，
+// 確認回應為 500 + "code":"internal"、堆疊寫入日誌，且後續請求正常。
+func TestRecoverPanic(t *testing.T) {
+	var logs bytes.Buffer
+	s := NewServer(bank.NewBank(), nil, WithLogger(log.New(&logs, "", 0)))
+	mux := http.NewServeMux()
+	mux.HandleFunc("/boom", func(http.ResponseWriter, *http.Request) { panic("secret internal state") })
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, _ *http.Request) { writeJSON(w, http.StatusOK, map[string]bool{"ok": true}) })
+	ts := httptest.NewServer(s.recoverMiddleware(mux))
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ {
+		resp, err := ts.Client().Get(ts.URL + "/boom")
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		var e errorBody
+		if err := json.Unmarshal(raw, &e); err != nil {
+			t.Fatalf("body is not JSON: %q", raw)
+		}
+		if resp.StatusCode != http.StatusInternalServerError || e.Code != "internal" {
+			t.Fatalf("code=%d body=%+v want 500 internal", resp.StatusCode, e)
+		}
+		if strings.Contains(string(raw), "secret") {
+			t.Fatalf("panic value leaked to client: %s", raw)
+		}
+
+		// ✅ panic 之後伺服器仍可服務
+		resp, err = ts.Client().Get(ts.URL + "/ok")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("after panic: code=%d want 200", resp.StatusCode)
+		}
+	}
+	if !strings.Contains(logs.String(), "secret internal state") || !strings.Contains(logs.String(), "goroutine") {
+		t.Fatalf("log missing panic value or stack:\n%s", logs.String())
+	}
+}
+
+// TestRecoverAbortHandler 確認 http.ErrAbortHandler 不被攔截，而是交由 net/http 中斷連線。
+func TestRecoverAbortHandler(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil)
+	h := s.recoverMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(http.ErrAbortHandler) }))
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("recovered %v want http.ErrAbortHandler", v)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	t.Fatal("ErrAbortHandler was swallowed")
+}
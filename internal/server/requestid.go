@@ -0,0 +1,60 @@
+// internal/server/requestid.go
+//
+// 本檔為每個請求指定請求 ID，方便在日誌與用戶端之間追查同一個請求：
+//   - 用戶端已帶合法的 X-Request-ID（例如由前端或上游閘道產生）時沿用，否則產生 16 字元的隨機十六進位字串；
+//   - 請求 ID 一律以 X-Request-ID 回應標頭回傳，並存入 request context，供請求日誌（見 logging.go）使用。
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// RequestIDHeader 為請求 ID 的請求 / 回應標頭名稱。
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen 為沿用用戶端請求 ID 的長度上限。
+const maxRequestIDLen = 128
+
+// requestIDKey 為請求 ID 在 context 中的鍵。
+type requestIDKey struct{}
+
+// RequestID 回傳 ctx 中的請求 ID；未經過 requestIDMiddleware 時回傳空字串。
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// newRequestID 產生 16 字元的隨機十六進位請求 ID。
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// validRequestID 判斷用戶端提供的請求 ID 是否可沿用：非空、不過長、只含可見的 ASCII 字元（避免日誌注入）。
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// requestIDMiddleware 指定請求 ID、寫入回應標頭並存入 context。
+func (s *Server) requestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
@@ -4,7 +4,8 @@
 // 透過集中管理 JSON 與錯誤輸出，可確保整個 REST API 的一致性與可維護性。
 // 設計理念：
 //   - 「成功回應」使用標準 JSON 編碼（Content-Type: application/json）。
-//   - 「錯誤回應」統一由 writeErr 輸出，方便日後集中擴充（例如自訂錯誤結構）。
+//   - 「錯誤回應」統一由 writeErr 輸出為 {"error": "...", "code": "..."}，
+//     code 為由錯誤對應而來的穩定代碼（例如 insufficient_balance、not_found）。
 //
 // 若未來要支援更完整的 API 響應規範（如 RFC 7807 problem+json），
 // 僅需在此檔擴充，無須修改各個 handler。
@@ -12,7 +13,11 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+
+	"banking/internal/bank"
 )
 
 // writeJSON 統一輸出成功回應。
@@ -25,14 +30,175 @@ func writeJSON(w http.ResponseWriter, code int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
-// writeErr 統一輸出錯誤回應。
-// - err.Error()：直接輸出為純文字訊息
-// - code：HTTP 狀態碼（400、404、409 等）
-//
-// 此為簡化版；若要回傳 JSON 格式錯誤（例如 {"error": "..."}），
-// 可擴充為：
-//
-//	writeJSON(w, code, map[string]string{"error": err.Error()})
+// errorBody 為錯誤回應的 JSON 結構：
+// - Error：人類可讀的錯誤訊息（即 err.Error()）
+// - Code：穩定的機器可讀代碼，用戶端應以此判斷錯誤種類，而非比對訊息文字
+type errorBody struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+// errMethodNotAllowed 與 errRouteNotFound 為 handler 層的通用錯誤。
+var (
+	errMethodNotAllowed = errors.New("method not allowed")
+	errRouteNotFound    = errors.New("not found")
+)
+
+// errorCodes 將已知錯誤對應為錯誤代碼；依序以 errors.Is 比對，先符合者優先。
+var errorCodes = []struct {
+	err  error
+	code string
+}{
+	{bank.ErrNotFound, "not_found"},
+	{bank.ErrBadAmount, "bad_amount"},
+	{bank.ErrNegativeBalance, "negative_balance"},
+	{bank.ErrInsufficient, "insufficient_balance"},
+	{bank.ErrSameAccount, "same_account"},
+	{bank.ErrNoteBudget, "note_budget_exceeded"},
+	{bank.ErrNameNotAllowed, "name_not_allowed"},
+	{bank.ErrBadName, "bad_name"},
+	{bank.ErrTxNotFound, "tx_not_found"},
+	{bank.ErrBadOp, "bad_operation"},
+	{bank.ErrCurrencyDisabled, "currency_disabled"},
+	{bank.ErrBelowMinimum, "below_minimum"},
+	{bank.ErrClosed, "account_closed"},
+	{bank.ErrNonZeroBalance, "non_zero_balance"},
+	{bank.ErrFrozen, "bank_frozen"},
+	{bank.ErrSystemAccount, "system_account"},
+	{bank.ErrCurrencyMismatch, "currency_mismatch"},
+	{bank.ErrBadCurrency, "bad_currency"},
+	{bank.ErrBadFilter, "bad_filter"},
+	{bank.ErrBadSort, "bad_sort"},
+	{bank.ErrNotReversible, "not_reversible"},
+	{bank.ErrAlreadyReversed, "already_reversed"},
+	{bank.ErrHoldNotFound, "hold_not_found"},
+	{bank.ErrAuthHoldNotFound, "authorization_not_found"},
+	{bank.ErrDuplicateRequest, "duplicate_request"},
+	{bank.ErrDuplicateRef, "duplicate_ref"},
+	{bank.ErrPendingApproval, "pending_approval"},
+	{bank.ErrOverflow, "overflow"},
+	{bank.ErrAccountFrozen, "account_frozen"},
+	{bank.ErrDailyLimitExceeded, "daily_limit_exceeded"},
+	{bank.ErrVersionMismatch, "version_mismatch"},
+	{bank.ErrInconsistent, "ledger_inconsistent"},
+	{bank.ErrSnapshotVersion, "unsupported_snapshot_version"},
+	{bank.ErrNotEmpty, "bank_not_empty"},
+	{errBadIfMatch, "bad_if_match"},
+	{errUnauthorized, "unauthorized"},
+	{errForbidden, "forbidden"},
+	{errIdemScope, "idempotency_key_reused"},
+	{errIdemParams, "idempotency_key_reused"},
+	{errReceiptsDisabled, "receipts_disabled"},
+	{errNotPersisted, "not_persisted"},
+	{errRateLimited, "rate_limited"},
+	{errBodyTooLarge, "too_large"},
+	{errUnsupportedMedia, "unsupported_media_type"},
+	{errMethodNotAllowed, "method_not_allowed"},
+	{errRouteNotFound, "not_found"},
+	{errInternal, "internal"},
+	{errTimeout, "timeout"},
+}
+
+// errorStatuses 將領域錯誤對應為 HTTP 狀態碼（各錯誤的對應亦記載於 bank/errors.go）；
+// 依序以 errors.Is 比對，先符合者優先。新增領域錯誤時須同時加入此表與 errorCodes。
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	// 404：帳戶、交易或保留不存在
+	{bank.ErrNotFound, http.StatusNotFound},
+	{bank.ErrTxNotFound, http.StatusNotFound},
+	{bank.ErrHoldNotFound, http.StatusNotFound},
+	{bank.ErrAuthHoldNotFound, http.StatusNotFound},
+
+	// 400：請求本身不合法
+	{bank.ErrBadAmount, http.StatusBadRequest},
+	{bank.ErrNegativeBalance, http.StatusBadRequest},
+	{bank.ErrSameAccount, http.StatusBadRequest},
+	{bank.ErrNoteBudget, http.StatusBadRequest},
+	{bank.ErrNameNotAllowed, http.StatusBadRequest},
+	{bank.ErrBadName, http.StatusBadRequest},
+	{bank.ErrBadOp, http.StatusBadRequest},
+	{bank.ErrBelowMinimum, http.StatusBadRequest},
+	{bank.ErrCurrencyMismatch, http.StatusBadRequest},
+	{bank.ErrBadCurrency, http.StatusBadRequest},
+	{bank.ErrBadFilter, http.StatusBadRequest},
+	{bank.ErrBadSort, http.StatusBadRequest},
+	{bank.ErrSnapshotVersion, http.StatusBadRequest},
+	{errBadIfMatch, http.StatusBadRequest},
+
+	// 409：與帳戶 / 帳本目前狀態衝突
+	{bank.ErrInsufficient, http.StatusConflict},
+	{bank.ErrCurrencyDisabled, http.StatusConflict},
+	{bank.ErrClosed, http.StatusConflict},
+	{bank.ErrNonZeroBalance, http.StatusConflict},
+	{bank.ErrSystemAccount, http.StatusConflict},
+	{bank.ErrNotReversible, http.StatusConflict},
+	{bank.ErrAlreadyReversed, http.StatusConflict},
+	{bank.ErrDuplicateRequest, http.StatusConflict},
+	{bank.ErrDuplicateRef, http.StatusConflict},
+	{bank.ErrPendingApproval, http.StatusConflict},
+	{bank.ErrOverflow, http.StatusConflict},
+	{bank.ErrInconsistent, http.StatusConflict},
+	{bank.ErrNotEmpty, http.StatusConflict},
+
+	// 其他
+	{bank.ErrVersionMismatch, http.StatusPreconditionFailed},
+	{bank.ErrAccountFrozen, http.StatusLocked},
+	{bank.ErrDailyLimitExceeded, http.StatusTooManyRequests},
+	{bank.ErrFrozen, http.StatusServiceUnavailable},
+	{errTimeout, http.StatusServiceUnavailable},
+}
+
+// httpStatusFor 回傳錯誤對應的 HTTP 狀態碼（見 errorStatuses）；
+// 不在表中的錯誤屬於未預期的伺服器錯誤，回傳 500。
+// 處理領域操作錯誤的 handler 一律以 writeErr(w, err, httpStatusFor(err)) 回應，不再各自判斷。
+func httpStatusFor(err error) int {
+	for _, c := range errorStatuses {
+		if errors.Is(err, c.err) {
+			return c.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// statusCodes 為未知錯誤依 HTTP 狀態碼決定的預設代碼。
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusConflict:              "conflict",
+	http.StatusPreconditionFailed:    "precondition_failed",
+	http.StatusRequestEntityTooLarge: "too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusTooManyRequests:       "rate_limited",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+// errorCode 回傳 err 對應的錯誤代碼；未知錯誤依 status 決定，其餘一律為 "internal"。
+func errorCode(err error, status int) string {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			return c.code
+		}
+	}
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	return "internal"
+}
+
+// writeErr 統一輸出錯誤回應：{"error": "...", "code": "..."}（Content-Type: application/json）。
+// - err：錯誤訊息與代碼的來源（代碼見 errorCode）
+// - code：HTTP 狀態碼（400、404、409 等），由呼叫端決定，本函式不會改變
 func writeErr(w http.ResponseWriter, err error, code int) {
-	http.Error(w, err.Error(), code)
+	writeJSON(w, code, errorBody{Error: err.Error(), Code: errorCode(err, code)})
+}
+
+// methodNotAllowed 回應 405（code method_not_allowed），並以 Allow 標頭列出路徑允許的方法。
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
 }
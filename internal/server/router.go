@@ -12,7 +12,23 @@
 //   - main.go 組裝整體應用（注入 Bank、Storage、Persist Hook）
 package server
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
+
+// apiPrefixes 為各 API 版本的掛載前綴（見 Router）。
+var apiPrefixes = []string{"/api/v1", "/api/v2"}
+
+// apiPath 去除路徑的 API 版本前綴，供路由外側的中介層（驗證、速率限制、路由統計）判斷端點。
+func apiPath(path string) string {
+	for _, p := range apiPrefixes {
+		if rest, ok := strings.CutPrefix(path, p); ok {
+			return rest
+		}
+	}
+	return path
+}
 
 // Router 建立並回傳整個 HTTP 處理鏈。
 // 採明確路由註冊（非反射式），確保高可讀性與低魔法性。
@@ -26,35 +42,111 @@ func (s *Server) Router() http.Handler {
 
 	// 健康檢查：可供監控或 Docker liveness probe 使用。
 	v1.HandleFunc("/health", s.health)
+	//   - GET /health/live  → 行程存活
+	//   - GET /health/ready → 最近一次保存失敗時 503（見 health.go）
+	v1.HandleFunc("/health/live", s.healthLive)
+	v1.HandleFunc("/health/ready", s.healthReady)
+
+	// 全行統計：GET /stats → {"accounts": n, "total_balance": x}
+	v1.HandleFunc("/stats", s.stats)
 
 	// 帳戶操作：
 	//   - GET  /accounts          → 列出帳戶
 	//   - POST /accounts          → 建立帳戶
 	v1.HandleFunc("/accounts", s.accounts)
+	//   - GET  /accounts.csv      → 帳戶清單匯出（CSV，見 export.go）
+	v1.HandleFunc("/accounts.csv", s.accountsCSV)
+
+	// 批次查詢（精確路徑優先於 /accounts/ 子路徑）：
+	//   - POST /accounts/get
+	v1.HandleFunc("/accounts/get", s.accountsGet)
+	//   - POST /accounts/logs
+	v1.HandleFunc("/accounts/logs", s.accountsLogs)
 
-	// 帳戶子操作：
-	//   - GET  /accounts/{id}
+	// 帳戶子操作（存款 / 提款支援 Idempotency-Key，見 idempotency.go）：
+	//   - GET    /accounts/{id}
+	//   - PATCH  /accounts/{id}  → 更名
+	//   - DELETE /accounts/{id}
 	//   - POST /accounts/{id}/deposit
 	//   - POST /accounts/{id}/withdraw
+	//   - POST /accounts/{id}/close
+	//   - POST /accounts/{id}/freeze、/unfreeze（admin）
+	//   - POST /accounts/{id}/hold、/hold/{holdID}/capture、/hold/{holdID}/release（見 holds.go）
+	//   - GET  /accounts/{id}/balance
 	//   - GET  /accounts/{id}/logs
-	v1.HandleFunc("/accounts/", s.accountSubroutes)
+	//   - GET  /accounts/{id}/logs.ofx
+	//   - GET  /accounts/{id}/logs.csv
+	//   - GET  /accounts/{id}/logs.ndjson
+	//   - GET  /accounts/{id}/netflow
+	v1.HandleFunc("/accounts/", s.idempotent("v1", s.accountSubroutes))
 
-	// 轉帳操作：
+	// 轉帳操作（支援 Idempotency-Key）：
 	//   - POST /transfer
-	v1.HandleFunc("/transfer", s.transfer)
+	v1.HandleFunc("/transfer", s.idempotent("v1", s.transfer))
+
+	// 大額轉帳審核（管理者）：
+	//   - POST /transfers/{txID}/approve
+	//   - POST /transfers/{txID}/reject
+	v1.HandleFunc("/transfers/", s.transferReview)
+
+	// 批次操作：
+	//   - POST /batch（mode: atomic | partial）
+	v1.HandleFunc("/batch", s.batch)
+
+	// 全行交易流：GET /transactions?limit=&since=&account=（見 feed.go）
+	v1.HandleFunc("/transactions", s.transactions)
+
+	// 交易收據：
+	//   - GET  /transactions/{txID}/receipt
+	//   - POST /receipts/verify
+	v1.HandleFunc("/transactions/", s.transactionSubroutes)
+	v1.HandleFunc("/receipts/verify", s.verifyReceiptHandler)
+
+	// Prometheus 指標：GET /metrics（見 metrics.go）
+	v1.Handle("/metrics", s.metricsHandler())
+
+	// 營運管理：
+	//   - GET/POST /admin/currencies → 暫停 / 恢復幣別交易
+	v1.HandleFunc("/admin/currencies", s.adminCurrencies)
+	//   - GET      /admin/activity?minutes=N → 最近 N 分鐘的全行交易
+	v1.HandleFunc("/admin/activity", s.adminActivity)
+	//   - POST     /admin/freeze-all、/admin/unfreeze-all → 全行凍結 / 解除
+	v1.HandleFunc("/admin/freeze-all", s.adminFreeze(true))
+	v1.HandleFunc("/admin/unfreeze-all", s.adminFreeze(false))
+	//   - POST     /admin/reindex → 由日誌重建索引並回報異常
+	v1.HandleFunc("/admin/reindex", s.adminReindex)
+	//   - GET      /admin/verify → 檢查帳戶餘額與日誌是否一致
+	v1.HandleFunc("/admin/verify", s.adminVerify)
+	//   - POST     /admin/reset → 清空帳本（測試 / 展示環境）
+	v1.HandleFunc("/admin/reset", s.adminReset)
+	//   - GET      /admin/export → 匯出完整快照；POST /admin/import[?force=true] → 匯入快照
+	v1.HandleFunc("/admin/export", s.adminExport)
+	v1.HandleFunc("/admin/import", s.adminImport)
+	//   - POST     /admin/accounts/{id}/approve → 核准待審核帳戶
+	v1.HandleFunc("/admin/accounts/", s.adminAccounts)
+	//   - GET      /admin/routes → 每條路由的請求數、錯誤率與延遲
+	v1.HandleFunc("/admin/routes", s.adminRoutes)
 
 	// ────────────────
 	// API Version Mounting
 	// ────────────────
 	//
 	// 將上述所有端點掛在 /api/v1/ 下。
-	// 若未來有 /api/v2，只需額外建立一組 mux。
 	root := http.NewServeMux()
 	root.Handle("/api/v1/", http.StripPrefix("/api/v1", v1))
 
+	// API v2（snake_case 欄位，見 v2.go）：
+	//   - POST /api/v2/transfer（支援 Idempotency-Key）
+	v2 := http.NewServeMux()
+	v2.HandleFunc("/transfer", s.idempotent("v2", s.transferV2))
+	root.Handle("/api/v2/", http.StripPrefix("/api/v2", v2))
+
 	// 同時保留根路徑（/），方便本地開發或測試。
 	// 若想強制所有 API 都走 /api/v1，可移除此行。
 	root.Handle("/", v1)
 
-	return root
+	// 中介層（由外而內）：路由統計（見 routes.go）→ 請求 ID（見 requestid.go）→ 請求日誌（見 logging.go）
+	// → panic 復原（見 recover.go）→ CORS（見 cors.go）→ 速率限制（見 ratelimit.go）→ API Key 驗證（見 auth.go）
+	// → 請求本體檢查（見 decode.go）→ 請求逾時（見 timeout.go）→ 回應壓縮（見 gzip.go）。
+	return s.metricsMiddleware(s.requestIDMiddleware(s.logMiddleware(s.recoverMiddleware(s.corsMiddleware(s.rateLimitMiddleware(s.authMiddleware(s.bodyLimitMiddleware(s.timeoutMiddleware(s.gzipMiddleware(root))))))))))
 }
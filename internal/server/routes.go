@@ -0,0 +1,158 @@
+// internal/server/routes.go
+//
+// 本檔實作每條路由的請求統計（不依賴 Prometheus），並由 GET /admin/routes 以 JSON 呈現：
+// 總請求數、成功數、4xx / 5xx 錯誤數、錯誤率，以及 p50 / p95 延遲。
+//
+// 路由以「方法 + 路徑樣板」彙整（例如 "POST /accounts/{id}/deposit"），
+// 路徑中的帳戶 ID / TxID 皆被替換為佔位符，未知路徑歸入 "/other"，避免統計項目無限制成長。
+// 延遲百分位以每條路由最近 latencySamples 筆樣本計算。
+package server
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencySamples 為每條路由保留的延遲樣本數。
+const latencySamples = 1024
+
+// routeStats 彙整所有路由的統計。
+type routeStats struct {
+	mu     sync.Mutex
+	routes map[string]*routeStat
+}
+
+// routeStat 為單一路由的計數與延遲樣本（環狀緩衝）。
+type routeStat struct {
+	total, success, err4xx, err5xx int64
+	lat                            []time.Duration
+	next                           int
+}
+
+// RouteSummary 為 /admin/routes 回應中的單一路由統計。
+type RouteSummary struct {
+	Route     string  `json:"route"`
+	Total     int64   `json:"total"`
+	Success   int64   `json:"success"`
+	Errors4xx int64   `json:"errors_4xx"`
+	Errors5xx int64   `json:"errors_5xx"`
+	ErrorRate float64 `json:"error_rate"`
+	P50ms     float64 `json:"p50_ms"`
+	P95ms     float64 `json:"p95_ms"`
+}
+
+func newRouteStats() *routeStats {
+	return &routeStats{routes: make(map[string]*routeStat)}
+}
+
+// observe 記錄一次請求。
+func (rs *routeStats) observe(route string, code int, dur time.Duration) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	st, ok := rs.routes[route]
+	if !ok {
+		st = &routeStat{}
+		rs.routes[route] = st
+	}
+	st.total++
+	switch {
+	case code >= 500:
+		st.err5xx++
+	case code >= 400:
+		st.err4xx++
+	default:
+		st.success++
+	}
+	if len(st.lat) < latencySamples {
+		st.lat = append(st.lat, dur)
+	} else {
+		st.lat[st.next] = dur
+		st.next = (st.next + 1) % latencySamples
+	}
+}
+
+// summary 回傳依路由名稱排序的統計快照。
+func (rs *routeStats) summary() []RouteSummary {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	out := make([]RouteSummary, 0, len(rs.routes))
+	for route, st := range rs.routes {
+		lat := slices.Clone(st.lat)
+		slices.Sort(lat)
+		out = append(out, RouteSummary{
+			Route:     route,
+			Total:     st.total,
+			Success:   st.success,
+			Errors4xx: st.err4xx,
+			Errors5xx: st.err5xx,
+			ErrorRate: float64(st.err4xx+st.err5xx) / float64(st.total),
+			P50ms:     percentileMs(lat, 0.50),
+			P95ms:     percentileMs(lat, 0.95),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Route < out[j].Route })
+	return out
+}
+
+// percentileMs 以最近排名法取已排序樣本的百分位，單位為毫秒。
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(float64(len(sorted))*p+0.5) - 1
+	i = min(max(i, 0), len(sorted)-1)
+	return float64(sorted[i]) / float64(time.Millisecond)
+}
+
+// knownRoots 為可辨識的第一層路徑；其餘歸入 "/other"。
+var knownRoots = map[string]bool{
+	"health": true, "accounts": true, "transfer": true, "transfers": true,
+	"transactions": true, "receipts": true, "batch": true, "admin": true, "stats": true,
+	"accounts.csv": true, "metrics": true,
+}
+
+// routeLabel 將請求轉為統計用的路由樣板，例如 "GET /accounts/{id}/logs"。
+func routeLabel(method, path string) string {
+	path = apiPath(path)
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if !knownRoots[parts[0]] {
+		return method + " /other"
+	}
+	switch {
+	case parts[0] == "accounts" && len(parts) >= 2 && parts[1] != "get" && path != "/accounts/logs":
+		parts[1] = "{id}"
+		if len(parts) >= 4 && parts[2] == "hold" {
+			parts[3] = "{holdID}"
+		}
+	case (parts[0] == "transactions" || parts[0] == "transfers") && len(parts) >= 2:
+		parts[1] = "{txID}"
+	case parts[0] == "admin" && len(parts) >= 3 && parts[1] == "accounts":
+		parts[2] = "{id}"
+	}
+	return method + " /" + strings.Join(parts, "/")
+}
+
+// metricsMiddleware 為每個請求記錄路由統計與 Prometheus 耗時指標。
+func (s *Server) metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		route, dur := routeLabel(r.Method, r.URL.Path), time.Since(start)
+		s.routes.observe(route, rec.code, dur)
+		s.metrics.observeRequest(route, rec.code, dur)
+	})
+}
+
+// adminRoutes 處理 GET /admin/routes：回傳每條路由的請求統計。
+func (s *Server) adminRoutes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"routes": s.routes.summary()})
+}
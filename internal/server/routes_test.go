@@ -0,0 +1,62 @@
+// internal/server/routes_test.go
+//
+// 測試路由統計：成功與錯誤請求被正確歸類到路由樣板，且 /admin/routes 回報相符的數字。
+package server
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+func TestAdminRoutes(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID, nil, 200, nil)
+	doJSON(t, cli, "GET", ts.URL+"/api/v1/accounts/"+a.ID, nil, 200, nil)
+	doJSON(t, cli, "GET", ts.URL+"/accounts/999", nil, 404, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 5}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": -5}, 400, nil)
+
+	var resp struct {
+		Routes []RouteSummary `json:"routes"`
+	}
+	doJSON(t, cli, "GET", ts.URL+"/admin/routes", nil, 200, &resp)
+	got := make(map[string]RouteSummary)
+	for _, r := range resp.Routes {
+		got[r.Route] = r
+	}
+
+	acct := got["GET /accounts/{id}"]
+	if acct.Total != 3 || acct.Success != 2 || acct.Errors4xx != 1 || acct.Errors5xx != 0 {
+		t.Fatalf("GET /accounts/{id}=%+v", acct)
+	}
+	if acct.ErrorRate < 0.33 || acct.ErrorRate > 0.34 {
+		t.Fatalf("error rate=%v want 1/3", acct.ErrorRate)
+	}
+	dep := got["POST /accounts/{id}/deposit"]
+	if dep.Total != 2 || dep.Success != 1 || dep.Errors4xx != 1 || dep.P95ms < dep.P50ms {
+		t.Fatalf("POST /accounts/{id}/deposit=%+v", dep)
+	}
+}
+
+func TestRouteLabel(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/accounts/42/logs":   "GET /accounts/{id}/logs",
+		"/accounts/get":              "GET /accounts/get",
+		"/accounts/logs":             "GET /accounts/logs",
+		"/transactions/tx-9/receipt": "GET /transactions/{txID}/receipt",
+		"/admin/accounts/7/approve":  "GET /admin/accounts/{id}/approve",
+		"/wp-login.php":              "GET /other",
+	}
+	for path, want := range cases {
+		if got := routeLabel("GET", path); got != want {
+			t.Fatalf("routeLabel(%q)=%q want %q", path, got, want)
+		}
+	}
+}
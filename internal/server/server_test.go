@@ -7,19 +7,27 @@
 // 測試重點：
 //  1. API 行為符合題目需求（Create / Deposit / Withdraw / Transfer / Logs）。
 //  2. 成功操作會觸發持久化 persist()。
-//  3. 錯誤狀況皆有正確 HTTP 狀態碼（400, 405, 409 等）。
+//  3. 錯誤狀況皆有正確 HTTP 狀態碼（400, 405, 409 等）與 JSON 錯誤代碼。
 //  4. 確保測試不依賴外部服務，使用 httptest.Server 完成端對端模擬。
 package server
 
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"banking/internal/bank"
+	"banking/internal/storage"
 )
 
 // doJSON 為測試輔助函式：
@@ -96,18 +104,28 @@ func TestHTTPFlowAndPersistHook(t *testing.T) {
 	}
 
 	// 5️⃣ 查詢帳戶日誌
-	var logs []bank.Log
+	var logs struct {
+		Logs []bank.Log `json:"logs"`
+	}
 	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a2.ID+"/logs", nil, 200, &logs)
-	if len(logs) == 0 {
+	if len(logs.Logs) == 0 {
 		t.Fatal("expect logs")
 	}
 
 	// 6️⃣ 錯誤情境測試
 	// (a) 餘額不足 → 409 Conflict
-	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 999999}, 409, nil)
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 999999}, 409, &e)
+	if e.Code != "insufficient_balance" {
+		t.Fatalf("insufficient code=%q", e.Code)
+	}
 
 	// (b) 錯誤方法 → 405 Method Not Allowed
-	doJSON(t, cli, "GET", ts.URL+"/transfer", nil, 405, nil)
+	e = errorBody{}
+	doJSON(t, cli, "GET", ts.URL+"/transfer", nil, 405, &e)
+	if e.Code != "method_not_allowed" {
+		t.Fatalf("405 code=%q", e.Code)
+	}
 
 	// (c) JSON 格式錯誤 → 400 Bad Request
 	req, _ := http.NewRequest("POST", ts.URL+"/accounts/"+a1.ID+"/deposit", bytes.NewBufferString("{bad json}"))
@@ -123,22 +141,980 @@ func TestHTTPFlowAndPersistHook(t *testing.T) {
 	}
 }
 
+// TestJSONErrorBody
+// ------------------------------------------------------------
+// 驗證錯誤回應為 JSON：{"error": "...", "code": "..."}，
+// Content-Type 為 application/json，狀態碼維持不變（餘額不足 409、帳戶不存在 404）。
+// ------------------------------------------------------------
+func TestJSONErrorBody(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	do := func(method, url string, body any, wantStatus int) errorBody {
+		t.Helper()
+		buf, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewReader(buf))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := ts.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != wantStatus {
+			t.Fatalf("code=%d want %d", resp.StatusCode, wantStatus)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+			t.Fatalf("content-type=%q want application/json", ct)
+		}
+		var e errorBody
+		if err := json.NewDecoder(resp.Body).Decode(&e); err != nil {
+			t.Fatalf("error body is not JSON: %v", err)
+		}
+		return e
+	}
+
+	// 1️⃣ 餘額不足的轉帳 → 409 + insufficient_balance
+	e := do("POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 500}, http.StatusConflict)
+	if e.Code != "insufficient_balance" || e.Error != bank.ErrInsufficient.Error() {
+		t.Fatalf("insufficient body=%+v", e)
+	}
+
+	// 2️⃣ 不存在的帳戶 → 404 + not_found
+	if e := do("GET", ts.URL+"/accounts/999", nil, http.StatusNotFound); e.Code != "not_found" {
+		t.Fatalf("not found body=%+v", e)
+	}
+
+	// 3️⃣ 未對應的錯誤依狀態碼決定代碼（壞 JSON → bad_request）
+	if e := do("POST", ts.URL+"/accounts/"+a1.ID+"/deposit", "{bad", http.StatusBadRequest); e.Code != "bad_request" {
+		t.Fatalf("bad request body=%+v", e)
+	}
+}
+
+// TestAccountFreezeHTTP
+// ------------------------------------------------------------
+// 驗證 POST /accounts/{id}/freeze、/unfreeze：凍結後提款與轉帳（雙向）回傳 423 + account_frozen，
+// GET 仍可查詢且顯示 frozen；解除凍結後恢復。
+// ------------------------------------------------------------
+func TestAccountFreezeHTTP(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var acc bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/freeze", nil, 200, &acc)
+	if !acc.Frozen {
+		t.Fatalf("freeze response=%+v", acc)
+	}
+
+	// ❌ 凍結帳戶的提款、轉出、轉入 → 423
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 1}, 423, &e)
+	if e.Code != "account_frozen" {
+		t.Fatalf("withdraw code=%q", e.Code)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 1}, 423, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a2.ID, "To": a1.ID, "Amount": 1}, 423, nil)
+
+	// ✅ 仍可查詢
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a1.ID, nil, 200, &acc)
+	if !acc.Frozen || acc.Balance != 100 {
+		t.Fatalf("get frozen account=%+v", acc)
+	}
+
+	// ✅ 解除凍結後恢復；不存在的帳戶 404
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/unfreeze", nil, 200, &acc)
+	var after bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 1}, 200, &after)
+	if after.Frozen || after.Balance != 99 {
+		t.Fatalf("after unfreeze=%+v", after)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/999/freeze", nil, 404, nil)
+}
+
+// TestDailyLimitHTTP
+// ------------------------------------------------------------
+// 驗證超過每日提款限額的提款與轉帳回傳 429 + daily_limit_exceeded。
+// ------------------------------------------------------------
+func TestDailyLimitHTTP(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+	_ = b.SetDailyLimit(a1.ID, 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 100}, 200, nil)
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 1}, 429, &e)
+	if e.Code != "daily_limit_exceeded" {
+		t.Fatalf("withdraw code=%q", e.Code)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 1}, 429, nil)
+}
+
+// TestReverseHTTP
+// ------------------------------------------------------------
+// 驗證 POST /transactions/{txID}/reverse：成功沖正回傳沖正交易並退回款項；
+// 重複沖正 409 + already_reversed、原收款方餘額不足 409、不存在的交易 404、GET 405。
+// ------------------------------------------------------------
+func TestReverseHTTP(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var tr struct {
+		TxID string `json:"tx_id"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 60}, 200, &tr)
+
+	// ✅ 沖正
+	var resp struct {
+		Tx bank.Tx `json:"tx"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transactions/"+tr.TxID+"/reverse", nil, 200, &resp)
+	if resp.Tx.Type != bank.TxReversal || resp.Tx.Ref != tr.TxID || resp.Tx.From != a2.ID || resp.Tx.Amount != 60 {
+		t.Fatalf("reversal tx=%+v", resp.Tx)
+	}
+	if got, _ := b.Get(a1.ID); got.Balance != 100 {
+		t.Fatalf("payer balance=%d want 100", got.Balance)
+	}
+
+	// ❌ 重複沖正
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/transactions/"+tr.TxID+"/reverse", nil, 409, &e)
+	if e.Code != "already_reversed" {
+		t.Fatalf("code=%q", e.Code)
+	}
+
+	// ❌ 原收款方已轉出 → 餘額不足
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 40}, 200, &tr)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a2.ID+"/withdraw", map[string]any{"amount": 40}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transactions/"+tr.TxID+"/reverse", nil, 409, &e)
+	if e.Code != "insufficient_balance" {
+		t.Fatalf("code=%q", e.Code)
+	}
+
+	doJSON(t, cli, "POST", ts.URL+"/transactions/tx-999/reverse", nil, 404, nil)
+	doJSON(t, cli, "GET", ts.URL+"/transactions/"+tr.TxID+"/reverse", nil, 405, nil)
+}
+
 // TestMethodNotAllowed
 // ------------------------------------------------------------
-// 驗證對不支援的 HTTP 方法或錯誤路徑會正確回傳 405/404。
-// 確保 router 與 handler 皆有適當限制。
+// 驗證 /accounts/{id} 之下的路由判定：未知子路徑一律 404；
+// 已知路徑但方法不符一律 405，且 Allow 標頭列出允許的方法。
 // ------------------------------------------------------------
 func TestMethodNotAllowed(t *testing.T) {
 	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
 	s := NewServer(b, nil)
 	ts := httptest.NewServer(s.Router())
 	defer ts.Close()
 	cli := ts.Client()
 
-	// POST /accounts/{id} → 錯誤方法 (無對應子路徑)
-	req, _ := http.NewRequest("POST", ts.URL+"/accounts/1", nil)
-	resp, _ := cli.Do(req)
-	if resp.StatusCode != 405 && resp.StatusCode != 404 {
-		t.Fatalf("code=%d want 405 or 404", resp.StatusCode)
+	base := "/accounts/" + a.ID
+	cases := []struct {
+		method, path string
+		code         int
+		allow        string // 僅 405 時檢查
+	}{
+		{"POST", base, 405, "GET, PATCH, DELETE"},
+		{"PUT", base, 405, "GET, PATCH, DELETE"},
+		{"POST", "/accounts/999", 405, "GET, PATCH, DELETE"}, // 方法判定先於帳戶是否存在
+		{"GET", base + "/deposit", 405, "POST"},
+		{"GET", base + "/withdraw", 405, "POST"},
+		{"DELETE", base + "/close", 405, "POST"},
+		{"GET", base + "/freeze", 405, "POST"},
+		{"GET", base + "/hold", 405, "POST"},
+		{"GET", base + "/hold/hold-1/capture", 405, "POST"},
+		{"POST", base + "/balance", 405, "GET"},
+		{"POST", base + "/logs", 405, "GET"},
+		{"POST", base + "/logs.csv", 405, "GET"},
+		{"POST", base + "/statement", 405, "GET"},
+		{"GET", base + "/unknown", 404, ""},
+		{"POST", base + "/unknown", 404, ""},
+		{"GET", base + "/logs/extra", 404, ""},
+		{"POST", base + "/deposit/extra", 404, ""},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest(c.method, ts.URL+c.path, nil)
+		resp, err := cli.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != c.code {
+			t.Errorf("%s %s code=%d want %d", c.method, c.path, resp.StatusCode, c.code)
+			continue
+		}
+		if got := resp.Header.Get("Allow"); got != c.allow {
+			t.Errorf("%s %s Allow=%q want %q", c.method, c.path, got, c.allow)
+		}
+	}
+}
+
+// TestAccountsMultiGet 驗證 POST /accounts/get：
+// 寬鬆模式回傳找到的帳戶並列出 missing；嚴格模式遇到未知 ID 回傳 404。
+func TestAccountsMultiGet(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 200)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Accounts map[string]bank.Account `json:"accounts"`
+		Missing  []string                `json:"missing"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/get", map[string]any{"ids": []string{a1.ID, a2.ID, "999"}}, 200, &resp)
+	if len(resp.Accounts) != 2 || resp.Accounts[a2.ID].Balance != 200 {
+		t.Fatalf("accounts=%v", resp.Accounts)
+	}
+	if len(resp.Missing) != 1 || resp.Missing[0] != "999" {
+		t.Fatalf("missing=%v", resp.Missing)
+	}
+
+	doJSON(t, cli, "POST", ts.URL+"/accounts/get", map[string]any{"ids": []string{a1.ID, "999"}, "strict": true}, 404, nil)
+}
+
+// TestAccountsLogs 驗證 POST /accounts/logs：一次回傳多個帳戶的日誌，不存在的 ID 列於 missing。
+func TestAccountsLogs(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	_ = b.Transfer(a1.ID, a2.ID, bank.NewMoney(30, "USD"))
+	_, _ = b.Deposit(a1.ID, bank.NewMoney(5, "USD"), "")
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Logs    map[string][]bank.Log `json:"logs"`
+		Missing []string              `json:"missing"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/logs", map[string]any{"ids": []string{a1.ID, a2.ID, "999"}}, 200, &resp)
+	if len(resp.Logs) != 2 || len(resp.Logs[a1.ID]) != 2 || len(resp.Logs[a2.ID]) != 1 {
+		t.Fatalf("logs=%+v", resp.Logs)
+	}
+	if l := resp.Logs[a2.ID][0]; l.Type != "transfer" || l.Direction != "in" || l.CounterID != a1.ID {
+		t.Fatalf("payee log=%+v", l)
+	}
+	if len(resp.Missing) != 1 || resp.Missing[0] != "999" {
+		t.Fatalf("missing=%v", resp.Missing)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/logs", nil, 405, nil)
+}
+
+// TestCloseAccount 驗證 POST /accounts/{id}/close：有餘額 409、關閉後異動 409、不存在 404。
+func TestCloseAccount(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 5)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/close", nil, 409, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/withdraw", map[string]any{"amount": 5}, 200, nil)
+
+	var acc bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/close", nil, 200, &acc)
+	if acc.Status != bank.StatusClosed {
+		t.Fatalf("status=%q want closed", acc.Status)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 409, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/999/close", nil, 404, nil)
+}
+
+// TestDeleteAccount 驗證 DELETE /accounts/{id}：有餘額 409、歸零後 204 並觸發 persist、不存在 404。
+func TestDeleteAccount(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 5)
+	persisted := 0
+	ts := httptest.NewServer(NewServer(b, func() error { persisted++; return nil }).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	doJSON(t, cli, "DELETE", ts.URL+"/accounts/"+a.ID, nil, 409, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/withdraw", map[string]any{"amount": 5}, 200, nil)
+
+	before := persisted
+	doJSON(t, cli, "DELETE", ts.URL+"/accounts/"+a.ID, nil, 204, nil)
+	if persisted != before+1 {
+		t.Fatalf("persist not called after delete")
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID, nil, 404, nil)
+	doJSON(t, cli, "DELETE", ts.URL+"/accounts/"+a.ID, nil, 404, nil)
+}
+
+// TestRenameAccount 驗證 PATCH /accounts/{id}：成功回傳新名稱並觸發 persist；空白名稱 400、不存在 404。
+func TestRenameAccount(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("Alcie", 5)
+	persisted := 0
+	ts := httptest.NewServer(NewServer(b, func() error { persisted++; return nil }).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var acc bank.Account
+	doJSON(t, cli, "PATCH", ts.URL+"/accounts/"+a.ID, map[string]any{"name": "Alice"}, 200, &acc)
+	if acc.Name != "Alice" || persisted != 1 {
+		t.Fatalf("renamed=%+v persisted=%d", acc, persisted)
+	}
+	doJSON(t, cli, "PATCH", ts.URL+"/accounts/"+a.ID, map[string]any{"name": "  "}, 400, nil)
+	doJSON(t, cli, "PATCH", ts.URL+"/accounts/999", map[string]any{"name": "Bob"}, 404, nil)
+	if persisted != 1 {
+		t.Fatalf("failed renames must not persist, persisted=%d", persisted)
+	}
+}
+
+// TestPersistFailure 驗證 persist 鉤子失敗時回應 500（not_persisted），而非回報成功；
+// 記憶體中的變更保留，帶 Idempotency-Key 的重送直接重播、不會重複入帳。
+func TestPersistFailure(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	fail := true
+	ts := httptest.NewServer(NewServer(b, func() error {
+		if fail {
+			return errors.New("disk full")
+		}
+		return nil
+	}).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	// ❌ 存款：500，錯誤代碼 not_persisted
+	var eb errorBody
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 50}, 500, &eb)
+	if eb.Code != "not_persisted" || !strings.Contains(eb.Error, "applied but not persisted") || !strings.Contains(eb.Error, "disk full") {
+		t.Fatalf("error body=%+v", eb)
+	}
+	// 1️⃣ 變更已套用，不回滾
+	if acc, _ := b.Get(a.ID); acc.Balance != 150 {
+		t.Fatalf("balance=%d want 150", acc.Balance)
+	}
+
+	// 2️⃣ 帶 Idempotency-Key：第一次 500，重送重播同一回應，不再入帳
+	url := ts.URL + "/accounts/" + a.ID + "/deposit"
+	if code, _, _ := postWithKey(t, url, "k1", map[string]any{"amount": 10}); code != 500 {
+		t.Fatalf("code=%d want 500", code)
+	}
+	if code, _, replayed := postWithKey(t, url, "k1", map[string]any{"amount": 10}); code != 500 || !replayed {
+		t.Fatalf("retry code=%d replayed=%v want replayed 500", code, replayed)
+	}
+	if acc, _ := b.Get(a.ID); acc.Balance != 160 {
+		t.Fatalf("balance=%d want 160", acc.Balance)
+	}
+
+	// ✅ 儲存恢復後照常回應 200
+	fail = false
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/withdraw", map[string]any{"amount": 60}, 200, nil)
+}
+
+// TestPersistFailureDebounced 驗證合併寫入（預設設定）下背景保存失敗後，之後的變更回應 500 not_persisted，
+// 直到下一次保存成功；第一筆變更在保存前即回應，仍為 200。
+func TestPersistFailureDebounced(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	var fail atomic.Bool
+	fail.Store(true)
+	p := storage.NewPersister(func() error {
+		if fail.Load() {
+			return errors.New("disk full")
+		}
+		return nil
+	}, 5*time.Millisecond)
+	defer p.Close()
+	ts := httptest.NewServer(NewServer(b, p.MarkDirty).Router())
+	defer ts.Close()
+	cli := ts.Client()
+	deposit := fmt.Sprintf("%s/accounts/%s/deposit", ts.URL, a.ID)
+
+	doJSON(t, cli, "POST", deposit, map[string]any{"amount": 1}, 200, nil)
+	deadline := time.Now().Add(2 * time.Second)
+	for p.LastErr() == nil && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	var eb errorBody
+	doJSON(t, cli, "POST", deposit, map[string]any{"amount": 1}, 500, &eb)
+	if eb.Code != "not_persisted" || !strings.Contains(eb.Error, "disk full") {
+		t.Fatalf("error body=%+v", eb)
+	}
+	if acc, _ := b.Get(a.ID); acc.Balance != 102 {
+		t.Fatalf("balance=%d want 102 (applied, not rolled back)", acc.Balance)
+	}
+
+	fail.Store(false)
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	doJSON(t, cli, "POST", deposit, map[string]any{"amount": 1}, 200, nil)
+}
+
+// TestOverflowConflict 驗證會讓餘額溢位的存款與轉帳回傳 409。
+func TestOverflowConflict(t *testing.T) {
+	b := bank.NewBank()
+	rich, _ := b.Create("Rich", math.MaxInt64-1)
+	other, _ := b.Create("Other", 10)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+rich.ID+"/deposit", map[string]any{"amount": 2}, 409, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": other.ID, "To": rich.ID, "Amount": 2}, 409, nil)
+}
+
+// TestCreateWithCurrency 驗證建立帳戶可指定幣別（預設 USD），跨幣別轉帳回傳 400。
+func TestCreateWithCurrency(t *testing.T) {
+	ts := httptest.NewServer(NewServer(bank.NewBank(), nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var usd, eur bank.Account
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 100}, 201, &usd)
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "B", "balance": 0, "currency": "eur"}, 201, &eur)
+	if usd.Currency != "USD" || eur.Currency != "EUR" {
+		t.Fatalf("currencies=%q %q", usd.Currency, eur.Currency)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "C", "currency": "euro"}, 400, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": usd.ID, "To": eur.ID, "Amount": 5}, 400, nil)
+}
+
+// TestStats 驗證 GET /stats 回傳帳戶數與餘額總和。
+func TestStats(t *testing.T) {
+	b := bank.NewBank()
+	_, _ = b.Create("A", 100)
+	_, _ = b.Create("B", 250)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var got map[string]int64
+	doJSON(t, ts.Client(), "GET", ts.URL+"/stats", nil, 200, &got)
+	if got["accounts"] != 2 || got["total_balance"] != 350 {
+		t.Fatalf("stats=%v", got)
+	}
+	doJSON(t, ts.Client(), "POST", ts.URL+"/stats", nil, 405, nil)
+}
+
+// TestIDsAreStrings 驗證帳戶 ID 在建立、查詢、轉帳與日誌中皆以 JSON 字串往返，
+// 不會被轉為數字；以數字傳入 ID 的請求則被拒絕。
+func TestIDsAreStrings(t *testing.T) {
+	b := bank.NewBank()
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	// mustString 確認 m[key] 為 JSON 字串並回傳其值
+	mustString := func(where string, m map[string]any, key string) string {
+		t.Helper()
+		v, ok := m[key].(string)
+		if !ok {
+			t.Fatalf("%s: %q is %T (%v), want string", where, key, m[key], m[key])
+		}
+		return v
+	}
+
+	// 1️⃣ 建立與查詢
+	var a1, a2, got map[string]any
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 100}, 201, &a1)
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "B", "balance": 0}, 201, &a2)
+	id1, id2 := mustString("create", a1, "id"), mustString("create", a2, "id")
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+id1, nil, 200, &got)
+	if mustString("get", got, "id") != id1 {
+		t.Fatalf("get id=%v want %q", got["id"], id1)
+	}
+
+	// 2️⃣ 轉帳回應中的帳戶 ID
+	var tr map[string]any
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": id1, "To": id2, "Amount": 10}, 200, &tr)
+	mustString("transfer", tr, "tx_id")
+	if from, _ := tr["from"].(map[string]any); mustString("transfer.from", from, "id") != id1 {
+		t.Fatalf("transfer from=%v", tr["from"])
+	}
+
+	// 3️⃣ 日誌中的對方帳戶 ID
+	var logs struct {
+		Logs []map[string]any `json:"logs"`
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+id2+"/logs", nil, 200, &logs)
+	if len(logs.Logs) != 1 || mustString("logs", logs.Logs[0], "counter_account") != id1 {
+		t.Fatalf("logs=%v", logs)
+	}
+
+	// ❌ 以數字傳入 ID 不會被默默轉型
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": 1, "To": 2, "Amount": 1}, 400, nil)
+}
+
+// TestNetFlowEndpoint 驗證 GET /accounts/{id}/netflow 的彙總結果與參數錯誤處理。
+func TestNetFlowEndpoint(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	_, _ = b.Deposit(a.ID, bank.NewMoney(100, "USD"), "")
+	_, _ = b.Withdraw(a.ID, bank.NewMoney(40, "USD"), "")
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var resp struct {
+		Inflow  int64 `json:"inflow"`
+		Outflow int64 `json:"outflow"`
+		Net     int64 `json:"net"`
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/netflow", nil, 200, &resp)
+	if resp.Inflow != 100 || resp.Outflow != 40 || resp.Net != 60 {
+		t.Fatalf("resp=%+v", resp)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/netflow?from=2000-01-01&to=2000-01-02", nil, 200, &resp)
+	if resp.Inflow != 0 || resp.Outflow != 0 || resp.Net != 0 {
+		t.Fatalf("empty period resp=%+v", resp)
+	}
+	doJSON(t, cli, "GET", ts.URL+"/accounts/"+a.ID+"/netflow?from=yesterday", nil, 400, nil)
+	doJSON(t, cli, "GET", ts.URL+"/accounts/999/netflow", nil, 404, nil)
+}
+
+// TestTransferReview 驗證大額轉帳回傳 202（回應前先保存，保存失敗則 500 not_persisted）
+// 並需經 /transfers/{txID}/approve 才入帳。
+func TestTransferReview(t *testing.T) {
+	b := bank.NewBank()
+	b.SetTransferHold(100, time.Hour)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+	persists, fail := 0, false
+	ts := httptest.NewServer(NewServer(b, func() error {
+		persists++
+		if fail {
+			return errors.New("disk full")
+		}
+		return nil
+	}).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	// ✅ 門檻以下立即完成
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 50}, 200, nil)
+
+	// 1️⃣ 門檻以上 → 202 審核中，目的帳戶未入帳
+	var held struct {
+		TxID   string `json:"tx_id"`
+		Status string `json:"status"`
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 500}, 202, &held)
+	if held.Status != bank.TxPendingReview || persists != 2 {
+		t.Fatalf("status=%q persists=%d want 2", held.Status, persists)
+	}
+	if a, _ := b.Get(a2.ID); a.Balance != 50 {
+		t.Fatalf("balance=%d want 50 before approval", a.Balance)
+	}
+
+	// ❌ 進入審核但保存失敗 → 500 not_persisted（保留已生效，不回滾）
+	fail = true
+	var eb errorBody
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 200}, 500, &eb)
+	if eb.Code != "not_persisted" {
+		t.Fatalf("error body=%+v", eb)
+	}
+	fail = false
+
+	// 2️⃣ 核准後入帳；重複核准 404
+	doJSON(t, cli, "POST", ts.URL+"/transfers/"+held.TxID+"/approve", nil, 200, nil)
+	if a, _ := b.Get(a2.ID); a.Balance != 550 {
+		t.Fatalf("balance=%d want 550 after approval", a.Balance)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfers/"+held.TxID+"/reject", nil, 404, nil)
+}
+
+// TestDebouncedPersistDeposits 以 storage.Persister 作為 persist 鉤子：
+// 大量存款請求只標記 dirty，不會每次寫入快照；關機時 Close 只補寫一次且內容為最終狀態。
+func TestDebouncedPersistDeposits(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	path := filepath.Join(t.TempDir(), "data.json")
+	var saves atomic.Int32
+	p := storage.NewPersister(func() error {
+		saves.Add(1)
+		return storage.SaveSnapshot(path, b.Snapshot())
+	}, time.Hour) // 間隔刻意拉長：測試期間背景不會寫入
+	ts := httptest.NewServer(NewServer(b, p.MarkDirty).Router())
+	defer ts.Close()
+
+	for i := 0; i < 200; i++ {
+		doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 200, nil)
+	}
+	if n := saves.Load(); n != 0 {
+		t.Fatalf("saves during requests=%d want 0", n)
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := storage.LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := saves.Load(); n != 1 || snap.Accounts[0].Balance != 200 {
+		t.Fatalf("saves=%d balance=%d, want one save with balance 200", n, snap.Accounts[0].Balance)
+	}
+}
+
+// memStore 為測試用的記憶體 storage.Store，記錄每次 Save 的快照。
+type memStore struct {
+	mu    sync.Mutex
+	saved []storage.Snapshot
+}
+
+func (m *memStore) Load() (storage.Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.saved) == 0 {
+		return storage.Snapshot{}, errors.New("empty store")
+	}
+	return m.saved[len(m.saved)-1], nil
+}
+
+func (m *memStore) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.saved)
+}
+
+func (m *memStore) Save(s storage.Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.saved = append(m.saved, s)
+	return nil
+}
+
+// TestPersistThroughStore 驗證 persist 鉤子依賴 storage.Store 介面：
+// 成功存款後 Save 被呼叫且快照含最新餘額；失敗的存款不觸發 Save。
+func TestPersistThroughStore(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	st := &memStore{}
+	ts := httptest.NewServer(NewServer(b, storage.SaveFunc(st, b.Snapshot)).Router())
+	defer ts.Close()
+
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 50}, 200, nil)
+	snap, err := st.Load()
+	if err != nil || st.count() != 1 || snap.Accounts[0].Balance != 150 {
+		t.Fatalf("saves=%d err=%v, want one save with balance 150", st.count(), err)
+	}
+
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": -1}, 400, nil)
+	if st.count() != 1 {
+		t.Fatalf("failed deposit triggered Save")
+	}
+}
+
+// TestCreateAccountLocation 驗證 POST /accounts 回傳的 Location 與 links.self 指向新帳戶，
+// 並依請求路徑保留或省略 /api/v1 前綴；跟隨 Location 可取得同一帳戶。
+func TestCreateAccountLocation(t *testing.T) {
+	ts := httptest.NewServer(NewServer(bank.NewBank(), nil).Router())
+	defer ts.Close()
+
+	for _, prefix := range []string{"/api/v1", ""} {
+		resp, err := http.Post(ts.URL+prefix+"/accounts", "application/json", strings.NewReader(`{"name":"A","balance":10}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body struct {
+			ID    string            `json:"id"`
+			Links map[string]string `json:"links"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		want := prefix + "/accounts/" + body.ID
+		if resp.StatusCode != http.StatusCreated || body.ID == "" {
+			t.Fatalf("%q: code=%d id=%q", prefix, resp.StatusCode, body.ID)
+		}
+		if loc := resp.Header.Get("Location"); loc != want || body.Links["self"] != want {
+			t.Fatalf("%q: Location=%q self=%q want %q", prefix, loc, body.Links["self"], want)
+		}
+		var got bank.Account
+		doJSON(t, ts.Client(), "GET", ts.URL+want, nil, 200, &got)
+		if got.ID != body.ID {
+			t.Fatalf("%q: followed Location got account %q want %q", prefix, got.ID, body.ID)
+		}
+	}
+}
+
+// TestAccountBalance 確認 GET /accounts/{id}/balance 只回傳 id 與 balance，存款後餘額正確；不存在的帳戶 404。
+func TestAccountBalance(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 50}, http.StatusOK, nil)
+	var got map[string]any
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts/"+a.ID+"/balance", nil, http.StatusOK, &got)
+	if len(got) != 2 || got["id"] != a.ID || got["balance"] != float64(150) {
+		t.Fatalf("balance resp=%v want {id:%s balance:150}", got, a.ID)
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts/999/balance", nil, http.StatusNotFound, nil)
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/balance", nil, http.StatusMethodNotAllowed, nil)
+}
+
+// TestAccountExternalRef 驗證：POST /accounts 接受 external_ref 並出現在回應中；
+// 重複的參照回傳 409（duplicate_ref）；GET /accounts?ref= 依參照查回帳戶，查無時 404。
+func TestAccountExternalRef(t *testing.T) {
+	b := bank.NewBank()
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var created map[string]any
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts", map[string]any{"name": "A", "balance": 100, "external_ref": "user-42"}, http.StatusCreated, &created)
+	if created["external_ref"] != "user-42" {
+		t.Fatalf("created=%v want external_ref user-42", created)
+	}
+	var errResp map[string]any
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts", map[string]any{"name": "B", "external_ref": "user-42"}, http.StatusConflict, &errResp)
+	if errResp["code"] != "duplicate_ref" {
+		t.Fatalf("duplicate resp=%v want code duplicate_ref", errResp)
+	}
+
+	var got map[string]any
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts?ref=user-42", nil, http.StatusOK, &got)
+	if got["id"] != created["id"] || got["balance"] != float64(100) {
+		t.Fatalf("lookup=%v want account %v", got, created["id"])
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts?ref=nobody", nil, http.StatusNotFound, nil)
+}
+
+// TestDepositWithdrawNote 驗證存款 / 提款可附上 note，寫入日誌；未附時沿用預設備註。
+func TestDepositWithdrawNote(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	base := ts.URL + "/accounts/" + a.ID
+
+	doJSON(t, ts.Client(), "POST", base+"/deposit", map[string]any{"amount": 50, "note": "cash deposit branch 12"}, http.StatusOK, nil)
+	doJSON(t, ts.Client(), "POST", base+"/withdraw", map[string]any{"amount": 20, "note": "chargeback"}, http.StatusOK, nil)
+	doJSON(t, ts.Client(), "POST", base+"/deposit", map[string]any{"amount": 1}, http.StatusOK, nil)
+
+	var resp struct {
+		Logs []struct {
+			Type string `json:"type"`
+			Note string `json:"note"`
+		} `json:"logs"`
+	}
+	doJSON(t, ts.Client(), "GET", base+"/logs", nil, http.StatusOK, &resp)
+	if len(resp.Logs) != 3 ||
+		resp.Logs[0].Type != "deposit" || resp.Logs[0].Note != "cash deposit branch 12" ||
+		resp.Logs[1].Type != "withdraw" || resp.Logs[1].Note != "chargeback" ||
+		resp.Logs[2].Note != "deposit" {
+		t.Fatalf("logs=%+v", resp.Logs)
+	}
+}
+
+// TestDepositWithdrawMissingAccount 驗證帳戶不存在時，存款 / 提款一律回傳 404，
+// 與請求主體是否合法無關（帳戶檢查先於主體解析）。
+func TestDepositWithdrawMissingAccount(t *testing.T) {
+	b := bank.NewBank()
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	bodies := []string{
+		`{"amount": 50}`,
+		`{"amount": -5}`,
+		`{"amount": "fifty"}`,
+		`{not json`,
+		``,
+	}
+	for _, op := range []string{"deposit", "withdraw"} {
+		for _, body := range bodies {
+			resp, err := ts.Client().Post(ts.URL+"/accounts/999/"+op, "application/json", strings.NewReader(body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var e errorBody
+			_ = json.NewDecoder(resp.Body).Decode(&e)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusNotFound || e.Code != "not_found" {
+				t.Errorf("%s body=%q code=%d (%q) want 404 not_found", op, body, resp.StatusCode, e.Code)
+			}
+		}
+	}
+}
+
+// TestCreateNegativeBalanceCode 驗證開戶負餘額與零金額存款回傳不同的錯誤代碼（皆為 400）。
+func TestCreateNegativeBalanceCode(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/accounts", map[string]any{"name": "B", "balance": -1}, 400, &e)
+	if e.Code != "negative_balance" {
+		t.Fatalf("create code=%q want negative_balance", e.Code)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 0}, 400, &e)
+	if e.Code != "bad_amount" {
+		t.Fatalf("deposit code=%q want bad_amount", e.Code)
+	}
+}
+
+// TestInsufficientStatus 驗證餘額不足在各端點一律回傳 409（insufficient_balance），
+// 提款與轉帳的錯誤對應一致（見 httpStatusFor）。
+func TestInsufficientStatus(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 10)
+	a2, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/withdraw", map[string]any{"amount": 11}, http.StatusConflict, &e)
+	if e.Code != "insufficient_balance" {
+		t.Fatalf("withdraw code=%q", e.Code)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 11}, http.StatusConflict, &e)
+	if e.Code != "insufficient_balance" {
+		t.Fatalf("transfer code=%q", e.Code)
+	}
+	if got, _ := b.Get(a1.ID); got.Balance != 10 {
+		t.Fatalf("balance=%d want 10", got.Balance)
+	}
+}
+
+// TestHTTPStatusFor 驗證領域錯誤（含包裝後的錯誤）對應的 HTTP 狀態碼；未知錯誤為 500。
+func TestHTTPStatusFor(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{bank.ErrInsufficient, http.StatusConflict},
+		{bank.ErrDailyLimitExceeded, http.StatusTooManyRequests},
+		{bank.ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("leg 2: %w", bank.ErrNotFound), http.StatusNotFound},
+		{bank.ErrBadAmount, http.StatusBadRequest},
+		{bank.ErrAccountFrozen, http.StatusLocked},
+		{bank.ErrFrozen, http.StatusServiceUnavailable},
+		{bank.ErrVersionMismatch, http.StatusPreconditionFailed},
+		{bank.ErrNonZeroBalance, http.StatusConflict},
+		{errors.New("unexpected"), http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		if got := httpStatusFor(c.err); got != c.want {
+			t.Errorf("httpStatusFor(%v)=%d want %d", c.err, got, c.want)
+		}
+	}
+}
+
+// TestTransferCreateTo 驗證 POST /transfer?create_to=true：成功時開立新帳戶並轉入金額（201 + Location）；
+// 餘額不足回傳 409 且不留下新帳戶；同時指定 To 回傳 400。
+func TestTransferCreateTo(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	saves := 0
+	ts := httptest.NewServer(NewServer(b, func() error { saves++; return nil }).Router())
+	defer ts.Close()
+	cli := ts.Client()
+
+	body, _ := json.Marshal(map[string]any{"From": a.ID, "Amount": 30, "to_name": "Bob"})
+	resp, err := cli.Post(ts.URL+"/transfer?create_to=true", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		TxID string         `json:"tx_id"`
+		From map[string]any `json:"from"`
+		To   map[string]any `json:"to"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&out)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Location") != "/accounts/2" {
+		t.Fatalf("code=%d location=%q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if out.TxID == "" || out.From["balance"] != float64(70) || out.To["id"] != "2" || out.To["name"] != "Bob" || out.To["balance"] != float64(30) {
+		t.Fatalf("resp=%+v", out)
+	}
+	if saves != 1 {
+		t.Fatalf("persist calls=%d want 1", saves)
+	}
+
+	// ❌ 餘額不足：不留下新帳戶
+	var e errorBody
+	doJSON(t, cli, "POST", ts.URL+"/transfer?create_to=true", map[string]any{"From": a.ID, "Amount": 71, "to_name": "Carol"}, http.StatusConflict, &e)
+	if e.Code != "insufficient_balance" {
+		t.Fatalf("code=%q", e.Code)
+	}
+	if n := len(b.List()); n != 2 {
+		t.Fatalf("accounts=%d want 2", n)
+	}
+	doJSON(t, cli, "POST", ts.URL+"/transfer?create_to=true", map[string]any{"From": a.ID, "To": "2", "Amount": 1, "to_name": "Carol"}, http.StatusBadRequest, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a.ID, "To": "2", "Amount": 1, "to_name": "Carol"}, http.StatusBadRequest, nil)
+	doJSON(t, cli, "POST", ts.URL+"/transfer?create_to=maybe", map[string]any{"From": a.ID, "Amount": 1, "to_name": "Carol"}, http.StatusBadRequest, nil)
+}
+
+// TestLogJSONOmitsEmptyFields 驗證日誌 JSON 省略空的 counter_account：存款日誌沒有該鍵，轉帳日誌有。
+func TestLogJSONOmitsEmptyFields(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 0)
+	a2, _ := b.Create("B", 0)
+	_, _ = b.Deposit(a1.ID, bank.NewMoney(50, "USD"), "")
+	_ = b.Transfer(a1.ID, a2.ID, bank.NewMoney(20, "USD"))
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	var resp struct {
+		Logs []map[string]any `json:"logs"`
+	}
+	doJSON(t, ts.Client(), "GET", ts.URL+"/accounts/"+a1.ID+"/logs", nil, http.StatusOK, &resp)
+	if len(resp.Logs) != 2 {
+		t.Fatalf("logs=%v", resp.Logs)
+	}
+	if _, ok := resp.Logs[0]["counter_account"]; ok {
+		t.Fatalf("deposit log has counter_account: %v", resp.Logs[0])
+	}
+	if resp.Logs[1]["counter_account"] != a2.ID {
+		t.Fatalf("transfer log=%v want counter_account %s", resp.Logs[1], a2.ID)
+	}
+}
+
+// TestAccountIncludeLogs 驗證 GET /accounts/{id}?include=logs 內嵌（可分頁的）日誌；未帶參數時回應不含 logs。
+func TestAccountIncludeLogs(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	for i := 1; i <= 3; i++ {
+		_, _ = b.Deposit(a.ID, bank.NewMoney(int64(i), "USD"), "")
+	}
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	cli := ts.Client()
+	base := ts.URL + "/accounts/" + a.ID
+
+	var plain map[string]any
+	doJSON(t, cli, "GET", base, nil, http.StatusOK, &plain)
+	if _, ok := plain["logs"]; ok || plain["balance"] != float64(6) {
+		t.Fatalf("plain=%v", plain)
+	}
+
+	var full struct {
+		Balance   int64             `json:"balance"`
+		Logs      []bank.Log        `json:"logs"`
+		LogsTotal int               `json:"logs_total"`
+		Links     map[string]string `json:"links"`
+	}
+	doJSON(t, cli, "GET", base+"?include=logs", nil, http.StatusOK, &full)
+	if full.Balance != 6 || full.LogsTotal != 3 || len(full.Logs) != 3 || full.Logs[2].Amount != 3 {
+		t.Fatalf("include=logs: %+v", full)
+	}
+	doJSON(t, cli, "GET", base+"?include=logs&offset=1&limit=1", nil, http.StatusOK, &full)
+	if full.LogsTotal != 3 || len(full.Logs) != 1 || full.Logs[0].Amount != 2 || full.Links["next"] == "" {
+		t.Fatalf("paged: %+v", full)
 	}
+	doJSON(t, cli, "GET", base+"?include=transfers", nil, http.StatusBadRequest, nil)
 }
@@ -0,0 +1,24 @@
+// internal/server/shutdown.go
+//
+// 本檔協調關機順序：先停止 HTTP 伺服器並等待進行中的請求完成，最後才寫入快照。
+// 若反過來先保存再關閉，保存之後才完成的請求（例如一筆轉帳）不會出現在快照中。
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Shutdown 依序優雅關閉：
+//  1. srv.Shutdown(ctx)：停止接受新連線，等待進行中的請求完成，最多等到 ctx 逾時；
+//  2. final（例如寫入最後一份快照）。
+//
+// final 一律執行（即使等待逾時，也要盡量保存已完成的異動）；兩者的錯誤合併回傳。
+func Shutdown(ctx context.Context, srv *http.Server, final func() error) error {
+	err := srv.Shutdown(ctx)
+	if final != nil {
+		err = errors.Join(err, final())
+	}
+	return err
+}
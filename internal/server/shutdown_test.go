@@ -0,0 +1,120 @@
+// internal/server/shutdown_test.go
+//
+// 測試關機順序：進行中的請求完成之後才執行最後的保存；等待逾時時仍會保存並回報逾時錯誤。
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingServer 啟動一個 handler 會等待 release 才回應的 http.Server，並記錄事件順序。
+type blockingServer struct {
+	srv     *http.Server
+	url     string
+	started chan struct{}
+	release chan struct{}
+
+	mu     sync.Mutex
+	events []string
+}
+
+func newBlockingServer(t *testing.T) *blockingServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bs := &blockingServer{url: "http://" + ln.Addr().String(), started: make(chan struct{}), release: make(chan struct{})}
+	bs.srv = &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(bs.started)
+		<-bs.release
+		bs.record("request")
+		w.WriteHeader(http.StatusOK)
+	})}
+	go func() { _ = bs.srv.Serve(ln) }()
+	return bs
+}
+
+func (bs *blockingServer) record(e string) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	bs.events = append(bs.events, e)
+}
+
+func (bs *blockingServer) snapshot() []string {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	return append([]string(nil), bs.events...)
+}
+
+func TestShutdownDrainsBeforePersist(t *testing.T) {
+	bs := newBlockingServer(t)
+
+	// 1️⃣ 發出一個會卡住的請求
+	reqDone := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(bs.url)
+		if err != nil {
+			reqDone <- 0
+			return
+		}
+		resp.Body.Close()
+		reqDone <- resp.StatusCode
+	}()
+	<-bs.started
+
+	// 2️⃣ 開始關機：請求尚未完成前不得保存
+	shutDone := make(chan error, 1)
+	go func() {
+		shutDone <- Shutdown(context.Background(), bs.srv, func() error { bs.record("persist"); return nil })
+	}()
+	select {
+	case err := <-shutDone:
+		t.Fatalf("shutdown returned before in-flight request finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if ev := bs.snapshot(); len(ev) != 0 {
+		t.Fatalf("events before release=%v want none", ev)
+	}
+
+	// 3️⃣ 放行請求：請求正常完成，之後才保存
+	close(bs.release)
+	if code := <-reqDone; code != http.StatusOK {
+		t.Fatalf("in-flight request code=%d want 200", code)
+	}
+	if err := <-shutDone; err != nil {
+		t.Fatalf("shutdown err=%v", err)
+	}
+	if ev := bs.snapshot(); len(ev) != 2 || ev[0] != "request" || ev[1] != "persist" {
+		t.Fatalf("events=%v want [request persist]", ev)
+	}
+}
+
+func TestShutdownTimeoutStillPersists(t *testing.T) {
+	bs := newBlockingServer(t)
+	defer close(bs.release)
+
+	go func() {
+		if resp, err := http.Get(bs.url); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-bs.started
+
+	// ❌ 請求一直未完成 → 逾時錯誤，但仍執行保存
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := Shutdown(ctx, bs.srv, func() error { bs.record("persist"); return nil })
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err=%v want deadline exceeded", err)
+	}
+	if ev := bs.snapshot(); len(ev) != 1 || ev[0] != "persist" {
+		t.Fatalf("events=%v want [persist]", ev)
+	}
+}
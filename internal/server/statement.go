@@ -0,0 +1,32 @@
+// internal/server/statement.go
+//
+// 本檔提供 GET /accounts/{id}/statement?from=&to=：帳戶在期間內的對帳單（見 bank.Statement），
+// 含期初 / 期末餘額、存款、提款、轉入、轉出合計與期間內的日誌。
+// from / to 的格式與 netflow 相同（RFC 3339 或日期），省略時該端不設限；期間為 [from, to)。
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// statement 處理 GET /accounts/{id}/statement。
+func (s *Server) statement(w http.ResponseWriter, r *http.Request, id string) {
+	q := r.URL.Query()
+	from, err := parseTimeParam(q.Get("from"))
+	if err != nil {
+		writeErr(w, fmt.Errorf("from: %w", err), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(q.Get("to"))
+	if err != nil {
+		writeErr(w, fmt.Errorf("to: %w", err), http.StatusBadRequest)
+		return
+	}
+	st, err := s.Bank.Statement(id, from, to)
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	writeJSON(w, http.StatusOK, st)
+}
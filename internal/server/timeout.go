@@ -0,0 +1,151 @@
+// internal/server/timeout.go
+//
+// 本檔實作請求逾時中介層：每個請求的 context 帶有截止時間（WithRequestTimeout，預設 30 秒），
+// 超過時立即回傳 503 JSON（"code":"timeout"），不讓卡住的保存或外部呼叫無限期占用連線。
+//   - handler 在另一個 goroutine 執行，輸出先寫入緩衝，於時限內完成才送出；逾時後的輸出一律丟棄。
+//   - 逾時只代表「回應來不及送出」：已套用的異動不會回滾（與保存失敗的 not_persisted 相同），
+//     帶 Idempotency-Key 重送可取得實際結果（見 idempotency.go）。
+//   - 尚未開始的異動會檢查 context（見 checkCtx），請求已逾時或用戶端已斷線時不再套用。
+//   - 事件串流（GET /accounts/{id}/events）為長連線，不受此限制。
+//   - 匯出（logs.csv / .ndjson / .ofx、accounts.csv 與 /admin/export）不經緩衝：大型匯出須維持串流輸出
+//     （見 body.go），緩衝會讓整份回應留在記憶體中；這些請求只帶截止時間的 context，逾時不會改寫為 503。
+//   - handler 的 panic 會轉回原 goroutine 再次拋出，交由 recoverMiddleware（見 recover.go）處理；
+//     已逾時（503 已送出、原 goroutine 已返回）才發生的 panic 無處可拋，改在執行 handler 的 goroutine 內記錄堆疊。
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRequestTimeout 為未設定 WithRequestTimeout 時的請求時限。
+const defaultRequestTimeout = 30 * time.Second
+
+// errTimeout 為請求超過時限的錯誤。
+var errTimeout = errors.New("request timed out")
+
+// WithRequestTimeout 設定每個請求的時限；d <= 0 代表不限制。
+func WithRequestTimeout(d time.Duration) Option {
+	return func(s *Server) { s.timeout = d }
+}
+
+// checkCtx 於套用異動前檢查請求 context：已逾時或已取消時回傳包裝 errTimeout 的錯誤。
+func checkCtx(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %v", errTimeout, err)
+	}
+	return nil
+}
+
+// timeoutMiddleware 為請求加上時限，逾時回傳 503。
+func (s *Server) timeoutMiddleware(next http.Handler) http.Handler {
+	if s.timeout <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/events") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), s.timeout)
+		defer cancel()
+		if streamingRoute(r.URL.Path) {
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
+		tw := &timeoutWriter{h: make(http.Header), code: http.StatusOK}
+		done := make(chan struct{})
+		panicked := make(chan any, 1)
+		go func() {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				// 與逾時分支在同一把鎖下判斷：逾時前交給原 goroutine，逾時後就地記錄
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				if !tw.timedOut {
+					panicked <- v
+				} else if v != http.ErrAbortHandler {
+					s.logPanic(r, v, debug.Stack())
+				}
+			}()
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case v := <-panicked:
+			panic(v)
+		case <-done:
+			tw.mu.Lock()
+			defer tw.mu.Unlock()
+			for k, v := range tw.h {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(tw.code)
+			_, _ = w.Write(tw.buf.Bytes())
+		case <-ctx.Done():
+			tw.mu.Lock()
+			tw.timedOut = true
+			tw.mu.Unlock()
+			select {
+			case v := <-panicked: // 與逾時同時發生的 panic 仍交由 recoverMiddleware 處理
+				panic(v)
+			default:
+			}
+			writeErr(w, errTimeout, http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// streamingRoute 回報路徑是否為直接串流輸出、不經逾時緩衝的匯出端點。
+func streamingRoute(path string) bool {
+	for _, suffix := range []string{".csv", ".ndjson", ".ofx", "/admin/export"} {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutWriter 緩衝 handler 的輸出；逾時後的寫入回傳 http.ErrHandlerTimeout。
+type timeoutWriter struct {
+	mu          sync.Mutex
+	h           http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header { return tw.h }
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.code = code
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.wroteHeader = true
+	return tw.buf.Write(p)
+}
@@ -0,0 +1,119 @@
+// internal/server/timeout_test.go
+//
+// 測試請求逾時：保存卡住時請求於時限後回傳 503 JSON，且不會等待保存完成；逾時後 handler 才 panic 仍會記錄。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// TestRequestTimeout 以刻意卡住的 persist hook 存款，確認請求於時限後回傳 503 + "code":"timeout"；
+// 不需保存的查詢不受影響。
+func TestRequestTimeout(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 100)
+	release := make(chan struct{})
+	defer close(release)
+	persist := func() error {
+		<-release
+		return nil
+	}
+	ts := httptest.NewServer(NewServer(b, persist, WithRequestTimeout(50*time.Millisecond)).Router())
+	defer ts.Close()
+
+	req, _ := http.NewRequest("POST", ts.URL+"/accounts/"+a.ID+"/deposit", bytes.NewBufferString(`{"amount":10}`))
+	req.Header.Set("Content-Type", "application/json")
+	start := time.Now()
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var e errorBody
+	_ = json.NewDecoder(resp.Body).Decode(&e)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || e.Code != "timeout" {
+		t.Fatalf("code=%d body=%+v want 503 timeout", resp.StatusCode, e)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Fatalf("request took %s; did not time out", d)
+	}
+
+	// ✅ 查詢不經過保存，正常回應
+	resp, err = ts.Client().Get(ts.URL + "/accounts/" + a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET code=%d want 200", resp.StatusCode)
+	}
+}
+
+// TestRequestTimeoutSkipsExports 驗證匯出與事件串流不經逾時緩衝（handler 直接寫入原本的 ResponseWriter），
+// 一般端點仍經緩衝；匯出的 context 仍帶有截止時間。
+func TestRequestTimeoutSkipsExports(t *testing.T) {
+	s := NewServer(bank.NewBank(), nil, WithRequestTimeout(time.Minute))
+	for path, wantBuffered := range map[string]bool{
+		"/accounts/1/logs.csv":    false,
+		"/accounts/1/logs.ndjson": false,
+		"/accounts/1/logs.ofx":    false,
+		"/accounts.csv":           false,
+		"/admin/export":           false,
+		"/api/v1/admin/export":    false,
+		"/accounts/1/events":      false,
+		"/accounts/1":             true,
+		"/transfer":               true,
+	} {
+		var buffered, deadline bool
+		h := s.timeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, buffered = w.(*timeoutWriter)
+			_, deadline = r.Context().Deadline()
+		}))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		if buffered != wantBuffered {
+			t.Errorf("%s: buffered=%v want %v", path, buffered, wantBuffered)
+		}
+		if !deadline && path != "/accounts/1/events" {
+			t.Errorf("%s: context has no deadline", path)
+		}
+	}
+}
+
+// TestRequestTimeoutLatePanic 驗證 handler 在逾時（503 已送出）之後才 panic 時，
+// panic 不會使程序崩潰，而是連同堆疊記錄到請求日誌。
+func TestRequestTimeoutLatePanic(t *testing.T) {
+	out := &syncBuffer{}
+	s := NewServer(bank.NewBank(), nil, WithRequestTimeout(20*time.Millisecond), WithLogger(log.New(out, "", 0)))
+	responded := make(chan struct{})
+	h := s.recoverMiddleware(s.timeoutMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		<-responded
+		panic("late boom")
+	})))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/transfer", nil))
+	close(responded)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("code=%d want 503", rec.Code)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(strings.Join(out.lines(), "\n"), "late boom") {
+		if time.Now().After(deadline) {
+			t.Fatalf("late panic not logged: %q", out.lines())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if logs := strings.Join(out.lines(), "\n"); !strings.Contains(logs, "path=/transfer") || !strings.Contains(logs, "goroutine") {
+		t.Fatalf("panic log missing request or stack: %q", logs)
+	}
+}
@@ -0,0 +1,77 @@
+// internal/server/transfers.go
+//
+// 本檔提供大額轉帳的審核端點（管理者權限）：
+//   - POST /transfers/{txID}/approve → 核准並過帳，回傳已提交的交易（若啟用則附收據）
+//   - POST /transfers/{txID}/reject  → 駁回並釋放保留資金
+//
+// 以及已過帳轉帳的沖正（管理者權限）：
+//   - POST /transactions/{txID}/reverse → 由原收款方退回原付款方，回傳沖正交易（若啟用則附收據）
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"banking/internal/bank"
+)
+
+// transferReview 處理 /transfers/{txID}/{approve|reject}。
+func (s *Server) transferReview(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/transfers/"), "/"), "/")
+	if len(parts) != 2 || parts[0] == "" {
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	txID := parts[0]
+
+	switch parts[1] {
+	case "approve":
+		tx, err := s.Bank.ApproveTransfer(txID)
+		if err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		resp := map[string]any{"tx": tx}
+		if rc := s.receipt(tx); rc != nil {
+			resp["receipt"] = rc
+		}
+		if !s.persisted(w) {
+			return
+		}
+		writeJSON(w, http.StatusOK, resp)
+	case "reject":
+		if err := s.Bank.RejectTransfer(txID); err != nil {
+			writeErr(w, err, httpStatusFor(err))
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"tx_id": txID, "status": "rejected"})
+	default:
+		writeErr(w, errRouteNotFound, http.StatusNotFound)
+	}
+}
+
+// reverseTx 處理 POST /transactions/{txID}/reverse：沖正已過帳的轉帳（見 bank.Reverse）。
+// 交易不存在 404；不是轉帳、已沖正過或原收款方餘額不足 409。
+func (s *Server) reverseTx(w http.ResponseWriter, r *http.Request, txID string) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	tx, err := s.apply(r.Context(), bank.Op{Type: bank.TxReversal, Ref: txID})
+	if err != nil {
+		writeErr(w, err, httpStatusFor(err))
+		return
+	}
+	resp := map[string]any{"tx": tx}
+	if rc := s.receipt(tx); rc != nil {
+		resp["receipt"] = rc
+	}
+	if !s.persisted(w) {
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,63 @@
+// internal/server/v2.go
+//
+// 本檔為 API v2 的端點（掛載於 /api/v2，見 router.go）。v2 的請求與回應一律使用 snake_case 欄位，
+// v1 維持原格式不變以保持相容。
+//
+//	POST /api/v2/transfer  → 請求 {"from","to","amount"}
+//	                          回應 {"tx_id","from","to","amount","fee"}（若啟用則附上收據）
+//
+// fee 為該筆交易實際收取的手續費（而非目前的費率設定）；大額轉帳進入審核時回傳 202，
+// 並附 "status":"pending_review"，此時 fee 為 0（手續費於核准過帳時才收取）。
+// 轉帳邏輯與 v1 共用 execTransfer，兩版本經由同一個 bank 操作執行。
+package server
+
+import (
+	"net/http"
+
+	"banking/internal/bank"
+)
+
+// transferV2Response 為 v2 轉帳的回應；金額表示方式同其他回應（見 money.go）。
+type transferV2Response struct {
+	TxID    string   `json:"tx_id"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Amount  amount   `json:"amount"`
+	Fee     amount   `json:"fee"`
+	Status  string   `json:"status,omitempty"`
+	Receipt *Receipt `json:"receipt,omitempty"`
+}
+
+// transferV2 處理 POST /api/v2/transfer。
+func (s *Server) transferV2(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErr(w, errMethodNotAllowed, http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		From   string `json:"from"`
+		To     string `json:"to"`
+		Amount int64  `json:"amount"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	tx, ok := s.execTransfer(w, r, req.From, req.To, req.Amount)
+	if !ok {
+		return
+	}
+	resp := transferV2Response{
+		TxID: tx.ID, From: tx.From, To: tx.To,
+		Amount: s.money(tx.Amount), Fee: s.money(tx.Fee),
+	}
+	if !s.persisted(w) {
+		return
+	}
+	if tx.Status == bank.TxPendingReview {
+		resp.Status = tx.Status
+		writeJSON(w, http.StatusAccepted, resp)
+		return
+	}
+	resp.Receipt = s.receipt(tx)
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,89 @@
+// internal/server/v2_test.go
+//
+// 測試 API v2 轉帳：v1 與 v2 以各自的欄位格式轉帳，錯誤對應一致；
+// v2 回報實際收取的手續費，且 Idempotency-Key 不跨版本重播。
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// TestTransferV1AndV2 分別經 /api/v1/transfer（From / To / Amount）與 /api/v2/transfer（snake_case）轉帳，
+// 確認兩者回應格式與最終餘額；v2 回應附手續費。
+func TestTransferV1AndV2(t *testing.T) {
+	b := bank.NewBankWithFee(2)
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+	c := ts.Client()
+
+	// 1️⃣ v1：欄位與回應維持原格式
+	var v1 struct {
+		Message string       `json:"message"`
+		TxID    string       `json:"tx_id"`
+		From    bank.Account `json:"from"`
+	}
+	doJSON(t, c, "POST", ts.URL+"/api/v1/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 10}, http.StatusOK, &v1)
+	if v1.Message != "transfer success" || v1.TxID == "" || v1.From.Balance != 88 {
+		t.Fatalf("v1 resp=%+v", v1)
+	}
+
+	// 2️⃣ v2：snake_case 請求，回應 {tx_id, from, to, amount, fee}
+	var v2 map[string]any
+	doJSON(t, c, "POST", ts.URL+"/api/v2/transfer", map[string]any{"from": a1.ID, "to": a2.ID, "amount": 20}, http.StatusOK, &v2)
+	if v2["tx_id"] == "" || v2["tx_id"] == v1.TxID || v2["from"] != a1.ID || v2["to"] != a2.ID ||
+		v2["amount"] != float64(20) || v2["fee"] != float64(2) {
+		t.Fatalf("v2 resp=%v", v2)
+	}
+	if got, _ := b.Get(a2.ID); got.Balance != 30 {
+		t.Fatalf("receiver balance=%d want 30", got.Balance)
+	}
+
+	// ❌ v2 的錯誤對應與 v1 相同：餘額不足 409、未知欄位 400
+	doJSON(t, c, "POST", ts.URL+"/api/v2/transfer", map[string]any{"from": a1.ID, "to": a2.ID, "ammount": 1}, http.StatusBadRequest, nil)
+	doJSON(t, c, "POST", ts.URL+"/api/v2/transfer", map[string]any{"from": a1.ID, "to": a2.ID, "amount": 1000}, http.StatusConflict, nil)
+}
+
+// TestTransferV2FeeAndIdempotency 驗證：
+// 1️⃣ 進入審核的大額轉帳回報 fee 0（手續費尚未收取），而非目前的費率設定，且回應前已保存；
+// 2️⃣ 同一 Idempotency-Key 與相同本文先用於 v1、再用於 v2 → 409，不會把 v1 格式的回應重播給 v2。
+func TestTransferV2FeeAndIdempotency(t *testing.T) {
+	b := bank.NewBankWithFee(2)
+	b.SetTransferHold(50, time.Hour)
+	a1, _ := b.Create("A", 1000)
+	a2, _ := b.Create("B", 0)
+	persists := 0
+	ts := httptest.NewServer(NewServer(b, func() error { persists++; return nil }).Router())
+	defer ts.Close()
+
+	var pending map[string]any
+	doJSON(t, ts.Client(), "POST", ts.URL+"/api/v2/transfer", map[string]any{"from": a1.ID, "to": a2.ID, "amount": 100}, http.StatusAccepted, &pending)
+	if pending["status"] != bank.TxPendingReview || pending["fee"] != float64(0) || persists != 1 {
+		t.Fatalf("pending resp=%v persists=%d", pending, persists)
+	}
+
+	body := map[string]any{"from": a1.ID, "to": a2.ID, "amount": 10}
+	if code, _, _ := postWithKey(t, ts.URL+"/api/v1/transfer", "xfer-1", body); code != http.StatusOK {
+		t.Fatalf("v1 code=%d", code)
+	}
+	code, out, replay := postWithKey(t, ts.URL+"/api/v2/transfer", "xfer-1", body)
+	if code != http.StatusConflict || replay {
+		t.Fatalf("v2 reuse code=%d replay=%v body=%s", code, replay, out)
+	}
+	code, out, _ = postWithKey(t, ts.URL+"/api/v2/transfer", "xfer-2", body)
+	var v2 map[string]any
+	_ = json.Unmarshal([]byte(out), &v2)
+	if code != http.StatusOK || v2["fee"] != float64(2) {
+		t.Fatalf("v2 code=%d resp=%s", code, out)
+	}
+	if got := get(t, b, a2.ID).Balance; got != 20 {
+		t.Fatalf("receiver balance=%d want 20", got)
+	}
+}
@@ -0,0 +1,64 @@
+// internal/server/version.go
+//
+// 本檔實作帳戶版本的 HTTP 介面（樂觀並行控制，見 bank/version.go）：
+//   - GET /accounts/{id} 與存提款回應帶 ETag: "<version>"，回應本文亦含 "version"；
+//   - 存款、提款、轉帳可帶 If-Match: "<version>"，版本不符回傳 412（code version_mismatch）且不執行。
+//     轉帳比對的是付款方（From）的版本。
+//
+// If-Match 接受帶引號或不帶引號的版本號，以及弱驗證器 W/"<version>"；"*" 視同未指定。
+// 不帶 If-Match 的請求維持原本行為。
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"banking/internal/bank"
+)
+
+// errBadIfMatch 表示 If-Match 標頭不是單一版本號。
+var errBadIfMatch = errors.New("If-Match must be a single account version")
+
+// etag 回傳帳戶版本的 ETag 值（強驗證器，含引號）。
+func etag(a *bank.Account) string {
+	return strconv.Quote(strconv.FormatInt(a.Version, 10))
+}
+
+// setETag 於回應標頭寫入帳戶的 ETag；須在 writeJSON 之前呼叫。
+func setETag(w http.ResponseWriter, a *bank.Account) {
+	if a != nil {
+		w.Header().Set("ETag", etag(a))
+	}
+}
+
+// ifMatchVersion 解析 If-Match；ok 為 false 代表未指定（或為 "*"）。
+func ifMatchVersion(r *http.Request) (version int64, ok bool, err error) {
+	v := strings.TrimSpace(r.Header.Get("If-Match"))
+	if v == "" || v == "*" {
+		return 0, false, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	version, err = strconv.ParseInt(v, 10, 64)
+	if err != nil || version < 0 {
+		return 0, false, errBadIfMatch
+	}
+	return version, true, nil
+}
+
+// applyIfMatch 同 apply，但請求帶 If-Match 時改為條件式執行（見 bank.ApplyIfVersion）。
+func (s *Server) applyIfMatch(r *http.Request, op bank.Op) (bank.Tx, error) {
+	version, ok, err := ifMatchVersion(r)
+	if err != nil {
+		return bank.Tx{}, err
+	}
+	if !ok {
+		return s.apply(r.Context(), op)
+	}
+	if err := checkCtx(r.Context()); err != nil {
+		return bank.Tx{}, err
+	}
+	tx, err := s.Bank.ApplyIfVersion(op, version)
+	return s.observe(op, tx, err)
+}
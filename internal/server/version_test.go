@@ -0,0 +1,81 @@
+// internal/server/version_test.go
+//
+// 測試 If-Match 條件式異動：GET 回應帶 ETag 與 version，以正確版本存款成功，
+// 以過期版本提款 / 轉帳回傳 412 且不改變餘額。
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"banking/internal/bank"
+)
+
+// doIfMatch 送出帶 If-Match 的 POST 請求，回傳狀態碼、ETag 與錯誤代碼（若有）。
+func doIfMatch(t *testing.T, url, ifMatch string, body any) (int, string, string) {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	req, _ := http.NewRequest(http.MethodPost, url, bytes.NewReader(raw))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var e errorBody
+	_ = json.NewDecoder(resp.Body).Decode(&e)
+	return resp.StatusCode, resp.Header.Get("ETag"), e.Code
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 1_000)
+	c, _ := b.Create("C", 0)
+	ts := httptest.NewServer(NewServer(b, nil).Router())
+	defer ts.Close()
+
+	// GET 回應帶 ETag 與 version
+	resp, err := http.Get(ts.URL + "/accounts/" + a.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bank.Account
+	_ = json.NewDecoder(resp.Body).Decode(&got)
+	resp.Body.Close()
+	if tag := resp.Header.Get("ETag"); tag != `"0"` || got.Version != 0 {
+		t.Fatalf("ETag=%q version=%d want \"0\" / 0", tag, got.Version)
+	}
+
+	// 以正確版本存款 → 200，新 ETag 為 "1"
+	code, tag, _ := doIfMatch(t, ts.URL+"/accounts/"+a.ID+"/deposit", `"0"`, map[string]int64{"amount": 100})
+	if code != http.StatusOK || tag != `"1"` {
+		t.Fatalf("deposit code=%d ETag=%q want 200 / \"1\"", code, tag)
+	}
+
+	// 以過期版本提款、轉帳 → 412，不執行
+	code, _, errCode := doIfMatch(t, ts.URL+"/accounts/"+a.ID+"/withdraw", `"0"`, map[string]int64{"amount": 100})
+	if code != http.StatusPreconditionFailed || errCode != "version_mismatch" {
+		t.Fatalf("stale withdraw code=%d err=%q want 412 version_mismatch", code, errCode)
+	}
+	code, _, _ = doIfMatch(t, ts.URL+"/transfer", `W/"0"`, map[string]any{"From": a.ID, "To": c.ID, "Amount": 100})
+	if code != http.StatusPreconditionFailed {
+		t.Fatalf("stale transfer code=%d want 412", code)
+	}
+	if acc, _ := b.Get(a.ID); acc.Balance != 1_100 || acc.Version != 1 {
+		t.Fatalf("account=%+v want balance 1100, version 1", acc)
+	}
+
+	// 無法解析的 If-Match → 400；"*" 視同未指定
+	if code, _, errCode = doIfMatch(t, ts.URL+"/accounts/"+a.ID+"/withdraw", "abc", map[string]int64{"amount": 1}); code != http.StatusBadRequest || errCode != "bad_if_match" {
+		t.Fatalf("bad If-Match code=%d err=%q want 400 bad_if_match", code, errCode)
+	}
+	if code, _, _ = doIfMatch(t, ts.URL+"/transfer", "*", map[string]any{"From": a.ID, "To": c.ID, "Amount": 100}); code != http.StatusOK {
+		t.Fatalf("If-Match * transfer code=%d want 200", code)
+	}
+}
@@ -0,0 +1,195 @@
+// internal/server/webhook.go
+//
+// 本檔實作交易 webhook：存款、提款、轉帳成功後，將事件以 JSON POST 到設定的 URL，供外部系統整合。
+//   - 非同步送出：事件放入佇列後即返回，不延遲、也不影響原本的 HTTP 回應；
+//   - 佇列最多保留 webhookQueueMax 筆未送出的事件，由單一背景 goroutine 依提交順序送出；
+//     接收端長時間無回應而佇列已滿時，新事件直接丟棄並記錄日誌，不會無限制佔用記憶體；
+//   - 送出失敗（連線錯誤或非 2xx）時記錄日誌，並以指數退避重試，最多 webhookAttempts 次後放棄；
+//   - 轉帳與沖正（見 transfers.go）對雙方各送一個事件（direction 為 out / in），balance 為該帳戶在交易後的餘額
+//     （取自交易提交當下的 bank.Tx.Balances，不受之後其他交易影響）；
+//   - 待審核的轉帳（見 bank.TxPendingReview）資金尚未移動，不送出事件。
+//
+// 提款去重命中（見 bank.Op.RequestID）回傳的是先前的交易，不再送出事件；
+// 但重試仍可能讓同一事件送出不只一次，接收端應以 tx_id + account 去重。
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// webhook 重試設定與預設 HTTP 逾時。
+const (
+	webhookAttempts = 4
+	webhookBackoff  = 200 * time.Millisecond // 第一次重試前的等待，之後每次加倍
+	webhookTimeout  = 5 * time.Second
+	webhookQueueMax = 10000 // 未送出事件的上限，超過時丟棄新事件
+)
+
+// WebhookEvent 為 webhook 送出的事件內容。
+type WebhookEvent struct {
+	TxID      string    `json:"tx_id"`
+	Type      string    `json:"type"`
+	Account   string    `json:"account"`
+	Direction string    `json:"direction"` // in：入帳；out：扣款
+	Amount    int64     `json:"amount"`
+	Balance   int64     `json:"balance"` // 交易後的帳戶餘額
+	Time      time.Time `json:"time"`
+}
+
+// WithWebhook 啟用交易 webhook，將事件 POST 到 url。
+// client 為 nil 時使用逾時 webhookTimeout 的預設 client；測試可注入自訂的 client。
+func WithWebhook(url string, client *http.Client) Option {
+	return func(s *Server) {
+		if client == nil {
+			client = &http.Client{Timeout: webhookTimeout}
+		}
+		s.webhook = newWebhook(url, client, webhookBackoff)
+	}
+}
+
+// webhook 為事件佇列與背景送出者。
+type webhook struct {
+	url     string
+	client  *http.Client
+	backoff time.Duration
+
+	mu      sync.Mutex
+	queue   []WebhookEvent
+	pending int           // 已入列但尚未送出（或放棄）的事件數
+	wake    chan struct{} // 有新事件時通知背景 goroutine（容量 1，不阻塞）
+}
+
+// newWebhook 建立 webhook 並啟動背景送出 goroutine。
+func newWebhook(url string, client *http.Client, backoff time.Duration) *webhook {
+	w := &webhook{url: url, client: client, backoff: backoff, wake: make(chan struct{}, 1)}
+	go w.run()
+	return w
+}
+
+// enqueue 將事件放入佇列並喚醒背景 goroutine；永不阻塞。佇列已滿時丟棄 events 並記錄日誌。
+func (w *webhook) enqueue(events ...WebhookEvent) {
+	w.mu.Lock()
+	if w.pending+len(events) > webhookQueueMax {
+		w.mu.Unlock()
+		for _, e := range events {
+			log.Printf("webhook: queue full (%d events), dropping tx_id=%s account=%s", webhookQueueMax, e.TxID, e.Account)
+		}
+		return
+	}
+	w.queue = append(w.queue, events...)
+	w.pending += len(events)
+	w.mu.Unlock()
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 依序取出佇列中的事件並送出。
+func (w *webhook) run() {
+	for range w.wake {
+		for {
+			w.mu.Lock()
+			batch := w.queue
+			w.queue = nil
+			w.mu.Unlock()
+			if len(batch) == 0 {
+				break
+			}
+			for _, e := range batch {
+				w.deliver(e)
+				w.mu.Lock()
+				w.pending--
+				w.mu.Unlock()
+			}
+		}
+	}
+}
+
+// deliver 送出單一事件，失敗時記錄日誌並退避重試，最多 webhookAttempts 次。
+func (w *webhook) deliver(e WebhookEvent) {
+	body, _ := json.Marshal(e)
+	wait := w.backoff
+	for attempt := 1; ; attempt++ {
+		err := w.post(body)
+		if err == nil {
+			return
+		}
+		if attempt == webhookAttempts {
+			log.Printf("webhook: giving up on tx_id=%s account=%s after %d attempts: %v", e.TxID, e.Account, attempt, err)
+			return
+		}
+		log.Printf("webhook: tx_id=%s account=%s attempt %d failed: %v", e.TxID, e.Account, attempt, err)
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// post 送出一次請求；連線錯誤或非 2xx 回應皆視為失敗。
+func (w *webhook) post(body []byte) error {
+	resp, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// flush 等待佇列中的事件全部送出（或放棄），最多等到 ctx 結束。
+func (w *webhook) flush(ctx context.Context) error {
+	tick := time.NewTicker(10 * time.Millisecond)
+	defer tick.Stop()
+	for {
+		w.mu.Lock()
+		n := w.pending
+		w.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("webhook: %d events not delivered: %w", n, ctx.Err())
+		case <-tick.C:
+		}
+	}
+}
+
+// FlushWebhooks 等待尚未送出的 webhook 事件送出，最多等到 ctx 結束；未啟用 webhook 時立即返回。
+// 關機時於停止 HTTP 伺服器之後呼叫，避免結束程式時遺失事件。
+func (s *Server) FlushWebhooks(ctx context.Context) error {
+	if s.webhook == nil {
+		return nil
+	}
+	return s.webhook.flush(ctx)
+}
+
+// notifyTx 依已提交的交易產生 webhook 事件並放入佇列；未啟用 webhook、交易待審核，
+// 或交易未附提交當下的餘額（提款去重命中）時不處理。
+func (s *Server) notifyTx(tx bank.Tx) {
+	if s.webhook == nil || tx.Status == bank.TxPendingReview || tx.Balances == nil {
+		return
+	}
+	event := func(id, dir string) WebhookEvent {
+		return WebhookEvent{TxID: tx.ID, Type: tx.Type, Account: id, Direction: dir, Amount: tx.Amount, Balance: tx.Balances[id], Time: tx.Time}
+	}
+	switch tx.Type {
+	case bank.TxDeposit:
+		s.webhook.enqueue(event(tx.Account, "in"))
+	case bank.TxWithdraw, bank.TxCapture:
+		s.webhook.enqueue(event(tx.Account, "out"))
+	case bank.TxTransfer, bank.TxReversal:
+		s.webhook.enqueue(event(tx.From, "out"), event(tx.To, "in"))
+	}
+}
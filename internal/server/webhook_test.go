@@ -0,0 +1,169 @@
+// internal/server/webhook_test.go
+//
+// 測試交易 webhook：存款事件內容正確、轉帳對雙方各送一個事件、
+// 大量並發交易不遺失事件且餘額取自各自的交易、接收端暫時失敗時會重試，以及佇列上限。
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"banking/internal/bank"
+)
+
+// webhookSink 為測試用的 webhook 接收端；前 fail 次請求回應 500。
+type webhookSink struct {
+	mu     sync.Mutex
+	fail   int
+	calls  int
+	events []WebhookEvent
+}
+
+func (k *webhookSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.calls++
+	if k.calls <= k.fail {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	var e WebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	k.events = append(k.events, e)
+}
+
+func (k *webhookSink) received() []WebhookEvent {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]WebhookEvent(nil), k.events...)
+}
+
+// newWebhookServer 建立啟用 webhook（送往 sink）的 Server 與其測試伺服器。
+func newWebhookServer(t *testing.T, b *bank.Bank, sink *webhookSink) (*Server, *httptest.Server) {
+	t.Helper()
+	hook := httptest.NewServer(sink)
+	t.Cleanup(hook.Close)
+	s := NewServer(b, nil, WithWebhook(hook.URL, hook.Client()))
+	s.webhook.backoff = time.Millisecond // 縮短測試中的重試等待
+	ts := httptest.NewServer(s.Router())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func flushWebhooks(t *testing.T, s *Server) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.FlushWebhooks(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhookDeposit(t *testing.T) {
+	b := bank.NewBank()
+	a1, _ := b.Create("A", 100)
+	a2, _ := b.Create("B", 0)
+	sink := &webhookSink{}
+	s, ts := newWebhookServer(t, b, sink)
+	cli := ts.Client()
+
+	// 1️⃣ 存款 → 一個事件，內容與交易一致
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a1.ID+"/deposit", map[string]any{"amount": 50}, 200, nil)
+	flushWebhooks(t, s)
+	got := sink.received()
+	if len(got) != 1 {
+		t.Fatalf("events=%+v want 1", got)
+	}
+	e := got[0]
+	if e.TxID == "" || e.Type != bank.TxDeposit || e.Account != a1.ID || e.Direction != "in" ||
+		e.Amount != 50 || e.Balance != 150 || e.Time.IsZero() {
+		t.Fatalf("deposit event=%+v", e)
+	}
+
+	// 2️⃣ 轉帳 → 雙方各一個事件；失敗的操作不送事件
+	doJSON(t, cli, "POST", ts.URL+"/transfer", map[string]any{"From": a1.ID, "To": a2.ID, "Amount": 30}, 200, nil)
+	doJSON(t, cli, "POST", ts.URL+"/accounts/"+a2.ID+"/withdraw", map[string]any{"amount": 999}, 409, nil)
+	flushWebhooks(t, s)
+	got = sink.received()
+	if len(got) != 3 {
+		t.Fatalf("events=%+v want 3", got)
+	}
+	out, in := got[1], got[2]
+	if out.Account != a1.ID || out.Direction != "out" || out.Balance != 120 ||
+		in.Account != a2.ID || in.Direction != "in" || in.Balance != 30 || out.TxID != in.TxID {
+		t.Fatalf("transfer events=%+v %+v", out, in)
+	}
+}
+
+func TestWebhookConcurrentNoDrops(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	sink := &webhookSink{}
+	s, ts := newWebhookServer(t, b, sink)
+
+	// ✅ 100 筆並發存款 → 100 個事件，tx_id 不重複；balance 為各筆交易提交當下的餘額，恰為 1..100
+	const n = 100
+	var wg sync.WaitGroup
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 1}, 200, nil)
+		}()
+	}
+	wg.Wait()
+	flushWebhooks(t, s)
+	seen, balances := map[string]bool{}, map[int64]bool{}
+	for _, e := range sink.received() {
+		seen[e.TxID] = true
+		balances[e.Balance] = true
+	}
+	if len(seen) != n {
+		t.Fatalf("distinct events=%d want %d", len(seen), n)
+	}
+	for i := int64(1); i <= n; i++ {
+		if !balances[i] {
+			t.Fatalf("no event with balance %d: %v", i, balances)
+		}
+	}
+}
+
+// TestWebhookQueueFull 驗證佇列已滿時丟棄新事件，不再增加佇列長度。
+func TestWebhookQueueFull(t *testing.T) {
+	w := &webhook{wake: make(chan struct{}, 1)} // 不啟動背景 goroutine，事件留在佇列中
+	for i := range webhookQueueMax {
+		w.enqueue(WebhookEvent{TxID: fmt.Sprint(i)})
+	}
+	w.enqueue(WebhookEvent{TxID: "out"}, WebhookEvent{TxID: "in"})
+	if len(w.queue) != webhookQueueMax || w.pending != webhookQueueMax {
+		t.Fatalf("queue=%d pending=%d want %d", len(w.queue), w.pending, webhookQueueMax)
+	}
+}
+
+func TestWebhookRetries(t *testing.T) {
+	b := bank.NewBank()
+	a, _ := b.Create("A", 0)
+	sink := &webhookSink{fail: 2}
+	s, ts := newWebhookServer(t, b, sink)
+
+	// ❌ 前兩次 500 → 重試後送達；原請求不受影響
+	doJSON(t, ts.Client(), "POST", ts.URL+"/accounts/"+a.ID+"/deposit", map[string]any{"amount": 5}, 200, nil)
+	flushWebhooks(t, s)
+	if got := sink.received(); len(got) != 1 || got[0].Amount != 5 {
+		t.Fatalf("events=%+v want one deposit of 5", got)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.calls != 3 {
+		t.Fatalf("calls=%d want 3", sink.calls)
+	}
+}
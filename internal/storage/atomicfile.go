@@ -0,0 +1,85 @@
+// internal/storage/atomicfile.go
+//
+// 快照檔的耐久寫入（SaveSnapshot 使用）：
+//   - 仍採「暫存檔 + rename」的原子替換，讀者永遠只會看到完整的舊檔或新檔；
+//   - 暫存檔在關閉前 fsync，rename 後再 fsync 所在目錄，
+//     確保當機或斷電後 rename 本身也已落盤（部分檔案系統只 fsync 檔案並不足夠）；
+//   - 遇到暫時性錯誤（EINTR、EAGAIN、EBUSY）時退避重試，最多 saveAttempts 次；
+//     其他錯誤（例如磁碟已滿、權限不足）立即回傳。
+//
+// syncFile / syncDir 為套件層級變數，測試可替換以驗證 fsync 確實被呼叫並模擬暫時性錯誤。
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// 寫入重試設定。
+const saveAttempts = 3
+
+// saveBackoff 為第一次重試前的等待，之後每次加倍；測試可縮短。
+var saveBackoff = 20 * time.Millisecond
+
+// syncFile 將檔案內容落盤。
+var syncFile = func(f *os.File) error { return f.Sync() }
+
+// syncDir 將目錄項目（rename 結果）落盤；Windows 無法對目錄 fsync，略過。
+var syncDir = func(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// writeFileAtomic 以暫存檔 + rename 寫入 path，並於暫時性錯誤時重試。
+func writeFileAtomic(path string, data []byte) error {
+	wait := saveBackoff
+	for attempt := 1; ; attempt++ {
+		err := writeFileSynced(path, data)
+		if err == nil || !isTransient(err) || attempt == saveAttempts {
+			return err
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+}
+
+// writeFileSynced 寫入 path+".tmp"、fsync、關閉後 rename 為 path，最後 fsync 所在目錄。
+// 任一步驟失敗時移除暫存檔，原檔維持不變。
+func writeFileSynced(path string, data []byte) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = syncFile(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// isTransient 回報錯誤是否為值得重試的暫時性錯誤。
+func isTransient(err error) bool {
+	return errors.Is(err, syscall.EINTR) || errors.Is(err, syscall.EAGAIN) || errors.Is(err, syscall.EBUSY)
+}
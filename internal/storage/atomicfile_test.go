@@ -0,0 +1,104 @@
+// internal/storage/atomicfile_test.go
+//
+// 測試快照的耐久寫入：成功保存時檔案與目錄皆有 fsync 且可重新載入；
+// 暫時性錯誤會重試，其他錯誤立即回傳且不留下暫存檔、不動到原檔。
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// stubSync 替換 syncFile / syncDir：fileErrs 依序作為前幾次 syncFile 的結果，並記錄呼叫次數。
+func stubSync(t *testing.T, fileErrs ...error) (fileCalls, dirCalls *int) {
+	t.Helper()
+	origFile, origDir, origBackoff := syncFile, syncDir, saveBackoff
+	t.Cleanup(func() { syncFile, syncDir, saveBackoff = origFile, origDir, origBackoff })
+	saveBackoff = time.Millisecond
+	fileCalls, dirCalls = new(int), new(int)
+	syncFile = func(f *os.File) error {
+		*fileCalls++
+		if *fileCalls <= len(fileErrs) {
+			return fileErrs[*fileCalls-1]
+		}
+		return origFile(f)
+	}
+	syncDir = func(dir string) error {
+		*dirCalls++
+		return origDir(dir)
+	}
+	return fileCalls, dirCalls
+}
+
+func TestSaveSnapshotSyncs(t *testing.T) {
+	fileCalls, dirCalls := stubSync(t)
+	path := filepath.Join(t.TempDir(), "data.json")
+	snap := Snapshot{NextID: 2, Accounts: []PersistAccount{{ID: "1", Name: "A", Balance: 100}}}
+
+	// 1️⃣ 成功保存：檔案與目錄各 fsync 一次
+	if err := SaveSnapshot(path, snap); err != nil {
+		t.Fatal(err)
+	}
+	if *fileCalls != 1 || *dirCalls != 1 {
+		t.Fatalf("syncFile=%d syncDir=%d want 1/1", *fileCalls, *dirCalls)
+	}
+	// ✅ 可重新載入，且未留下暫存檔
+	loaded, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.NextID != 2 || len(loaded.Accounts) != 1 || loaded.Accounts[0].Balance != 100 {
+		t.Fatalf("loaded=%+v", loaded)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temp file left behind: %v", err)
+	}
+}
+
+func TestSaveSnapshotRetry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := SaveSnapshot(path, Snapshot{NextID: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1️⃣ 暫時性錯誤兩次 → 第三次成功
+	fileCalls, _ := stubSync(t, syscall.EINTR, syscall.EAGAIN)
+	if err := SaveSnapshot(path, Snapshot{NextID: 5}); err != nil {
+		t.Fatalf("want success after retries, got %v", err)
+	}
+	if *fileCalls != 3 {
+		t.Fatalf("syncFile calls=%d want 3", *fileCalls)
+	}
+	if loaded, _ := LoadSnapshot(path); loaded.NextID != 5 {
+		t.Fatalf("NextID=%d want 5", loaded.NextID)
+	}
+
+	// ❌ 非暫時性錯誤：不重試，原檔不變、暫存檔已移除
+	diskFull := errors.New("no space left on device")
+	fileCalls, _ = stubSync(t, diskFull)
+	if err := SaveSnapshot(path, Snapshot{NextID: 9}); !errors.Is(err, diskFull) {
+		t.Fatalf("want disk full error, got %v", err)
+	}
+	if *fileCalls != 1 {
+		t.Fatalf("syncFile calls=%d want 1 (no retry)", *fileCalls)
+	}
+	if loaded, _ := LoadSnapshot(path); loaded.NextID != 5 {
+		t.Fatalf("original overwritten: NextID=%d want 5", loaded.NextID)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("temp file left behind: %v", err)
+	}
+
+	// ❌ 暫時性錯誤持續發生：saveAttempts 次後放棄
+	fileCalls, _ = stubSync(t, syscall.EBUSY, syscall.EBUSY, syscall.EBUSY, syscall.EBUSY)
+	if err := SaveSnapshot(path, Snapshot{NextID: 9}); !errors.Is(err, syscall.EBUSY) {
+		t.Fatalf("want EBUSY, got %v", err)
+	}
+	if *fileCalls != saveAttempts {
+		t.Fatalf("syncFile calls=%d want %d", *fileCalls, saveAttempts)
+	}
+}
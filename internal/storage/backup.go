@@ -0,0 +1,83 @@
+// internal/storage/backup.go
+//
+// 快照輪替備份：啟用 WithBackups(n) 後，SaveSnapshot 除了以原子 rename 更新正式檔（例如 data.json），
+// 另寫一份帶時間戳的備份 data-<timestamp>.json，只保留最新的 n 份，較舊的自動刪除。
+// 正式檔一次寫壞時，可從備份挑一份複製回正式檔還原（見 ListBackups）。
+//
+// 時間戳為 UTC、固定長度（含奈秒），因此檔名的字典序即時間順序。
+// 備份與正式檔內容完全相同（含加密），可直接以 LoadSnapshot 載入。
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// backupLayout 為備份檔名中的時間戳格式。
+const backupLayout = "20060102T150405.000000000Z"
+
+// WithBackups 讓 SaveSnapshot 每次保存時另寫一份時間戳備份，並只保留最新的 n 份；n <= 0 不備份（預設）。
+func WithBackups(n int) Option {
+	return func(o *options) { o.backups = n }
+}
+
+// backupParts 將正式檔路徑拆成備份檔名的前綴（目錄 + 主檔名 + "-"）與副檔名；
+// 壓縮快照的副檔名含 .gz，例如 data.json.gz → data-<timestamp>.json.gz。
+func backupParts(path string) (prefix, ext string) {
+	ext = filepath.Ext(path)
+	if ext == ".gz" {
+		ext = filepath.Ext(strings.TrimSuffix(path, ext)) + ext
+	}
+	return strings.TrimSuffix(path, ext) + "-", ext
+}
+
+// backupPath 回傳時間 t 的備份檔路徑，例如 data.json → data-20250301T090000.000000000Z.json。
+func backupPath(path string, t time.Time) string {
+	prefix, ext := backupParts(path)
+	return prefix + t.UTC().Format(backupLayout) + ext
+}
+
+// ListBackups 回傳 path 的所有時間戳備份，由舊到新排序；不符合備份命名的檔案不列入。
+func ListBackups(path string) ([]string, error) {
+	prefix, ext := backupParts(path)
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name := filepath.Join(filepath.Dir(path), e.Name())
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(backupLayout, stamp); err != nil {
+			continue
+		}
+		out = append(out, name)
+	}
+	slices.Sort(out)
+	return out, nil
+}
+
+// writeBackup 寫入時間 t 的備份，並刪除超過 keep 份的舊備份。
+func writeBackup(path string, data []byte, t time.Time, keep int) error {
+	if err := writeFileAtomic(backupPath(path, t), data); err != nil {
+		return fmt.Errorf("write backup: %w", err)
+	}
+	backups, err := ListBackups(path)
+	if err != nil {
+		return fmt.Errorf("prune backups: %w", err)
+	}
+	for len(backups) > keep {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("prune backups: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
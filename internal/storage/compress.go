@@ -0,0 +1,49 @@
+// internal/storage/compress.go
+//
+// 快照壓縮：路徑以 .gz 結尾（例如 data.json.gz）時，SaveSnapshot 先以 gzip 壓縮縮排後的 JSON，
+// 再（視設定）加密並原子寫入；加密在壓縮之後，因為密文無法再壓縮。
+// LoadSnapshot 依 gzip 檔頭自動解壓，與副檔名無關，因此手動解壓或改名後的檔案仍可載入。
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+)
+
+// gzipMagic 為 gzip 資料的前兩個位元組（RFC 1952）。
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// isGzipPath 判斷快照路徑是否要求壓縮。
+func isGzipPath(path string) bool {
+	return strings.HasSuffix(path, ".gz")
+}
+
+// isGzipped 判斷資料是否為 gzip 格式。
+func isGzipped(data []byte) bool {
+	return bytes.HasPrefix(data, gzipMagic)
+}
+
+// compress 以 gzip 壓縮 data。
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress 解開 compress 產生的資料。
+func decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
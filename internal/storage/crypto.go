@@ -0,0 +1,75 @@
+// internal/storage/crypto.go
+//
+// 快照的靜態加密（encryption at rest）。
+// 使用 AES-256-GCM：金鑰由設定值經 SHA-256 推導（可使用任意長度的密語），
+// 檔案格式為 magic 前綴 + 隨機 nonce + 密文（含 GCM 驗證標籤）。
+// magic 前綴讓 LoadSnapshot 能分辨加密檔與既有的明文 JSON，平滑升級。
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+)
+
+// 載入加密快照的錯誤。
+var (
+	// ErrDecrypt：金鑰錯誤或檔案遭竄改 / 損毀（GCM 驗證失敗）。
+	ErrDecrypt = errors.New("snapshot decryption failed: wrong key or corrupted file")
+	// ErrEncrypted：快照已加密，但未設定金鑰。
+	ErrEncrypted = errors.New("snapshot is encrypted but no key is configured")
+)
+
+// encMagic 標記加密快照的檔頭。
+var encMagic = []byte("BANKENC1")
+
+// isEncrypted 判斷資料是否為加密快照。
+func isEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encMagic)
+}
+
+// newGCM 由設定金鑰推導 AES-256 金鑰並建立 GCM。
+func newGCM(key []byte) (cipher.AEAD, error) {
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt 以隨機 nonce 加密明文，回傳 magic + nonce + 密文。
+func encrypt(key, plain []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, encMagic...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, nil), nil
+}
+
+// decrypt 解開 encrypt 產生的資料。
+func decrypt(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	data = data[len(encMagic):]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("encrypted snapshot too short")
+	}
+	nonce, ct := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, ct, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plain, nil
+}
@@ -0,0 +1,108 @@
+// internal/storage/journal.go
+//
+// 交易日誌檔（journal）：每筆成功的存款 / 提款 / 轉帳以一行 JSON 追加寫入（append-only）。
+// 快照只會定期寫入，兩次快照之間若程式崩潰，重啟時可由「最後一份快照 + journal」重建狀態：
+//   - 每行記錄操作本身（類型、帳戶、金額…）以及提交時配發的 TxID 與時間；
+//   - 重播（bank.ReplayJournal）略過 TxID 序號不大於快照 next_tx_id 的項目（已包含在快照中），
+//     以及世代（epoch）與快照不同的項目（帳本清空前後的操作），其餘依序重新套用，並沿用原本的時間戳；
+//   - 正常結束時先寫入快照再 Truncate 清空 journal。
+//
+// 追加只呼叫 write（不 fsync）：可承受程式崩潰，但不保證斷電時最後幾筆已落盤。
+// 崩潰可能留下寫到一半的最後一行，ReadJournal 會忽略它。
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// JournalEntry 為 journal 中的一筆操作；欄位語意同 bank.Op 與 bank.Tx。
+type JournalEntry struct {
+	TxID      string    `json:"tx_id"`
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	Account   string    `json:"account,omitempty"`
+	From      string    `json:"from,omitempty"`
+	To        string    `json:"to,omitempty"`
+	Amount    int64     `json:"amount"`
+	RequestID string    `json:"request_id,omitempty"`
+	Ref       string    `json:"ref,omitempty"`   // 沖正交易所沖正的原 TxID
+	Note      string    `json:"note,omitempty"`  // 存款 / 提款的自訂備註
+	Name      string    `json:"name,omitempty"`  // 轉帳並開戶的新帳戶名稱（見 bank.TransferOrCreate）
+	Epoch     int64     `json:"epoch,omitempty"` // 寫入時的帳本世代（見 Snapshot.Epoch）；舊 journal 無此欄位時為 0
+}
+
+// Journal 為以追加模式開啟的 journal 檔案，可由多個 goroutine 同時 Append。
+type Journal struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// OpenJournal 以追加模式開啟（不存在時建立）path。
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{f: f}, nil
+}
+
+// Append 將 e 以單行 JSON 寫入檔尾。
+func (j *Journal) Append(e JournalEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.f.Write(append(line, '\n'))
+	return err
+}
+
+// Truncate 清空 journal（快照已涵蓋所有項目之後呼叫）。
+func (j *Journal) Truncate() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.f.Truncate(0)
+}
+
+// Close 關閉檔案。
+func (j *Journal) Close() error {
+	return j.f.Close()
+}
+
+// ReadJournal 依寫入順序讀出 path 中的所有項目；檔案不存在時回傳空結果。
+// 最後一行若不完整（崩潰時寫到一半）則忽略；中間出現無法解析的行則回傳錯誤。
+func ReadJournal(path string) ([]JournalEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []JournalEntry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			if !bytes.HasSuffix(data, []byte("\n")) && n == bytes.Count(data, []byte("\n"))+1 {
+				break // 未以換行結尾的最後一行：寫到一半即崩潰
+			}
+			return nil, fmt.Errorf("journal line %d: %w", n, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
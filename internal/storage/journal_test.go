@@ -0,0 +1,51 @@
+// internal/storage/journal_test.go
+//
+// 測試目標：journal 檔案的追加、讀取與清空。
+//  1. Append 的項目依序讀回；檔案不存在時讀回空結果。
+//  2. 崩潰留下的不完整最後一行被忽略；中間的壞行回傳錯誤。
+//  3. Truncate 後檔案為空，之後仍可繼續追加。
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournalAppendReadTruncate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.ndjson")
+	if got, err := ReadJournal(path); err != nil || len(got) != 0 {
+		t.Fatalf("missing file: %v %v", got, err)
+	}
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer j.Close()
+	_ = j.Append(JournalEntry{TxID: "tx-1", Type: "deposit", Account: "1", Amount: 10})
+	_ = j.Append(JournalEntry{TxID: "tx-2", Type: "transfer", From: "1", To: "2", Amount: 5})
+
+	// ✅ 模擬崩潰：最後一行只寫了一半
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	_, _ = f.WriteString(`{"tx_id":"tx-3","ty`)
+	_ = f.Close()
+	got, err := ReadJournal(path)
+	if err != nil || len(got) != 2 || got[1].TxID != "tx-2" || got[1].To != "2" {
+		t.Fatalf("read=%+v err=%v", got, err)
+	}
+
+	// ❌ 中間的壞行不是崩潰造成的，視為錯誤
+	_ = os.WriteFile(path+".bad", []byte("{oops}\n{\"tx_id\":\"tx-1\"}\n"), 0o644)
+	if _, err := ReadJournal(path + ".bad"); err == nil {
+		t.Fatal("want error for corrupt middle line")
+	}
+
+	// ✅ Truncate 後清空，仍可追加
+	if err := j.Truncate(); err != nil {
+		t.Fatal(err)
+	}
+	_ = j.Append(JournalEntry{TxID: "tx-9", Type: "deposit", Account: "1", Amount: 1})
+	if got, _ := ReadJournal(path); len(got) != 1 || got[0].TxID != "tx-9" {
+		t.Fatalf("after truncate: %+v", got)
+	}
+}
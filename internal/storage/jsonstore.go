@@ -2,8 +2,8 @@
 //
 // 提供 JSON 快照 (Snapshot) 的序列化與反序列化實作。
 // 用於 Bank 系統的輕量持久化方案，可在未接資料庫前保存狀態。
-// 採「原子寫入」策略 (atomic write)：先寫入 .tmp 檔，再以 rename() 取代原檔，
-// 可避免中途寫入失敗導致檔案損壞，是常見的安全儲存設計模式。
+// 採「原子寫入」策略 (atomic write)：先寫入 .tmp 檔並 fsync，再以 rename() 取代原檔並 fsync 目錄，
+// 可避免中途寫入失敗導致檔案損壞，是常見的安全儲存設計模式（見 atomicfile.go）。
 //
 // ───────────────────────────────
 // 設計理念：
@@ -14,52 +14,130 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 )
 
+// Option 為 SaveSnapshot / LoadSnapshot 的可選設定。
+type Option func(*options)
+
+type options struct {
+	key             []byte // 非空時啟用 AES-GCM 加密（見 crypto.go）
+	preserveUnknown bool   // 載入時保留未知欄位（見 unknown.go）
+	backups         int    // 保留的時間戳備份份數；0 為不備份（見 backup.go）
+}
+
+// WithKey 設定快照加密金鑰；空值代表不加密（預設，維持明文 JSON）。
+func WithKey(key []byte) Option {
+	return func(o *options) { o.key = key }
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// JSONStore 為以單一 JSON 檔案保存快照的 Store 實作（LoadSnapshot / SaveSnapshot 的包裝）。
+type JSONStore struct {
+	path string
+	opts []Option
+}
+
+// NewJSONStore 建立保存於 path 的 JSONStore；opts 同 SaveSnapshot（例如 WithKey）。
+func NewJSONStore(path string, opts ...Option) *JSONStore {
+	return &JSONStore{path: path, opts: opts}
+}
+
+// Load 讀取快照，見 LoadSnapshot。
+func (s *JSONStore) Load() (Snapshot, error) {
+	return LoadSnapshot(s.path, s.opts...)
+}
+
+// Save 以原子寫入保存快照，見 SaveSnapshot。
+func (s *JSONStore) Save(snap Snapshot) error {
+	return SaveSnapshot(s.path, snap, s.opts...)
+}
+
 // LoadSnapshot 讀取指定路徑的 JSON 快照，並解析成 Snapshot 結構。
 // 回傳完整快照資料或錯誤。
 // 若檔案不存在或格式錯誤，回傳對應錯誤給上層 (通常於系統啟動時呼叫)。
-func LoadSnapshot(path string) (Snapshot, error) {
+// 設定金鑰時會先解密；既有的明文快照仍可直接載入，下次儲存即轉為加密格式。
+// 金鑰錯誤回傳 ErrDecrypt；檔案已加密但未設定金鑰回傳 ErrEncrypted。
+// 啟用 WithPreserveUnknown 時，不認得的欄位會保存在 Extra 並於下次儲存寫回。
+// gzip 壓縮的快照會自動解壓（見 compress.go）。
+func LoadSnapshot(path string, opts ...Option) (Snapshot, error) {
 	var snap Snapshot
-	f, err := os.Open(path)
+	o := buildOptions(opts)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return snap, err
 	}
-	defer f.Close()
-	err = json.NewDecoder(f).Decode(&snap)
-	return snap, err
+	if isEncrypted(data) && len(o.key) == 0 {
+		return snap, ErrEncrypted
+	}
+	if len(o.key) > 0 && isEncrypted(data) {
+		if data, err = decrypt(o.key, data); err != nil {
+			return snap, err
+		}
+	}
+	if isGzipped(data) {
+		if data, err = decompress(data); err != nil {
+			return snap, fmt.Errorf("decompress snapshot: %w", err)
+		}
+	}
+	if err = json.Unmarshal(data, &snap); err != nil || !o.preserveUnknown {
+		return snap, err
+	}
+	return snap, captureUnknown(data, &snap)
 }
 
 // SaveSnapshot 將 Snapshot 序列化為 JSON 檔案，並採原子方式寫入。
 // 流程：
 //  1. 設定 Meta.Storage 與當前時間戳。
-//  2. 寫入 path+".tmp" 暫存檔。
-//  3. 寫入完成後使用 os.Rename() 取代正式檔案。
+//  2. 序列化（路徑以 .gz 結尾則 gzip 壓縮，若設定金鑰則再加密）後寫入 path+".tmp" 暫存檔並 fsync。
+//  3. 寫入完成後使用 os.Rename() 取代正式檔案，再 fsync 所在目錄。
 //
-// 這樣設計確保在寫入中斷（例如停電或程式崩潰）時，原檔不會損壞。
-func SaveSnapshot(path string, snap Snapshot) error {
+// 這樣設計確保在寫入中斷（例如停電或程式崩潰）時，原檔不會損壞；
+// 回傳 nil 時新快照已落盤。暫時性錯誤會退避重試數次（見 writeFileAtomic）。
+// 啟用 WithBackups 時，正式檔寫入成功後另寫一份時間戳備份並刪除過舊的備份。
+func SaveSnapshot(path string, snap Snapshot, opts ...Option) error {
+	o := buildOptions(opts)
 	snap.Meta.Storage = "json_snapshot"
 	snap.Meta.Timestamp = time.Now()
-	tmp := path + ".tmp"
-
-	// 建立暫存檔案
-	f, err := os.Create(tmp)
-	if err != nil {
-		return err
-	}
 
 	// 使用縮排格式輸出，方便人類閱讀（例如除錯或手動檢視）
-	enc := json.NewEncoder(f)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
 	enc.SetIndent("", "  ")
 	if err := enc.Encode(snap); err != nil {
-		f.Close()
 		return err
 	}
-	f.Close()
+	data := buf.Bytes()
+	if isGzipPath(path) {
+		var err error
+		if data, err = compress(data); err != nil {
+			return err
+		}
+	}
+	if len(o.key) > 0 {
+		var err error
+		if data, err = encrypt(o.key, data); err != nil {
+			return err
+		}
+	}
 
-	// 原子替換
-	return os.Rename(tmp, path)
+	// 寫入暫存檔案、落盤後原子替換
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+	if o.backups > 0 {
+		return writeBackup(path, data, snap.Meta.Timestamp, o.backups)
+	}
+	return nil
 }
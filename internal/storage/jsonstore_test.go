@@ -11,8 +11,13 @@
 package storage
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -62,3 +67,247 @@ func TestJSONSnapshotRoundTrip(t *testing.T) {
 		t.Fatalf("meta mismatch: %+v", loaded.Meta)
 	}
 }
+
+// TestEncryptedSnapshotRoundTrip
+// ------------------------------------------------------------
+// 驗證設定金鑰時：
+//   - 檔案內容不是明文 JSON（無法直接解析、也看不到帳戶名稱）。
+//   - 以相同金鑰可解密還原出原始快照。
+//   - 未加密的既有快照在設定金鑰後仍可載入。
+//   - 金鑰錯誤或未設定金鑰時回傳明確的錯誤。
+//
+// ------------------------------------------------------------
+func TestEncryptedSnapshotRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	key := []byte("correct horse battery staple")
+
+	orig := Snapshot{
+		Meta:     Meta{Version: 1},
+		NextID:   2,
+		Accounts: []PersistAccount{{ID: "1", Name: "Alice", Balance: 100}, {ID: "2", Name: "Bob", Balance: 50}},
+	}
+	if err := SaveSnapshot(path, orig, WithKey(key)); err != nil {
+		t.Fatalf("SaveSnapshot err=%v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var probe map[string]any
+	if json.Unmarshal(raw, &probe) == nil || bytes.Contains(raw, []byte("Alice")) {
+		t.Fatalf("snapshot on disk should not be readable JSON")
+	}
+
+	loaded, err := LoadSnapshot(path, WithKey(key))
+	if err != nil {
+		t.Fatalf("LoadSnapshot err=%v", err)
+	}
+	if loaded.NextID != orig.NextID || len(loaded.Accounts) != 2 || loaded.Accounts[0].Name != "Alice" || loaded.Accounts[1].Balance != 50 {
+		t.Fatalf("mismatch: loaded=%+v", loaded)
+	}
+
+	// 明文快照 + 設定金鑰 → 仍可載入（升級路徑）
+	plain := filepath.Join(dir, "plain.json")
+	if err := SaveSnapshot(plain, orig); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSnapshot(plain, WithKey(key)); err != nil {
+		t.Fatalf("plaintext snapshot with key configured: %v", err)
+	}
+
+	// ❌ 金鑰錯誤 → ErrDecrypt；未設定金鑰 → ErrEncrypted（不可誤當成空快照）
+	if _, err := LoadSnapshot(path, WithKey([]byte("wrong key"))); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("wrong key err=%v, want ErrDecrypt", err)
+	}
+	if _, err := LoadSnapshot(path); !errors.Is(err, ErrEncrypted) {
+		t.Fatalf("no key err=%v, want ErrEncrypted", err)
+	}
+}
+
+// TestPreserveUnknownFields
+// ------------------------------------------------------------
+// 模擬舊版程式載入新版快照（頂層與帳戶各多一個未知欄位）：
+//   - 啟用 WithPreserveUnknown 時，重新儲存後未知欄位原樣保留。
+//   - 未啟用時維持原行為（未知欄位被丟棄）。
+//
+// ------------------------------------------------------------
+func TestPreserveUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "future.json")
+	future := `{
+  "_meta": {"storage": "json_snapshot", "version": 2},
+  "next_id": 2,
+  "retention_policy": {"days": 90},
+  "accounts": [
+    {"id": "1", "name": "A", "balance": 100, "logs": [], "kyc_level": 3}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(future), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		opts []Option
+		keep bool
+	}{
+		{"preserve", []Option{WithPreserveUnknown()}, true},
+		{"default", nil, false},
+	} {
+		snap, err := LoadSnapshot(path, tc.opts...)
+		if err != nil {
+			t.Fatalf("%s: LoadSnapshot err=%v", tc.name, err)
+		}
+		out := filepath.Join(dir, tc.name+".json")
+		if err := SaveSnapshot(out, snap); err != nil {
+			t.Fatalf("%s: SaveSnapshot err=%v", tc.name, err)
+		}
+		raw, _ := os.ReadFile(out)
+		var got struct {
+			Retention map[string]int `json:"retention_policy"`
+			Accounts  []struct {
+				Balance int64 `json:"balance"`
+				KYC     *int  `json:"kyc_level"`
+			} `json:"accounts"`
+		}
+		if err := json.Unmarshal(raw, &got); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(got.Accounts) != 1 || got.Accounts[0].Balance != 100 {
+			t.Fatalf("%s: known fields lost: %s", tc.name, raw)
+		}
+		kept := got.Retention["days"] == 90 && got.Accounts[0].KYC != nil && *got.Accounts[0].KYC == 3
+		if kept != tc.keep {
+			t.Fatalf("%s: future fields kept=%v want %v: %s", tc.name, kept, tc.keep, raw)
+		}
+	}
+}
+
+// TestSnapshotBackupsRotate
+// ------------------------------------------------------------
+// 驗證 WithBackups(n)：
+//   - 連續保存多次後只保留最新的 n 份時間戳備份，較舊的被刪除。
+//   - 最新的備份與正式檔內容相同，可直接載入。
+//   - 目錄中的其他檔案（不符合備份命名）不受影響。
+//
+// ------------------------------------------------------------
+func TestSnapshotBackupsRotate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	other := filepath.Join(dir, "data-notes.json")
+	if err := os.WriteFile(other, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	const keep = 3
+	var all []string
+	for i := 1; i <= 5; i++ {
+		snap := Snapshot{NextID: int64(i)}
+		if err := SaveSnapshot(path, snap, WithBackups(keep)); err != nil {
+			t.Fatalf("save %d: %v", i, err)
+		}
+		backups, err := ListBackups(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		all = append(all, backups[len(backups)-1])
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != keep {
+		t.Fatalf("backups=%v, want %d", backups, keep)
+	}
+	// 保留的是最後 keep 次保存的備份
+	for i, b := range backups {
+		if want := all[len(all)-keep+i]; b != want {
+			t.Fatalf("backups[%d]=%s, want %s", i, b, want)
+		}
+	}
+	for _, old := range all[:len(all)-keep] {
+		if _, err := os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+			t.Fatalf("old backup %s not pruned: %v", old, err)
+		}
+	}
+
+	newest, err := LoadSnapshot(backups[keep-1])
+	if err != nil {
+		t.Fatalf("load newest backup: %v", err)
+	}
+	current, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newest.NextID != 5 || current.NextID != 5 {
+		t.Fatalf("newest backup NextID=%d, data.json NextID=%d, want 5", newest.NextID, current.NextID)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Fatalf("unrelated file removed: %v", err)
+	}
+}
+
+// TestGzipSnapshotRoundTrip
+// ------------------------------------------------------------
+// 驗證路徑以 .gz 結尾時：
+//   - 檔案為 gzip 格式，且大量帳戶時明顯小於明文版本。
+//   - 載入結果與明文快照完全相同（除保存時間外）。
+//   - 壓縮 + 加密可同時使用。
+//
+// ------------------------------------------------------------
+func TestGzipSnapshotRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	plainPath := filepath.Join(dir, "data.json")
+	gzPath := filepath.Join(dir, "data.json.gz")
+
+	orig := Snapshot{Meta: Meta{Version: 1}, NextID: 1001}
+	for i := 1; i <= 1000; i++ {
+		id := strconv.Itoa(i)
+		orig.Accounts = append(orig.Accounts, PersistAccount{
+			ID: id, Name: "Account " + id, Balance: int64(i * 100),
+			Logs: []any{map[string]any{"type": "deposit", "amount": float64(i * 100), "tx_id": id}},
+		})
+	}
+	if err := SaveSnapshot(plainPath, orig); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveSnapshot(gzPath, orig); err != nil {
+		t.Fatalf("SaveSnapshot(.gz) err=%v", err)
+	}
+
+	plainInfo, _ := os.Stat(plainPath)
+	gzInfo, _ := os.Stat(gzPath)
+	if gzInfo.Size()*2 > plainInfo.Size() {
+		t.Fatalf("gz size=%d not much smaller than plain size=%d", gzInfo.Size(), plainInfo.Size())
+	}
+	raw, _ := os.ReadFile(gzPath)
+	if !isGzipped(raw) {
+		t.Fatalf("snapshot at .gz path is not gzip")
+	}
+
+	want, err := LoadSnapshot(plainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadSnapshot(gzPath)
+	if err != nil {
+		t.Fatalf("LoadSnapshot(.gz) err=%v", err)
+	}
+	got.Meta.Timestamp = want.Meta.Timestamp
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("gz snapshot differs from plain snapshot")
+	}
+
+	// 壓縮後再加密，載入時先解密再解壓
+	key := []byte("k")
+	if err := SaveSnapshot(gzPath, orig, WithKey(key)); err != nil {
+		t.Fatal(err)
+	}
+	got, err = LoadSnapshot(gzPath, WithKey(key))
+	if err != nil || len(got.Accounts) != 1000 || got.Accounts[999].Balance != 100000 {
+		t.Fatalf("encrypted gz: err=%v accounts=%d", err, len(got.Accounts))
+	}
+}
@@ -12,7 +12,13 @@
 // ───────────────────────────────
 package storage
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
+
+// SnapshotVersion 為目前快照的結構版本號（寫入 Meta.Version）。
+const SnapshotVersion = 1
 
 // Meta 為所有持久化快照的中繼資料 (metadata)。
 // 用於記錄儲存方式、版本、建立時間與說明。
@@ -27,17 +33,49 @@ type Meta struct {
 // PersistAccount 為帳戶在儲存層的序列化格式。
 // 不含同步鎖或方法，僅保存資料狀態，確保可安全序列化至 JSON 或資料庫。
 type PersistAccount struct {
-	ID      string `json:"id"`      // 帳戶唯一 ID
-	Name    string `json:"name"`    // 帳戶名稱
-	Balance int64  `json:"balance"` // 帳戶餘額，以最小貨幣單位儲存
-	Logs    []any  `json:"logs"`    // 交易日誌，以任意型別儲存（JSON 可直接還原）
+	ID             string `json:"id"`                        // 帳戶唯一 ID
+	Name           string `json:"name"`                      // 帳戶名稱
+	Balance        int64  `json:"balance"`                   // 帳戶餘額，以最小貨幣單位儲存
+	Currency       string `json:"currency,omitempty"`        // 幣別代碼（ISO-4217）；舊快照無此欄位時視為 USD
+	Status         string `json:"status,omitempty"`          // 帳戶狀態；舊快照無此欄位時視為 active
+	ExternalRef    string `json:"external_ref,omitempty"`    // 整合方自訂的外部參照；舊快照無此欄位時為空
+	OverdraftLimit int64  `json:"overdraft_limit,omitempty"` // 透支額度；舊快照無此欄位時為 0
+	MinBalance     int64  `json:"min_balance,omitempty"`     // 最低餘額；舊快照無此欄位時為 0（不限制）
+	Frozen         bool   `json:"frozen,omitempty"`          // 帳戶凍結；舊快照無此欄位時為未凍結
+	Version        int64  `json:"version,omitempty"`         // 帳戶版本號；舊快照無此欄位時為 0
+	OpeningBalance *int64 `json:"opening_balance,omitempty"` // 開戶餘額；舊快照無此欄位時為 nil（由餘額與日誌反推）
+	Logs           []any  `json:"logs"`                      // 交易日誌，以任意型別儲存（JSON 可直接還原）
+
+	DailyLimit       int64  `json:"daily_limit,omitempty"`        // 每日提款限額；舊快照無此欄位時為 0（不限制）
+	DailyWithdrawn   int64  `json:"daily_withdrawn,omitempty"`    // DailyWithdrawnOn 當日的累計提款與轉出
+	DailyWithdrawnOn string `json:"daily_withdrawn_on,omitempty"` // DailyWithdrawn 所屬的 UTC 日期（YYYY-MM-DD）
+	InterestRateBps  int    `json:"interest_rate_bps,omitempty"`  // 每期利率（基點）；舊快照無此欄位時為 0（不計息）
+
+	Extra map[string]json.RawMessage `json:"-"` // 較新版本寫入、本版不認得的欄位（見 unknown.go）
+}
+
+// PersistHold 為一筆授權保留（預先授權）在儲存層的序列化格式。
+type PersistHold struct {
+	ID      string    `json:"id"`               // 保留 ID，例如 "hold-1"
+	Account string    `json:"account"`          // 被保留資金的帳戶 ID
+	Amount  int64     `json:"amount"`           // 保留金額
+	Created time.Time `json:"created"`          // 建立時間
+	Expires time.Time `json:"expires,omitzero"` // 到期時間；零值代表永不到期
 }
 
 // Snapshot 為 Bank 狀態的完整快照。
 // 包含所有帳戶資料與中繼資訊，用於整體載入與保存。
 // 每次程式結束或狀態改變時可重新產出，確保系統一致性。
 type Snapshot struct {
-	Meta     Meta             `json:"_meta"`    // 中繼資料（儲存資訊與版本）
-	NextID   int64            `json:"next_id"`  // 下一個帳戶可用 ID
-	Accounts []PersistAccount `json:"accounts"` // 帳戶清單（序列化後的純資料）
+	Meta     Meta             `json:"_meta"`                // 中繼資料（儲存資訊與版本）
+	NextID   int64            `json:"next_id"`              // 下一個帳戶可用 ID
+	NextTxID int64            `json:"next_tx_id,omitempty"` // 最近一次使用的交易序號
+	NextSeq  int64            `json:"next_seq,omitempty"`   // 最近一次使用的日誌序號（Log.Seq）
+	Epoch    int64            `json:"epoch,omitempty"`      // 帳本世代：清空帳本時遞增，journal 只重播同一世代的項目
+	Accounts []PersistAccount `json:"accounts"`             // 帳戶清單（序列化後的純資料）
+
+	NextHoldID int64         `json:"next_hold_id,omitempty"` // 最近一次使用的授權保留序號
+	Holds      []PersistHold `json:"holds,omitempty"`        // 尚未請款或釋放的授權保留
+
+	Extra map[string]json.RawMessage `json:"-"` // 較新版本寫入、本版不認得的頂層欄位（見 unknown.go）
 }
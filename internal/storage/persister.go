@@ -0,0 +1,169 @@
+// internal/storage/persister.go
+//
+// Persister 將「每次異動都寫入整份快照」改為合併寫入（debounce）：
+//   - 異動端只呼叫 MarkDirty 標記狀態已變更，不做任何 I/O；
+//   - 背景 goroutine 收到第一個標記後等待 interval，期間的標記一併合併，再執行一次 save；
+//     因此無論請求多密集，save 最多每 interval 執行一次；
+//   - StartAutoSave 另外每隔固定時間無條件保存一次，避免 kill -9 等非正常結束遺失近期變更；
+//   - Close 停止背景 goroutine 並立即補寫尚未保存的變更（供關機流程使用）。
+//
+// 所有寫入（合併寫入、定期保存、Flush / SaveNow、Close）都經由同一把 mu 序列化，不會同時執行 save。
+//
+// save 失敗時保留 dirty 標記，由下一次排程或 Flush 重試。每次 save 的結果都記為 LastErr 並通知 OnSave 的回呼，
+// 合併模式下 MarkDirty 也會回傳最近一次失敗，直到下一次保存成功；背景寫入的失敗因此不會被吞掉。
+package storage
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Persister 以固定間隔合併多次異動的快照寫入。
+type Persister struct {
+	save     func() error
+	interval time.Duration
+
+	mu    sync.Mutex  // 序列化 save，確保同一時間只有一個寫入
+	dirty atomic.Bool // 自上次成功 save 以來是否有未保存的異動
+
+	resMu   sync.Mutex  // 保護 lastErr 與 onSave；不與 mu 共用，save 進行中也可讀取
+	lastErr error       // 最近一次 save 的錯誤；nil 代表成功或尚未保存
+	onSave  func(error) // 每次 save 後呼叫（見 OnSave）
+
+	kick      chan struct{} // 喚醒背景 goroutine（容量 1，多次標記自動合併）
+	stop      chan struct{}
+	wg        sync.WaitGroup // 背景 goroutine（合併寫入、定期保存）
+	closeOnce sync.Once
+}
+
+// NewPersister 建立以 interval 合併寫入的 Persister，並啟動背景 goroutine。
+// interval <= 0 代表不合併：MarkDirty 會同步呼叫 save（等同舊行為）。
+func NewPersister(save func() error, interval time.Duration) *Persister {
+	p := &Persister{
+		save:     save,
+		interval: interval,
+		kick:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	if interval > 0 {
+		p.wg.Add(1)
+		go p.loop()
+	}
+	return p
+}
+
+// MarkDirty 標記狀態已變更；合併模式下只排程寫入並立即回傳 LastErr（最近一次背景保存失敗時非 nil），
+// 同步模式（interval <= 0）下直接寫入並回傳 save 的錯誤。
+// 簽章與 server.NewServer 的 persist 回呼相容。
+func (p *Persister) MarkDirty() error {
+	p.dirty.Store(true)
+	if p.interval <= 0 {
+		return p.Flush()
+	}
+	select {
+	case p.kick <- struct{}{}:
+	default:
+	}
+	return p.LastErr()
+}
+
+// OnSave 設定每次 save 後呼叫的回呼（參數為 save 的結果，nil 代表成功），例如更新就緒狀態；
+// 回呼在寫入 goroutine 中執行，不應阻塞。
+func (p *Persister) OnSave(fn func(error)) {
+	p.resMu.Lock()
+	defer p.resMu.Unlock()
+	p.onSave = fn
+}
+
+// LastErr 回傳最近一次 save 的錯誤；尚未保存過或最近一次成功時回傳 nil。
+func (p *Persister) LastErr() error {
+	p.resMu.Lock()
+	defer p.resMu.Unlock()
+	return p.lastErr
+}
+
+// report 記錄一次 save 的結果並通知 OnSave 的回呼。
+func (p *Persister) report(err error) {
+	p.resMu.Lock()
+	p.lastErr = err
+	fn := p.onSave
+	p.resMu.Unlock()
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// Flush 若有未保存的異動則立即寫入；可與背景寫入並行呼叫。
+func (p *Persister) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.dirty.Swap(false) {
+		return nil
+	}
+	err := p.save()
+	p.report(err)
+	if err != nil {
+		p.dirty.Store(true)
+		return err
+	}
+	return nil
+}
+
+// SaveNow 不論是否有未保存的異動都立即寫入一次。
+func (p *Persister) SaveNow() error {
+	p.dirty.Store(true)
+	return p.Flush()
+}
+
+// StartAutoSave 啟動定期保存：每隔 every 呼叫一次 SaveNow，直到 Close。
+// every <= 0 時不做任何事；須在 Close 之前呼叫。
+func (p *Persister) StartAutoSave(every time.Duration) {
+	if every <= 0 {
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				_ = p.SaveNow()
+			}
+		}
+	}()
+}
+
+// Close 停止背景 goroutine 並補寫尚未保存的異動；重複呼叫只會停止一次。
+func (p *Persister) Close() error {
+	p.closeOnce.Do(func() { close(p.stop) })
+	p.wg.Wait()
+	return p.Flush()
+}
+
+// loop 等待標記，滿 interval 後合併寫入一次。
+func (p *Persister) loop() {
+	defer p.wg.Done()
+	timer := time.NewTimer(p.interval)
+	timer.Stop()
+	for {
+		select {
+		case <-p.stop:
+			timer.Stop()
+			return
+		case <-p.kick:
+			timer.Reset(p.interval)
+		}
+		select {
+		case <-p.stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+			_ = p.Flush()
+		}
+	}
+}
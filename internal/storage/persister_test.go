@@ -0,0 +1,150 @@
+// internal/storage/persister_test.go
+//
+// 測試目標：Persister 的合併寫入行為。
+//  1. 大量並行的 MarkDirty 只觸發少量 save，且 Close 會補寫最後的變更。
+//  2. interval <= 0 時每次 MarkDirty 都同步 save；save 失敗保留 dirty 以便重試。
+//  3. StartAutoSave 依排程無條件保存，Close 後停止。
+//  4. 背景寫入的結果經由 OnSave / LastErr 回報，失敗期間 MarkDirty 回傳該錯誤。
+package storage
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPersisterDebounces(t *testing.T) {
+	var saves, marked, saved atomic.Int64
+	p := NewPersister(func() error {
+		saves.Add(1)
+		saved.Store(marked.Load())
+		return nil
+	}, 50*time.Millisecond)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 10; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				marked.Add(1)
+				_ = p.MarkDirty()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// ✅ 1000 次標記只合併成極少數的寫入
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := saves.Load(); n < 1 || n > 10 {
+		t.Fatalf("saves=%d, want 1..10 for 1000 marks", n)
+	}
+	// ✅ Close 之後最後一次寫入已涵蓋所有標記
+	if saved.Load() != 1000 {
+		t.Fatalf("last save saw %d marks, want 1000", saved.Load())
+	}
+
+	// ✅ 沒有新變更時 Close / Flush 不會再寫入
+	before := saves.Load()
+	_ = p.Close()
+	if saves.Load() != before {
+		t.Fatalf("clean Close should not save")
+	}
+}
+
+func TestPersisterSynchronous(t *testing.T) {
+	var saves int
+	fail := true
+	p := NewPersister(func() error {
+		saves++
+		if fail {
+			return errors.New("disk full")
+		}
+		return nil
+	}, 0)
+
+	// ❌ 同步寫入失敗：錯誤回傳給呼叫端，dirty 保留
+	if err := p.MarkDirty(); err == nil {
+		t.Fatal("want save error")
+	}
+	// ✅ 下一次 Flush 重試成功
+	fail = false
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if saves != 2 {
+		t.Fatalf("saves=%d want 2", saves)
+	}
+	_ = p.MarkDirty()
+	if err := p.Close(); err != nil || saves != 3 {
+		t.Fatalf("saves=%d err=%v, want 3 synchronous saves", saves, err)
+	}
+}
+
+func TestPersisterAutoSave(t *testing.T) {
+	var saves atomic.Int64
+	p := NewPersister(func() error { saves.Add(1); return nil }, time.Hour)
+	p.StartAutoSave(10 * time.Millisecond)
+
+	// ✅ 沒有任何異動也會依排程保存
+	deadline := time.Now().Add(2 * time.Second)
+	for saves.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := saves.Load(); n < 3 {
+		t.Fatalf("auto-saves=%d after 2s, want >= 3", n)
+	}
+
+	// ✅ Close 後定期保存停止
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	n := saves.Load()
+	time.Sleep(50 * time.Millisecond)
+	if saves.Load() != n {
+		t.Fatalf("auto-save kept running after Close")
+	}
+}
+
+func TestPersisterReportsBackgroundErrors(t *testing.T) {
+	var fail atomic.Bool
+	fail.Store(true)
+	p := NewPersister(func() error {
+		if fail.Load() {
+			return errors.New("disk full")
+		}
+		return nil
+	}, 5*time.Millisecond)
+	defer p.Close()
+	results := make(chan error, 16)
+	p.OnSave(func(err error) { results <- err })
+
+	// ❌ 合併寫入在背景失敗：回呼收到錯誤，LastErr 與之後的 MarkDirty 都回報該錯誤
+	if err := p.MarkDirty(); err != nil {
+		t.Fatalf("first MarkDirty err=%v, want nil before any save", err)
+	}
+	select {
+	case err := <-results:
+		if err == nil {
+			t.Fatal("OnSave got nil, want save error")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no background save reported")
+	}
+	if p.LastErr() == nil || p.MarkDirty() == nil {
+		t.Fatalf("LastErr=%v, want save error", p.LastErr())
+	}
+
+	// ✅ 恢復後下一次保存成功，錯誤清除
+	fail.Store(false)
+	if err := p.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.LastErr(); err != nil {
+		t.Fatalf("LastErr=%v after successful save", err)
+	}
+}
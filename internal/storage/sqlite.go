@@ -0,0 +1,288 @@
+// internal/storage/sqlite.go
+//
+// SQLiteStore 為以 SQLite 資料庫保存快照的 Store 實作（純 Go 驅動 modernc.org/sqlite，不需 cgo）。
+//
+// 資料表：
+//   - accounts：每個帳戶一列（ord 保存快照中的順序）；extra 為本版不認得欄位的 JSON。
+//   - logs：每筆日誌一列（account_id + seq），body 為日誌的 JSON。
+//     storage 層不認識 bank.Log 的結構，因此日誌以 JSON 原樣保存，交由 bank.Restore 解析。
+//   - meta：鍵值表，保存 next_id、next_tx_id、epoch、版本等快照層級欄位；授權保留（holds）數量少，以 JSON 存於此表。
+//
+// Save 在單一交易內清空並重寫三張表；中途失敗（含程式崩潰）時交易不會提交，資料庫維持上一份快照。
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"time"
+
+	_ "modernc.org/sqlite" // 註冊 "sqlite" 驅動
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS accounts (
+	id              TEXT PRIMARY KEY,
+	ord             INTEGER NOT NULL,
+	name            TEXT NOT NULL,
+	balance         INTEGER NOT NULL,
+	currency        TEXT NOT NULL DEFAULT '',
+	status          TEXT NOT NULL DEFAULT '',
+	overdraft_limit INTEGER NOT NULL DEFAULT 0,
+	frozen          INTEGER NOT NULL DEFAULT 0,
+	min_balance     INTEGER NOT NULL DEFAULT 0,
+	daily_limit     INTEGER NOT NULL DEFAULT 0,
+	daily_withdrawn INTEGER NOT NULL DEFAULT 0,
+	daily_day       TEXT NOT NULL DEFAULT '',
+	interest_bps    INTEGER NOT NULL DEFAULT 0,
+	version         INTEGER NOT NULL DEFAULT 0,
+	opening_balance INTEGER,
+	external_ref    TEXT NOT NULL DEFAULT '',
+	extra           TEXT
+);
+CREATE TABLE IF NOT EXISTS logs (
+	account_id TEXT NOT NULL REFERENCES accounts(id),
+	seq        INTEGER NOT NULL,
+	body       TEXT NOT NULL,
+	PRIMARY KEY (account_id, seq)
+);
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);`
+
+// sqliteAddedColumns 為資料表建立後才新增的欄位；OpenSQLite 會替舊資料庫補上（ALTER TABLE ADD COLUMN）。
+var sqliteAddedColumns = []struct{ table, column, def string }{
+	{"accounts", "frozen", "INTEGER NOT NULL DEFAULT 0"},
+	{"accounts", "min_balance", "INTEGER NOT NULL DEFAULT 0"},
+	{"accounts", "daily_limit", "INTEGER NOT NULL DEFAULT 0"},
+	{"accounts", "daily_withdrawn", "INTEGER NOT NULL DEFAULT 0"},
+	{"accounts", "daily_day", "TEXT NOT NULL DEFAULT ''"},
+	{"accounts", "version", "INTEGER NOT NULL DEFAULT 0"},
+	{"accounts", "opening_balance", "INTEGER"},
+	{"accounts", "external_ref", "TEXT NOT NULL DEFAULT ''"},
+	{"accounts", "interest_bps", "INTEGER NOT NULL DEFAULT 0"},
+}
+
+// SQLiteStore 以 SQLite 資料庫檔案保存快照。
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// OpenSQLite 開啟（不存在時建立）path 的資料庫並建立資料表。使用完畢須呼叫 Close。
+func OpenSQLite(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1) // SQLite 同時只允許一個寫入者；單一連線避免 SQLITE_BUSY
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite schema: %w", err)
+	}
+	if err := migrateSQLite(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite migrate: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// migrateSQLite 為舊版資料庫補上 sqliteAddedColumns 中缺少的欄位。
+func migrateSQLite(db *sql.DB) error {
+	for _, c := range sqliteAddedColumns {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column).Scan(&n); err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, c.table, c.column, c.def)); err != nil {
+			return fmt.Errorf("add %s.%s: %w", c.table, c.column, err)
+		}
+	}
+	return nil
+}
+
+// Close 關閉資料庫連線。
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Load 讀取最近一次保存的快照；資料庫尚未保存過快照時回傳包裝 fs.ErrNotExist 的錯誤。
+func (s *SQLiteStore) Load() (Snapshot, error) {
+	var snap Snapshot
+	meta, err := s.loadMeta()
+	if err != nil {
+		return snap, err
+	}
+	if len(meta) == 0 {
+		return snap, fmt.Errorf("sqlite store: no snapshot: %w", fs.ErrNotExist)
+	}
+	snap.Meta.Storage = meta["storage"]
+	snap.Meta.Note = meta["note"]
+	snap.Meta.Version, _ = strconv.Atoi(meta["version"])
+	snap.Meta.Timestamp, _ = time.Parse(time.RFC3339Nano, meta["timestamp"])
+	snap.NextID, _ = strconv.ParseInt(meta["next_id"], 10, 64)
+	snap.NextTxID, _ = strconv.ParseInt(meta["next_tx_id"], 10, 64)
+	snap.NextSeq, _ = strconv.ParseInt(meta["next_seq"], 10, 64)
+	snap.Epoch, _ = strconv.ParseInt(meta["epoch"], 10, 64)
+	snap.NextHoldID, _ = strconv.ParseInt(meta["next_hold_id"], 10, 64)
+	if v := meta["holds"]; v != "" {
+		if err := json.Unmarshal([]byte(v), &snap.Holds); err != nil {
+			return snap, fmt.Errorf("sqlite meta holds: %w", err)
+		}
+	}
+	if v := meta["extra"]; v != "" {
+		if err := json.Unmarshal([]byte(v), &snap.Extra); err != nil {
+			return snap, fmt.Errorf("sqlite meta extra: %w", err)
+		}
+	}
+
+	rows, err := s.db.Query(`SELECT id, name, balance, currency, status, overdraft_limit, frozen, min_balance, daily_limit, daily_withdrawn, daily_day, interest_bps, version, opening_balance, external_ref, extra FROM accounts ORDER BY ord`)
+	if err != nil {
+		return snap, err
+	}
+	defer rows.Close()
+	index := make(map[string]int)
+	for rows.Next() {
+		var pa PersistAccount
+		var extra sql.NullString
+		if err := rows.Scan(&pa.ID, &pa.Name, &pa.Balance, &pa.Currency, &pa.Status, &pa.OverdraftLimit, &pa.Frozen, &pa.MinBalance, &pa.DailyLimit, &pa.DailyWithdrawn, &pa.DailyWithdrawnOn, &pa.InterestRateBps, &pa.Version, &pa.OpeningBalance, &pa.ExternalRef, &extra); err != nil {
+			return snap, err
+		}
+		if extra.Valid {
+			if err := json.Unmarshal([]byte(extra.String), &pa.Extra); err != nil {
+				return snap, fmt.Errorf("sqlite account %s extra: %w", pa.ID, err)
+			}
+		}
+		pa.Logs = []any{}
+		index[pa.ID] = len(snap.Accounts)
+		snap.Accounts = append(snap.Accounts, pa)
+	}
+	if err := rows.Err(); err != nil {
+		return snap, err
+	}
+
+	logs, err := s.db.Query(`SELECT account_id, body FROM logs ORDER BY account_id, seq`)
+	if err != nil {
+		return snap, err
+	}
+	defer logs.Close()
+	for logs.Next() {
+		var id, body string
+		if err := logs.Scan(&id, &body); err != nil {
+			return snap, err
+		}
+		if i, ok := index[id]; ok {
+			snap.Accounts[i].Logs = append(snap.Accounts[i].Logs, json.RawMessage(body))
+		}
+	}
+	return snap, logs.Err()
+}
+
+// loadMeta 讀取 meta 表的所有鍵值。
+func (s *SQLiteStore) loadMeta() (map[string]string, error) {
+	rows, err := s.db.Query(`SELECT key, value FROM meta`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	meta := make(map[string]string)
+	for rows.Next() {
+		var k, v string
+		if err := rows.Scan(&k, &v); err != nil {
+			return nil, err
+		}
+		meta[k] = v
+	}
+	return meta, rows.Err()
+}
+
+// Save 於單一交易內以 snap 取代資料庫中的快照；任何錯誤都會回滾。
+func (s *SQLiteStore) Save(snap Snapshot) (err error) {
+	snap.Meta.Storage = "sqlite"
+	snap.Meta.Timestamp = time.Now()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+	for _, q := range []string{`DELETE FROM logs`, `DELETE FROM accounts`, `DELETE FROM meta`} {
+		if _, err = tx.Exec(q); err != nil {
+			return err
+		}
+	}
+
+	meta := map[string]string{
+		"storage":      snap.Meta.Storage,
+		"version":      strconv.Itoa(snap.Meta.Version),
+		"timestamp":    snap.Meta.Timestamp.Format(time.RFC3339Nano),
+		"note":         snap.Meta.Note,
+		"next_id":      strconv.FormatInt(snap.NextID, 10),
+		"next_tx_id":   strconv.FormatInt(snap.NextTxID, 10),
+		"next_seq":     strconv.FormatInt(snap.NextSeq, 10),
+		"next_hold_id": strconv.FormatInt(snap.NextHoldID, 10),
+		"epoch":        strconv.FormatInt(snap.Epoch, 10),
+	}
+	if len(snap.Holds) > 0 {
+		var raw []byte
+		if raw, err = json.Marshal(snap.Holds); err != nil {
+			return err
+		}
+		meta["holds"] = string(raw)
+	}
+	if len(snap.Extra) > 0 {
+		var raw []byte
+		if raw, err = json.Marshal(snap.Extra); err != nil {
+			return err
+		}
+		meta["extra"] = string(raw)
+	}
+	for k, v := range meta {
+		if _, err = tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)`, k, v); err != nil {
+			return err
+		}
+	}
+
+	insAcct, err := tx.Prepare(`INSERT INTO accounts (id, ord, name, balance, currency, status, overdraft_limit, frozen, min_balance, daily_limit, daily_withdrawn, daily_day, interest_bps, version, opening_balance, external_ref, extra) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insAcct.Close()
+	insLog, err := tx.Prepare(`INSERT INTO logs (account_id, seq, body) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insLog.Close()
+	for i, pa := range snap.Accounts {
+		var extra sql.NullString
+		if len(pa.Extra) > 0 {
+			var raw []byte
+			if raw, err = json.Marshal(pa.Extra); err != nil {
+				return err
+			}
+			extra = sql.NullString{String: string(raw), Valid: true}
+		}
+		if _, err = insAcct.Exec(pa.ID, i, pa.Name, pa.Balance, pa.Currency, pa.Status, pa.OverdraftLimit, pa.Frozen, pa.MinBalance, pa.DailyLimit, pa.DailyWithdrawn, pa.DailyWithdrawnOn, pa.InterestRateBps, pa.Version, pa.OpeningBalance, pa.ExternalRef, extra); err != nil {
+			return fmt.Errorf("sqlite account %s: %w", pa.ID, err)
+		}
+		for seq, l := range pa.Logs {
+			var body []byte
+			if body, err = json.Marshal(l); err != nil {
+				return err
+			}
+			if _, err = insLog.Exec(pa.ID, seq, string(body)); err != nil {
+				return fmt.Errorf("sqlite log %s/%d: %w", pa.ID, seq, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
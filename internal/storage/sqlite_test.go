@@ -0,0 +1,145 @@
+// internal/storage/sqlite_test.go
+//
+// 測試目標：SQLiteStore 以暫存資料庫檔案往返快照。
+//  1. 空資料庫 Load 回傳 fs.ErrNotExist。
+//  2. 含日誌的快照 Save 後（重新開啟資料庫）Load，帳戶、餘額、日誌筆數、序號與授權保留一致。
+//  3. 再次 Save 會完整取代舊快照（刪除的帳戶不殘留）。
+//  4. 舊版資料庫（缺少後來新增的欄位）開啟時自動補上欄位，既有資料可照常載入。
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.db")
+	st, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Load(); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("empty db Load err=%v, want fs.ErrNotExist", err)
+	}
+
+	log := func(tx string, amt int64, dir string) any {
+		return map[string]any{"tx_id": tx, "type": "deposit", "amount": amt, "direction": dir, "counter_account": "", "note": "deposit"}
+	}
+	opening := int64(100)
+	snap := Snapshot{
+		Meta:       Meta{Version: 1, Note: "test"},
+		NextID:     2,
+		NextTxID:   3,
+		NextSeq:    4,
+		NextHoldID: 5,
+		Holds:      []PersistHold{{ID: "hold-5", Account: "1", Amount: 30, Created: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		Accounts: []PersistAccount{
+			{ID: "2", Name: "B", Balance: 50, Currency: "EUR", Status: "active", ExternalRef: "user-42", Logs: []any{log("tx-3", 50, "in")}},
+			{ID: "1", Name: "A", Balance: 150, Currency: "USD", Status: "active", OverdraftLimit: 20, MinBalance: 5, Frozen: true, Version: 7, OpeningBalance: &opening,
+				DailyLimit: 500, DailyWithdrawn: 120, DailyWithdrawnOn: "2026-01-02",
+				Logs: []any{log("tx-1", 100, "in"), log("tx-2", 50, "in")}},
+		},
+	}
+	if err := st.Save(snap); err != nil {
+		t.Fatal(err)
+	}
+	_ = st.Close()
+
+	// 重新開啟，確認資料確實寫入檔案
+	st, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	got, err := st.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Holds) != 1 || got.NextHoldID != 5 || got.Holds[0].ID != "hold-5" || got.Holds[0].Amount != 30 || !got.Holds[0].Created.Equal(snap.Holds[0].Created) {
+		t.Fatalf("holds=%+v next=%d", got.Holds, got.NextHoldID)
+	}
+	if got.Meta.Storage != "sqlite" || got.NextID != 2 || got.NextTxID != 3 || got.NextSeq != 4 || len(got.Accounts) != 2 {
+		t.Fatalf("snapshot header=%+v next=%d/%d accounts=%d", got.Meta, got.NextID, got.NextTxID, len(got.Accounts))
+	}
+	for i, want := range snap.Accounts {
+		a := got.Accounts[i]
+		if a.ID != want.ID || a.Balance != want.Balance || a.Currency != want.Currency || a.OverdraftLimit != want.OverdraftLimit || a.MinBalance != want.MinBalance || a.Frozen != want.Frozen || a.Version != want.Version ||
+			a.DailyLimit != want.DailyLimit || a.DailyWithdrawn != want.DailyWithdrawn || a.DailyWithdrawnOn != want.DailyWithdrawnOn || a.ExternalRef != want.ExternalRef {
+			t.Fatalf("account %d=%+v want %+v", i, a, want)
+		}
+		if (a.OpeningBalance == nil) != (want.OpeningBalance == nil) || (a.OpeningBalance != nil && *a.OpeningBalance != *want.OpeningBalance) {
+			t.Fatalf("account %s opening=%v want %v", a.ID, a.OpeningBalance, want.OpeningBalance)
+		}
+		if len(a.Logs) != len(want.Logs) {
+			t.Fatalf("account %s logs=%d want %d", a.ID, len(a.Logs), len(want.Logs))
+		}
+	}
+	var first struct {
+		TxID   string `json:"tx_id"`
+		Amount int64  `json:"amount"`
+	}
+	raw, _ := json.Marshal(got.Accounts[1].Logs[1])
+	if err := json.Unmarshal(raw, &first); err != nil || first.TxID != "tx-2" || first.Amount != 50 {
+		t.Fatalf("log order/content lost: %s", raw)
+	}
+
+	// 再次保存較小的快照：舊帳戶與日誌不得殘留
+	snap.Accounts = snap.Accounts[1:]
+	if err := st.Save(snap); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = st.Load(); len(got.Accounts) != 1 || got.Accounts[0].ID != "1" || len(got.Accounts[0].Logs) != 2 {
+		t.Fatalf("after resave: %+v", got.Accounts)
+	}
+}
+
+func TestSQLiteMigratesOldSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.db")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 建立 accounts 表尚無後來新增欄位（sqliteAddedColumns）的舊版資料庫
+	old := sqliteSchema
+	for _, c := range sqliteAddedColumns {
+		col := fmt.Sprintf("\t%-15s %s,\n", c.column, c.def)
+		if !strings.Contains(old, col) {
+			t.Fatalf("test setup: column %q not found in schema", col)
+		}
+		old = strings.Replace(old, col, "", 1)
+	}
+	for _, q := range []string{
+		old,
+		`INSERT INTO accounts (id, ord, name, balance) VALUES ('1', 0, 'A', 10)`,
+		`INSERT INTO meta (key, value) VALUES ('next_id', '1'), ('version', '1')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = db.Close()
+
+	st, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatalf("open old db: %v", err)
+	}
+	defer st.Close()
+	got, err := st.Load()
+	if err != nil || len(got.Accounts) != 1 || got.Accounts[0].Balance != 10 || got.Accounts[0].Frozen {
+		t.Fatalf("load old db: %+v err=%v", got.Accounts, err)
+	}
+	got.Accounts[0].Frozen = true
+	if err := st.Save(got); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = st.Load(); !got.Accounts[0].Frozen {
+		t.Fatal("frozen flag not saved after migration")
+	}
+}
@@ -0,0 +1,22 @@
+// internal/storage/store.go
+//
+// Store 為快照儲存後端的抽象介面。
+// bank 只負責產生 / 還原 Snapshot，server 只呼叫 persist 回呼；
+// 兩者都不知道資料實際存在哪裡，因此更換後端（JSON 檔、資料庫…）只需在 main 換掉 Store 實作。
+package storage
+
+// Store 載入與保存完整的銀行快照。
+//   - Load：讀取最近一次保存的快照；尚無資料時回傳錯誤（呼叫端通常以空銀行啟動）。
+//   - Save：以新快照取代既有資料；實作須確保失敗時不會留下寫到一半的狀態。
+type Store interface {
+	Load() (Snapshot, error)
+	Save(Snapshot) error
+}
+
+// SaveFunc 回傳「取得快照並交給 st 保存」的 persist 回呼，
+// 簽章與 server.NewServer 的 persist 參數及 NewPersister 的 save 參數相容。
+func SaveFunc(st Store, snapshot func() Snapshot) func() error {
+	return func() error {
+		return st.Save(snapshot())
+	}
+}
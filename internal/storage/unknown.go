@@ -0,0 +1,114 @@
+// internal/storage/unknown.go
+//
+// 前向相容：較舊的程式載入較新版本寫出的快照時，預設會丟棄不認得的 JSON 欄位，
+// 下次儲存即造成資料遺失。啟用 WithPreserveUnknown 後，LoadSnapshot 會把快照頂層與
+// 每個帳戶中不認得的欄位以 json.RawMessage 原樣保存在 Extra，SaveSnapshot 時再寫回。
+//
+// 限制：交易日誌（logs）由上層重建為型別化紀錄，其中的未知欄位不在保存範圍內。
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// WithPreserveUnknown 讓 LoadSnapshot 保留未知欄位（存入 Snapshot.Extra / PersistAccount.Extra）。
+func WithPreserveUnknown() Option {
+	return func(o *options) { o.preserveUnknown = true }
+}
+
+// MarshalJSON 輸出已知欄位，並附上 Extra 中保存的未知欄位（已知欄位優先）。
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	type plain Snapshot
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+// MarshalJSON 輸出已知欄位，並附上 Extra 中保存的未知欄位（已知欄位優先）。
+func (a PersistAccount) MarshalJSON() ([]byte, error) {
+	type plain PersistAccount
+	return marshalWithExtra(plain(a), a.Extra)
+}
+
+// marshalWithExtra 序列化 v；有 extra 時依鍵名排序附加在已知欄位之後（已知欄位的順序不變）。
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(data, &known); err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(data[:len(data)-1]) // 去掉結尾的 '}'
+	for _, k := range slices.Sorted(maps.Keys(extra)) {
+		if _, dup := known[k]; dup {
+			continue
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// DecodeSnapshot 解析快照 JSON：不拒絕未知欄位，並與 WithPreserveUnknown 相同將其保存在 Extra，
+// 供匯入外部提供的快照時原樣保留較新版本寫入的欄位（見 server 的 /admin/import）。
+func DecodeSnapshot(data []byte) (Snapshot, error) {
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return snap, err
+	}
+	return snap, captureUnknown(data, &snap)
+}
+
+// captureUnknown 由原始 JSON 找出 Snapshot 與各帳戶中不認得的欄位，存入對應的 Extra。
+func captureUnknown(data []byte, snap *Snapshot) error {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(data, &top); err != nil {
+		return err
+	}
+	snap.Extra = unknownFields(top, reflect.TypeFor[Snapshot]())
+
+	var accts []map[string]json.RawMessage
+	if raw, ok := top["accounts"]; ok {
+		if err := json.Unmarshal(raw, &accts); err != nil {
+			return err
+		}
+	}
+	for i := range snap.Accounts {
+		if i < len(accts) {
+			snap.Accounts[i].Extra = unknownFields(accts[i], reflect.TypeFor[PersistAccount]())
+		}
+	}
+	return nil
+}
+
+// unknownFields 回傳 m 中未對應到 t 任一 JSON 標籤的欄位；沒有時回傳 nil。
+func unknownFields(m map[string]json.RawMessage, t reflect.Type) map[string]json.RawMessage {
+	known := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	var out map[string]json.RawMessage
+	for k, raw := range m {
+		if !known[k] {
+			if out == nil {
+				out = make(map[string]json.RawMessage)
+			}
+			out[k] = raw
+		}
+	}
+	return out
+}